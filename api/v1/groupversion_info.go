@@ -0,0 +1,34 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+
+// Package v1 contains API Schema definitions for the rabbitmq v1 API group.
+// v1 is served next to v1beta1, which remains the storage version. RabbitmqClusters are converted between the
+// versions by the conversion webhook of the operator.
+//
+// The only change of the spec is the plugins section: spec.rabbitmq.additionalPlugins of v1beta1 is
+// spec.plugins.additional in v1. The tls and override sections and the list of imagePullSecrets are already
+// structured in v1beta1, so v1 keeps them as they are.
+// +kubebuilder:object:generate=true
+// +groupName=rabbitmq.com
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is group version used to register these objects
+	GroupVersion = schema.GroupVersion{Group: "rabbitmq.com", Version: "v1"}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the types in this group-version to the given scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
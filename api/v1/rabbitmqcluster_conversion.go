@@ -0,0 +1,136 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+
+package v1
+
+import (
+	"github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts the RabbitmqCluster to the v1beta1 hub version.
+func (src *RabbitmqCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.RabbitmqCluster)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	dst.Spec = v1beta1.RabbitmqClusterSpec{
+		Replicas:                      src.Spec.Replicas,
+		Image:                         src.Spec.Image,
+		ImagePullPolicy:               src.Spec.ImagePullPolicy,
+		ImagePullSecrets:              src.Spec.ImagePullSecrets,
+		ServiceAccountName:            src.Spec.ServiceAccountName,
+		Service:                       src.Spec.Service,
+		HeadlessService:               src.Spec.HeadlessService,
+		ManagementService:             src.Spec.ManagementService,
+		PodServices:                   src.Spec.PodServices,
+		ManagementIngress:             src.Spec.ManagementIngress,
+		Route:                         src.Spec.Route,
+		Gateway:                       src.Spec.Gateway,
+		Monitoring:                    src.Spec.Monitoring,
+		Persistence:                   src.Spec.Persistence,
+		Resources:                     src.Spec.Resources,
+		Affinity:                      src.Spec.Affinity,
+		Tolerations:                   src.Spec.Tolerations,
+		AdditionalConfigMaps:          src.Spec.AdditionalConfigMaps,
+		AdditionalSecrets:             src.Spec.AdditionalSecrets,
+		TLS:                           src.Spec.TLS,
+		Override:                      src.Spec.Override,
+		SkipPostDeploySteps:           src.Spec.SkipPostDeploySteps,
+		TerminationGracePeriodSeconds: src.Spec.TerminationGracePeriodSeconds,
+		DelayStartSeconds:             src.Spec.DelayStartSeconds,
+		SecretBackend:                 src.Spec.SecretBackend,
+		RemoteCluster:                 src.Spec.RemoteCluster,
+		RolloutAnalysis:               src.Spec.RolloutAnalysis,
+		UpdateStrategy:                src.Spec.UpdateStrategy,
+		Rabbitmq: v1beta1.RabbitmqClusterConfigurationSpec{
+			AdditionalPlugins:   src.Spec.Plugins.Additional,
+			AdditionalConfig:    src.Spec.Rabbitmq.AdditionalConfig,
+			AdvancedConfig:      src.Spec.Rabbitmq.AdvancedConfig,
+			EnvConfig:           src.Spec.Rabbitmq.EnvConfig,
+			ErlangInetConfig:    src.Spec.Rabbitmq.ErlangInetConfig,
+			DefaultQueueType:    src.Spec.Rabbitmq.DefaultQueueType,
+			QueueLeaderLocator:  src.Spec.Rabbitmq.QueueLeaderLocator,
+			DefaultUserTags:     src.Spec.Rabbitmq.DefaultUserTags,
+			ConfigFrom:          src.Spec.Rabbitmq.ConfigFrom,
+			Definitions:         src.Spec.Rabbitmq.Definitions,
+			Auth:                src.Spec.Rabbitmq.Auth,
+			MQTT:                src.Spec.Rabbitmq.MQTT,
+			STOMP:               src.Spec.Rabbitmq.STOMP,
+			Logging:             src.Spec.Rabbitmq.Logging,
+			Metrics:             src.Spec.Rabbitmq.Metrics,
+			PeerDiscovery:       src.Spec.Rabbitmq.PeerDiscovery,
+			DiskFreeLimit:       src.Spec.Rabbitmq.DiskFreeLimit,
+			MemoryHighWatermark: src.Spec.Rabbitmq.MemoryHighWatermark,
+			AdditionalUsers:     src.Spec.Rabbitmq.AdditionalUsers,
+		},
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1beta1 hub version to the RabbitmqCluster.
+func (dst *RabbitmqCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.RabbitmqCluster)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	dst.Spec = RabbitmqClusterSpec{
+		Replicas:                      src.Spec.Replicas,
+		Image:                         src.Spec.Image,
+		ImagePullPolicy:               src.Spec.ImagePullPolicy,
+		ImagePullSecrets:              src.Spec.ImagePullSecrets,
+		ServiceAccountName:            src.Spec.ServiceAccountName,
+		Service:                       src.Spec.Service,
+		HeadlessService:               src.Spec.HeadlessService,
+		ManagementService:             src.Spec.ManagementService,
+		PodServices:                   src.Spec.PodServices,
+		ManagementIngress:             src.Spec.ManagementIngress,
+		Route:                         src.Spec.Route,
+		Gateway:                       src.Spec.Gateway,
+		Monitoring:                    src.Spec.Monitoring,
+		Persistence:                   src.Spec.Persistence,
+		Resources:                     src.Spec.Resources,
+		Affinity:                      src.Spec.Affinity,
+		Tolerations:                   src.Spec.Tolerations,
+		AdditionalConfigMaps:          src.Spec.AdditionalConfigMaps,
+		AdditionalSecrets:             src.Spec.AdditionalSecrets,
+		TLS:                           src.Spec.TLS,
+		Override:                      src.Spec.Override,
+		SkipPostDeploySteps:           src.Spec.SkipPostDeploySteps,
+		TerminationGracePeriodSeconds: src.Spec.TerminationGracePeriodSeconds,
+		DelayStartSeconds:             src.Spec.DelayStartSeconds,
+		SecretBackend:                 src.Spec.SecretBackend,
+		RemoteCluster:                 src.Spec.RemoteCluster,
+		RolloutAnalysis:               src.Spec.RolloutAnalysis,
+		UpdateStrategy:                src.Spec.UpdateStrategy,
+		Plugins: RabbitmqClusterPluginsSpec{
+			Additional: src.Spec.Rabbitmq.AdditionalPlugins,
+		},
+		Rabbitmq: RabbitmqClusterConfigurationSpec{
+			AdditionalConfig:    src.Spec.Rabbitmq.AdditionalConfig,
+			AdvancedConfig:      src.Spec.Rabbitmq.AdvancedConfig,
+			EnvConfig:           src.Spec.Rabbitmq.EnvConfig,
+			ErlangInetConfig:    src.Spec.Rabbitmq.ErlangInetConfig,
+			DefaultQueueType:    src.Spec.Rabbitmq.DefaultQueueType,
+			QueueLeaderLocator:  src.Spec.Rabbitmq.QueueLeaderLocator,
+			DefaultUserTags:     src.Spec.Rabbitmq.DefaultUserTags,
+			ConfigFrom:          src.Spec.Rabbitmq.ConfigFrom,
+			Definitions:         src.Spec.Rabbitmq.Definitions,
+			Auth:                src.Spec.Rabbitmq.Auth,
+			MQTT:                src.Spec.Rabbitmq.MQTT,
+			STOMP:               src.Spec.Rabbitmq.STOMP,
+			Logging:             src.Spec.Rabbitmq.Logging,
+			Metrics:             src.Spec.Rabbitmq.Metrics,
+			PeerDiscovery:       src.Spec.Rabbitmq.PeerDiscovery,
+			DiskFreeLimit:       src.Spec.Rabbitmq.DiskFreeLimit,
+			MemoryHighWatermark: src.Spec.Rabbitmq.MemoryHighWatermark,
+			AdditionalUsers:     src.Spec.Rabbitmq.AdditionalUsers,
+		},
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package v1
+
+import (
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Conversion", func() {
+	It("moves the additional plugins between spec.rabbitmq and spec.plugins", func() {
+		hub := &v1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "rabbit", Namespace: "default"},
+			Spec: v1beta1.RabbitmqClusterSpec{
+				Replicas: ptr.To(int32(3)),
+				Rabbitmq: v1beta1.RabbitmqClusterConfigurationSpec{
+					AdditionalPlugins: []v1beta1.Plugin{"rabbitmq_shovel"},
+					AdditionalConfig:  "log.console.level = debug",
+				},
+			},
+		}
+
+		cluster := &RabbitmqCluster{}
+		Expect(cluster.ConvertFrom(hub)).To(Succeed())
+		Expect(cluster.Name).To(Equal("rabbit"))
+		Expect(cluster.Spec.Replicas).To(Equal(ptr.To(int32(3))))
+		Expect(cluster.Spec.Plugins.Additional).To(ConsistOf(v1beta1.Plugin("rabbitmq_shovel")))
+		Expect(cluster.Spec.Rabbitmq.AdditionalConfig).To(Equal("log.console.level = debug"))
+
+		converted := &v1beta1.RabbitmqCluster{}
+		Expect(cluster.ConvertTo(converted)).To(Succeed())
+		Expect(converted).To(Equal(hub))
+	})
+
+	It("keeps the tls and override sections and the pull secrets of v1beta1", func() {
+		hub := &v1beta1.RabbitmqCluster{
+			Spec: v1beta1.RabbitmqClusterSpec{
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}, {Name: "mirror"}},
+				TLS: v1beta1.TLSSpec{
+					SecretName:   "tls-secret",
+					CaSecretName: "ca-secret",
+				},
+				Override: v1beta1.RabbitmqClusterOverrideSpec{
+					Service: &v1beta1.Service{
+						EmbeddedLabelsAnnotations: &v1beta1.EmbeddedLabelsAnnotations{
+							Labels: map[string]string{"team": "messaging"},
+						},
+					},
+				},
+			},
+		}
+
+		cluster := &RabbitmqCluster{}
+		Expect(cluster.ConvertFrom(hub)).To(Succeed())
+		Expect(cluster.Spec.ImagePullSecrets).To(Equal(hub.Spec.ImagePullSecrets))
+		Expect(cluster.Spec.TLS).To(Equal(hub.Spec.TLS))
+		Expect(cluster.Spec.Override).To(Equal(hub.Spec.Override))
+	})
+
+	It("does not lose fields in a round trip", func() {
+		fuzzer := fuzz.New().NilChance(0.2).NumElements(1, 2)
+		for i := 0; i < 100; i++ {
+			hub := &v1beta1.RabbitmqCluster{}
+			fuzzer.Fuzz(&hub.Spec)
+			fuzzer.Fuzz(&hub.Status)
+
+			cluster := &RabbitmqCluster{}
+			Expect(cluster.ConvertFrom(hub)).To(Succeed())
+			converted := &v1beta1.RabbitmqCluster{}
+			Expect(cluster.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		}
+	})
+
+	It("does not lose fields in a round trip from v1", func() {
+		fuzzer := fuzz.New().NilChance(0.2).NumElements(1, 2)
+		for i := 0; i < 100; i++ {
+			cluster := &RabbitmqCluster{}
+			fuzzer.Fuzz(&cluster.Spec)
+			fuzzer.Fuzz(&cluster.Status)
+
+			hub := &v1beta1.RabbitmqCluster{}
+			Expect(cluster.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.Rabbitmq.AdditionalPlugins).To(Equal(cluster.Spec.Plugins.Additional))
+			converted := &RabbitmqCluster{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted).To(Equal(cluster))
+		}
+	})
+})
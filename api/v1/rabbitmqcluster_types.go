@@ -0,0 +1,293 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+
+package v1
+
+import (
+	"github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.rabbitmqVersion",priority=1
+// +kubebuilder:printcolumn:name="AllReplicasReady",type="string",JSONPath=".status.conditions[?(@.type == 'AllReplicasReady')].status"
+// +kubebuilder:printcolumn:name="ReconcileSuccess",type="string",JSONPath=".status.conditions[?(@.type == 'ReconcileSuccess')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:shortName={"rmq"},categories=all;rabbitmq
+// RabbitmqCluster is the Schema for the RabbitmqCluster API. Each instance of this object
+// corresponds to a single RabbitMQ cluster.
+type RabbitmqCluster struct {
+	// Embedded metadata identifying a Kind and API Version of an object.
+	// For more info, see: https://pkg.go.dev/k8s.io/apimachinery/pkg/apis/meta/v1#TypeMeta
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the desired state of the RabbitmqCluster Custom Resource.
+	Spec RabbitmqClusterSpec `json:"spec,omitempty"`
+	// Status presents the observed state of RabbitmqCluster
+	Status v1beta1.RabbitmqClusterStatus `json:"status,omitempty"`
+}
+
+// Spec is the desired state of the RabbitmqCluster Custom Resource.
+// It is the v1beta1 spec, except that plugins are configured in spec.plugins instead of spec.rabbitmq.additionalPlugins.
+type RabbitmqClusterSpec struct {
+	// Replicas is the number of nodes in the RabbitMQ cluster. Each node is deployed as a Replica in a StatefulSet. Only 1, 3, 5 replicas clusters are tested.
+	// This value should be an odd number to ensure the resultant cluster can establish exactly one quorum of nodes
+	// in the event of a fragmenting network partition.
+	// Reducing the number of replicas is rejected, since it loses quorum queue members and data, unless
+	// the annotation rabbitmq.com/unsafeScaleDown is set to "true".
+	// +optional
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=1
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Image is the name of the RabbitMQ docker image to use for RabbitMQ nodes in the RabbitmqCluster.
+	// Must be provided together with ImagePullSecrets in order to use an image in a private registry.
+	// Changing the image to a lower RabbitMQ version, or to a version skipping a minor version, is refused
+	// unless the annotation rabbitmq.com/unsafeVersionChange is set to "true".
+	Image string `json:"image,omitempty"`
+	// ImagePullPolicy of the RabbitMQ image, used by the rabbitmq and setup-container containers.
+	// If not set, it is IfNotPresent for images referenced by digest or by a tag other than latest, and Always otherwise.
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// List of Secret resource containing access credentials to the registry for the RabbitMQ image. Required if the docker registry is private.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ServiceAccountName is the name of an existing ServiceAccount used by the RabbitMQ Pods.
+	// If set, the operator does not create the ServiceAccount, Role and RoleBinding for the cluster.
+	// The ServiceAccount must be allowed to get endpoints and create events in the namespace of the cluster
+	// for the Kubernetes peer discovery to work.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// The desired state of the Kubernetes Service to create for the cluster.
+	// +kubebuilder:default:={type: "ClusterIP"}
+	Service v1beta1.RabbitmqClusterServiceSpec `json:"service,omitempty"`
+	// Configuration of the headless Service used for peer discovery and inter-node communication.
+	// The Service is named <cluster-name>-nodes.
+	// +optional
+	HeadlessService v1beta1.RabbitmqClusterHeadlessServiceSpec `json:"headlessService,omitempty"`
+	// ManagementService creates a separate Service exposing only the management UI and HTTP API.
+	// It allows the management UI to be exposed differently from the messaging protocols.
+	// The Service is named <cluster-name>-management.
+	ManagementService *v1beta1.RabbitmqClusterManagementServiceSpec `json:"managementService,omitempty"`
+	// PodServices creates one Service per RabbitMQ Pod, so that external clients can connect to each RabbitMQ node directly,
+	// for example stream clients connecting to the advertised host of a stream leader.
+	// The Services are named like the Pods, <cluster-name>-server-<ordinal>.
+	PodServices *v1beta1.RabbitmqClusterPodServicesSpec `json:"podServices,omitempty"`
+	// ManagementIngress creates an Ingress routing to the management UI and HTTP API.
+	// The Ingress is named <cluster-name>-management and routes to the management Service if enabled,
+	// and to the client Service otherwise.
+	ManagementIngress *v1beta1.RabbitmqClusterManagementIngressSpec `json:"managementIngress,omitempty"`
+	// Route creates OpenShift Routes for the cluster. It is ignored when the OpenShift Route API is not available.
+	// The management Route is named <cluster-name>-management and routes to the management Service if enabled,
+	// and to the client Service otherwise.
+	Route *v1beta1.RabbitmqClusterRouteSpec `json:"route,omitempty"`
+	// Gateway attaches Gateway API routes for AMQP and AMQPS to an existing Gateway.
+	// It is ignored when the Gateway API TCPRoute and TLSRoute resources are not available.
+	Gateway *v1beta1.RabbitmqClusterGatewaySpec `json:"gateway,omitempty"`
+	// Monitoring creates a ServiceMonitor or PodMonitor of the Prometheus Operator scraping the Prometheus metrics
+	// of the RabbitMQ nodes. It is ignored when the Prometheus Operator CRDs are not available.
+	Monitoring *v1beta1.RabbitmqClusterMonitoringSpec `json:"monitoring,omitempty"`
+	// The desired persistent storage configuration for each Pod in the cluster.
+	// +kubebuilder:default:={storage: "10Gi"}
+	Persistence v1beta1.RabbitmqClusterPersistenceSpec `json:"persistence,omitempty"`
+	// The desired compute resource requirements of Pods in the cluster.
+	// If not set, the operator sets the default resources of its configuration file,
+	// or limits of 2000m CPU and 2Gi memory and requests of 1000m CPU and 2Gi memory.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Affinity scheduling rules to be applied on created Pods.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations is the list of Toleration resources attached to each Pod in the RabbitmqCluster.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Configuration options for RabbitMQ Pods created in the cluster.
+	Rabbitmq RabbitmqClusterConfigurationSpec `json:"rabbitmq,omitempty"`
+	// Plugins to enable on the RabbitmqCluster.
+	// +optional
+	Plugins RabbitmqClusterPluginsSpec `json:"plugins,omitempty"`
+	// AdditionalConfigMaps are ConfigMaps in the namespace of the RabbitmqCluster with rabbitmq.conf fragments.
+	// Each key is mounted as /etc/rabbitmq/conf.d/50-<configmap-name>-<key> and loaded after the operator defaults,
+	// but before spec.rabbitmq.additionalConfig.
+	// Changes to the ConfigMaps are only applied once the RabbitMQ Pods are restarted.
+	// +optional
+	AdditionalConfigMaps []v1beta1.ConfigFragmentSource `json:"additionalConfigMaps,omitempty"`
+	// AdditionalSecrets are Secrets in the namespace of the RabbitmqCluster with rabbitmq.conf fragments.
+	// Each key is mounted as /etc/rabbitmq/conf.d/60-<secret-name>-<key> and loaded after the additional ConfigMaps,
+	// but before spec.rabbitmq.additionalConfig.
+	// Changes to the Secrets are only applied once the RabbitMQ Pods are restarted.
+	// +optional
+	AdditionalSecrets []v1beta1.ConfigFragmentSource `json:"additionalSecrets,omitempty"`
+	// TLS-related configuration for the RabbitMQ cluster.
+	TLS v1beta1.TLSSpec `json:"tls,omitempty"`
+	// Provides the ability to override the generated manifest of several child resources.
+	Override v1beta1.RabbitmqClusterOverrideSpec `json:"override,omitempty"`
+	// If unset, or set to false, the cluster will run `rabbitmq-queues rebalance all` whenever the cluster is updated.
+	// Set to true to prevent the operator rebalancing queue leaders after a cluster update or after new replicas joined the cluster.
+	// Has no effect if the cluster only consists of one node.
+	// For more information, see https://www.rabbitmq.com/rabbitmq-queues.8.html#rebalance
+	SkipPostDeploySteps bool `json:"skipPostDeploySteps,omitempty"`
+	// TerminationGracePeriodSeconds is the timeout that each rabbitmqcluster pod will have to terminate gracefully.
+	// It defaults to 604800 seconds ( a week long) to ensure that the container preStop lifecycle hook can finish running.
+	// For more information, see: https://github.com/rabbitmq/cluster-operator/blob/main/docs/design/20200520-graceful-pod-termination.md
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=604800
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// DelayStartSeconds is the time the init container (`setup-container`) will sleep before terminating.
+	// This effectively delays the time between starting the Pod and starting the `rabbitmq` container.
+	// RabbitMQ relies on up-to-date DNS entries early during peer discovery.
+	// The purpose of this artificial delay is to ensure that DNS entries are up-to-date when booting RabbitMQ.
+	// For more information, see https://github.com/kubernetes/kubernetes/issues/92559
+	// If your Kubernetes DNS backend is configured with a low DNS cache value or publishes not ready addresses
+	// promptly, you can decrase this value or set it to 0.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=30
+	DelayStartSeconds *int32 `json:"delayStartSeconds,omitempty"`
+	// Secret backend configuration for the RabbitmqCluster.
+	// Enables to fetch default user credentials and certificates from K8s external secret stores.
+	SecretBackend v1beta1.SecretBackend `json:"secretBackend,omitempty"`
+	// RemoteCluster deploys the RabbitmqCluster into a different Kubernetes cluster.
+	// If not set, the RabbitmqCluster is deployed into the cluster the operator runs in.
+	RemoteCluster *v1beta1.RemoteClusterSpec `json:"remoteCluster,omitempty"`
+	// RolloutAnalysis runs Prometheus queries after every rollout of the StatefulSet, such as upgrades
+	// and restarts on configuration changes, and pauses reconciliation or rolls back the image if any query fails.
+	RolloutAnalysis *v1beta1.RolloutAnalysisSpec `json:"rolloutAnalysis,omitempty"`
+	// UpdateStrategy controls how Pods are updated when the Pod template changes, e.g. on upgrades.
+	// RollingUpdate, the default, lets the StatefulSet controller replace Pods once the previous Pod is ready.
+	// HealthGated makes the operator replace one Pod at a time, only once the node is not quorum critical
+	// and no alarms are raised.
+	// +optional
+	// +kubebuilder:validation:Enum=RollingUpdate;HealthGated
+	UpdateStrategy v1beta1.UpdateStrategyType `json:"updateStrategy,omitempty"`
+}
+
+// RabbitMQ-related configuration.
+type RabbitmqClusterConfigurationSpec struct {
+	// Modify to add to the rabbitmq.conf file in addition to default configurations set by the operator.
+	// The configuration is loaded after all other configuration files written by the operator, including
+	// spec.additionalConfigMaps and spec.additionalSecrets, so that its settings take precedence.
+	// Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart and will cause rabbitmq downtime.
+	// For more information on this config, see https://www.rabbitmq.com/configure.html#config-file
+	// +kubebuilder:validation:MaxLength:=100000
+	AdditionalConfig string `json:"additionalConfig,omitempty"`
+	// Specify any rabbitmq advanced.config configurations to apply to the cluster.
+	// Use it for settings which can only be expressed as Erlang terms, such as LDAP DN lookup templates or message interceptors.
+	// Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart and will cause rabbitmq downtime.
+	// For more information on advanced config, see https://www.rabbitmq.com/configure.html#advanced-config-file
+	// +kubebuilder:validation:MaxLength:=100000
+	AdvancedConfig string `json:"advancedConfig,omitempty"`
+	// Modify to add to the rabbitmq-env.conf file. Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart and will cause rabbitmq downtime.
+	// Environment variables of the rabbitmq container, including those set through spec.override, take precedence over rabbitmq-env.conf.
+	// For more information on env config, see https://www.rabbitmq.com/man/rabbitmq-env.conf.5.html
+	// +kubebuilder:validation:MaxLength:=100000
+	EnvConfig string `json:"envConfig,omitempty"`
+	// Erlang Inet configuration to apply to the Erlang VM running rabbit.
+	// See also: https://www.erlang.org/doc/apps/erts/inet_cfg.html
+	// +kubebuilder:validation:MaxLength:=2000
+	ErlangInetConfig string `json:"erlangInetConfig,omitempty"`
+	// The queue type of queues declared without an x-queue-type argument in virtual hosts without a default queue type.
+	// Defaults to the RabbitMQ default, which is classic.
+	// For more information, see https://www.rabbitmq.com/docs/vhosts#default-queue-type
+	// +kubebuilder:validation:Enum:=quorum;classic;stream
+	DefaultQueueType string `json:"defaultQueueType,omitempty"`
+	// The strategy to place the leader of new queues and streams. With client-local, the leader is placed on the node
+	// the declaring client is connected to. With balanced, leaders are spread across the nodes of the cluster.
+	// Defaults to balanced, so that leaders do not pile up on the node clients connect to first.
+	// For more information, see https://www.rabbitmq.com/docs/clustering#replica-placement
+	// +kubebuilder:validation:Enum:=client-local;balanced
+	// +kubebuilder:default:=balanced
+	// +optional
+	QueueLeaderLocator string `json:"queueLeaderLocator,omitempty"`
+	// Tags of the default user. Defaults to administrator.
+	// Use monitoring or management to restrict the default user when an administrator identity is provisioned separately.
+	// The tags are applied when RabbitMQ creates the default user on the first boot of the cluster.
+	// For more information, see https://www.rabbitmq.com/docs/access-control#user-tags
+	// +kubebuilder:validation:MaxItems:=4
+	// +optional
+	DefaultUserTags []v1beta1.UserTag `json:"defaultUserTags,omitempty"`
+	// A ConfigMap in the namespace of the RabbitmqCluster with rabbitmq.conf settings maintained by the user.
+	// The settings are copied into the server configuration, where they are loaded after the operator defaults,
+	// spec.additionalConfigMaps and spec.additionalSecrets, but before spec.rabbitmq.additionalConfig.
+	// Changes to the ConfigMap trigger a StatefulSet rolling restart. Changes are picked up immediately if the ConfigMap
+	// is labelled with app.kubernetes.io/part-of=rabbitmq, and otherwise on the next reconcile of the RabbitmqCluster.
+	// +optional
+	ConfigFrom *v1beta1.ConfigFromSource `json:"configFrom,omitempty"`
+	// A ConfigMap or Secret in the namespace of the RabbitmqCluster with a definitions file, e.g. exported from the management UI.
+	// The definitions are imported when a node boots, which provisions virtual hosts, users, permissions, policies, queues,
+	// exchanges and bindings declaratively, including on nodes which replace a lost node.
+	// Changes to the definitions are only imported once the RabbitMQ Pods are restarted.
+	// For more information, see https://www.rabbitmq.com/docs/definitions#import-on-boot
+	// +optional
+	Definitions *v1beta1.DefinitionsSource `json:"definitions,omitempty"`
+	// Authentication and authorization backends of RabbitMQ.
+	// +optional
+	Auth v1beta1.RabbitmqClusterAuthSpec `json:"auth,omitempty"`
+	// Enables and configures the MQTT plugin. The MQTT ports are added to the client Service.
+	// +optional
+	MQTT *v1beta1.MQTTSpec `json:"mqtt,omitempty"`
+	// Enables and configures the STOMP plugin. The STOMP ports are added to the client Service.
+	// +optional
+	STOMP *v1beta1.STOMPSpec `json:"stomp,omitempty"`
+	// Logging of the RabbitMQ nodes to the console, i.e. to the logs of the rabbitmq container.
+	// Defaults to JSON logs, so that the logs can be collected by log aggregators without parsing.
+	// Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart.
+	// +optional
+	Logging v1beta1.RabbitmqClusterLoggingSpec `json:"logging,omitempty"`
+	// Settings of the Prometheus endpoint of the rabbitmq_prometheus plugin.
+	// Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart.
+	// +optional
+	Metrics v1beta1.RabbitmqClusterMetricsSpec `json:"metrics,omitempty"`
+	// Settings of the Kubernetes peer discovery, which RabbitMQ nodes use to find each other and form the cluster.
+	PeerDiscovery v1beta1.RabbitmqClusterPeerDiscoverySpec `json:"peerDiscovery,omitempty"`
+	// Free disk space below which RabbitMQ raises the disk alarm and blocks publishers.
+	// The relative limit is a ratio of the memory available to RabbitMQ, e.g. 1.5.
+	// Defaults to an absolute limit of 2GB.
+	// For more information, see https://www.rabbitmq.com/docs/disk-alarms
+	DiskFreeLimit *v1beta1.ResourceAlarmThreshold `json:"diskFreeLimit,omitempty"`
+	// Memory use above which RabbitMQ raises the memory alarm and blocks publishers.
+	// The relative watermark is a ratio of the memory available to RabbitMQ between 0 and 1, e.g. 0.6.
+	// When the memory of the rabbitmq container is limited, the memory available to RabbitMQ is the limit minus a headroom.
+	// Defaults to the RabbitMQ default.
+	// For more information, see https://www.rabbitmq.com/docs/memory
+	MemoryHighWatermark *v1beta1.ResourceAlarmThreshold `json:"memoryHighWatermark,omitempty"`
+	// Users created by the operator in addition to the default user, so that applications do not share the default user.
+	// The users are created once all RabbitMQ nodes are ready, and are deleted when they are removed from the list.
+	// Changes to the referenced Secrets are picked up immediately if the Secrets are labelled with
+	// app.kubernetes.io/part-of=rabbitmq, and otherwise on the next reconcile of the RabbitmqCluster.
+	// +optional
+	// +listType=map
+	// +listMapKey=secretName
+	// +kubebuilder:validation:MaxItems:=100
+	AdditionalUsers []v1beta1.RabbitmqUser `json:"additionalUsers,omitempty"`
+}
+
+// Plugins to enable on the RabbitmqCluster.
+type RabbitmqClusterPluginsSpec struct {
+	// List of plugins to enable in addition to essential plugins: rabbitmq_management, rabbitmq_prometheus, and rabbitmq_peer_discovery_k8s.
+	// The operator writes the enabled_plugins file from this list, so plugins enabled by editing the plugins ConfigMap are disabled again.
+	// The validating webhook rejects plugins which are not shipped with the RabbitMQ version of the image,
+	// unless they are listed in the comma separated annotation rabbitmq.com/communityPlugins.
+	// +kubebuilder:validation:MaxItems:=100
+	Additional []v1beta1.Plugin `json:"additional,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RabbitmqClusterList contains a list of RabbitmqClusters.
+type RabbitmqClusterList struct {
+	// Embedded metadata identifying a Kind and API Version of an object.
+	// For more info, see: https://pkg.go.dev/k8s.io/apimachinery/pkg/apis/meta/v1#TypeMeta
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// Array of RabbitmqCluster resources.
+	Items []RabbitmqCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RabbitmqCluster{}, &RabbitmqClusterList{})
+}
@@ -0,0 +1,275 @@
+//go:build !ignore_autogenerated
+
+/*
+RabbitMQ Cluster Operator
+
+Copyright 2020 VMware, Inc. All Rights Reserved.
+
+This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+
+This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqCluster) DeepCopyInto(out *RabbitmqCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqCluster.
+func (in *RabbitmqCluster) DeepCopy() *RabbitmqCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RabbitmqCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterConfigurationSpec) DeepCopyInto(out *RabbitmqClusterConfigurationSpec) {
+	*out = *in
+	if in.DefaultUserTags != nil {
+		in, out := &in.DefaultUserTags, &out.DefaultUserTags
+		*out = make([]v1beta1.UserTag, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigFrom != nil {
+		in, out := &in.ConfigFrom, &out.ConfigFrom
+		*out = new(v1beta1.ConfigFromSource)
+		**out = **in
+	}
+	if in.Definitions != nil {
+		in, out := &in.Definitions, &out.Definitions
+		*out = new(v1beta1.DefinitionsSource)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Auth.DeepCopyInto(&out.Auth)
+	if in.MQTT != nil {
+		in, out := &in.MQTT, &out.MQTT
+		*out = new(v1beta1.MQTTSpec)
+		**out = **in
+	}
+	if in.STOMP != nil {
+		in, out := &in.STOMP, &out.STOMP
+		*out = new(v1beta1.STOMPSpec)
+		**out = **in
+	}
+	in.Logging.DeepCopyInto(&out.Logging)
+	out.Metrics = in.Metrics
+	in.PeerDiscovery.DeepCopyInto(&out.PeerDiscovery)
+	if in.DiskFreeLimit != nil {
+		in, out := &in.DiskFreeLimit, &out.DiskFreeLimit
+		*out = new(v1beta1.ResourceAlarmThreshold)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemoryHighWatermark != nil {
+		in, out := &in.MemoryHighWatermark, &out.MemoryHighWatermark
+		*out = new(v1beta1.ResourceAlarmThreshold)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalUsers != nil {
+		in, out := &in.AdditionalUsers, &out.AdditionalUsers
+		*out = make([]v1beta1.RabbitmqUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterConfigurationSpec.
+func (in *RabbitmqClusterConfigurationSpec) DeepCopy() *RabbitmqClusterConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterList) DeepCopyInto(out *RabbitmqClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RabbitmqCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterList.
+func (in *RabbitmqClusterList) DeepCopy() *RabbitmqClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RabbitmqClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterPluginsSpec) DeepCopyInto(out *RabbitmqClusterPluginsSpec) {
+	*out = *in
+	if in.Additional != nil {
+		in, out := &in.Additional, &out.Additional
+		*out = make([]v1beta1.Plugin, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterPluginsSpec.
+func (in *RabbitmqClusterPluginsSpec) DeepCopy() *RabbitmqClusterPluginsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterPluginsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterSpec) DeepCopyInto(out *RabbitmqClusterSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	in.HeadlessService.DeepCopyInto(&out.HeadlessService)
+	if in.ManagementService != nil {
+		in, out := &in.ManagementService, &out.ManagementService
+		*out = new(v1beta1.RabbitmqClusterManagementServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodServices != nil {
+		in, out := &in.PodServices, &out.PodServices
+		*out = new(v1beta1.RabbitmqClusterPodServicesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagementIngress != nil {
+		in, out := &in.ManagementIngress, &out.ManagementIngress
+		*out = new(v1beta1.RabbitmqClusterManagementIngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(v1beta1.RabbitmqClusterRouteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(v1beta1.RabbitmqClusterGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(v1beta1.RabbitmqClusterMonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Persistence.DeepCopyInto(&out.Persistence)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Rabbitmq.DeepCopyInto(&out.Rabbitmq)
+	in.Plugins.DeepCopyInto(&out.Plugins)
+	if in.AdditionalConfigMaps != nil {
+		in, out := &in.AdditionalConfigMaps, &out.AdditionalConfigMaps
+		*out = make([]v1beta1.ConfigFragmentSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalSecrets != nil {
+		in, out := &in.AdditionalSecrets, &out.AdditionalSecrets
+		*out = make([]v1beta1.ConfigFragmentSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.TLS.DeepCopyInto(&out.TLS)
+	in.Override.DeepCopyInto(&out.Override)
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DelayStartSeconds != nil {
+		in, out := &in.DelayStartSeconds, &out.DelayStartSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	in.SecretBackend.DeepCopyInto(&out.SecretBackend)
+	if in.RemoteCluster != nil {
+		in, out := &in.RemoteCluster, &out.RemoteCluster
+		*out = new(v1beta1.RemoteClusterSpec)
+		**out = **in
+	}
+	if in.RolloutAnalysis != nil {
+		in, out := &in.RolloutAnalysis, &out.RolloutAnalysis
+		*out = new(v1beta1.RolloutAnalysisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterSpec.
+func (in *RabbitmqClusterSpec) DeepCopy() *RabbitmqClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,13 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+
+package v1beta1
+
+// Hub marks v1beta1 as the version which other versions of RabbitmqCluster are converted to and from.
+// It is the storage version.
+func (*RabbitmqCluster) Hub() {}
@@ -0,0 +1,105 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+)
+
+// ErrNotDefaulted is returned by CheckDefaults if fields defaulted by the CRD or by the defaulting webhook are not set.
+var ErrNotDefaulted = errors.New("RabbitmqCluster is not defaulted")
+
+// +kubebuilder:object:generate=false
+
+// OperatorDefaults are the defaults of the operator configuration, which are set on RabbitmqClusters by the
+// defaulting webhook, or by the reconciler if the webhook is not deployed.
+type OperatorDefaults struct {
+	// RabbitmqImage is set as spec.image if it is not set, or always if ControlRabbitmqImage is true.
+	RabbitmqImage string
+	// UserUpdaterImage is the default user updater image of RabbitmqClusters using Vault.
+	UserUpdaterImage string
+	// ImagePullSecrets are set if spec.imagePullSecrets is not set.
+	ImagePullSecrets []corev1.LocalObjectReference
+	// StorageClassName is set as spec.persistence.storageClassName on new RabbitmqClusters, since the storage class
+	// of volume claim templates cannot change. It is not set on RabbitmqClusters deployed into a remote cluster,
+	// where the storage classes of the operator's cluster might not exist.
+	StorageClassName string
+	// Resources are set if spec.resources is not set.
+	Resources *corev1.ResourceRequirements
+	// ControlRabbitmqImage makes the operator control the images of all RabbitmqClusters (experimental).
+	ControlRabbitmqImage bool
+}
+
+// SetDefaults sets the defaults of the CRD schema which the builders of child resources rely on.
+// The API server sets them when a RabbitmqCluster is created, so this only matters for RabbitmqClusters
+// which are not read from the API server, e.g. in tests.
+func (cluster *RabbitmqCluster) SetDefaults() {
+	if cluster.Spec.Replicas == nil {
+		cluster.Spec.Replicas = ptr.To(int32(1))
+	}
+	if cluster.Spec.Persistence.Storage == nil {
+		cluster.Spec.Persistence.Storage = ptr.To(k8sresource.MustParse("10Gi"))
+	}
+	if cluster.Spec.Service.Type == "" {
+		cluster.Spec.Service.Type = corev1.ServiceTypeClusterIP
+	}
+	if cluster.Spec.TerminationGracePeriodSeconds == nil {
+		cluster.Spec.TerminationGracePeriodSeconds = ptr.To(int64(604800))
+	}
+	if cluster.Spec.DelayStartSeconds == nil {
+		cluster.Spec.DelayStartSeconds = ptr.To(int32(30))
+	}
+}
+
+// SetOperatorDefaults sets the defaults of the operator configuration. The storage class is only set if
+// setStorageClass is true, i.e. if the StatefulSet does not exist yet.
+func (cluster *RabbitmqCluster) SetOperatorDefaults(defaults OperatorDefaults, setStorageClass bool) {
+	if cluster.Spec.Image == "" || defaults.ControlRabbitmqImage {
+		cluster.Spec.Image = defaults.RabbitmqImage
+	}
+	if cluster.Spec.ImagePullSecrets == nil && len(defaults.ImagePullSecrets) > 0 {
+		cluster.Spec.ImagePullSecrets = append([]corev1.LocalObjectReference(nil), defaults.ImagePullSecrets...)
+	}
+	if cluster.Spec.Resources == nil && defaults.Resources != nil {
+		cluster.Spec.Resources = defaults.Resources.DeepCopy()
+	}
+	if setStorageClass && cluster.Spec.Persistence.StorageClassName == nil && defaults.StorageClassName != "" && !cluster.RemoteClusterEnabled() {
+		cluster.Spec.Persistence.StorageClassName = ptr.To(defaults.StorageClassName)
+	}
+	if cluster.UsesDefaultUserUpdaterImage(defaults.ControlRabbitmqImage) {
+		cluster.Spec.SecretBackend.Vault.DefaultUserUpdaterImage = ptr.To(defaults.UserUpdaterImage)
+	}
+}
+
+// CheckDefaults returns an error wrapping ErrNotDefaulted if fields which the builders of child resources rely on are not set.
+func (cluster *RabbitmqCluster) CheckDefaults() error {
+	var missing []string
+	if cluster.Spec.Replicas == nil {
+		missing = append(missing, "spec.replicas")
+	}
+	if cluster.Spec.Image == "" {
+		missing = append(missing, "spec.image")
+	}
+	if cluster.Spec.Persistence.Storage == nil {
+		missing = append(missing, "spec.persistence.storage")
+	}
+	if cluster.Spec.Service.Type == "" {
+		missing = append(missing, "spec.service.type")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s not set", ErrNotDefaulted, strings.Join(missing, ", "))
+	}
+	return nil
+}
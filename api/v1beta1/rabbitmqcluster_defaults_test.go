@@ -0,0 +1,89 @@
+package v1beta1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("Defaults", func() {
+	var (
+		cluster  *RabbitmqCluster
+		defaults OperatorDefaults
+	)
+
+	BeforeEach(func() {
+		cluster = &RabbitmqCluster{}
+		defaults = OperatorDefaults{
+			RabbitmqImage:    "rabbitmq:4.0.3-management",
+			UserUpdaterImage: "default-user-credential-updater",
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+			StorageClassName: "fast",
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("2Gi")},
+			},
+		}
+	})
+
+	It("sets the defaults of the CRD schema", func() {
+		Expect(cluster.CheckDefaults()).To(MatchError(ErrNotDefaulted))
+		cluster.SetDefaults()
+		Expect(cluster.Spec.Replicas).To(Equal(ptr.To(int32(1))))
+		Expect(cluster.Spec.Persistence.Storage).To(Equal(ptr.To(k8sresource.MustParse("10Gi"))))
+		Expect(cluster.Spec.Service.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect(cluster.CheckDefaults()).To(MatchError(ContainSubstring("spec.image not set")))
+	})
+
+	It("keeps the values which are set", func() {
+		cluster.Spec.Replicas = ptr.To(int32(3))
+		cluster.Spec.Image = "rabbitmq:3.13"
+		cluster.Spec.ImagePullSecrets = []corev1.LocalObjectReference{}
+		cluster.SetDefaults()
+		cluster.SetOperatorDefaults(defaults, true)
+		Expect(cluster.Spec.Replicas).To(Equal(ptr.To(int32(3))))
+		Expect(cluster.Spec.Image).To(Equal("rabbitmq:3.13"))
+		Expect(cluster.Spec.ImagePullSecrets).To(BeEmpty())
+	})
+
+	It("sets the defaults of the operator configuration", func() {
+		cluster.SetOperatorDefaults(defaults, false)
+		Expect(cluster.Spec.Image).To(Equal("rabbitmq:4.0.3-management"))
+		Expect(cluster.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "registry"}))
+		Expect(cluster.Spec.Resources.Limits.Memory().String()).To(Equal("2Gi"))
+		Expect(cluster.Spec.Persistence.StorageClassName).To(BeNil())
+
+		cluster.SetOperatorDefaults(defaults, true)
+		Expect(cluster.Spec.Persistence.StorageClassName).To(Equal(ptr.To("fast")))
+	})
+
+	It("overwrites the image if the operator controls it", func() {
+		cluster.Spec.Image = "rabbitmq:3.13"
+		defaults.ControlRabbitmqImage = true
+		cluster.SetOperatorDefaults(defaults, false)
+		Expect(cluster.Spec.Image).To(Equal("rabbitmq:4.0.3-management"))
+	})
+
+	Context("webhook", func() {
+		It("only sets the storage class on creation", func() {
+			defaulter := &RabbitmqClusterDefaulter{Defaults: defaults}
+			update := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Update},
+			})
+			Expect(defaulter.Default(update, cluster)).To(Succeed())
+			Expect(cluster.CheckDefaults()).To(Succeed())
+			Expect(cluster.Spec.Persistence.StorageClassName).To(BeNil())
+
+			create := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+			})
+			Expect(defaulter.Default(create, cluster)).To(Succeed())
+			Expect(cluster.Spec.Persistence.StorageClassName).To(Equal(ptr.To("fast")))
+		})
+	})
+})
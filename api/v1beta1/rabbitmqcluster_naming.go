@@ -0,0 +1,96 @@
+package v1beta1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// childResourceNameTemplate renders the names of child resources. Names are <name>-<suffix> if it is nil.
+var childResourceNameTemplate *template.Template
+
+// childResourceNameData is the data of the child resource name template.
+type childResourceNameData struct {
+	// Name of the RabbitmqCluster.
+	Name string
+	// Suffix of the child resource, e.g. "server" for the StatefulSet. It is empty for the client Service.
+	Suffix string
+	// Hash is the first 8 hex characters of the SHA-256 of the name and suffix, to keep truncated names unique.
+	Hash string
+}
+
+// childResourceNameSuffixes are the suffixes of all child resources the operator creates. Child resources of different
+// kinds share some suffixes, e.g. "server" for the StatefulSet, ServiceAccount and RoleBinding.
+var childResourceNameSuffixes = []string{
+	"",
+	"amqp",
+	"amqps",
+	"default-user",
+	"dry-run",
+	"erlang-cookie",
+	"management",
+	"nodes",
+	"peer-discovery",
+	"plugins-conf",
+	"server",
+	"server-conf",
+}
+
+var childResourceNameFuncs = template.FuncMap{
+	// trunc returns the first n characters of s
+	"trunc": func(n int, s string) string {
+		if len(s) > n {
+			return s[:n]
+		}
+		return s
+	},
+}
+
+// SetChildResourceNameTemplate configures the names of child resources with a text/template, e.g.
+// `{{ trunc 30 .Name }}-{{ .Hash }}-{{ .Suffix }}`. The template gets the Name of the RabbitmqCluster, the Suffix of
+// the child resource and a Hash of both, and the function trunc which returns the first n characters of a string.
+// Leading and trailing dashes are removed from the rendered names, which must be distinct for all suffixes of the
+// child resources. An empty template restores the default names, <name>-<suffix>.
+// The template is set once when the operator starts. Changing it renames the child resources of existing RabbitmqClusters,
+// which replaces their StatefulSets and leaves their PersistentVolumeClaims behind.
+func SetChildResourceNameTemplate(text string) error {
+	if text == "" {
+		childResourceNameTemplate = nil
+		return nil
+	}
+	tmpl, err := template.New("childResourceName").Funcs(childResourceNameFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid child resource name template: %w", err)
+	}
+	// suffixes by rendered name, since child resources of the same kind must not share a name
+	rendered := map[string]string{}
+	for _, suffix := range childResourceNameSuffixes {
+		name, err := renderChildResourceName(tmpl, "rabbitmq-cluster", suffix)
+		if err != nil {
+			return fmt.Errorf("invalid child resource name template: %w", err)
+		}
+		// Service names must be DNS labels, which is the strictest naming rule of the child resources
+		if errs := validation.IsDNS1035Label(name); len(errs) > 0 {
+			return fmt.Errorf("invalid child resource name template: rendered name %q is invalid: %s", name, strings.Join(errs, "; "))
+		}
+		if other, ok := rendered[name]; ok {
+			return fmt.Errorf("invalid child resource name template: suffixes %q and %q render the same name %q", other, suffix, name)
+		}
+		rendered[name] = suffix
+	}
+	childResourceNameTemplate = tmpl
+	return nil
+}
+
+func renderChildResourceName(tmpl *template.Template, name, suffix string) (string, error) {
+	hash := sha256.Sum256([]byte(name + "/" + suffix))
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, childResourceNameData{Name: name, Suffix: suffix, Hash: hex.EncodeToString(hash[:])[:8]}); err != nil {
+		return "", err
+	}
+	return strings.Trim(rendered.String(), "-"), nil
+}
@@ -1,8 +1,12 @@
 package v1beta1
 
 import (
+	"time"
+
 	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -19,9 +23,151 @@ type RabbitmqClusterStatus struct {
 	// duck type. See: https://github.com/servicebinding/spec#provisioned-service
 	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
 
+	// Number of RabbitMQ Pods which are ready, as reported by the StatefulSet.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
 	// observedGeneration is the most recent successful generation observed for this RabbitmqCluster. It corresponds to the
 	// RabbitmqCluster's generation, which is updated on mutation by the API Server.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// History of the image, replica and configuration changes applied by the Operator, ordered from oldest to newest.
+	// Only the most recent changes are kept.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	History []RabbitmqClusterChange `json:"history,omitempty"`
+
+	// Whether TLS is enabled, the listeners serving TLS, and the certificate of spec.tls.secretName loaded by the RabbitMQ nodes.
+	// +optional
+	TLS *RabbitmqClusterTLSStatus `json:"tls,omitempty"`
+
+	// Users of spec.rabbitmq.additionalUsers created by the operator.
+	// +optional
+	AdditionalUsers []RabbitmqClusterUserStatus `json:"additionalUsers,omitempty"`
+
+	// RabbitMQ nodes of the cluster with the readiness of their Pods and their cluster membership,
+	// updated periodically by the operator.
+	// +optional
+	Nodes []RabbitmqClusterNodeStatus `json:"nodes,omitempty"`
+
+	// Version of RabbitMQ running on the nodes, read once all nodes run the current StatefulSet revision.
+	// +optional
+	RabbitmqVersion string `json:"rabbitmqVersion,omitempty"`
+
+	// Version of Erlang/OTP running on the nodes, read once all nodes run the current StatefulSet revision.
+	// +optional
+	ErlangVersion string `json:"erlangVersion,omitempty"`
+
+	// StatefulSet revision which rabbitmqVersion and erlangVersion were read from.
+	// +optional
+	VersionsRevision string `json:"versionsRevision,omitempty"`
+
+	// Progress of the most recent rollout which changed the RabbitMQ version.
+	// +optional
+	Upgrade *RabbitmqClusterUpgradeStatus `json:"upgrade,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=InProgress;Completed
+type UpgradeState string
+
+const (
+	// Some nodes still run the previous RabbitMQ version.
+	UpgradeInProgress UpgradeState = "InProgress"
+	// All nodes run the new RabbitMQ version.
+	UpgradeCompleted UpgradeState = "Completed"
+)
+
+// A rollout of the StatefulSet which changes the RabbitMQ version.
+type RabbitmqClusterUpgradeStatus struct {
+	// Version of RabbitMQ before the upgrade.
+	FromVersion string `json:"fromVersion"`
+	// Version of RabbitMQ after the upgrade, as reported by the first updated node.
+	ToVersion string `json:"toVersion"`
+	// Number of Pods running the StatefulSet revision of the upgrade.
+	UpdatedReplicas int32        `json:"updatedReplicas"`
+	State           UpgradeState `json:"state"`
+	// StatefulSet revision of the upgrade.
+	Revision string `json:"revision"`
+	// Whether the operator enabled all feature flags after all nodes ran the new RabbitMQ version,
+	// so that they do not block the next upgrade.
+	// +optional
+	FeatureFlagsEnabled bool `json:"featureFlagsEnabled,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Running;Stopped;NotJoined;Unknown
+type NodeMembership string
+
+const (
+	// The node is a member of the cluster and runs.
+	NodeRunning NodeMembership = "Running"
+	// The node is a member of the cluster, but does not run.
+	NodeStopped NodeMembership = "Stopped"
+	// The node did not join the cluster yet, or was removed from it.
+	NodeNotJoined NodeMembership = "NotJoined"
+	// The cluster status could not be read from any node.
+	NodeMembershipUnknown NodeMembership = "Unknown"
+)
+
+// A RabbitMQ node of the cluster.
+type RabbitmqClusterNodeStatus struct {
+	// Name of the Pod running the node. Empty for cluster members without a Pod.
+	// +optional
+	Pod string `json:"pod,omitempty"`
+	// Name of the RabbitMQ node. Empty if the cluster status could not be read.
+	// +optional
+	Node string `json:"node,omitempty"`
+	// Whether the Pod is ready.
+	Ready bool `json:"ready"`
+	// Cluster membership of the node.
+	Membership NodeMembership `json:"membership"`
+}
+
+// A user of spec.rabbitmq.additionalUsers created by the operator.
+type RabbitmqClusterUserStatus struct {
+	Username string `json:"username"`
+	// Hash of the password, tags and permissions of the user, which identifies changes to them.
+	Hash string `json:"hash"`
+}
+
+// TLS listeners of the RabbitMQ nodes and the certificate they loaded.
+type RabbitmqClusterTLSStatus struct {
+	// Whether TLS is enabled.
+	Enabled bool `json:"enabled"`
+	// Names of the Service ports which serve TLS, such as amqps and management-tls.
+	// +optional
+	Listeners []string `json:"listeners,omitempty"`
+	// Hash of the certificate, which identifies the certificate when the Secret changes.
+	// Unset until the certificate of spec.tls.secretName was loaded.
+	// +optional
+	CertificateHash string `json:"certificateHash,omitempty"`
+	// Expiry of the certificate. Unset if the certificate could not be parsed.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// MaxHistoryEntries is the number of changes kept in status.history.
+const MaxHistoryEntries = 20
+
+// +kubebuilder:validation:Enum=Image;Replicas;Configuration
+type RabbitmqClusterChangeType string
+
+const (
+	ImageChange         RabbitmqClusterChangeType = "Image"
+	ReplicasChange      RabbitmqClusterChangeType = "Replicas"
+	ConfigurationChange RabbitmqClusterChangeType = "Configuration"
+)
+
+// A change applied by the Operator to the resources of the RabbitmqCluster.
+type RabbitmqClusterChange struct {
+	// Time at which the change was applied.
+	Time metav1.Time `json:"time"`
+	// Type of the change.
+	Type RabbitmqClusterChangeType `json:"type"`
+	// Value before the change. Configuration changes are recorded as a hash of the server configuration.
+	// +optional
+	Previous string `json:"previous,omitempty"`
+	// Value after the change. Configuration changes are recorded as a hash of the server configuration.
+	Current string `json:"current"`
 }
 
 // Contains references to resources created with the RabbitmqCluster resource.
@@ -51,11 +197,15 @@ type RabbitmqClusterServiceReference struct {
 	Namespace string `json:"namespace"`
 }
 
-func (clusterStatus *RabbitmqClusterStatus) SetConditions(resources []runtime.Object) {
+// SetConditions sets the status conditions derived from the child resources. configurationWarnings are reported
+// in the NoWarnings condition, see RabbitmqCluster.ConfigurationWarnings.
+func (clusterStatus *RabbitmqClusterStatus) SetConditions(resources []runtime.Object, configurationWarnings []string) {
 	var oldAllPodsReadyCondition *status.RabbitmqClusterCondition
 	var oldClusterAvailableCondition *status.RabbitmqClusterCondition
 	var oldNoWarningsCondition *status.RabbitmqClusterCondition
 	var oldReconcileCondition *status.RabbitmqClusterCondition
+	// conditions such as TLSCertificateLoaded or NoAlarms are only set by the operator once they apply, and are kept as they are
+	var otherConditions []status.RabbitmqClusterCondition
 
 	for _, condition := range clusterStatus.Conditions {
 		switch condition.Type {
@@ -67,12 +217,14 @@ func (clusterStatus *RabbitmqClusterStatus) SetConditions(resources []runtime.Ob
 			oldNoWarningsCondition = condition.DeepCopy()
 		case status.ReconcileSuccess:
 			oldReconcileCondition = condition.DeepCopy()
+		default:
+			otherConditions = append(otherConditions, *condition.DeepCopy())
 		}
 	}
 
 	allReplicasReadyCond := status.AllReplicasReadyCondition(resources, oldAllPodsReadyCondition)
 	clusterAvailableCond := status.ClusterAvailableCondition(resources, oldClusterAvailableCondition)
-	noWarningsCond := status.NoWarningsCondition(resources, oldNoWarningsCondition)
+	noWarningsCond := status.NoWarningsCondition(resources, configurationWarnings, oldNoWarningsCondition)
 
 	var reconciledCondition status.RabbitmqClusterCondition
 	if oldReconcileCondition != nil {
@@ -81,11 +233,21 @@ func (clusterStatus *RabbitmqClusterStatus) SetConditions(resources []runtime.Ob
 		reconciledCondition = status.ReconcileSuccessCondition(corev1.ConditionUnknown, "Initialising", "")
 	}
 
-	clusterStatus.Conditions = []status.RabbitmqClusterCondition{
+	clusterStatus.Conditions = append([]status.RabbitmqClusterCondition{
 		allReplicasReadyCond,
 		clusterAvailableCond,
 		noWarningsCond,
 		reconciledCondition,
+	}, otherConditions...)
+}
+
+// SetReadyReplicas sets status.readyReplicas to the ready replicas of the StatefulSet in resources.
+func (clusterStatus *RabbitmqClusterStatus) SetReadyReplicas(resources []runtime.Object) {
+	clusterStatus.ReadyReplicas = 0
+	for _, resource := range resources {
+		if sts, ok := resource.(*appsv1.StatefulSet); ok && sts != nil {
+			clusterStatus.ReadyReplicas = sts.Status.ReadyReplicas
+		}
 	}
 }
 
@@ -99,3 +261,93 @@ func (clusterStatus *RabbitmqClusterStatus) SetCondition(condType status.Rabbitm
 		}
 	}
 }
+
+// SetTLSCertificate records the TLS certificate loaded by the nodes, and sets the TLSCertificateLoaded condition
+// to its expiry. notAfter is nil if the certificate could not be parsed.
+func (clusterStatus *RabbitmqClusterStatus) SetTLSCertificate(hash string, notAfter *metav1.Time) {
+	if clusterStatus.TLS == nil {
+		clusterStatus.TLS = &RabbitmqClusterTLSStatus{Enabled: true}
+	}
+	clusterStatus.TLS.CertificateHash = hash
+	clusterStatus.TLS.NotAfter = notAfter
+
+	condStatus, reason, message := corev1.ConditionUnknown, "InvalidCertificate", "tls.crt is not a PEM encoded certificate"
+	if notAfter != nil {
+		condStatus, reason, message = corev1.ConditionTrue, "CertificateLoaded", "certificate is valid until "+notAfter.UTC().Format(time.RFC3339)
+	}
+	clusterStatus.upsertCondition(status.TLSCertificateLoadedCondition(condStatus, reason, message))
+}
+
+// SetTLSListeners sets status.tls.enabled and status.tls.listeners. The loaded certificate is forgotten when TLS is disabled.
+func (clusterStatus *RabbitmqClusterStatus) SetTLSListeners(listeners []string) {
+	if len(listeners) == 0 {
+		clusterStatus.TLS = &RabbitmqClusterTLSStatus{Enabled: false}
+		return
+	}
+	if clusterStatus.TLS == nil || !clusterStatus.TLS.Enabled {
+		clusterStatus.TLS = &RabbitmqClusterTLSStatus{Enabled: true}
+	}
+	clusterStatus.TLS.Listeners = listeners
+}
+
+// SetPaused sets the Paused condition to true, adding it if reconciliation was not paused before.
+func (clusterStatus *RabbitmqClusterStatus) SetPaused(message string) {
+	clusterStatus.upsertCondition(status.PausedCondition(corev1.ConditionTrue, "ReconciliationPaused", message))
+}
+
+// SetResumed sets the Paused condition to false, and returns whether reconciliation was paused before.
+func (clusterStatus *RabbitmqClusterStatus) SetResumed() bool {
+	for i := range clusterStatus.Conditions {
+		if clusterStatus.Conditions[i].Type == status.Paused && clusterStatus.Conditions[i].Status == corev1.ConditionTrue {
+			clusterStatus.Conditions[i].UpdateState(corev1.ConditionFalse)
+			clusterStatus.Conditions[i].UpdateReason("ReconciliationResumed", "reconciliation is no longer paused")
+			return true
+		}
+	}
+	return false
+}
+
+// SetDefaultUserPasswordRotated sets the DefaultUserPasswordRotated condition, adding it if the password was not rotated before.
+func (clusterStatus *RabbitmqClusterStatus) SetDefaultUserPasswordRotated(condStatus corev1.ConditionStatus, reason, message string) {
+	clusterStatus.upsertCondition(status.DefaultUserPasswordRotatedCondition(condStatus, reason, message))
+}
+
+// SetClusterHealth sets the NoAlarms and NoPartitions conditions from the descriptions of the alarms and network partitions
+// reported by the RabbitMQ nodes. An empty description means there are none.
+func (clusterStatus *RabbitmqClusterStatus) SetClusterHealth(alarms, partitions string) {
+	if alarms == "" {
+		clusterStatus.upsertCondition(status.NoAlarmsCondition(corev1.ConditionTrue, "NoAlarms", "no resource alarms raised"))
+	} else {
+		clusterStatus.upsertCondition(status.NoAlarmsCondition(corev1.ConditionFalse, "AlarmsRaised", alarms))
+	}
+	if partitions == "" {
+		clusterStatus.upsertCondition(status.NoPartitionsCondition(corev1.ConditionTrue, "NoPartitions", "all nodes can reach each other"))
+	} else {
+		clusterStatus.upsertCondition(status.NoPartitionsCondition(corev1.ConditionFalse, "PartitionsDetected", partitions))
+	}
+}
+
+// upsertCondition updates the condition of the same type, or adds the condition if there is none.
+func (clusterStatus *RabbitmqClusterStatus) upsertCondition(condition status.RabbitmqClusterCondition) {
+	for i := range clusterStatus.Conditions {
+		if clusterStatus.Conditions[i].Type == condition.Type {
+			clusterStatus.Conditions[i].UpdateState(condition.Status)
+			clusterStatus.Conditions[i].UpdateReason(condition.Reason, condition.Message)
+			return
+		}
+	}
+	clusterStatus.Conditions = append(clusterStatus.Conditions, condition)
+}
+
+// RecordChange appends a change to status.history, removing the oldest changes beyond MaxHistoryEntries.
+func (clusterStatus *RabbitmqClusterStatus) RecordChange(changeType RabbitmqClusterChangeType, previous, current string) {
+	clusterStatus.History = append(clusterStatus.History, RabbitmqClusterChange{
+		Time:     metav1.Now(),
+		Type:     changeType,
+		Previous: previous,
+		Current:  current,
+	})
+	if len(clusterStatus.History) > MaxHistoryEntries {
+		clusterStatus.History = clusterStatus.History[len(clusterStatus.History)-MaxHistoryEntries:]
+	}
+}
@@ -1,6 +1,9 @@
 package v1beta1
 
 import (
+	"strconv"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rabbitmq/cluster-operator/v2/internal/status"
@@ -52,7 +55,7 @@ var _ = Describe("RabbitmqClusterStatus", func() {
 			},
 		}
 
-		rabbitmqClusterStatus.SetConditions([]runtime.Object{sts, endPoints})
+		rabbitmqClusterStatus.SetConditions([]runtime.Object{sts, endPoints}, nil)
 
 		Expect(rabbitmqClusterStatus.Conditions).To(HaveLen(4))
 		Expect(rabbitmqClusterStatus.Conditions[0].Type).To(Equal(status.AllReplicasReady))
@@ -61,6 +64,19 @@ var _ = Describe("RabbitmqClusterStatus", func() {
 		Expect(rabbitmqClusterStatus.Conditions[3].Type).To(Equal(status.ReconcileSuccess))
 	})
 
+	It("sets the ready replicas of the StatefulSet", func() {
+		rabbitmqClusterStatus := RabbitmqClusterStatus{ReadyReplicas: 2}
+		sts := &appsv1.StatefulSet{}
+		sts.Status.ReadyReplicas = 3
+
+		rabbitmqClusterStatus.SetReadyReplicas([]runtime.Object{sts, &corev1.Endpoints{}})
+		Expect(rabbitmqClusterStatus.ReadyReplicas).To(Equal(int32(3)))
+
+		var missing *appsv1.StatefulSet
+		rabbitmqClusterStatus.SetReadyReplicas([]runtime.Object{missing, &corev1.Endpoints{}})
+		Expect(rabbitmqClusterStatus.ReadyReplicas).To(BeZero())
+	})
+
 	It("updates an arbitrary condition", func() {
 		someCondition := status.RabbitmqClusterCondition{}
 		someCondition.Type = "a-type"
@@ -83,4 +99,109 @@ var _ = Describe("RabbitmqClusterStatus", func() {
 		Expect(updatedCondition.LastTransitionTime).NotTo(Equal(notExpectedTime))
 		Expect(updatedCondition.LastTransitionTime.Before(&notExpectedTime)).To(BeFalse())
 	})
+
+	It("records changes and keeps only the most recent ones", func() {
+		rmqStatus := RabbitmqClusterStatus{}
+		rmqStatus.RecordChange(ImageChange, "rabbitmq:3.13", "rabbitmq:4.0")
+		Expect(rmqStatus.History).To(HaveLen(1))
+		Expect(rmqStatus.History[0].Type).To(Equal(ImageChange))
+		Expect(rmqStatus.History[0].Previous).To(Equal("rabbitmq:3.13"))
+		Expect(rmqStatus.History[0].Current).To(Equal("rabbitmq:4.0"))
+		Expect(rmqStatus.History[0].Time.IsZero()).To(BeFalse())
+
+		for i := 1; i <= MaxHistoryEntries; i++ {
+			rmqStatus.RecordChange(ReplicasChange, strconv.Itoa(i), strconv.Itoa(i+1))
+		}
+		Expect(rmqStatus.History).To(HaveLen(MaxHistoryEntries))
+		Expect(rmqStatus.History[0].Type).To(Equal(ReplicasChange))
+		Expect(rmqStatus.History[0].Previous).To(Equal("1"))
+		Expect(rmqStatus.History[MaxHistoryEntries-1].Current).To(Equal(strconv.Itoa(MaxHistoryEntries + 1)))
+	})
+
+	It("records the loaded TLS certificate and keeps its condition", func() {
+		rmqStatus := RabbitmqClusterStatus{}
+		rmqStatus.SetConditions([]runtime.Object{}, nil)
+		notAfter := metav1.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		rmqStatus.SetTLSCertificate("0123456789abcdef", &notAfter)
+
+		Expect(rmqStatus.TLS.CertificateHash).To(Equal("0123456789abcdef"))
+		Expect(rmqStatus.TLS.NotAfter).To(Equal(&notAfter))
+		Expect(rmqStatus.Conditions).To(HaveLen(5))
+		Expect(rmqStatus.Conditions[4].Type).To(Equal(status.TLSCertificateLoaded))
+		Expect(rmqStatus.Conditions[4].Status).To(Equal(corev1.ConditionTrue))
+		Expect(rmqStatus.Conditions[4].Message).To(Equal("certificate is valid until 2030-01-02T03:04:05Z"))
+
+		rmqStatus.SetConditions([]runtime.Object{}, nil)
+		Expect(rmqStatus.Conditions).To(HaveLen(5))
+
+		rmqStatus.SetTLSCertificate("fedcba9876543210", nil)
+		Expect(rmqStatus.Conditions).To(HaveLen(5))
+		Expect(rmqStatus.Conditions[4].Status).To(Equal(corev1.ConditionUnknown))
+		Expect(rmqStatus.Conditions[4].Reason).To(Equal("InvalidCertificate"))
+	})
+
+	It("records the TLS listeners and forgets the certificate when TLS is disabled", func() {
+		rmqStatus := RabbitmqClusterStatus{}
+		rmqStatus.SetTLSListeners([]string{"amqps", "management-tls"})
+		rmqStatus.SetTLSCertificate("0123456789abcdef", nil)
+		rmqStatus.SetTLSListeners([]string{"amqps", "management-tls", "prometheus-tls"})
+		Expect(rmqStatus.TLS).To(Equal(&RabbitmqClusterTLSStatus{
+			Enabled:         true,
+			Listeners:       []string{"amqps", "management-tls", "prometheus-tls"},
+			CertificateHash: "0123456789abcdef",
+		}))
+
+		rmqStatus.SetTLSListeners(nil)
+		Expect(rmqStatus.TLS).To(Equal(&RabbitmqClusterTLSStatus{Enabled: false}))
+	})
+
+	It("adds the Paused condition and sets it to false when resumed", func() {
+		rmqStatus := RabbitmqClusterStatus{}
+		rmqStatus.SetConditions([]runtime.Object{}, nil)
+		Expect(rmqStatus.SetResumed()).To(BeFalse())
+		Expect(rmqStatus.Conditions).To(HaveLen(4))
+
+		rmqStatus.SetPaused("label 'rabbitmq.com/pauseReconciliation' is set to true")
+		Expect(rmqStatus.Conditions).To(HaveLen(5))
+		Expect(rmqStatus.Conditions[4].Type).To(Equal(status.Paused))
+		Expect(rmqStatus.Conditions[4].Status).To(Equal(corev1.ConditionTrue))
+
+		Expect(rmqStatus.SetResumed()).To(BeTrue())
+		Expect(rmqStatus.Conditions[4].Status).To(Equal(corev1.ConditionFalse))
+		Expect(rmqStatus.Conditions[4].Reason).To(Equal("ReconciliationResumed"))
+		Expect(rmqStatus.SetResumed()).To(BeFalse())
+	})
+
+	It("adds and keeps the DefaultUserPasswordRotated condition", func() {
+		rmqStatus := RabbitmqClusterStatus{}
+		rmqStatus.SetConditions([]runtime.Object{}, nil)
+		rmqStatus.SetDefaultUserPasswordRotated(corev1.ConditionUnknown, "RotationInProgress", "changing the password")
+		Expect(rmqStatus.Conditions).To(HaveLen(5))
+		Expect(rmqStatus.Conditions[4].Type).To(Equal(status.DefaultUserPasswordRotated))
+
+		rmqStatus.SetConditions([]runtime.Object{}, nil)
+		rmqStatus.SetDefaultUserPasswordRotated(corev1.ConditionTrue, "PasswordRotated", "")
+		Expect(rmqStatus.Conditions).To(HaveLen(5))
+		Expect(rmqStatus.Conditions[4].Status).To(Equal(corev1.ConditionTrue))
+		Expect(rmqStatus.Conditions[4].Reason).To(Equal("PasswordRotated"))
+	})
+	It("adds and keeps the NoAlarms and NoPartitions conditions", func() {
+		rmqStatus := RabbitmqClusterStatus{}
+		rmqStatus.SetConditions([]runtime.Object{}, nil)
+		rmqStatus.SetClusterHealth("", "")
+		Expect(rmqStatus.Conditions).To(HaveLen(6))
+		Expect(rmqStatus.Conditions[4].Type).To(Equal(status.NoAlarms))
+		Expect(rmqStatus.Conditions[4].Status).To(Equal(corev1.ConditionTrue))
+		Expect(rmqStatus.Conditions[5].Type).To(Equal(status.NoPartitions))
+		Expect(rmqStatus.Conditions[5].Status).To(Equal(corev1.ConditionTrue))
+
+		rmqStatus.SetConditions([]runtime.Object{}, nil)
+		rmqStatus.SetClusterHealth("memory alarm on rabbit@server-0", "rabbit@server-0 cannot reach rabbit@server-1")
+		Expect(rmqStatus.Conditions).To(HaveLen(6))
+		Expect(rmqStatus.Conditions[4].Status).To(Equal(corev1.ConditionFalse))
+		Expect(rmqStatus.Conditions[4].Reason).To(Equal("AlarmsRaised"))
+		Expect(rmqStatus.Conditions[4].Message).To(Equal("memory alarm on rabbit@server-0"))
+		Expect(rmqStatus.Conditions[5].Status).To(Equal(corev1.ConditionFalse))
+		Expect(rmqStatus.Conditions[5].Reason).To(Equal("PartitionsDetected"))
+	})
 })
@@ -24,10 +24,14 @@ const DisableDefaultTopologySpreadAnnotation = "rabbitmq.com/disable-default-top
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.rabbitmqVersion",priority=1
 // +kubebuilder:printcolumn:name="AllReplicasReady",type="string",JSONPath=".status.conditions[?(@.type == 'AllReplicasReady')].status"
 // +kubebuilder:printcolumn:name="ReconcileSuccess",type="string",JSONPath=".status.conditions[?(@.type == 'ReconcileSuccess')].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:shortName={"rmq"},categories=all;rabbitmq
+// +kubebuilder:storageversion
 // RabbitmqCluster is the Schema for the RabbitmqCluster API. Each instance of this object
 // corresponds to a single RabbitMQ cluster.
 type RabbitmqCluster struct {
@@ -47,23 +51,64 @@ type RabbitmqClusterSpec struct {
 	// Replicas is the number of nodes in the RabbitMQ cluster. Each node is deployed as a Replica in a StatefulSet. Only 1, 3, 5 replicas clusters are tested.
 	// This value should be an odd number to ensure the resultant cluster can establish exactly one quorum of nodes
 	// in the event of a fragmenting network partition.
+	// Reducing the number of replicas is rejected, since it loses quorum queue members and data, unless
+	// the annotation rabbitmq.com/unsafeScaleDown is set to "true".
 	// +optional
 	// +kubebuilder:validation:Minimum:=0
 	// +kubebuilder:default:=1
 	Replicas *int32 `json:"replicas,omitempty"`
 	// Image is the name of the RabbitMQ docker image to use for RabbitMQ nodes in the RabbitmqCluster.
 	// Must be provided together with ImagePullSecrets in order to use an image in a private registry.
+	// Changing the image to a lower RabbitMQ version, or to a version skipping a minor version, is refused
+	// unless the annotation rabbitmq.com/unsafeVersionChange is set to "true".
 	Image string `json:"image,omitempty"`
+	// ImagePullPolicy of the RabbitMQ image, used by the rabbitmq and setup-container containers.
+	// If not set, it is IfNotPresent for images referenced by digest or by a tag other than latest, and Always otherwise.
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 	// List of Secret resource containing access credentials to the registry for the RabbitMQ image. Required if the docker registry is private.
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ServiceAccountName is the name of an existing ServiceAccount used by the RabbitMQ Pods.
+	// If set, the operator does not create the ServiceAccount, Role and RoleBinding for the cluster.
+	// The ServiceAccount must be allowed to get endpoints and create events in the namespace of the cluster
+	// for the Kubernetes peer discovery to work.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 	// The desired state of the Kubernetes Service to create for the cluster.
 	// +kubebuilder:default:={type: "ClusterIP"}
 	Service RabbitmqClusterServiceSpec `json:"service,omitempty"`
+	// Configuration of the headless Service used for peer discovery and inter-node communication.
+	// The Service is named <cluster-name>-nodes.
+	// +optional
+	HeadlessService RabbitmqClusterHeadlessServiceSpec `json:"headlessService,omitempty"`
+	// ManagementService creates a separate Service exposing only the management UI and HTTP API.
+	// It allows the management UI to be exposed differently from the messaging protocols.
+	// The Service is named <cluster-name>-management.
+	ManagementService *RabbitmqClusterManagementServiceSpec `json:"managementService,omitempty"`
+	// PodServices creates one Service per RabbitMQ Pod, so that external clients can connect to each RabbitMQ node directly,
+	// for example stream clients connecting to the advertised host of a stream leader.
+	// The Services are named like the Pods, <cluster-name>-server-<ordinal>.
+	PodServices *RabbitmqClusterPodServicesSpec `json:"podServices,omitempty"`
+	// ManagementIngress creates an Ingress routing to the management UI and HTTP API.
+	// The Ingress is named <cluster-name>-management and routes to the management Service if enabled,
+	// and to the client Service otherwise.
+	ManagementIngress *RabbitmqClusterManagementIngressSpec `json:"managementIngress,omitempty"`
+	// Route creates OpenShift Routes for the cluster. It is ignored when the OpenShift Route API is not available.
+	// The management Route is named <cluster-name>-management and routes to the management Service if enabled,
+	// and to the client Service otherwise.
+	Route *RabbitmqClusterRouteSpec `json:"route,omitempty"`
+	// Gateway attaches Gateway API routes for AMQP and AMQPS to an existing Gateway.
+	// It is ignored when the Gateway API TCPRoute and TLSRoute resources are not available.
+	Gateway *RabbitmqClusterGatewaySpec `json:"gateway,omitempty"`
+	// Monitoring creates a ServiceMonitor or PodMonitor of the Prometheus Operator scraping the Prometheus metrics
+	// of the RabbitMQ nodes. It is ignored when the Prometheus Operator CRDs are not available.
+	Monitoring *RabbitmqClusterMonitoringSpec `json:"monitoring,omitempty"`
 	// The desired persistent storage configuration for each Pod in the cluster.
 	// +kubebuilder:default:={storage: "10Gi"}
 	Persistence RabbitmqClusterPersistenceSpec `json:"persistence,omitempty"`
 	// The desired compute resource requirements of Pods in the cluster.
-	// +kubebuilder:default:={limits: {cpu: "2000m", memory: "2Gi"}, requests: {cpu: "1000m", memory: "2Gi"}}
+	// If not set, the operator sets the default resources of its configuration file,
+	// or limits of 2000m CPU and 2Gi memory and requests of 1000m CPU and 2Gi memory.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 	// Affinity scheduling rules to be applied on created Pods.
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
@@ -71,12 +116,24 @@ type RabbitmqClusterSpec struct {
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 	// Configuration options for RabbitMQ Pods created in the cluster.
 	Rabbitmq RabbitmqClusterConfigurationSpec `json:"rabbitmq,omitempty"`
+	// AdditionalConfigMaps are ConfigMaps in the namespace of the RabbitmqCluster with rabbitmq.conf fragments.
+	// Each key is mounted as /etc/rabbitmq/conf.d/50-<configmap-name>-<key> and loaded after the operator defaults,
+	// but before spec.rabbitmq.additionalConfig.
+	// Changes to the ConfigMaps are only applied once the RabbitMQ Pods are restarted.
+	// +optional
+	AdditionalConfigMaps []ConfigFragmentSource `json:"additionalConfigMaps,omitempty"`
+	// AdditionalSecrets are Secrets in the namespace of the RabbitmqCluster with rabbitmq.conf fragments.
+	// Each key is mounted as /etc/rabbitmq/conf.d/60-<secret-name>-<key> and loaded after the additional ConfigMaps,
+	// but before spec.rabbitmq.additionalConfig.
+	// Changes to the Secrets are only applied once the RabbitMQ Pods are restarted.
+	// +optional
+	AdditionalSecrets []ConfigFragmentSource `json:"additionalSecrets,omitempty"`
 	// TLS-related configuration for the RabbitMQ cluster.
 	TLS TLSSpec `json:"tls,omitempty"`
 	// Provides the ability to override the generated manifest of several child resources.
 	Override RabbitmqClusterOverrideSpec `json:"override,omitempty"`
 	// If unset, or set to false, the cluster will run `rabbitmq-queues rebalance all` whenever the cluster is updated.
-	// Set to true to prevent the operator rebalancing queue leaders after a cluster update.
+	// Set to true to prevent the operator rebalancing queue leaders after a cluster update or after new replicas joined the cluster.
 	// Has no effect if the cluster only consists of one node.
 	// For more information, see https://www.rabbitmq.com/rabbitmq-queues.8.html#rebalance
 	SkipPostDeploySteps bool `json:"skipPostDeploySteps,omitempty"`
@@ -99,15 +156,103 @@ type RabbitmqClusterSpec struct {
 	// Secret backend configuration for the RabbitmqCluster.
 	// Enables to fetch default user credentials and certificates from K8s external secret stores.
 	SecretBackend SecretBackend `json:"secretBackend,omitempty"`
+	// RemoteCluster deploys the RabbitmqCluster into a different Kubernetes cluster.
+	// If not set, the RabbitmqCluster is deployed into the cluster the operator runs in.
+	RemoteCluster *RemoteClusterSpec `json:"remoteCluster,omitempty"`
+	// RolloutAnalysis runs Prometheus queries after every rollout of the StatefulSet, such as upgrades
+	// and restarts on configuration changes, and pauses reconciliation or rolls back the image if any query fails.
+	RolloutAnalysis *RolloutAnalysisSpec `json:"rolloutAnalysis,omitempty"`
+	// UpdateStrategy controls how Pods are updated when the Pod template changes, e.g. on upgrades.
+	// RollingUpdate, the default, lets the StatefulSet controller replace Pods once the previous Pod is ready.
+	// HealthGated makes the operator replace one Pod at a time, only once the node is not quorum critical
+	// and no alarms are raised.
+	// +optional
+	// +kubebuilder:validation:Enum=RollingUpdate;HealthGated
+	UpdateStrategy UpdateStrategyType `json:"updateStrategy,omitempty"`
+}
+
+type UpdateStrategyType string
+
+const (
+	RollingUpdateStrategy UpdateStrategyType = "RollingUpdate"
+	HealthGatedStrategy   UpdateStrategyType = "HealthGated"
+)
+
+// RolloutAnalysisSpec configures the analysis run after each rollout of the RabbitmqCluster.
+type RolloutAnalysisSpec struct {
+	// URL of the Prometheus server to query, e.g. http://prometheus.monitoring.svc:9090
+	// The URL must be one of the Prometheus URLs allowed in the operator configuration.
+	// +kubebuilder:validation:Pattern:="^https?://"
+	PrometheusURL string `json:"prometheusURL"`
+	// Queries which must all succeed for the rollout to pass the analysis.
+	// +kubebuilder:validation:MinItems:=1
+	Queries []RolloutAnalysisQuery `json:"queries"`
+	// Seconds to wait after the last Pod of a rollout became ready before the queries are run.
+	// It should be at least the range of the queries, e.g. 300 for rate(...[5m]), so that the queries only cover the new revision.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=300
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// Action taken when the analysis fails. Pause sets the label rabbitmq.com/pauseReconciliation on the RabbitmqCluster.
+	// Rollback reverts spec.image to the last image which passed the analysis, if the images only differ in the patch version
+	// of RabbitMQ. Otherwise, e.g. after a failed upgrade to a new minor version, reconciliation is paused, since RabbitMQ
+	// does not support downgrades to a previous minor version.
+	// +kubebuilder:validation:Enum=Pause;Rollback
+	// +kubebuilder:default:="Pause"
+	OnFailure RolloutAnalysisFailurePolicy `json:"onFailure,omitempty"`
+}
+
+type RolloutAnalysisFailurePolicy string
+
+const (
+	RolloutAnalysisPause    RolloutAnalysisFailurePolicy = "Pause"
+	RolloutAnalysisRollback RolloutAnalysisFailurePolicy = "Rollback"
+)
+
+// RolloutAnalysisQuery is a PromQL query with thresholds its result must satisfy.
+// The query must return a scalar or a non-empty instant vector; every sample of a vector must satisfy the thresholds.
+type RolloutAnalysisQuery struct {
+	// Name of the query, used in events.
+	Name string `json:"name"`
+	// PromQL expression, e.g. sum(rate(rabbitmq_global_messages_received_total{namespace="my-namespace"}[5m]))
+	Query string `json:"query"`
+	// Minimum value of the query result.
+	Min *k8sresource.Quantity `json:"min,omitempty"`
+	// Maximum value of the query result.
+	Max *k8sresource.Quantity `json:"max,omitempty"`
+}
+
+// RemoteClusterSpec configures access to the Kubernetes cluster the RabbitmqCluster is deployed into.
+// Remote clusters must be enabled on the operator with the environment variable REMOTE_CLUSTERS.
+// Child resources are created in the Namespace with the same name as the RabbitmqCluster Namespace; that Namespace must exist.
+// Child resources are applied with the same safeguards as in the operator's cluster: scale down and unsupported version changes
+// are refused, persistent volumes are expanded and configuration changes roll the StatefulSet. Post-deploy steps and feature flags,
+// which run commands in the RabbitMQ Pods, are not performed for RabbitmqClusters in remote clusters.
+type RemoteClusterSpec struct {
+	// Name of a Secret in the same Namespace as the RabbitmqCluster, containing the kubeconfig for the remote cluster.
+	// The Secret must store this as kubeconfig. Only inline credentials and certificates are supported:
+	// exec credential plugins, auth providers and file paths are rejected.
+	// This Secret can be created by running `kubectl create secret generic remote-cluster --from-file=kubeconfig=path/to/kubeconfig`
+	KubeconfigSecret corev1.LocalObjectReference `json:"kubeconfigSecret"`
 }
 
 // SecretBackend configures a single secret backend.
-// Today, only Vault exists as supported secret backend.
-// Future secret backends could be Secrets Store CSI Driver.
+// Supported secret backends are Vault and the Secrets Store CSI Driver.
 // If not configured, K8s Secrets will be used.
 type SecretBackend struct {
 	Vault          *VaultSpec                  `json:"vault,omitempty"`
+	CSI            *CSISpec                    `json:"csi,omitempty"`
 	ExternalSecret corev1.LocalObjectReference `json:"externalSecret,omitempty"`
+	// Name of a pre-created Secret in the same Namespace as the RabbitmqCluster, containing the credentials of the default user
+	// in the keys username and password. The operator copies them into the default-user Secret instead of generating random credentials.
+	// Ignored when vault.defaultUserPath or externalSecret is set.
+	// +optional
+	ExistingAdminSecret corev1.LocalObjectReference `json:"existingAdminSecret,omitempty"`
+	// Name of a pre-created Secret in the same Namespace as the RabbitmqCluster, containing the Erlang cookie in the key .erlang.cookie.
+	// The operator mounts this Secret instead of generating a cookie, so that the cookie can be shared with other clusters or tooling.
+	// Nodes read the cookie when they start: all nodes must be restarted at once after the cookie changed.
+	// +optional
+	ExistingErlangCookieSecret corev1.LocalObjectReference `json:"existingErlangCookieSecret,omitempty"`
 }
 
 // VaultSpec will add Vault annotations (see https://www.vaultproject.io/docs/platform/k8s/injector/annotations)
@@ -134,6 +279,28 @@ type VaultSpec struct {
 	TLS                     VaultTLSSpec `json:"tls,omitempty"`
 }
 
+// CSISpec mounts a volume of the Secrets Store CSI Driver (https://secrets-store-csi-driver.sigs.k8s.io) into RabbitMQ Pods.
+// It requires the driver and a provider for the external secret store to be installed in the K8s cluster.
+// The objects of the SecretProviderClass must be mounted with the file names documented below.
+type CSISpec struct {
+	// Name of the SecretProviderClass in the same Namespace as the RabbitmqCluster.
+	// +kubebuilder:validation:MinLength:=1
+	SecretProviderClass string `json:"secretProviderClass"`
+	// Name of the CSI driver.
+	// +kubebuilder:default:="secrets-store.csi.k8s.io"
+	// +optional
+	Driver string `json:"driver,omitempty"`
+	// Read the credentials of the default user from the file default_user.conf of the volume,
+	// which contains the lines "default_user = <username>" and "default_pass = <password>".
+	// The operator does not create the default-user Secret.
+	// +optional
+	DefaultUser bool `json:"defaultUser,omitempty"`
+	// Read the server certificate, private key and CA certificate from the files tls.crt, tls.key and ca.crt of the volume.
+	// This enables TLS like spec.tls.secretName and spec.tls.caSecretName, which must not be set.
+	// +optional
+	TLS bool `json:"tls,omitempty"`
+}
+
 type VaultTLSSpec struct {
 	// Path in Vault PKI engine.
 	// For example "pki/issue/hashicorp-com".
@@ -351,10 +518,14 @@ type PersistentVolumeClaim struct {
 }
 
 // TLSSpec allows for the configuration of TLS certificates to be used by RabbitMQ. Also allows for non-TLS traffic to be disabled.
+// +kubebuilder:validation:XValidation:rule="!has(self.failIfNoPeerCert) || !self.failIfNoPeerCert || (has(self.caSecretName) && (!has(self.verify) || self.verify == 'verify_peer'))",message="failIfNoPeerCert requires caSecretName and verify_peer"
+// With a secretName, RabbitMQ, the management plugin and the prometheus plugin listen for TLS connections on ports 5671, 15671 and 15691,
+// which are added to the StatefulSet and the client Service, and so do the MQTT, STOMP and stream plugins when they are enabled.
 type TLSSpec struct {
 	// Name of a Secret in the same Namespace as the RabbitmqCluster, containing the server's private key & public certificate for TLS.
 	// The Secret must store these as tls.key and tls.crt, respectively.
 	// This Secret can be created by running `kubectl create secret tls tls-secret --cert=path/to/tls.crt --key=path/to/tls.key`
+	// When the certificate in the Secret changes, running nodes load it without a restart.
 	SecretName string `json:"secretName,omitempty"`
 	// Name of a Secret in the same Namespace as the RabbitmqCluster, containing the Certificate Authority's public certificate for TLS.
 	// The Secret must store this as ca.crt.
@@ -364,6 +535,33 @@ type TLSSpec struct {
 	// When set to true, the RabbitmqCluster disables non-TLS listeners for RabbitMQ, management plugin and for any enabled plugins in the following list: stomp, mqtt, web_stomp, web_mqtt.
 	// Only TLS-enabled clients will be able to connect.
 	DisableNonTLSListeners bool `json:"disableNonTLSListeners,omitempty"`
+	// Whether RabbitMQ requests and verifies the certificate of clients against the CA certificate of caSecretName.
+	// Defaults to verify_peer when caSecretName is set.
+	// +kubebuilder:validation:Enum:=verify_peer;verify_none
+	// +optional
+	Verify string `json:"verify,omitempty"`
+	// When set to true, clients which do not present a certificate are rejected, so that clients can authenticate
+	// with their certificate, e.g. with the rabbitmq_auth_mechanism_ssl plugin. Requires caSecretName and verify_peer.
+	// Applies to the listeners of AMQP, MQTT, STOMP and streams, but not to the management UI.
+	// +optional
+	FailIfNoPeerCert bool `json:"failIfNoPeerCert,omitempty"`
+	// Encrypts the Erlang distribution between RabbitMQ nodes, and between nodes and the CLI tools, with TLS.
+	// Nodes verify the certificate of their peers. Independent of the TLS settings of client connections above.
+	// Nodes with and without inter-node TLS cannot communicate, so enabling or disabling it on an existing
+	// RabbitmqCluster makes the cluster unavailable until the StatefulSet rolling restart completes.
+	// For more information, see https://www.rabbitmq.com/docs/clustering-ssl
+	// +optional
+	Distribution *DistributionTLSSpec `json:"distribution,omitempty"`
+}
+
+// TLS settings of the Erlang distribution between RabbitMQ nodes.
+type DistributionTLSSpec struct {
+	// Name of a Secret in the same Namespace as the RabbitmqCluster, containing the nodes' private key, public certificate
+	// and the Certificate Authority's public certificate as tls.key, tls.crt and ca.crt, respectively.
+	// The certificate is used as both server and client certificate, and must be valid for the host names of all nodes,
+	// e.g. *.<name>-nodes.<namespace>.
+	// +kubebuilder:validation:MinLength:=1
+	SecretName string `json:"secretName"`
 }
 
 // kubebuilder validating tags 'Pattern' and 'MaxLength' must be specified on string type.
@@ -374,21 +572,33 @@ type TLSSpec struct {
 // +kubebuilder:validation:MaxLength=100
 type Plugin string
 
+// A tag of the default user.
+// +kubebuilder:validation:Enum=administrator;monitoring;management;policymaker
+type UserTag string
+
 // RabbitMQ-related configuration.
 type RabbitmqClusterConfigurationSpec struct {
 	// List of plugins to enable in addition to essential plugins: rabbitmq_management, rabbitmq_prometheus, and rabbitmq_peer_discovery_k8s.
+	// The operator writes the enabled_plugins file from this list, so plugins enabled by editing the plugins ConfigMap are disabled again.
+	// The validating webhook rejects plugins which are not shipped with the RabbitMQ version of the image,
+	// unless they are listed in the comma separated annotation rabbitmq.com/communityPlugins.
 	// +kubebuilder:validation:MaxItems:=100
 	AdditionalPlugins []Plugin `json:"additionalPlugins,omitempty"`
 	// Modify to add to the rabbitmq.conf file in addition to default configurations set by the operator.
+	// The configuration is loaded after all other configuration files written by the operator, including
+	// spec.additionalConfigMaps and spec.additionalSecrets, so that its settings take precedence.
 	// Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart and will cause rabbitmq downtime.
 	// For more information on this config, see https://www.rabbitmq.com/configure.html#config-file
 	// +kubebuilder:validation:MaxLength:=100000
 	AdditionalConfig string `json:"additionalConfig,omitempty"`
 	// Specify any rabbitmq advanced.config configurations to apply to the cluster.
+	// Use it for settings which can only be expressed as Erlang terms, such as LDAP DN lookup templates or message interceptors.
+	// Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart and will cause rabbitmq downtime.
 	// For more information on advanced config, see https://www.rabbitmq.com/configure.html#advanced-config-file
 	// +kubebuilder:validation:MaxLength:=100000
 	AdvancedConfig string `json:"advancedConfig,omitempty"`
 	// Modify to add to the rabbitmq-env.conf file. Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart and will cause rabbitmq downtime.
+	// Environment variables of the rabbitmq container, including those set through spec.override, take precedence over rabbitmq-env.conf.
 	// For more information on env config, see https://www.rabbitmq.com/man/rabbitmq-env.conf.5.html
 	// +kubebuilder:validation:MaxLength:=100000
 	EnvConfig string `json:"envConfig,omitempty"`
@@ -396,6 +606,334 @@ type RabbitmqClusterConfigurationSpec struct {
 	// See also: https://www.erlang.org/doc/apps/erts/inet_cfg.html
 	// +kubebuilder:validation:MaxLength:=2000
 	ErlangInetConfig string `json:"erlangInetConfig,omitempty"`
+	// The queue type of queues declared without an x-queue-type argument in virtual hosts without a default queue type.
+	// Defaults to the RabbitMQ default, which is classic.
+	// For more information, see https://www.rabbitmq.com/docs/vhosts#default-queue-type
+	// +kubebuilder:validation:Enum:=quorum;classic;stream
+	DefaultQueueType string `json:"defaultQueueType,omitempty"`
+	// The strategy to place the leader of new queues and streams. With client-local, the leader is placed on the node
+	// the declaring client is connected to. With balanced, leaders are spread across the nodes of the cluster.
+	// Defaults to balanced, so that leaders do not pile up on the node clients connect to first.
+	// For more information, see https://www.rabbitmq.com/docs/clustering#replica-placement
+	// +kubebuilder:validation:Enum:=client-local;balanced
+	// +kubebuilder:default:=balanced
+	// +optional
+	QueueLeaderLocator string `json:"queueLeaderLocator,omitempty"`
+	// Tags of the default user. Defaults to administrator.
+	// Use monitoring or management to restrict the default user when an administrator identity is provisioned separately.
+	// The tags are applied when RabbitMQ creates the default user on the first boot of the cluster.
+	// For more information, see https://www.rabbitmq.com/docs/access-control#user-tags
+	// +kubebuilder:validation:MaxItems:=4
+	// +optional
+	DefaultUserTags []UserTag `json:"defaultUserTags,omitempty"`
+	// A ConfigMap in the namespace of the RabbitmqCluster with rabbitmq.conf settings maintained by the user.
+	// The settings are copied into the server configuration, where they are loaded after the operator defaults,
+	// spec.additionalConfigMaps and spec.additionalSecrets, but before spec.rabbitmq.additionalConfig.
+	// Changes to the ConfigMap trigger a StatefulSet rolling restart.
+	// +optional
+	ConfigFrom *ConfigFromSource `json:"configFrom,omitempty"`
+	// A ConfigMap or Secret in the namespace of the RabbitmqCluster with a definitions file, e.g. exported from the management UI.
+	// The definitions are imported when a node boots, which provisions virtual hosts, users, permissions, policies, queues,
+	// exchanges and bindings declaratively, including on nodes which replace a lost node.
+	// Changes to the definitions are only imported once the RabbitMQ Pods are restarted.
+	// For more information, see https://www.rabbitmq.com/docs/definitions#import-on-boot
+	// +optional
+	Definitions *DefinitionsSource `json:"definitions,omitempty"`
+	// Authentication and authorization backends of RabbitMQ.
+	// +optional
+	Auth RabbitmqClusterAuthSpec `json:"auth,omitempty"`
+	// Enables and configures the MQTT plugin. The MQTT ports are added to the client Service.
+	// +optional
+	MQTT *MQTTSpec `json:"mqtt,omitempty"`
+	// Enables and configures the STOMP plugin. The STOMP ports are added to the client Service.
+	// +optional
+	STOMP *STOMPSpec `json:"stomp,omitempty"`
+	// Logging of the RabbitMQ nodes to the console, i.e. to the logs of the rabbitmq container.
+	// Defaults to JSON logs, so that the logs can be collected by log aggregators without parsing.
+	// Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart.
+	// +optional
+	Logging RabbitmqClusterLoggingSpec `json:"logging,omitempty"`
+	// Settings of the Prometheus endpoint of the rabbitmq_prometheus plugin.
+	// Modifying this property on an existing RabbitmqCluster will trigger a StatefulSet rolling restart.
+	// +optional
+	Metrics RabbitmqClusterMetricsSpec `json:"metrics,omitempty"`
+	// Settings of the Kubernetes peer discovery, which RabbitMQ nodes use to find each other and form the cluster.
+	PeerDiscovery RabbitmqClusterPeerDiscoverySpec `json:"peerDiscovery,omitempty"`
+	// Free disk space below which RabbitMQ raises the disk alarm and blocks publishers.
+	// The relative limit is a ratio of the memory available to RabbitMQ, e.g. 1.5.
+	// Defaults to an absolute limit of 2GB.
+	// For more information, see https://www.rabbitmq.com/docs/disk-alarms
+	DiskFreeLimit *ResourceAlarmThreshold `json:"diskFreeLimit,omitempty"`
+	// Memory use above which RabbitMQ raises the memory alarm and blocks publishers.
+	// The relative watermark is a ratio of the memory available to RabbitMQ between 0 and 1, e.g. 0.6.
+	// When the memory of the rabbitmq container is limited, the memory available to RabbitMQ is the limit minus a headroom.
+	// Defaults to the RabbitMQ default.
+	// For more information, see https://www.rabbitmq.com/docs/memory
+	MemoryHighWatermark *ResourceAlarmThreshold `json:"memoryHighWatermark,omitempty"`
+	// Users created by the operator in addition to the default user, so that applications do not share the default user.
+	// The users are created once all RabbitMQ nodes are ready, and are deleted when they are removed from the list.
+	// Changes to the referenced Secrets are picked up immediately.
+	// +optional
+	// +listType=map
+	// +listMapKey=secretName
+	// +kubebuilder:validation:MaxItems:=100
+	AdditionalUsers []RabbitmqUser `json:"additionalUsers,omitempty"`
+}
+
+// A RabbitMQ user created by the operator.
+type RabbitmqUser struct {
+	// Name of a Secret in the namespace of the RabbitmqCluster with the keys username and password.
+	// +kubebuilder:validation:MinLength:=1
+	SecretName string `json:"secretName"`
+	// Tags of the user, e.g. management, monitoring or administrator.
+	// For more information, see https://www.rabbitmq.com/docs/management#permissions
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+	// Permissions of the user in virtual hosts.
+	// +optional
+	// +listType=map
+	// +listMapKey=vhost
+	Permissions []RabbitmqUserPermissions `json:"permissions,omitempty"`
+}
+
+// Permissions of a user in a virtual host, as regular expressions matching resource names.
+// For more information, see https://www.rabbitmq.com/docs/access-control
+type RabbitmqUserPermissions struct {
+	// Virtual host. It must exist.
+	// +kubebuilder:default:="/"
+	VHost string `json:"vhost"`
+	// +kubebuilder:default:=".*"
+	// +optional
+	Configure string `json:"configure,omitempty"`
+	// +kubebuilder:default:=".*"
+	// +optional
+	Write string `json:"write,omitempty"`
+	// +kubebuilder:default:=".*"
+	// +optional
+	Read string `json:"read,omitempty"`
+}
+
+// A threshold of a RabbitMQ resource alarm. Exactly one of absolute and relative must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.absolute) != has(self.relative)",message="exactly one of absolute and relative must be set"
+type ResourceAlarmThreshold struct {
+	// Absolute threshold in bytes, e.g. 4Gi.
+	Absolute *k8sresource.Quantity `json:"absolute,omitempty"`
+	// Relative threshold as a ratio, e.g. 0.6.
+	Relative *k8sresource.Quantity `json:"relative,omitempty"`
+}
+
+// Settings of the MQTT plugin.
+// For more information, see https://www.rabbitmq.com/docs/mqtt
+type MQTTSpec struct {
+	// Virtual host of MQTT connections which do not select one in their username or through a port mapping.
+	// Defaults to the RabbitMQ default, which is /.
+	// +optional
+	VHost string `json:"vhost,omitempty"`
+	// Topic exchange MQTT messages are published to. Defaults to the RabbitMQ default, which is amq.topic.
+	// +optional
+	Exchange string `json:"exchange,omitempty"`
+	// Enables MQTT over WebSockets with the rabbitmq_web_mqtt plugin.
+	// +optional
+	WebSockets bool `json:"webSockets,omitempty"`
+}
+
+// Settings of the STOMP plugin.
+// For more information, see https://www.rabbitmq.com/docs/stomp
+type STOMPSpec struct {
+	// Virtual host of STOMP connections which do not select one in their CONNECT frame.
+	// Defaults to the RabbitMQ default, which is /.
+	// +optional
+	VHost string `json:"vhost,omitempty"`
+	// Enables STOMP over WebSockets with the rabbitmq_web_stomp plugin.
+	// +optional
+	WebSockets bool `json:"webSockets,omitempty"`
+}
+
+// Console logging of RabbitMQ.
+// For more information, see https://www.rabbitmq.com/docs/logging
+type RabbitmqClusterLoggingSpec struct {
+	// Level of messages logged to the console. Defaults to the RabbitMQ default, which is info.
+	// +optional
+	Level LogLevel `json:"level,omitempty"`
+	// Format of the console logs. Defaults to json.
+	// +kubebuilder:validation:Enum:=json;plaintext
+	// +kubebuilder:default:=json
+	// +optional
+	Format string `json:"format,omitempty"`
+	// Levels of log categories, e.g. connection or queue, which override the level of the console.
+	// +listType:=map
+	// +listMapKey:=name
+	// +kubebuilder:validation:MaxItems:=20
+	// +optional
+	Categories []LogCategory `json:"categories,omitempty"`
+}
+
+// Settings of the Prometheus endpoint.
+// For more information, see https://www.rabbitmq.com/docs/prometheus
+type RabbitmqClusterMetricsSpec struct {
+	// Return metrics per queue, connection and channel from the metrics path, instead of metrics aggregated per node.
+	// The size of a scrape grows with the number of objects, so per-object metrics should be avoided on clusters with many objects.
+	// Per-object metrics are always available from /metrics/per-object and /metrics/detailed.
+	// +optional
+	PerObject bool `json:"perObject,omitempty"`
+	// Path of the default metrics endpoint. Defaults to the RabbitMQ default, which is /metrics.
+	// +kubebuilder:validation:Pattern:="^/[A-Za-z0-9/_-]*$"
+	// +kubebuilder:validation:MaxLength:=100
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// A RabbitMQ log level.
+// +kubebuilder:validation:Enum:=debug;info;warning;error;critical;none
+type LogLevel string
+
+// The level of a RabbitMQ log category.
+type LogCategory struct {
+	// Name of the log category, e.g. connection, channel, queue, federation, shovel, upgrade or ra.
+	// +kubebuilder:validation:Pattern:="^[a-z_]+$"
+	// +kubebuilder:validation:MaxLength:=50
+	Name string `json:"name"`
+	// Level of messages of the category.
+	Level LogLevel `json:"level"`
+}
+
+// Authentication and authorization backends of RabbitMQ.
+type RabbitmqClusterAuthSpec struct {
+	// Authenticates and authorizes clients with JWT access tokens issued by an OAuth 2.0 authorization server, e.g. UAA or Keycloak.
+	// Enables the rabbitmq_auth_backend_oauth2 plugin. The internal backend stays enabled as a fallback,
+	// so that the default user and the operator keep working.
+	// For more information, see https://www.rabbitmq.com/docs/oauth2
+	// +optional
+	OAuth2 *OAuth2Spec `json:"oauth2,omitempty"`
+}
+
+// Settings of the OAuth 2.0 backend. At least one of issuerURL, jwksURL and signingKey must be set
+// for RabbitMQ to validate the signature of tokens.
+// +kubebuilder:validation:XValidation:rule="has(self.issuerURL) || has(self.jwksURL) || has(self.signingKey)",message="one of issuerURL, jwksURL and signingKey must be set"
+type OAuth2Spec struct {
+	// Identifier of RabbitMQ as an OAuth 2.0 resource server. Tokens must include it in their audience.
+	// +kubebuilder:validation:MinLength=1
+	ResourceServerID string `json:"resourceServerId"`
+	// URL of the authorization server, from which the signing keys are discovered through OpenID Connect discovery.
+	// +optional
+	IssuerURL string `json:"issuerURL,omitempty"`
+	// URL of the JSON Web Key Set with the signing keys of the authorization server.
+	// +optional
+	JWKSURL string `json:"jwksURL,omitempty"`
+	// Prefix of the scopes in tokens which RabbitMQ uses for authorization.
+	// Defaults to the resource server id followed by a dot.
+	// +optional
+	ScopePrefix *string `json:"scopePrefix,omitempty"`
+	// Claim of tokens with scopes in addition to the scope claim.
+	// +optional
+	AdditionalScopesKey string `json:"additionalScopesKey,omitempty"`
+	// A signing key of the authorization server in PEM format, used to validate the signature of tokens.
+	// +optional
+	SigningKey *OAuth2SigningKey `json:"signingKey,omitempty"`
+	// Login to the management UI through the authorization server.
+	// +optional
+	Management *OAuth2ManagementSpec `json:"management,omitempty"`
+}
+
+// A signing key of the OAuth 2.0 authorization server in a Secret.
+type OAuth2SigningKey struct {
+	// The Secret with the signing key.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+	// The key of the Secret with the signing key.
+	// +kubebuilder:default:=signing-key.pem
+	// +optional
+	Key string `json:"key,omitempty"`
+	// The key id, which tokens reference in their kid header. Tokens without a kid header are validated with this key.
+	// +kubebuilder:default:=default
+	// +kubebuilder:validation:Pattern:="^[-._a-zA-Z0-9]+$"
+	// +optional
+	KeyID string `json:"keyId,omitempty"`
+}
+
+// Settings of the login to the management UI through the OAuth 2.0 authorization server.
+type OAuth2ManagementSpec struct {
+	// Client id of the management UI at the authorization server.
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientId"`
+	// URL of the authorization server the management UI redirects to. Defaults to the issuer URL.
+	// +optional
+	ProviderURL string `json:"providerURL,omitempty"`
+	// Space-separated scopes the management UI requests.
+	// +optional
+	Scopes string `json:"scopes,omitempty"`
+}
+
+// A ConfigMap or Secret key with RabbitMQ definitions in JSON format. Exactly one of configMapRef and secretRef must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapRef) != has(self.secretRef)",message="exactly one of configMapRef and secretRef must be set"
+type DefinitionsSource struct {
+	// The ConfigMap with the definitions.
+	// +optional
+	ConfigMapRef *corev1.LocalObjectReference `json:"configMapRef,omitempty"`
+	// The Secret with the definitions. Use a Secret if the definitions contain user password hashes.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+	// The key of the ConfigMap or Secret with the definitions.
+	// +kubebuilder:default:=definitions.json
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// DefinitionsKey returns the key of the ConfigMap or Secret with the definitions.
+func (source *DefinitionsSource) DefinitionsKey() string {
+	if source.Key == "" {
+		return "definitions.json"
+	}
+	return source.Key
+}
+
+// A ConfigMap key with rabbitmq.conf settings.
+type ConfigFromSource struct {
+	// The ConfigMap with the settings.
+	ConfigMapRef corev1.LocalObjectReference `json:"configMapRef"`
+	// The key of the ConfigMap with the settings.
+	// +kubebuilder:default:=rabbitmq.conf
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ConfigMapKey returns the key of the ConfigMap with the settings.
+func (source *ConfigFromSource) ConfigMapKey() string {
+	if source.Key == "" {
+		return "rabbitmq.conf"
+	}
+	return source.Key
+}
+
+// Settings of the Kubernetes peer discovery.
+// For more information, see https://www.rabbitmq.com/docs/cluster-formation#peer-discovery-k8s
+type RabbitmqClusterPeerDiscoverySpec struct {
+	// How peers are addressed, which also determines the RabbitMQ node names.
+	// With hostname, nodes are named after the stable DNS name of their Pod in the headless Service.
+	// With ip, nodes are named after the IPv4 address of their Pod. Pod IPs change when Pods are re-created,
+	// so ip requires spec.persistence.storage to be 0, and it is not supported with the IPv6 IP family.
+	// Changing the address type of an existing RabbitmqCluster renames all nodes.
+	// +kubebuilder:validation:Enum:=hostname;ip
+	// +kubebuilder:default:=hostname
+	AddressType string `json:"addressType,omitempty"`
+	// Number of times a node retries to discover peers before it fails to start.
+	// Defaults to the RabbitMQ default.
+	// +kubebuilder:validation:Minimum:=1
+	DiscoveryRetryLimit *int32 `json:"discoveryRetryLimit,omitempty"`
+	// Time in milliseconds between two attempts to discover peers.
+	// Defaults to the RabbitMQ default.
+	// +kubebuilder:validation:Minimum:=1
+	DiscoveryRetryIntervalMilliseconds *int32 `json:"discoveryRetryIntervalMilliseconds,omitempty"`
+}
+
+// A ConfigMap or Secret with rabbitmq.conf fragments.
+type ConfigFragmentSource struct {
+	// Name of the ConfigMap or Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Keys of the ConfigMap or Secret to load. Only files with the .conf extension are loaded by RabbitMQ.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Pattern=`^[-._a-zA-Z0-9]+\.conf$`
+	Keys []string `json:"keys"`
 }
 
 // The settings for the persistent storage desired for each Pod in the RabbitmqCluster.
@@ -416,23 +954,339 @@ type RabbitmqClusterServiceSpec struct {
 	// +kubebuilder:validation:Enum=ClusterIP;LoadBalancer;NodePort
 	// +kubebuilder:default:="ClusterIP"
 	Type corev1.ServiceType `json:"type,omitempty"`
-	// Annotations to add to the Service.
+	// Annotations to add to the Service. Unlike the annotations of the RabbitmqCluster, they are not filtered,
+	// so that annotations in Kubernetes domains such as external-dns.alpha.kubernetes.io/hostname or
+	// service.beta.kubernetes.io/aws-load-balancer-type can be set.
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// IPFamilyPolicy represents the dual-stack-ness requested or required by a Service
 	// See also: https://pkg.go.dev/k8s.io/api/core/v1#IPFamilyPolicy
 	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
 	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+	// IPFamilies lists the IP families of the Services, e.g. [IPv6] for IPv6-only clusters
+	// or [IPv6, IPv4] for dual-stack clusters where IPv6 is the primary family.
+	// If IPv6 is listed, RabbitMQ listeners bind to all IPv6 and IPv4 addresses.
+	// If IPv6 is the first family, Erlang distribution and the CLI tools use IPv6.
+	// See also: https://pkg.go.dev/k8s.io/api/core/v1#IPFamily
+	// +kubebuilder:validation:MaxItems:=2
+	// +kubebuilder:validation:items:Enum=IPv4;IPv6
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+	// NodePorts sets fixed node ports on the Service, keyed by Service port name, e.g. amqp or management.
+	// When meshPortNames is set, the port names without the protocol prefix are accepted as well.
+	// Ports without an entry get a node port allocated by Kubernetes.
+	// Only used when the Service type is NodePort or LoadBalancer.
+	NodePorts map[string]int32 `json:"nodePorts,omitempty"`
+	// LoadBalancerIP requests a specific IP address for the Service, if supported by the cloud provider.
+	// Only used when the Service type is LoadBalancer.
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+	// LoadBalancerSourceRanges restricts traffic through the load balancer to the given client CIDRs, if supported by the cloud provider.
+	// Only used when the Service type is LoadBalancer.
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// ExternalTrafficPolicy describes how nodes distribute external traffic to the RabbitMQ Pods.
+	// Set to Local to preserve the client source IP.
+	// Only used when the Service type is NodePort or LoadBalancer.
+	// See also: https://pkg.go.dev/k8s.io/api/core/v1#ServiceExternalTrafficPolicy
+	// +kubebuilder:validation:Enum=Cluster;Local
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+	// HealthCheckNodePort sets a fixed node port for the health check of the load balancer.
+	// If not set, a node port is allocated by Kubernetes.
+	// Only used when the Service type is LoadBalancer and externalTrafficPolicy is Local.
+	HealthCheckNodePort *int32 `json:"healthCheckNodePort,omitempty"`
+	// SessionAffinity routes connections from the same client IP to the same RabbitMQ Pod when set to ClientIP.
+	// See also: https://kubernetes.io/docs/reference/networking/virtual-ips/#session-affinity
+	// +kubebuilder:validation:Enum=None;ClientIP
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+	// SessionAffinityTimeoutSeconds is the maximum session sticky time when sessionAffinity is ClientIP.
+	// If not set, Kubernetes defaults it to 10800 seconds.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=86400
+	SessionAffinityTimeoutSeconds *int32 `json:"sessionAffinityTimeoutSeconds,omitempty"`
+	// InternalTrafficPolicy describes how nodes distribute traffic from within the Kubernetes cluster to the RabbitMQ Pods.
+	// Set to Local to route traffic only to RabbitMQ Pods on the same node as the client.
+	// See also: https://pkg.go.dev/k8s.io/api/core/v1#ServiceInternalTrafficPolicy
+	// +kubebuilder:validation:Enum=Cluster;Local
+	InternalTrafficPolicy *corev1.ServiceInternalTrafficPolicy `json:"internalTrafficPolicy,omitempty"`
+	// MeshPortNames prefixes the port names of the Services with their protocol, e.g. tcp-amqp or http-management,
+	// so that service meshes detect the protocol of each port from its name.
+	// All Service ports set appProtocol regardless of this setting.
+	// Changing this setting renames the Service ports, which affects clients and ServiceMonitors selecting ports by name.
+	MeshPortNames bool `json:"meshPortNames,omitempty"`
+}
+
+// Settable attributes for the headless Service resource.
+type RabbitmqClusterHeadlessServiceSpec struct {
+	// PublishNotReadyAddresses publishes the DNS records of RabbitMQ Pods before they are ready,
+	// which RabbitMQ nodes need to find each other while the cluster forms. Defaults to true.
+	// +optional
+	PublishNotReadyAddresses *bool `json:"publishNotReadyAddresses,omitempty"`
+	// CLIToolsPorts adds the port range 35672-35682 used by the RabbitMQ CLI tools to connect to the nodes,
+	// so that the ports can be referenced by name, for example in NetworkPolicies.
+	// +optional
+	CLIToolsPorts bool `json:"cliToolsPorts,omitempty"`
+	// AdditionalPorts are added to the ports of the headless Service, for example the inter-node port range
+	// configured with inet_dist_listen_min and inet_dist_listen_max in advanced.config.
+	// +optional
+	AdditionalPorts []corev1.ServicePort `json:"additionalPorts,omitempty"`
+}
+
+// Settable attributes for the management Service resource.
+type RabbitmqClusterManagementServiceSpec struct {
+	// Type of the management Service. Must be one of: ClusterIP, LoadBalancer, NodePort.
+	// For more info see https://pkg.go.dev/k8s.io/api/core/v1#ServiceType
+	// +kubebuilder:validation:Enum=ClusterIP;LoadBalancer;NodePort
+	// +kubebuilder:default:="ClusterIP"
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// Annotations to add to the management Service.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Settable attributes for the Services created per RabbitMQ Pod.
+type RabbitmqClusterPodServicesSpec struct {
+	// Type of the Services. Must be one of: LoadBalancer, NodePort.
+	// For more info see https://pkg.go.dev/k8s.io/api/core/v1#ServiceType
+	// +kubebuilder:validation:Enum=LoadBalancer;NodePort
+	// +kubebuilder:default:="LoadBalancer"
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// Annotations to add to the Services.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ExternalTrafficPolicy describes how nodes distribute external traffic to the RabbitMQ Pod.
+	// Set to Local to preserve the client source IP and avoid a second hop to the node running the Pod.
+	// See also: https://pkg.go.dev/k8s.io/api/core/v1#ServiceExternalTrafficPolicy
+	// +kubebuilder:validation:Enum=Cluster;Local
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+}
+
+func (cluster *RabbitmqCluster) PodServicesEnabled() bool {
+	return cluster.Spec.PodServices != nil
+}
+
+// IPv6Enabled returns true if IPv6 is one of the IP families of the Services.
+func (cluster *RabbitmqCluster) IPv6Enabled() bool {
+	for _, family := range cluster.Spec.Service.IPFamilies {
+		if family == corev1.IPv6Protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// IPv6Primary returns true if IPv6 is the primary IP family of the Services.
+func (cluster *RabbitmqCluster) IPv6Primary() bool {
+	families := cluster.Spec.Service.IPFamilies
+	return len(families) > 0 && families[0] == corev1.IPv6Protocol
+}
+
+func (cluster *RabbitmqCluster) ManagementServiceEnabled() bool {
+	return cluster.Spec.ManagementService != nil
+}
+
+// Settable attributes for the management Ingress resource.
+type RabbitmqClusterManagementIngressSpec struct {
+	// Host name of the Ingress rule. If not set, the rule applies to all inbound HTTP traffic.
+	Host string `json:"host,omitempty"`
+	// Path of the Ingress rule, matched as a prefix.
+	// +kubebuilder:default:="/"
+	// +kubebuilder:validation:Pattern:=`^/`
+	Path string `json:"path,omitempty"`
+	// IngressClassName is the name of the IngressClass implementing the Ingress.
+	// If not set, the default IngressClass of the Kubernetes cluster is used.
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// TLSSecretName is the name of a Secret of type kubernetes.io/tls used to terminate TLS for the host.
+	// If not set, TLS is not configured on the Ingress.
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// Annotations to add to the management Ingress.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (cluster *RabbitmqCluster) ManagementIngressEnabled() bool {
+	return cluster.Spec.ManagementIngress != nil
+}
+
+// Settable attributes for the OpenShift Route resources.
+type RabbitmqClusterRouteSpec struct {
+	// Host of the management Route. If not set, a host is generated by OpenShift.
+	Host string `json:"host,omitempty"`
+	// TLSTermination of the management Route. Must be one of: edge, reencrypt.
+	// If not set, the management Route is not secured.
+	// A reencrypt Route connects to the HTTPS management port and requires TLS to be enabled.
+	// +kubebuilder:validation:Enum=edge;reencrypt
+	TLSTermination string `json:"tlsTermination,omitempty"`
+	// AMQPSPassthrough creates a second Route named <cluster-name>-amqps, which passes TLS connections
+	// through to the AMQPS port of the client Service. Requires TLS to be enabled.
+	AMQPSPassthrough bool `json:"amqpsPassthrough,omitempty"`
+	// Host of the AMQPS Route. If not set, a host is generated by OpenShift.
+	AMQPSHost string `json:"amqpsHost,omitempty"`
+	// Annotations to add to the Routes.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (cluster *RabbitmqCluster) RouteEnabled() bool {
+	return cluster.Spec.Route != nil
+}
+
+func (cluster *RabbitmqCluster) AMQPSRouteEnabled() bool {
+	return cluster.RouteEnabled() && cluster.Spec.Route.AMQPSPassthrough && cluster.TLSEnabled()
+}
+
+// Settable attributes for the Gateway API routes.
+type RabbitmqClusterGatewaySpec struct {
+	// Name of the Gateway to attach the routes to.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Namespace of the Gateway. Defaults to the namespace of the RabbitmqCluster.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// AMQPSectionName is the name of the TCP listener of the Gateway for AMQP.
+	// If set, a TCPRoute named <cluster-name>-amqp is created.
+	// +optional
+	AMQPSectionName string `json:"amqpSectionName,omitempty"`
+	// AMQPSSectionName is the name of the TLS passthrough listener of the Gateway for AMQPS.
+	// If set and TLS is enabled, a TLSRoute named <cluster-name>-amqps is created.
+	// +optional
+	AMQPSSectionName string `json:"amqpsSectionName,omitempty"`
+	// Hostnames of the TLSRoute, matched against the SNI of AMQPS connections.
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+	// Annotations to add to the routes.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (cluster *RabbitmqCluster) AMQPGatewayRouteEnabled() bool {
+	return cluster.Spec.Gateway != nil && cluster.Spec.Gateway.AMQPSectionName != "" && !cluster.DisableNonTLSListeners()
+}
+
+func (cluster *RabbitmqCluster) AMQPSGatewayRouteEnabled() bool {
+	return cluster.Spec.Gateway != nil && cluster.Spec.Gateway.AMQPSSectionName != "" && cluster.TLSEnabled()
+}
+
+// Settable attributes of the ServiceMonitor or PodMonitor.
+type RabbitmqClusterMonitoringSpec struct {
+	// Enabled creates the ServiceMonitor or PodMonitor, named like the RabbitmqCluster.
+	Enabled bool `json:"enabled,omitempty"`
+	// Kind of the monitor. A ServiceMonitor scrapes the RabbitMQ nodes through the client Service,
+	// a PodMonitor scrapes the Pods directly, including Pods which are not ready.
+	// +kubebuilder:validation:Enum=ServiceMonitor;PodMonitor
+	// +kubebuilder:default:="ServiceMonitor"
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Interval at which the metrics are scraped, e.g. 30s. Defaults to the scrape interval of Prometheus.
+	// +kubebuilder:validation:Pattern:="^(0|(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$"
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// Labels to add to the monitor, e.g. to match the serviceMonitorSelector or podMonitorSelector of a Prometheus.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Relabelings applied to the targets before they are scraped.
+	// +optional
+	Relabelings []RelabelConfig `json:"relabelings,omitempty"`
+	// TLS configures how Prometheus verifies the certificates of the RabbitMQ nodes. If TLS is enabled for the
+	// RabbitmqCluster, the metrics are scraped from the TLS port 15691 instead of 15692, and the certificates are
+	// verified with ca.crt of spec.tls.caSecretName, or of spec.tls.secretName if it is not set.
+	// +optional
+	TLS *RabbitmqClusterMonitoringTLSSpec `json:"tls,omitempty"`
+	// PrometheusRule creates a PrometheusRule named like the RabbitmqCluster with alerts about memory and disk alarms,
+	// network partitions, unroutable messages and the loss of quorum of the RabbitmqCluster.
+	// The quorum alert requires the metrics of kube-state-metrics.
+	// +optional
+	PrometheusRule *RabbitmqClusterPrometheusRuleSpec `json:"prometheusRule,omitempty"`
+}
+
+// Settable attributes of the PrometheusRule.
+type RabbitmqClusterPrometheusRuleSpec struct {
+	// Labels to add to the PrometheusRule, e.g. to match the ruleSelector of a Prometheus.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// AlertLabels to add to all alerts, e.g. to route them to a team in Alertmanager.
+	// +optional
+	AlertLabels map[string]string `json:"alertLabels,omitempty"`
+}
+
+// TLS settings of the scrape requests of Prometheus.
+type RabbitmqClusterMonitoringTLSSpec struct {
+	// ServerName expected in the certificates of the RabbitMQ nodes, e.g. <cluster-name>-server-0.<cluster-name>-nodes.<namespace>
+	// for a wildcard certificate. Prometheus scrapes the Pod IPs, which are usually not in the certificates.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+	// InsecureSkipVerify disables the verification of the certificates of the RabbitMQ nodes.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// RelabelConfig is a relabeling rule of the Prometheus Operator.
+// For more information, see https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config
+type RelabelConfig struct {
+	// SourceLabels whose values are concatenated with the separator and matched against the regex.
+	// +optional
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+	// Separator of the concatenated source label values. Defaults to ;.
+	// +optional
+	Separator string `json:"separator,omitempty"`
+	// TargetLabel to which the result is written by the replace action.
+	// +optional
+	TargetLabel string `json:"targetLabel,omitempty"`
+	// Regex matched against the concatenated source label values. Defaults to (.*).
+	// +optional
+	Regex string `json:"regex,omitempty"`
+	// Modulus of the hash of the source label values, for the hashmod action.
+	// +optional
+	Modulus uint64 `json:"modulus,omitempty"`
+	// Replacement written to the target label by the replace action. Defaults to $1.
+	// +optional
+	Replacement *string `json:"replacement,omitempty"`
+	// Action of the relabeling. Defaults to replace.
+	// +kubebuilder:validation:Enum=replace;keep;drop;hashmod;labelmap;labeldrop;labelkeep;lowercase;uppercase;keepequal;dropequal
+	// +optional
+	Action string `json:"action,omitempty"`
+}
+
+func (cluster *RabbitmqCluster) MonitoringEnabled() bool {
+	return cluster.Spec.Monitoring != nil && cluster.Spec.Monitoring.Enabled
+}
+
+func (cluster *RabbitmqCluster) PrometheusRuleEnabled() bool {
+	return cluster.MonitoringEnabled() && cluster.Spec.Monitoring.PrometheusRule != nil
+}
+
+// RabbitmqImagePullPolicy returns the pull policy of the RabbitMQ image.
+func (cluster *RabbitmqCluster) RabbitmqImagePullPolicy() corev1.PullPolicy {
+	if cluster.Spec.ImagePullPolicy != "" {
+		return cluster.Spec.ImagePullPolicy
+	}
+	image := cluster.Spec.Image
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+	// the tag follows the last colon, unless that colon separates the registry host from its port
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i+1:], "/") {
+		if image[i+1:] != "latest" {
+			return corev1.PullIfNotPresent
+		}
+	}
+	return corev1.PullAlways
 }
 
 func (cluster *RabbitmqCluster) TLSEnabled() bool {
-	return cluster.SecretTLSEnabled() || cluster.VaultTLSEnabled()
+	return cluster.SecretTLSEnabled() || cluster.VaultTLSEnabled() || cluster.CSITLSEnabled()
 }
 func (cluster *RabbitmqCluster) SecretTLSEnabled() bool {
 	return cluster.Spec.TLS.SecretName != ""
 }
 
 func (cluster *RabbitmqCluster) MutualTLSEnabled() bool {
-	return (cluster.SecretTLSEnabled() && cluster.Spec.TLS.CaSecretName != "") || cluster.VaultTLSEnabled()
+	return (cluster.SecretTLSEnabled() && cluster.Spec.TLS.CaSecretName != "") || cluster.VaultTLSEnabled() || cluster.CSITLSEnabled()
+}
+
+// InterNodeTLSEnabled returns true if the Erlang distribution between nodes is encrypted with TLS.
+func (cluster *RabbitmqCluster) InterNodeTLSEnabled() bool {
+	return cluster.Spec.TLS.Distribution != nil
+}
+
+// TLSPeerVerification returns the verification of client certificates, which is verify_peer unless spec.tls.verify is set.
+func (cluster *RabbitmqCluster) TLSPeerVerification() string {
+	if cluster.Spec.TLS.Verify != "" {
+		return cluster.Spec.TLS.Verify
+	}
+	return "verify_peer"
 }
 
 func (cluster *RabbitmqCluster) MemoryLimited() bool {
@@ -447,8 +1301,39 @@ func (cluster *RabbitmqCluster) DisableNonTLSListeners() bool {
 	return cluster.Spec.TLS.DisableNonTLSListeners
 }
 
+// PeerDiscoveryByIP returns true if RabbitMQ nodes are addressed and named by the IP of their Pod.
+func (cluster *RabbitmqCluster) PeerDiscoveryByIP() bool {
+	return cluster.Spec.Rabbitmq.PeerDiscovery.AddressType == "ip"
+}
+
+// RequestedPlugins returns spec.rabbitmq.additionalPlugins and the plugins required by other settings of the spec.
+func (cluster *RabbitmqCluster) RequestedPlugins() []Plugin {
+	plugins := append([]Plugin{}, cluster.Spec.Rabbitmq.AdditionalPlugins...)
+	if cluster.OAuth2Enabled() {
+		plugins = append(plugins, "rabbitmq_auth_backend_oauth2")
+	}
+	if mqtt := cluster.Spec.Rabbitmq.MQTT; mqtt != nil {
+		plugins = append(plugins, "rabbitmq_mqtt")
+		if mqtt.WebSockets {
+			plugins = append(plugins, "rabbitmq_web_mqtt")
+		}
+	}
+	if stomp := cluster.Spec.Rabbitmq.STOMP; stomp != nil {
+		plugins = append(plugins, "rabbitmq_stomp")
+		if stomp.WebSockets {
+			plugins = append(plugins, "rabbitmq_web_stomp")
+		}
+	}
+	return plugins
+}
+
+func (cluster *RabbitmqCluster) OAuth2Enabled() bool {
+	return cluster.Spec.Rabbitmq.Auth.OAuth2 != nil
+}
+
+// AdditionalPluginEnabled returns true if the plugin is in spec.rabbitmq.additionalPlugins or required by other settings of the spec.
 func (cluster *RabbitmqCluster) AdditionalPluginEnabled(plugin Plugin) bool {
-	for _, p := range cluster.Spec.Rabbitmq.AdditionalPlugins {
+	for _, p := range cluster.RequestedPlugins() {
 		if p == plugin {
 			return true
 		}
@@ -471,6 +1356,20 @@ func (cluster *RabbitmqCluster) ExternalSecretEnabled() bool {
 	return cluster.Spec.SecretBackend.ExternalSecret.Name != ""
 }
 
+// ExistingAdminSecretEnabled returns true when the credentials of the default user are copied from spec.secretBackend.existingAdminSecret.
+func (cluster *RabbitmqCluster) ExistingAdminSecretEnabled() bool {
+	return cluster.Spec.SecretBackend.ExistingAdminSecret.Name != "" && !cluster.VaultDefaultUserSecretEnabled() && !cluster.ExternalSecretEnabled() && !cluster.CSIDefaultUserEnabled()
+}
+
+// ExistingErlangCookieSecretEnabled returns true when the Erlang cookie is read from spec.secretBackend.existingErlangCookieSecret.
+func (cluster *RabbitmqCluster) ExistingErlangCookieSecretEnabled() bool {
+	return cluster.Spec.SecretBackend.ExistingErlangCookieSecret.Name != ""
+}
+
+func (cluster *RabbitmqCluster) RemoteClusterEnabled() bool {
+	return cluster.Spec.RemoteCluster != nil
+}
+
 func (cluster *RabbitmqCluster) UsesDefaultUserUpdaterImage(controlRabbitmqImage bool) bool {
 	return cluster.VaultEnabled() && (cluster.Spec.SecretBackend.Vault.DefaultUserUpdaterImage == nil || controlRabbitmqImage)
 }
@@ -483,6 +1382,20 @@ func (cluster *RabbitmqCluster) VaultTLSEnabled() bool {
 	return cluster.VaultEnabled() && cluster.Spec.SecretBackend.Vault.TLSEnabled()
 }
 
+func (cluster *RabbitmqCluster) CSIEnabled() bool {
+	return cluster.Spec.SecretBackend.CSI != nil
+}
+
+// CSIDefaultUserEnabled returns true when the default user is read from the Secrets Store CSI Driver volume.
+func (cluster *RabbitmqCluster) CSIDefaultUserEnabled() bool {
+	return cluster.CSIEnabled() && cluster.Spec.SecretBackend.CSI.DefaultUser
+}
+
+// CSITLSEnabled returns true when the TLS certificates are read from the Secrets Store CSI Driver volume.
+func (cluster *RabbitmqCluster) CSITLSEnabled() bool {
+	return cluster.CSIEnabled() && cluster.Spec.SecretBackend.CSI.TLS
+}
+
 func (cluster *RabbitmqCluster) ServiceSubDomain() string {
 	return fmt.Sprintf("%s.%s.svc", cluster.Name, cluster.Namespace)
 }
@@ -499,12 +1412,25 @@ type RabbitmqClusterList struct {
 	Items []RabbitmqCluster `json:"items"`
 }
 
+// ChildResourceName returns the name of the child resource with the given suffix, <name>-<suffix> unless
+// SetChildResourceNameTemplate configured another scheme.
 func (cluster *RabbitmqCluster) ChildResourceName(name string) string {
+	if childResourceNameTemplate != nil {
+		// the template was rendered when it was set, so it does not fail
+		if rendered, err := renderChildResourceName(childResourceNameTemplate, cluster.Name, name); err == nil {
+			return rendered
+		}
+	}
 	return strings.TrimSuffix(strings.Join([]string{cluster.Name, name}, "-"), "-")
 }
 
 func (cluster *RabbitmqCluster) PVCName(i int) string {
-	return strings.Join([]string{"persistence", cluster.Name, "server", strconv.Itoa(i)}, "-")
+	return strings.Join([]string{"persistence", cluster.ChildResourceName("server"), strconv.Itoa(i)}, "-")
+}
+
+// HealthGatedUpdates returns true if the operator replaces outdated Pods one at a time after health checks.
+func (cluster *RabbitmqCluster) HealthGatedUpdates() bool {
+	return cluster.Spec.UpdateStrategy == HealthGatedStrategy
 }
 
 func (cluster *RabbitmqCluster) DisableDefaultTopologySpreadConstraints() bool {
@@ -158,6 +158,36 @@ var _ = Describe("RabbitmqCluster", func() {
 				resource := generateRabbitmqClusterObject("iam")
 				Expect(resource.ChildResourceName("great")).To(Equal("iam-great"))
 			})
+
+			When("a child resource name template is set", func() {
+				AfterEach(func() {
+					Expect(SetChildResourceNameTemplate("")).To(Succeed())
+				})
+
+				It("renders the names with the template", func() {
+					Expect(SetChildResourceNameTemplate("{{ trunc 5 .Name }}-{{ .Hash }}-{{ .Suffix }}")).To(Succeed())
+					resource := generateRabbitmqClusterObject("a-very-long-cluster-name")
+					Expect(resource.ChildResourceName("server")).To(MatchRegexp(`^a-ver-[0-9a-f]{8}-server$`))
+					Expect(resource.ChildResourceName("")).To(MatchRegexp(`^a-ver-[0-9a-f]{8}$`))
+					Expect(resource.ChildResourceName("server")).NotTo(Equal(generateRabbitmqClusterObject("a-very-other-name").ChildResourceName("server")))
+					Expect(resource.PVCName(0)).To(Equal("persistence-" + resource.ChildResourceName("server") + "-0"))
+				})
+
+				It("rejects templates rendering invalid names", func() {
+					Expect(SetChildResourceNameTemplate("{{ .Name }}_{{ .Suffix }}")).To(MatchError(ContainSubstring("is invalid")))
+					Expect(SetChildResourceNameTemplate("{{ .Namespace }}")).To(MatchError(ContainSubstring("invalid child resource name template")))
+					Expect(generateRabbitmqClusterObject("iam").ChildResourceName("great")).To(Equal("iam-great"))
+				})
+
+				DescribeTable("rejects templates rendering the same name for different suffixes",
+					func(template, collidingSuffixes string) {
+						Expect(SetChildResourceNameTemplate(template)).To(MatchError(ContainSubstring(collidingSuffixes)))
+						Expect(generateRabbitmqClusterObject("iam").ChildResourceName("server")).To(Equal("iam-server"))
+					},
+					Entry("without suffix", "{{ .Name }}", `suffixes "" and "amqp"`),
+					Entry("with a truncated suffix", "{{ .Name }}-{{ trunc 6 .Suffix }}", `suffixes "server" and "server-conf"`),
+				)
+			})
 		})
 
 		Context("Default settings", func() {
@@ -168,6 +198,8 @@ var _ = Describe("RabbitmqCluster", func() {
 
 			BeforeEach(func() {
 				expectedClusterInstance = *generateRabbitmqClusterObject("foo")
+				// resources are defaulted by the operator, not by the CRD
+				expectedClusterInstance.Spec.Resources = nil
 			})
 
 			When("CR spec is empty", func() {
@@ -496,7 +528,7 @@ var _ = Describe("RabbitmqCluster", func() {
 				},
 			}
 
-			rabbitmqClusterStatus.SetConditions([]runtime.Object{statefulset, endPoints})
+			rabbitmqClusterStatus.SetConditions([]runtime.Object{statefulset, endPoints}, nil)
 
 			Expect(rabbitmqClusterStatus.Conditions).To(HaveLen(4))
 			Expect(rabbitmqClusterStatus.Conditions[0].Type).To(Equal(status.AllReplicasReady))
@@ -528,6 +560,21 @@ var _ = Describe("RabbitmqCluster", func() {
 			Expect(updatedCondition.LastTransitionTime.Before(&notExpectedTime)).To(BeFalse())
 		})
 	})
+	DescribeTable("RabbitmqImagePullPolicy",
+		func(image string, configured, expected corev1.PullPolicy) {
+			r := generateRabbitmqClusterObject("testrabbit")
+			r.Spec.Image = image
+			r.Spec.ImagePullPolicy = configured
+			Expect(r.RabbitmqImagePullPolicy()).To(Equal(expected))
+		},
+		Entry("configured policy", "rabbitmq:latest", corev1.PullNever, corev1.PullNever),
+		Entry("digest", "rabbitmq@sha256:0123456789abcdef", corev1.PullPolicy(""), corev1.PullIfNotPresent),
+		Entry("version tag", "rabbitmq:4.0.3-management", corev1.PullPolicy(""), corev1.PullIfNotPresent),
+		Entry("registry with port and tag", "registry.example.com:5000/rabbitmq:4.0.3", corev1.PullPolicy(""), corev1.PullIfNotPresent),
+		Entry("latest tag", "rabbitmq:latest", corev1.PullPolicy(""), corev1.PullAlways),
+		Entry("no tag", "rabbitmq", corev1.PullPolicy(""), corev1.PullAlways),
+		Entry("registry with port and no tag", "registry.example.com:5000/rabbitmq", corev1.PullPolicy(""), corev1.PullAlways),
+	)
 	Context("PVC Name helper function", func() {
 		It("returns the correct PVC name", func() {
 			r := generateRabbitmqClusterObject("testrabbit")
@@ -0,0 +1,258 @@
+package v1beta1
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rabbitmq/cluster-operator/v2/internal/configsyntax"
+	"github.com/rabbitmq/cluster-operator/v2/internal/plugins"
+	"github.com/rabbitmq/cluster-operator/v2/internal/versionskew"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// CommunityPluginsAnnotation lists comma separated plugins which are accepted in spec.rabbitmq.additionalPlugins
+// although they are not shipped with RabbitMQ, like community plugins added to a custom image.
+const CommunityPluginsAnnotation = "rabbitmq.com/communityPlugins"
+
+var supportedServiceTypes = []string{
+	string(corev1.ServiceTypeClusterIP),
+	string(corev1.ServiceTypeLoadBalancer),
+	string(corev1.ServiceTypeNodePort),
+}
+
+// Validate returns the errors of settings which cannot be deployed, so that the validating webhook rejects them
+// instead of the reconciler failing to create the child resources.
+func (cluster *RabbitmqCluster) Validate() field.ErrorList {
+	return cluster.validate(nil)
+}
+
+// ValidateUpdate validates an updated RabbitmqCluster like Validate, except for the additional plugins which old
+// already enabled. They were accepted before, e.g. by an older version of the operator, and RabbitmqClusters which
+// enable them must remain updatable.
+func (cluster *RabbitmqCluster) ValidateUpdate(old *RabbitmqCluster) field.ErrorList {
+	return cluster.validate(old)
+}
+
+// validate returns the errors of Validate. old is the RabbitmqCluster before an update, or nil.
+func (cluster *RabbitmqCluster) validate(old *RabbitmqCluster) field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	// the name is part of the hostnames of the Pods, which are DNS labels
+	for _, msg := range validation.IsDNS1035Label(cluster.Name) {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), cluster.Name, msg))
+	}
+	if cluster.Spec.Replicas != nil {
+		if *cluster.Spec.Replicas < 0 {
+			errs = append(errs, field.Invalid(spec.Child("replicas"), *cluster.Spec.Replicas, "must be greater than or equal to 0"))
+		} else if len(errs) == 0 && *cluster.Spec.Replicas > 0 {
+			podName := fmt.Sprintf("%s-%d", cluster.ChildResourceName("server"), *cluster.Spec.Replicas-1)
+			for _, msg := range validation.IsDNS1035Label(podName) {
+				errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), cluster.Name, fmt.Sprintf("Pod name %s: %s", podName, msg)))
+			}
+		}
+	}
+	if cluster.Spec.Persistence.Storage != nil && cluster.Spec.Persistence.Storage.Sign() < 0 {
+		errs = append(errs, field.Invalid(spec.Child("persistence", "storage"), cluster.Spec.Persistence.Storage.String(), "must be greater than or equal to 0"))
+	}
+	if cluster.Spec.Resources != nil {
+		errs = append(errs, validateResources(cluster.Spec.Resources, spec.Child("resources"))...)
+	}
+	if t := cluster.Spec.Service.Type; t != "" && t != corev1.ServiceTypeClusterIP && t != corev1.ServiceTypeLoadBalancer && t != corev1.ServiceTypeNodePort {
+		errs = append(errs, field.NotSupported(spec.Child("service", "type"), t, supportedServiceTypes))
+	}
+	// syntax errors would only show up as RabbitMQ nodes failing to boot after the rollout
+	if err := configsyntax.CheckRabbitmqConf(cluster.Spec.Rabbitmq.AdditionalConfig); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("rabbitmq", "additionalConfig"), field.OmitValueType{}, err.Error()))
+	}
+	if err := configsyntax.CheckAdvancedConfig(cluster.Spec.Rabbitmq.AdvancedConfig); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("rabbitmq", "advancedConfig"), field.OmitValueType{}, err.Error()))
+	}
+	errs = append(errs, validateResourceAlarmThreshold(cluster.Spec.Rabbitmq.DiskFreeLimit, 0, spec.Child("rabbitmq", "diskFreeLimit"))...)
+	errs = append(errs, validateResourceAlarmThreshold(cluster.Spec.Rabbitmq.MemoryHighWatermark, 1, spec.Child("rabbitmq", "memoryHighWatermark"))...)
+	errs = append(errs, cluster.validatePlugins(spec.Child("rabbitmq", "additionalPlugins"), old)...)
+	errs = append(errs, cluster.validatePeerDiscovery(spec.Child("rabbitmq", "peerDiscovery", "addressType"))...)
+	return append(errs, cluster.validateOverride(spec.Child("override"))...)
+}
+
+// validatePlugins returns errors for additional plugins which are neither shipped with the RabbitMQ version of the
+// image nor listed in the community plugins annotation. Any plugin shipped with RabbitMQ is accepted if the image
+// tag has no version. Plugins which old already enabled are not validated.
+func (cluster *RabbitmqCluster) validatePlugins(path *field.Path, old *RabbitmqCluster) field.ErrorList {
+	var errs field.ErrorList
+	version, _ := versionskew.ImageVersion(cluster.Spec.Image)
+	community := map[string]bool{}
+	for _, plugin := range strings.Split(cluster.Annotations[CommunityPluginsAnnotation], ",") {
+		community[strings.TrimSpace(plugin)] = true
+	}
+	for i, plugin := range cluster.Spec.Rabbitmq.AdditionalPlugins {
+		if plugins.Shipped(string(plugin), version) || community[string(plugin)] ||
+			(old != nil && slices.Contains(old.Spec.Rabbitmq.AdditionalPlugins, plugin)) {
+			continue
+		}
+		msg := "is not shipped with RabbitMQ"
+		if version != "" {
+			msg += " " + version
+		}
+		errs = append(errs, field.Invalid(path.Index(i), plugin,
+			fmt.Sprintf("%s; add it to the annotation %s if it is a community plugin of the image", msg, CommunityPluginsAnnotation)))
+	}
+	return errs
+}
+
+// validatePeerDiscovery returns errors for the ip address type combined with settings it does not support.
+// Nodes are named after the IPv4 address of their Pod, which changes when the Pod is re-created, so the node
+// would not find its own data on a persistent volume.
+func (cluster *RabbitmqCluster) validatePeerDiscovery(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if !cluster.PeerDiscoveryByIP() {
+		return errs
+	}
+	if cluster.IPv6Enabled() {
+		errs = append(errs, field.Invalid(path, cluster.Spec.Rabbitmq.PeerDiscovery.AddressType, "is not supported with the IPv6 IP family; use hostname"))
+	}
+	if storage := cluster.Spec.Persistence.Storage; storage == nil || !storage.IsZero() {
+		errs = append(errs, field.Invalid(path, cluster.Spec.Rabbitmq.PeerDiscovery.AddressType, "is not supported with persistent storage; set spec.persistence.storage to 0 or use hostname"))
+	}
+	return errs
+}
+
+// validateResourceAlarmThreshold returns errors unless exactly one of absolute and relative is set. Both must be
+// greater than 0, and relative must be at most maxRelative if maxRelative is not 0.
+func validateResourceAlarmThreshold(threshold *ResourceAlarmThreshold, maxRelative float64, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if threshold == nil {
+		return errs
+	}
+	switch {
+	case threshold.Absolute != nil && threshold.Relative != nil:
+		errs = append(errs, field.Invalid(path, field.OmitValueType{}, "only one of absolute and relative can be set"))
+	case threshold.Absolute != nil:
+		if threshold.Absolute.Sign() <= 0 {
+			errs = append(errs, field.Invalid(path.Child("absolute"), threshold.Absolute.String(), "must be greater than 0"))
+		}
+	case threshold.Relative != nil:
+		if relative := threshold.Relative.AsApproximateFloat64(); relative <= 0 || (maxRelative != 0 && relative > maxRelative) {
+			msg := "must be greater than 0"
+			if maxRelative != 0 {
+				msg = fmt.Sprintf("must be greater than 0 and less than or equal to %g", maxRelative)
+			}
+			errs = append(errs, field.Invalid(path.Child("relative"), threshold.Relative.String(), msg))
+		}
+	default:
+		errs = append(errs, field.Required(path, "one of absolute and relative must be set"))
+	}
+	return errs
+}
+
+// validateResources returns errors for negative quantities and for requests above the limits.
+func validateResources(resources *corev1.ResourceRequirements, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, list := range []struct {
+		path      *field.Path
+		resources corev1.ResourceList
+	}{
+		{path.Child("limits"), resources.Limits},
+		{path.Child("requests"), resources.Requests},
+	} {
+		for name, quantity := range list.resources {
+			if quantity.Sign() < 0 {
+				errs = append(errs, field.Invalid(list.path.Key(string(name)), quantity.String(), "must be greater than or equal to 0"))
+			}
+		}
+	}
+	for name, request := range resources.Requests {
+		if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(path.Child("requests").Key(string(name)), request.String(),
+				fmt.Sprintf("must be less than or equal to the %s limit of %s", name, limit.String())))
+		}
+	}
+	return errs
+}
+
+// validateOverride returns errors for overrides which contradict the settings of the spec they override.
+func (cluster *RabbitmqCluster) validateOverride(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	override := cluster.Spec.Override
+	if override.StatefulSet != nil && override.StatefulSet.Spec != nil {
+		stsSpec := path.Child("statefulSet", "spec")
+		if replicas := override.StatefulSet.Spec.Replicas; replicas != nil && cluster.Spec.Replicas != nil && *replicas != *cluster.Spec.Replicas {
+			errs = append(errs, field.Invalid(stsSpec.Child("replicas"), *replicas, "conflicts with spec.replicas; set spec.replicas instead"))
+		}
+		for i, claim := range override.StatefulSet.Spec.VolumeClaimTemplates {
+			if claim.Name != "persistence" || cluster.Spec.Persistence.Storage == nil {
+				continue
+			}
+			if storage, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]; ok && storage.Cmp(*cluster.Spec.Persistence.Storage) != 0 {
+				errs = append(errs, field.Invalid(stsSpec.Child("volumeClaimTemplates").Index(i).Child("spec", "resources", "requests").Key(string(corev1.ResourceStorage)),
+					storage.String(), "conflicts with spec.persistence.storage; set spec.persistence.storage instead"))
+			}
+		}
+	}
+	if override.Service != nil && override.Service.Spec != nil {
+		if t := override.Service.Spec.Type; t != "" && cluster.Spec.Service.Type != "" && t != cluster.Spec.Service.Type {
+			errs = append(errs, field.Invalid(path.Child("service", "spec", "type"), t, "conflicts with spec.service.type; set spec.service.type instead"))
+		}
+	}
+	return errs
+}
+
+// ValidateImmutableFields returns errors for changes which cannot be applied to the existing StatefulSet, since they
+// change immutable fields of StatefulSets or PersistentVolumeClaims. Without the validating webhook, such changes
+// would be accepted and then fail on every reconcile.
+func (cluster *RabbitmqCluster) ValidateImmutableFields(old *RabbitmqCluster) field.ErrorList {
+	var errs field.ErrorList
+	persistence := field.NewPath("spec", "persistence")
+
+	// the storage class can be set on RabbitmqClusters which were not defaulted on creation
+	if oldClass := old.Spec.Persistence.StorageClassName; oldClass != nil {
+		if newClass := cluster.Spec.Persistence.StorageClassName; newClass == nil || *newClass != *oldClass {
+			errs = append(errs, field.Forbidden(persistence.Child("storageClassName"),
+				fmt.Sprintf("is immutable, since the storage class of PersistentVolumeClaims cannot change; it is %s", *oldClass)))
+		}
+	}
+	if oldStorage, newStorage := old.Spec.Persistence.Storage, cluster.Spec.Persistence.Storage; oldStorage != nil && newStorage != nil {
+		if newStorage.Cmp(*oldStorage) < 0 {
+			errs = append(errs, field.Forbidden(persistence.Child("storage"),
+				fmt.Sprintf("cannot shrink from %s to %s, since shrinking persistent volumes is not supported", oldStorage.String(), newStorage.String())))
+		} else if oldStorage.IsZero() && !newStorage.IsZero() {
+			errs = append(errs, field.Forbidden(persistence.Child("storage"), "cannot change from 0, since changing from ephemeral to persistent storage is not supported"))
+		}
+	}
+
+	oldSts, newSts := statefulSetOverrideSpec(old), statefulSetOverrideSpec(cluster)
+	stsSpec := field.NewPath("spec", "override", "statefulSet", "spec")
+	if !equality.Semantic.DeepEqual(oldSts.Selector, newSts.Selector) {
+		errs = append(errs, field.Forbidden(stsSpec.Child("selector"), "is immutable, since the selector of StatefulSets cannot change"))
+	}
+	if oldSts.ServiceName != newSts.ServiceName {
+		errs = append(errs, field.Forbidden(stsSpec.Child("serviceName"), "is immutable, since the serviceName of StatefulSets cannot change"))
+	}
+	if oldSts.PodManagementPolicy != newSts.PodManagementPolicy {
+		errs = append(errs, field.Forbidden(stsSpec.Child("podManagementPolicy"), "is immutable, since the podManagementPolicy of StatefulSets cannot change"))
+	}
+	if !equality.Semantic.DeepEqual(claimNames(oldSts.VolumeClaimTemplates), claimNames(newSts.VolumeClaimTemplates)) {
+		errs = append(errs, field.Forbidden(stsSpec.Child("volumeClaimTemplates"), "cannot add or remove volume claim templates, since the volumeClaimTemplates of StatefulSets cannot change"))
+	}
+	return errs
+}
+
+// statefulSetOverrideSpec returns spec.override.statefulSet.spec, or an empty spec if it is not set.
+func statefulSetOverrideSpec(cluster *RabbitmqCluster) *StatefulSetSpec {
+	if cluster.Spec.Override.StatefulSet == nil || cluster.Spec.Override.StatefulSet.Spec == nil {
+		return &StatefulSetSpec{}
+	}
+	return cluster.Spec.Override.StatefulSet.Spec
+}
+
+func claimNames(claims []PersistentVolumeClaim) []string {
+	var names []string
+	for _, claim := range claims {
+		names = append(names, claim.Name)
+	}
+	return names
+}
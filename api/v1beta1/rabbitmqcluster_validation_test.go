@@ -0,0 +1,288 @@
+package v1beta1
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Validate", func() {
+	var cluster *RabbitmqCluster
+
+	BeforeEach(func() {
+		cluster = &RabbitmqCluster{ObjectMeta: metav1.ObjectMeta{Name: "rabbit", Namespace: "default"}}
+		cluster.SetDefaults()
+	})
+
+	It("accepts a default cluster", func() {
+		Expect(cluster.Validate()).To(BeEmpty())
+	})
+
+	It("rejects names which are not DNS labels", func() {
+		cluster.Name = "rabbit.example"
+		Expect(cluster.Validate().ToAggregate()).To(MatchError(ContainSubstring("metadata.name")))
+	})
+
+	It("rejects names which make Pod names too long", func() {
+		cluster.Name = strings.Repeat("a", 55)
+		cluster.Spec.Replicas = ptr.To(int32(3))
+		Expect(cluster.Validate().ToAggregate()).To(MatchError(ContainSubstring("Pod name")))
+	})
+
+	It("rejects negative replicas", func() {
+		cluster.Spec.Replicas = ptr.To(int32(-1))
+		Expect(cluster.Validate().ToAggregate()).To(MatchError(ContainSubstring("spec.replicas")))
+	})
+
+	It("rejects negative quantities and requests above the limits", func() {
+		cluster.Spec.Persistence.Storage = ptr.To(k8sresource.MustParse("-1Gi"))
+		cluster.Spec.Resources = &corev1.ResourceRequirements{
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("1Gi")},
+			Requests: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("2Gi")},
+		}
+		errs := cluster.Validate()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs.ToAggregate()).To(MatchError(And(
+			ContainSubstring("spec.persistence.storage"),
+			ContainSubstring("spec.resources.requests[memory]"),
+		)))
+	})
+
+	It("rejects unknown service types", func() {
+		cluster.Spec.Service.Type = corev1.ServiceTypeExternalName
+		Expect(cluster.Validate().ToAggregate()).To(MatchError(ContainSubstring("spec.service.type")))
+	})
+
+	It("rejects overrides which conflict with the spec", func() {
+		cluster.Spec.Override = RabbitmqClusterOverrideSpec{
+			StatefulSet: &StatefulSet{Spec: &StatefulSetSpec{Replicas: ptr.To(int32(3))}},
+			Service:     &Service{Spec: &corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort}},
+		}
+		Expect(cluster.Validate().ToAggregate()).To(MatchError(And(
+			ContainSubstring("spec.override.statefulSet.spec.replicas"),
+			ContainSubstring("spec.override.service.spec.type"),
+		)))
+	})
+
+	It("rejects syntax errors in the configuration files", func() {
+		cluster.Spec.Rabbitmq.AdditionalConfig = "log.console.level debug"
+		cluster.Spec.Rabbitmq.AdvancedConfig = "[{rabbit, []}]"
+		Expect(cluster.Validate().ToAggregate()).To(MatchError(And(
+			ContainSubstring("spec.rabbitmq.additionalConfig: Invalid value: syntax error on line 1"),
+			ContainSubstring("spec.rabbitmq.advancedConfig: Invalid value: syntax error on line 1"),
+		)))
+	})
+
+	It("rejects plugins which are not shipped with the RabbitMQ version of the image", func() {
+		cluster.Spec.Image = "rabbitmq:4.0.5-management"
+		cluster.Spec.Rabbitmq.AdditionalPlugins = []Plugin{"rabbitmq_shovel", "rabbitmq_shovels", "rabbitmq_shovel_prometheus"}
+		Expect(cluster.Validate().ToAggregate()).To(MatchError(And(
+			ContainSubstring(`spec.rabbitmq.additionalPlugins[1]: Invalid value: "rabbitmq_shovels": is not shipped with RabbitMQ 4.0.5`),
+			ContainSubstring(`spec.rabbitmq.additionalPlugins[2]: Invalid value: "rabbitmq_shovel_prometheus": is not shipped with RabbitMQ 4.0.5`),
+		)))
+
+		By("accepting any plugin shipped with RabbitMQ if the image has no version")
+		cluster.Spec.Image = "rabbitmq:latest"
+		Expect(cluster.Validate().ToAggregate()).To(MatchError(
+			ContainSubstring(`spec.rabbitmq.additionalPlugins[1]: Invalid value: "rabbitmq_shovels": is not shipped with RabbitMQ;`)))
+		Expect(cluster.Validate()).To(HaveLen(1))
+	})
+
+	It("accepts plugins listed in the community plugins annotation", func() {
+		cluster.Annotations = map[string]string{CommunityPluginsAnnotation: "rabbitmq_delayed_message_exchange, rabbitmq_message_deduplication"}
+		cluster.Spec.Rabbitmq.AdditionalPlugins = []Plugin{"rabbitmq_delayed_message_exchange", "rabbitmq_message_deduplication"}
+		Expect(cluster.Validate()).To(BeEmpty())
+	})
+
+	It("only rejects plugins on update which were not enabled before", func() {
+		cluster.Spec.Image = "rabbitmq:4.0.5-management"
+		cluster.Spec.Rabbitmq.AdditionalPlugins = []Plugin{"rabbitmq_shovels"}
+		old := cluster.DeepCopy()
+		cluster.Spec.Replicas = ptr.To(int32(3))
+		Expect(cluster.ValidateUpdate(old)).To(BeEmpty())
+
+		cluster.Spec.Rabbitmq.AdditionalPlugins = append(cluster.Spec.Rabbitmq.AdditionalPlugins, "rabbitmq_shovel_prometheus")
+		errs := cluster.ValidateUpdate(old)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs.ToAggregate()).To(MatchError(
+			ContainSubstring(`spec.rabbitmq.additionalPlugins[1]: Invalid value: "rabbitmq_shovel_prometheus"`)))
+
+		By("validating all plugins of new clusters")
+		Expect(cluster.Validate()).To(HaveLen(2))
+	})
+
+	It("rejects resource alarm thresholds which are out of range", func() {
+		cluster.Spec.Rabbitmq.DiskFreeLimit = &ResourceAlarmThreshold{
+			Absolute: ptr.To(k8sresource.MustParse("4Gi")),
+			Relative: ptr.To(k8sresource.MustParse("1.5")),
+		}
+		cluster.Spec.Rabbitmq.MemoryHighWatermark = &ResourceAlarmThreshold{Relative: ptr.To(k8sresource.MustParse("1.2"))}
+		errs := cluster.Validate()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs.ToAggregate()).To(MatchError(And(
+			ContainSubstring("spec.rabbitmq.diskFreeLimit: Invalid value"),
+			ContainSubstring("spec.rabbitmq.memoryHighWatermark.relative"),
+		)))
+
+		cluster.Spec.Rabbitmq.DiskFreeLimit = &ResourceAlarmThreshold{Relative: ptr.To(k8sresource.MustParse("1.5"))}
+		cluster.Spec.Rabbitmq.MemoryHighWatermark = &ResourceAlarmThreshold{Relative: ptr.To(k8sresource.MustParse("0.6"))}
+		Expect(cluster.Validate()).To(BeEmpty())
+	})
+
+	It("rejects the ip address type with IPv6 or persistent storage", func() {
+		cluster.Spec.Rabbitmq.PeerDiscovery.AddressType = "ip"
+		cluster.Spec.Service.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+		errs := cluster.Validate()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs.ToAggregate()).To(MatchError(And(
+			ContainSubstring("IPv6"),
+			ContainSubstring("persistent storage"),
+		)))
+
+		cluster.Spec.Service.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+		cluster.Spec.Persistence.Storage = ptr.To(k8sresource.MustParse("0"))
+		Expect(cluster.Validate()).To(BeEmpty())
+	})
+
+	It("returns an Invalid error from the webhook", func() {
+		cluster.Spec.Replicas = ptr.To(int32(-1))
+		_, err := (&RabbitmqClusterValidator{}).ValidateCreate(context.Background(), cluster)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("ValidateImmutableFields", func() {
+	var old, cluster *RabbitmqCluster
+
+	BeforeEach(func() {
+		old = &RabbitmqCluster{ObjectMeta: metav1.ObjectMeta{Name: "rabbit", Namespace: "default"}}
+		old.SetDefaults()
+		old.Spec.Persistence.StorageClassName = ptr.To("fast")
+		cluster = old.DeepCopy()
+	})
+
+	It("accepts changes of mutable fields", func() {
+		cluster.Spec.Replicas = ptr.To(int32(3))
+		cluster.Spec.Persistence.Storage = ptr.To(k8sresource.MustParse("20Gi"))
+		Expect(cluster.ValidateImmutableFields(old)).To(BeEmpty())
+	})
+
+	It("accepts setting the storage class of a cluster which was not defaulted", func() {
+		old.Spec.Persistence.StorageClassName = nil
+		Expect(cluster.ValidateImmutableFields(old)).To(BeEmpty())
+	})
+
+	It("rejects changing the storage class", func() {
+		cluster.Spec.Persistence.StorageClassName = ptr.To("slow")
+		Expect(cluster.ValidateImmutableFields(old).ToAggregate()).To(MatchError(ContainSubstring("spec.persistence.storageClassName: Forbidden")))
+	})
+
+	It("rejects shrinking the storage and switching from ephemeral storage", func() {
+		cluster.Spec.Persistence.Storage = ptr.To(k8sresource.MustParse("5Gi"))
+		Expect(cluster.ValidateImmutableFields(old).ToAggregate()).To(MatchError(ContainSubstring("cannot shrink from 10Gi to 5Gi")))
+
+		old.Spec.Persistence.Storage = ptr.To(k8sresource.MustParse("0"))
+		Expect(cluster.ValidateImmutableFields(old).ToAggregate()).To(MatchError(ContainSubstring("cannot change from 0")))
+	})
+
+	It("rejects changing immutable fields of the StatefulSet override", func() {
+		cluster.Spec.Override.StatefulSet = &StatefulSet{Spec: &StatefulSetSpec{ServiceName: "other"}}
+		Expect(cluster.ValidateImmutableFields(old).ToAggregate()).To(MatchError(ContainSubstring("spec.override.statefulSet.spec.serviceName: Forbidden")))
+	})
+
+	It("returns an Invalid error from the webhook", func() {
+		cluster.Spec.Persistence.StorageClassName = ptr.To("slow")
+		_, err := (&RabbitmqClusterValidator{}).ValidateUpdate(context.Background(), old, cluster)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Namespace allowlist and denylist", func() {
+	var (
+		cluster   *RabbitmqCluster
+		validator *RabbitmqClusterValidator
+	)
+
+	BeforeEach(func() {
+		cluster = &RabbitmqCluster{ObjectMeta: metav1.ObjectMeta{Name: "rabbit", Namespace: "team-a"}}
+		cluster.SetDefaults()
+		validator = &RabbitmqClusterValidator{}
+	})
+
+	It("allows all namespaces by default", func() {
+		_, err := validator.ValidateCreate(context.Background(), cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects namespaces which do not match the allowlist", func() {
+		validator.AllowedNamespaces = []string{"team-*", "shared"}
+		_, err := validator.ValidateCreate(context.Background(), cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		cluster.Namespace = "default"
+		_, err = validator.ValidateCreate(context.Background(), cluster)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("the operator only allows RabbitmqClusters in namespaces team-*, shared")))
+	})
+
+	It("rejects namespaces which match the denylist even if they are allowed", func() {
+		validator.AllowedNamespaces = []string{"team-*"}
+		validator.DeniedNamespaces = []string{"team-a"}
+		_, err := validator.ValidateCreate(context.Background(), cluster)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("the operator denies RabbitmqClusters in namespace team-a")))
+	})
+
+	It("does not reject updates of existing RabbitmqClusters", func() {
+		validator.DeniedNamespaces = []string{"team-a"}
+		_, err := validator.ValidateUpdate(context.Background(), cluster, cluster.DeepCopy())
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("Namespace quota", func() {
+	var (
+		cluster   *RabbitmqCluster
+		validator *RabbitmqClusterValidator
+		checked   []*RabbitmqCluster
+	)
+
+	BeforeEach(func() {
+		cluster = &RabbitmqCluster{ObjectMeta: metav1.ObjectMeta{Name: "rabbit", Namespace: "team-a"}}
+		cluster.SetDefaults()
+		checked = nil
+		validator = &RabbitmqClusterValidator{
+			CheckQuota: func(_ context.Context, cluster, old *RabbitmqCluster) error {
+				checked = append(checked, old)
+				return errors.New("namespace quota exceeded")
+			},
+		}
+	})
+
+	It("rejects RabbitmqClusters exceeding the quota", func() {
+		_, err := validator.ValidateCreate(context.Background(), cluster)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("namespace quota exceeded")))
+
+		old := cluster.DeepCopy()
+		_, err = validator.ValidateUpdate(context.Background(), old, cluster)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(checked).To(Equal([]*RabbitmqCluster{nil, old}))
+	})
+
+	It("does not check the quota of RabbitmqClusters which are being deleted", func() {
+		old := cluster.DeepCopy()
+		cluster.DeletionTimestamp = ptr.To(metav1.Now())
+		_, err := validator.ValidateUpdate(context.Background(), old, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked).To(BeEmpty())
+	})
+})
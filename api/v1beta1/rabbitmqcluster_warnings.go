@@ -0,0 +1,71 @@
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// deprecatedConfigKeys maps rabbitmq.conf keys which are deprecated by RabbitMQ to the keys replacing them.
+var deprecatedConfigKeys = map[string]string{
+	"queue_master_locator": "queue_leader_locator",
+}
+
+// ConfigurationWarnings lists deprecated or risky settings of the RabbitmqCluster. The settings are accepted,
+// but are reported in the NoWarnings condition so that they can be found across clusters.
+func (cluster *RabbitmqCluster) ConfigurationWarnings() []string {
+	var warnings []string
+
+	for _, key := range additionalConfigKeys(cluster.Spec.Rabbitmq.AdditionalConfig) {
+		if replacement, ok := deprecatedConfigKeys[key]; ok {
+			warnings = append(warnings, fmt.Sprintf("spec.rabbitmq.additionalConfig sets %s, which is deprecated in favour of %s", key, replacement))
+		}
+	}
+	if additionalConfigValue(cluster.Spec.Rabbitmq.AdditionalConfig, "cluster_partition_handling") == "ignore" {
+		warnings = append(warnings, "spec.rabbitmq.additionalConfig sets cluster_partition_handling to ignore, so the nodes of the cluster diverge during network partitions")
+	}
+	if cluster.Spec.Replicas != nil && *cluster.Spec.Replicas > 0 && *cluster.Spec.Replicas%2 == 0 {
+		warnings = append(warnings, fmt.Sprintf("spec.replicas is %d; quorum queues and streams tolerate no more node failures than with %d replicas, use an odd number of replicas", *cluster.Spec.Replicas, *cluster.Spec.Replicas-1))
+	}
+	if cluster.Spec.Persistence.Storage != nil && cluster.Spec.Persistence.Storage.Cmp(k8sresource.MustParse("0Gi")) == 0 {
+		warnings = append(warnings, "spec.persistence.storage is 0, so messages and definitions are lost when a Pod is restarted")
+	}
+	return warnings
+}
+
+// additionalConfigKeys returns the keys set in a rabbitmq.conf snippet, in the order they are set.
+func additionalConfigKeys(config string) []string {
+	var keys []string
+	for _, line := range strings.Split(config, "\n") {
+		if key, _, ok := strings.Cut(strings.TrimSpace(line), "="); ok && !strings.HasPrefix(key, "#") {
+			keys = append(keys, strings.TrimSpace(key))
+		}
+	}
+	return keys
+}
+
+// additionalConfigValue returns the value of the last setting of key in a rabbitmq.conf snippet.
+func additionalConfigValue(config, key string) string {
+	var value string
+	for _, line := range strings.Split(config, "\n") {
+		k, v, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && strings.TrimSpace(k) == key {
+			value = strings.TrimSpace(v)
+		}
+	}
+	return value
+}
+
+// AdmissionWarnings lists the configuration warnings and settings which are risky in production, which the validating
+// webhook returns to clients as warnings. production is true if the namespace of the RabbitmqCluster is a production namespace.
+func (cluster *RabbitmqCluster) AdmissionWarnings(production bool) []string {
+	warnings := cluster.ConfigurationWarnings()
+	if cluster.Spec.Resources == nil || cluster.Spec.Resources.Limits.Memory().IsZero() {
+		warnings = append(warnings, "spec.resources.limits.memory is not set, so the memory high watermark of RabbitMQ is relative to the memory of the Kubernetes node instead of the Pod")
+	}
+	if production && !cluster.TLSEnabled() {
+		warnings = append(warnings, "spec.tls.secretName is not set in a production namespace, so clients connect to RabbitMQ without TLS")
+	}
+	return warnings
+}
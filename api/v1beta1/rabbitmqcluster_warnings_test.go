@@ -0,0 +1,91 @@
+package v1beta1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ConfigurationWarnings", func() {
+	var cluster *RabbitmqCluster
+
+	BeforeEach(func() {
+		cluster = &RabbitmqCluster{}
+		cluster.Spec.Replicas = ptr.To(int32(3))
+		cluster.Spec.Persistence.Storage = ptr.To(k8sresource.MustParse("10Gi"))
+	})
+
+	It("returns no warnings for a default cluster", func() {
+		Expect(cluster.ConfigurationWarnings()).To(BeEmpty())
+	})
+
+	It("warns about an even number of replicas", func() {
+		cluster.Spec.Replicas = ptr.To(int32(2))
+		Expect(cluster.ConfigurationWarnings()).To(ConsistOf(ContainSubstring("spec.replicas is 2")))
+	})
+
+	It("warns about a cluster without persistence", func() {
+		cluster.Spec.Persistence.Storage = ptr.To(k8sresource.MustParse("0"))
+		Expect(cluster.ConfigurationWarnings()).To(ConsistOf(ContainSubstring("spec.persistence.storage is 0")))
+	})
+
+	It("warns about partition handling set to ignore", func() {
+		cluster.Spec.Rabbitmq.AdditionalConfig = "cluster_partition_handling = autoheal\ncluster_partition_handling = ignore"
+		Expect(cluster.ConfigurationWarnings()).To(ConsistOf(ContainSubstring("cluster_partition_handling to ignore")))
+	})
+
+	It("warns about deprecated configuration keys", func() {
+		cluster.Spec.Rabbitmq.AdditionalConfig = "# queue_master_locator = min-masters\nqueue_master_locator = min-masters"
+		Expect(cluster.ConfigurationWarnings()).To(ConsistOf(ContainSubstring("queue_master_locator, which is deprecated in favour of queue_leader_locator")))
+	})
+})
+
+var _ = Describe("AdmissionWarnings", func() {
+	var cluster *RabbitmqCluster
+
+	BeforeEach(func() {
+		cluster = &RabbitmqCluster{ObjectMeta: metav1.ObjectMeta{Name: "rabbit", Namespace: "production"}}
+		cluster.SetDefaults()
+		cluster.Spec.Resources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("2Gi")},
+		}
+	})
+
+	It("returns no warnings for a cluster with memory limit outside production", func() {
+		Expect(cluster.AdmissionWarnings(false)).To(BeEmpty())
+	})
+
+	It("includes the configuration warnings", func() {
+		cluster.Spec.Replicas = ptr.To(int32(2))
+		Expect(cluster.AdmissionWarnings(false)).To(ConsistOf(ContainSubstring("spec.replicas is 2")))
+	})
+
+	It("warns about a cluster without memory limit", func() {
+		cluster.Spec.Resources = nil
+		Expect(cluster.AdmissionWarnings(false)).To(ConsistOf(ContainSubstring("spec.resources.limits.memory is not set")))
+	})
+
+	It("warns about a cluster without TLS in production namespaces", func() {
+		Expect(cluster.AdmissionWarnings(true)).To(ConsistOf(ContainSubstring("without TLS")))
+		cluster.Spec.TLS.SecretName = "tls"
+		Expect(cluster.AdmissionWarnings(true)).To(BeEmpty())
+	})
+
+	It("is returned by the webhook for namespaces matching the production selector", func() {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production", Labels: map[string]string{"environment": "production"}}}
+		validator := &RabbitmqClusterValidator{
+			Client:                      fake.NewClientBuilder().WithObjects(namespace).Build(),
+			ProductionNamespaceSelector: labels.SelectorFromSet(labels.Set{"environment": "production"}),
+		}
+		warnings, err := validator.ValidateCreate(context.Background(), cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("without TLS")))
+	})
+})
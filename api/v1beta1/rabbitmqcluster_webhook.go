@@ -0,0 +1,187 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/mutate-rabbitmq-com-v1beta1-rabbitmqcluster,mutating=true,failurePolicy=ignore,sideEffects=None,groups=rabbitmq.com,resources=rabbitmqclusters,verbs=create;update,versions=v1beta1,name=mrabbitmqcluster.rabbitmq.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-rabbitmq-com-v1beta1-rabbitmqcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=rabbitmq.com,resources=rabbitmqclusters,verbs=create;update,versions=v1beta1,name=vrabbitmqcluster.rabbitmq.com,admissionReviewVersions=v1
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+
+// +kubebuilder:object:generate=false
+
+// RabbitmqClusterDefaulter is the defaulting webhook of RabbitmqClusters. Setting the defaults at admission time makes the
+// effective spec visible, e.g. in kubectl get -o yaml, and keeps diffs of GitOps tools stable.
+// The webhook ignores failures, since the reconciler sets the same defaults if the webhook is not reachable.
+type RabbitmqClusterDefaulter struct {
+	Defaults OperatorDefaults
+}
+
+var _ admission.CustomDefaulter = &RabbitmqClusterDefaulter{}
+
+// Default sets the defaults of the CRD schema and of the operator configuration.
+func (d *RabbitmqClusterDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*RabbitmqCluster)
+	if !ok {
+		return fmt.Errorf("expected a RabbitmqCluster but got %T", obj)
+	}
+	cluster.SetDefaults()
+	req, err := admission.RequestFromContext(ctx)
+	cluster.SetOperatorDefaults(d.Defaults, err == nil && req.Operation == admissionv1.Create)
+	return nil
+}
+
+// +kubebuilder:object:generate=false
+
+// RabbitmqClusterValidator is the validating webhook of RabbitmqClusters. It rejects specs which cannot be deployed
+// at admission time, instead of the reconciler failing on every reconcile, and warns about risky settings.
+// The webhook does not ignore failures, so RabbitmqClusters cannot be created or updated while it is unreachable.
+type RabbitmqClusterValidator struct {
+	// Client reads the namespaces of RabbitmqClusters.
+	Client client.Reader
+	// ProductionNamespaceSelector selects the namespaces in which RabbitmqClusters without TLS are warned about.
+	// No namespace is selected if it is nil or empty.
+	ProductionNamespaceSelector labels.Selector
+	// AllowedNamespaces are the namespaces in which RabbitmqClusters can be created, as patterns of path.Match,
+	// e.g. "team-*". RabbitmqClusters can be created in all namespaces if it is empty.
+	AllowedNamespaces []string
+	// DeniedNamespaces are the namespaces in which RabbitmqClusters cannot be created, even if they are allowed.
+	DeniedNamespaces []string
+	// CheckQuota returns an error if a created or updated RabbitmqCluster exceeds the quota of its namespace.
+	// old is nil for created RabbitmqClusters. Quotas are not enforced if it is nil.
+	CheckQuota func(ctx context.Context, cluster, old *RabbitmqCluster) error
+}
+
+var _ admission.CustomValidator = &RabbitmqClusterValidator{}
+
+// ValidateCreate validates a new RabbitmqCluster.
+func (v *RabbitmqClusterValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*RabbitmqCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a RabbitmqCluster but got %T", obj)
+	}
+	if err := v.checkNamespace(cluster.Namespace); err != nil {
+		return nil, forbidden(cluster, err)
+	}
+	if err := v.checkQuota(ctx, cluster, nil); err != nil {
+		return nil, err
+	}
+	return v.warnings(ctx, cluster), invalid(cluster, cluster.Validate())
+}
+
+// ValidateUpdate validates an updated RabbitmqCluster, and rejects changes of fields which are immutable
+// in the StatefulSet. RabbitmqClusters which are being deleted are not validated,
+// so that removing their finalizer always succeeds.
+func (v *RabbitmqClusterValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := newObj.(*RabbitmqCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a RabbitmqCluster but got %T", newObj)
+	}
+	old, ok := oldObj.(*RabbitmqCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a RabbitmqCluster but got %T", oldObj)
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	if err := v.checkQuota(ctx, cluster, old); err != nil {
+		return nil, err
+	}
+	return v.warnings(ctx, cluster), invalid(cluster, append(cluster.ValidateUpdate(old), cluster.ValidateImmutableFields(old)...))
+}
+
+// ValidateDelete accepts all deletions.
+func (v *RabbitmqClusterValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// checkNamespace returns an error if RabbitmqClusters cannot be created in the namespace. Existing RabbitmqClusters
+// are not affected, so that they can still be updated and deleted after the namespace is denied.
+func (v *RabbitmqClusterValidator) checkNamespace(namespace string) error {
+	if matchesAny(namespace, v.DeniedNamespaces) {
+		return fmt.Errorf("the operator denies RabbitmqClusters in namespace %s", namespace)
+	}
+	if len(v.AllowedNamespaces) > 0 && !matchesAny(namespace, v.AllowedNamespaces) {
+		return fmt.Errorf("the operator only allows RabbitmqClusters in namespaces %s", strings.Join(v.AllowedNamespaces, ", "))
+	}
+	return nil
+}
+
+// checkQuota returns a Forbidden error if the RabbitmqCluster exceeds the quota of its namespace.
+func (v *RabbitmqClusterValidator) checkQuota(ctx context.Context, cluster, old *RabbitmqCluster) error {
+	if v.CheckQuota == nil {
+		return nil
+	}
+	if err := v.CheckQuota(ctx, cluster, old); err != nil {
+		return forbidden(cluster, err)
+	}
+	return nil
+}
+
+// matchesAny returns true if the name matches one of the patterns. Malformed patterns match no name.
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// warnings returns the admission warnings of a RabbitmqCluster. A namespace which cannot be read is not
+// considered a production namespace, so that warnings never block admission.
+func (v *RabbitmqClusterValidator) warnings(ctx context.Context, cluster *RabbitmqCluster) admission.Warnings {
+	production := false
+	if v.Client != nil && v.ProductionNamespaceSelector != nil && !v.ProductionNamespaceSelector.Empty() {
+		namespace := &corev1.Namespace{}
+		if err := v.Client.Get(ctx, client.ObjectKey{Name: cluster.Namespace}, namespace); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to read namespace of RabbitmqCluster", "namespace", cluster.Namespace)
+		} else {
+			production = v.ProductionNamespaceSelector.Matches(labels.Set(namespace.Labels))
+		}
+	}
+	return cluster.AdmissionWarnings(production)
+}
+
+// forbidden returns a Forbidden error of the API server for the RabbitmqCluster.
+func forbidden(cluster *RabbitmqCluster, err error) error {
+	return apierrors.NewForbidden(GroupVersion.WithResource("rabbitmqclusters").GroupResource(), cluster.Name, err)
+}
+
+// invalid returns an Invalid error of the API server listing the errors, or nil if there are none.
+func invalid(cluster *RabbitmqCluster, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("RabbitmqCluster").GroupKind(), cluster.Name, errs)
+}
+
+// SetupWebhookWithManager registers the webhooks of RabbitmqClusters with the webhook server of the manager.
+func (r *RabbitmqCluster) SetupWebhookWithManager(mgr ctrl.Manager, defaulter *RabbitmqClusterDefaulter, validator *RabbitmqClusterValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(defaulter).
+		WithValidator(validator).
+		Complete()
+}
@@ -22,6 +22,97 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSISpec) DeepCopyInto(out *CSISpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSISpec.
+func (in *CSISpec) DeepCopy() *CSISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CSISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigFragmentSource) DeepCopyInto(out *ConfigFragmentSource) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigFragmentSource.
+func (in *ConfigFragmentSource) DeepCopy() *ConfigFragmentSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigFragmentSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigFromSource) DeepCopyInto(out *ConfigFromSource) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigFromSource.
+func (in *ConfigFromSource) DeepCopy() *ConfigFromSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigFromSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionsSource) DeepCopyInto(out *DefinitionsSource) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionsSource.
+func (in *DefinitionsSource) DeepCopy() *DefinitionsSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionsSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DistributionTLSSpec) DeepCopyInto(out *DistributionTLSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DistributionTLSSpec.
+func (in *DistributionTLSSpec) DeepCopy() *DistributionTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DistributionTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedLabelsAnnotations) DeepCopyInto(out *EmbeddedLabelsAnnotations) {
 	*out = *in
@@ -80,6 +171,97 @@ func (in *EmbeddedObjectMeta) DeepCopy() *EmbeddedObjectMeta {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogCategory) DeepCopyInto(out *LogCategory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCategory.
+func (in *LogCategory) DeepCopy() *LogCategory {
+	if in == nil {
+		return nil
+	}
+	out := new(LogCategory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTSpec) DeepCopyInto(out *MQTTSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTSpec.
+func (in *MQTTSpec) DeepCopy() *MQTTSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2ManagementSpec) DeepCopyInto(out *OAuth2ManagementSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ManagementSpec.
+func (in *OAuth2ManagementSpec) DeepCopy() *OAuth2ManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2ManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2SigningKey) DeepCopyInto(out *OAuth2SigningKey) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2SigningKey.
+func (in *OAuth2SigningKey) DeepCopy() *OAuth2SigningKey {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2SigningKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2Spec) DeepCopyInto(out *OAuth2Spec) {
+	*out = *in
+	if in.ScopePrefix != nil {
+		in, out := &in.ScopePrefix, &out.ScopePrefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.SigningKey != nil {
+		in, out := &in.SigningKey, &out.SigningKey
+		*out = new(OAuth2SigningKey)
+		**out = **in
+	}
+	if in.Management != nil {
+		in, out := &in.Management, &out.Management
+		*out = new(OAuth2ManagementSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2Spec.
+func (in *OAuth2Spec) DeepCopy() *OAuth2Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PersistentVolumeClaim) DeepCopyInto(out *PersistentVolumeClaim) {
 	*out = *in
@@ -150,6 +332,42 @@ func (in *RabbitmqCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterAuthSpec) DeepCopyInto(out *RabbitmqClusterAuthSpec) {
+	*out = *in
+	if in.OAuth2 != nil {
+		in, out := &in.OAuth2, &out.OAuth2
+		*out = new(OAuth2Spec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterAuthSpec.
+func (in *RabbitmqClusterAuthSpec) DeepCopy() *RabbitmqClusterAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterChange) DeepCopyInto(out *RabbitmqClusterChange) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterChange.
+func (in *RabbitmqClusterChange) DeepCopy() *RabbitmqClusterChange {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RabbitmqClusterConfigurationSpec) DeepCopyInto(out *RabbitmqClusterConfigurationSpec) {
 	*out = *in
@@ -158,6 +376,52 @@ func (in *RabbitmqClusterConfigurationSpec) DeepCopyInto(out *RabbitmqClusterCon
 		*out = make([]Plugin, len(*in))
 		copy(*out, *in)
 	}
+	if in.DefaultUserTags != nil {
+		in, out := &in.DefaultUserTags, &out.DefaultUserTags
+		*out = make([]UserTag, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigFrom != nil {
+		in, out := &in.ConfigFrom, &out.ConfigFrom
+		*out = new(ConfigFromSource)
+		**out = **in
+	}
+	if in.Definitions != nil {
+		in, out := &in.Definitions, &out.Definitions
+		*out = new(DefinitionsSource)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Auth.DeepCopyInto(&out.Auth)
+	if in.MQTT != nil {
+		in, out := &in.MQTT, &out.MQTT
+		*out = new(MQTTSpec)
+		**out = **in
+	}
+	if in.STOMP != nil {
+		in, out := &in.STOMP, &out.STOMP
+		*out = new(STOMPSpec)
+		**out = **in
+	}
+	in.Logging.DeepCopyInto(&out.Logging)
+	out.Metrics = in.Metrics
+	in.PeerDiscovery.DeepCopyInto(&out.PeerDiscovery)
+	if in.DiskFreeLimit != nil {
+		in, out := &in.DiskFreeLimit, &out.DiskFreeLimit
+		*out = new(ResourceAlarmThreshold)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemoryHighWatermark != nil {
+		in, out := &in.MemoryHighWatermark, &out.MemoryHighWatermark
+		*out = new(ResourceAlarmThreshold)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalUsers != nil {
+		in, out := &in.AdditionalUsers, &out.AdditionalUsers
+		*out = make([]RabbitmqUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterConfigurationSpec.
@@ -195,6 +459,60 @@ func (in *RabbitmqClusterDefaultUser) DeepCopy() *RabbitmqClusterDefaultUser {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterGatewaySpec) DeepCopyInto(out *RabbitmqClusterGatewaySpec) {
+	*out = *in
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterGatewaySpec.
+func (in *RabbitmqClusterGatewaySpec) DeepCopy() *RabbitmqClusterGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterHeadlessServiceSpec) DeepCopyInto(out *RabbitmqClusterHeadlessServiceSpec) {
+	*out = *in
+	if in.PublishNotReadyAddresses != nil {
+		in, out := &in.PublishNotReadyAddresses, &out.PublishNotReadyAddresses
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdditionalPorts != nil {
+		in, out := &in.AdditionalPorts, &out.AdditionalPorts
+		*out = make([]v1.ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterHeadlessServiceSpec.
+func (in *RabbitmqClusterHeadlessServiceSpec) DeepCopy() *RabbitmqClusterHeadlessServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterHeadlessServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RabbitmqClusterList) DeepCopyInto(out *RabbitmqClusterList) {
 	*out = *in
@@ -224,55 +542,306 @@ func (in *RabbitmqClusterList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
-	return nil
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterLoggingSpec) DeepCopyInto(out *RabbitmqClusterLoggingSpec) {
+	*out = *in
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]LogCategory, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterLoggingSpec.
+func (in *RabbitmqClusterLoggingSpec) DeepCopy() *RabbitmqClusterLoggingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterLoggingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterManagementIngressSpec) DeepCopyInto(out *RabbitmqClusterManagementIngressSpec) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterManagementIngressSpec.
+func (in *RabbitmqClusterManagementIngressSpec) DeepCopy() *RabbitmqClusterManagementIngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterManagementIngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterManagementServiceSpec) DeepCopyInto(out *RabbitmqClusterManagementServiceSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterManagementServiceSpec.
+func (in *RabbitmqClusterManagementServiceSpec) DeepCopy() *RabbitmqClusterManagementServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterManagementServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterMetricsSpec) DeepCopyInto(out *RabbitmqClusterMetricsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterMetricsSpec.
+func (in *RabbitmqClusterMetricsSpec) DeepCopy() *RabbitmqClusterMetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterMetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterMonitoringSpec) DeepCopyInto(out *RabbitmqClusterMonitoringSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Relabelings != nil {
+		in, out := &in.Relabelings, &out.Relabelings
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(RabbitmqClusterMonitoringTLSSpec)
+		**out = **in
+	}
+	if in.PrometheusRule != nil {
+		in, out := &in.PrometheusRule, &out.PrometheusRule
+		*out = new(RabbitmqClusterPrometheusRuleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterMonitoringSpec.
+func (in *RabbitmqClusterMonitoringSpec) DeepCopy() *RabbitmqClusterMonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterMonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterMonitoringTLSSpec) DeepCopyInto(out *RabbitmqClusterMonitoringTLSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterMonitoringTLSSpec.
+func (in *RabbitmqClusterMonitoringTLSSpec) DeepCopy() *RabbitmqClusterMonitoringTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterMonitoringTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterNodeStatus) DeepCopyInto(out *RabbitmqClusterNodeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterNodeStatus.
+func (in *RabbitmqClusterNodeStatus) DeepCopy() *RabbitmqClusterNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterOverrideSpec) DeepCopyInto(out *RabbitmqClusterOverrideSpec) {
+	*out = *in
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(StatefulSet)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(Service)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterOverrideSpec.
+func (in *RabbitmqClusterOverrideSpec) DeepCopy() *RabbitmqClusterOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterPeerDiscoverySpec) DeepCopyInto(out *RabbitmqClusterPeerDiscoverySpec) {
+	*out = *in
+	if in.DiscoveryRetryLimit != nil {
+		in, out := &in.DiscoveryRetryLimit, &out.DiscoveryRetryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DiscoveryRetryIntervalMilliseconds != nil {
+		in, out := &in.DiscoveryRetryIntervalMilliseconds, &out.DiscoveryRetryIntervalMilliseconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterPeerDiscoverySpec.
+func (in *RabbitmqClusterPeerDiscoverySpec) DeepCopy() *RabbitmqClusterPeerDiscoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterPeerDiscoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterPersistenceSpec) DeepCopyInto(out *RabbitmqClusterPersistenceSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterPersistenceSpec.
+func (in *RabbitmqClusterPersistenceSpec) DeepCopy() *RabbitmqClusterPersistenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterPersistenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterPodServicesSpec) DeepCopyInto(out *RabbitmqClusterPodServicesSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterPodServicesSpec.
+func (in *RabbitmqClusterPodServicesSpec) DeepCopy() *RabbitmqClusterPodServicesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterPodServicesSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RabbitmqClusterOverrideSpec) DeepCopyInto(out *RabbitmqClusterOverrideSpec) {
+func (in *RabbitmqClusterPrometheusRuleSpec) DeepCopyInto(out *RabbitmqClusterPrometheusRuleSpec) {
 	*out = *in
-	if in.StatefulSet != nil {
-		in, out := &in.StatefulSet, &out.StatefulSet
-		*out = new(StatefulSet)
-		(*in).DeepCopyInto(*out)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(Service)
-		(*in).DeepCopyInto(*out)
+	if in.AlertLabels != nil {
+		in, out := &in.AlertLabels, &out.AlertLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterOverrideSpec.
-func (in *RabbitmqClusterOverrideSpec) DeepCopy() *RabbitmqClusterOverrideSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterPrometheusRuleSpec.
+func (in *RabbitmqClusterPrometheusRuleSpec) DeepCopy() *RabbitmqClusterPrometheusRuleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RabbitmqClusterOverrideSpec)
+	out := new(RabbitmqClusterPrometheusRuleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RabbitmqClusterPersistenceSpec) DeepCopyInto(out *RabbitmqClusterPersistenceSpec) {
+func (in *RabbitmqClusterRouteSpec) DeepCopyInto(out *RabbitmqClusterRouteSpec) {
 	*out = *in
-	if in.StorageClassName != nil {
-		in, out := &in.StorageClassName, &out.StorageClassName
-		*out = new(string)
-		**out = **in
-	}
-	if in.Storage != nil {
-		in, out := &in.Storage, &out.Storage
-		x := (*in).DeepCopy()
-		*out = &x
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterPersistenceSpec.
-func (in *RabbitmqClusterPersistenceSpec) DeepCopy() *RabbitmqClusterPersistenceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterRouteSpec.
+func (in *RabbitmqClusterRouteSpec) DeepCopy() *RabbitmqClusterRouteSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RabbitmqClusterPersistenceSpec)
+	out := new(RabbitmqClusterRouteSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -329,6 +898,38 @@ func (in *RabbitmqClusterServiceSpec) DeepCopyInto(out *RabbitmqClusterServiceSp
 		*out = new(v1.IPFamilyPolicy)
 		**out = **in
 	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]v1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodePorts != nil {
+		in, out := &in.NodePorts, &out.NodePorts
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheckNodePort != nil {
+		in, out := &in.HealthCheckNodePort, &out.HealthCheckNodePort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SessionAffinityTimeoutSeconds != nil {
+		in, out := &in.SessionAffinityTimeoutSeconds, &out.SessionAffinityTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InternalTrafficPolicy != nil {
+		in, out := &in.InternalTrafficPolicy, &out.InternalTrafficPolicy
+		*out = new(v1.ServiceInternalTrafficPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterServiceSpec.
@@ -355,6 +956,37 @@ func (in *RabbitmqClusterSpec) DeepCopyInto(out *RabbitmqClusterSpec) {
 		copy(*out, *in)
 	}
 	in.Service.DeepCopyInto(&out.Service)
+	in.HeadlessService.DeepCopyInto(&out.HeadlessService)
+	if in.ManagementService != nil {
+		in, out := &in.ManagementService, &out.ManagementService
+		*out = new(RabbitmqClusterManagementServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodServices != nil {
+		in, out := &in.PodServices, &out.PodServices
+		*out = new(RabbitmqClusterPodServicesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagementIngress != nil {
+		in, out := &in.ManagementIngress, &out.ManagementIngress
+		*out = new(RabbitmqClusterManagementIngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(RabbitmqClusterRouteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(RabbitmqClusterGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(RabbitmqClusterMonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Persistence.DeepCopyInto(&out.Persistence)
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
@@ -374,7 +1006,21 @@ func (in *RabbitmqClusterSpec) DeepCopyInto(out *RabbitmqClusterSpec) {
 		}
 	}
 	in.Rabbitmq.DeepCopyInto(&out.Rabbitmq)
-	out.TLS = in.TLS
+	if in.AdditionalConfigMaps != nil {
+		in, out := &in.AdditionalConfigMaps, &out.AdditionalConfigMaps
+		*out = make([]ConfigFragmentSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalSecrets != nil {
+		in, out := &in.AdditionalSecrets, &out.AdditionalSecrets
+		*out = make([]ConfigFragmentSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.TLS.DeepCopyInto(&out.TLS)
 	in.Override.DeepCopyInto(&out.Override)
 	if in.TerminationGracePeriodSeconds != nil {
 		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
@@ -387,6 +1033,16 @@ func (in *RabbitmqClusterSpec) DeepCopyInto(out *RabbitmqClusterSpec) {
 		**out = **in
 	}
 	in.SecretBackend.DeepCopyInto(&out.SecretBackend)
+	if in.RemoteCluster != nil {
+		in, out := &in.RemoteCluster, &out.RemoteCluster
+		*out = new(RemoteClusterSpec)
+		**out = **in
+	}
+	if in.RolloutAnalysis != nil {
+		in, out := &in.RolloutAnalysis, &out.RolloutAnalysis
+		*out = new(RolloutAnalysisSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterSpec.
@@ -419,6 +1075,33 @@ func (in *RabbitmqClusterStatus) DeepCopyInto(out *RabbitmqClusterStatus) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]RabbitmqClusterChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(RabbitmqClusterTLSStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalUsers != nil {
+		in, out := &in.AdditionalUsers, &out.AdditionalUsers
+		*out = make([]RabbitmqClusterUserStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]RabbitmqClusterNodeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(RabbitmqClusterUpgradeStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterStatus.
@@ -431,6 +1114,233 @@ func (in *RabbitmqClusterStatus) DeepCopy() *RabbitmqClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterTLSStatus) DeepCopyInto(out *RabbitmqClusterTLSStatus) {
+	*out = *in
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterTLSStatus.
+func (in *RabbitmqClusterTLSStatus) DeepCopy() *RabbitmqClusterTLSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterTLSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterUpgradeStatus) DeepCopyInto(out *RabbitmqClusterUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterUpgradeStatus.
+func (in *RabbitmqClusterUpgradeStatus) DeepCopy() *RabbitmqClusterUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqClusterUserStatus) DeepCopyInto(out *RabbitmqClusterUserStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqClusterUserStatus.
+func (in *RabbitmqClusterUserStatus) DeepCopy() *RabbitmqClusterUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqClusterUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqUser) DeepCopyInto(out *RabbitmqUser) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]RabbitmqUserPermissions, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqUser.
+func (in *RabbitmqUser) DeepCopy() *RabbitmqUser {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitmqUserPermissions) DeepCopyInto(out *RabbitmqUserPermissions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitmqUserPermissions.
+func (in *RabbitmqUserPermissions) DeepCopy() *RabbitmqUserPermissions {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitmqUserPermissions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replacement != nil {
+		in, out := &in.Replacement, &out.Replacement
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelabelConfig.
+func (in *RelabelConfig) DeepCopy() *RelabelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RelabelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterSpec) DeepCopyInto(out *RemoteClusterSpec) {
+	*out = *in
+	out.KubeconfigSecret = in.KubeconfigSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterSpec.
+func (in *RemoteClusterSpec) DeepCopy() *RemoteClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceAlarmThreshold) DeepCopyInto(out *ResourceAlarmThreshold) {
+	*out = *in
+	if in.Absolute != nil {
+		in, out := &in.Absolute, &out.Absolute
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Relative != nil {
+		in, out := &in.Relative, &out.Relative
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceAlarmThreshold.
+func (in *ResourceAlarmThreshold) DeepCopy() *ResourceAlarmThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceAlarmThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutAnalysisQuery) DeepCopyInto(out *RolloutAnalysisQuery) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutAnalysisQuery.
+func (in *RolloutAnalysisQuery) DeepCopy() *RolloutAnalysisQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutAnalysisQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutAnalysisSpec) DeepCopyInto(out *RolloutAnalysisSpec) {
+	*out = *in
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]RolloutAnalysisQuery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutAnalysisSpec.
+func (in *RolloutAnalysisSpec) DeepCopy() *RolloutAnalysisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutAnalysisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *STOMPSpec) DeepCopyInto(out *STOMPSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new STOMPSpec.
+func (in *STOMPSpec) DeepCopy() *STOMPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(STOMPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretBackend) DeepCopyInto(out *SecretBackend) {
 	*out = *in
@@ -439,7 +1349,14 @@ func (in *SecretBackend) DeepCopyInto(out *SecretBackend) {
 		*out = new(VaultSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(CSISpec)
+		**out = **in
+	}
 	out.ExternalSecret = in.ExternalSecret
+	out.ExistingAdminSecret = in.ExistingAdminSecret
+	out.ExistingErlangCookieSecret = in.ExistingErlangCookieSecret
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretBackend.
@@ -552,6 +1469,11 @@ func (in *StatefulSetSpec) DeepCopy() *StatefulSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
 	*out = *in
+	if in.Distribution != nil {
+		in, out := &in.Distribution, &out.Distribution
+		*out = new(DistributionTLSSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSpec.
@@ -0,0 +1,12 @@
+package controllers
+
+import (
+	"context"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+)
+
+// UpdateStatus exposes updateStatus to the tests of concurrent status writes.
+func (r *RabbitmqClusterReconciler) UpdateStatus(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, mutate func(*rabbitmqv1beta1.RabbitmqClusterStatus)) error {
+	return r.updateStatus(ctx, rmq, mutate)
+}
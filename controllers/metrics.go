@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// tlsCertificateExpiry exposes status.tls.notAfter of each RabbitmqCluster on the metrics endpoint of the operator,
+// so that certificate expiry can be alerted on.
+var tlsCertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "rabbitmq_cluster_operator_tls_certificate_expiry_timestamp_seconds",
+	Help: "Expiry of the TLS certificate loaded by the nodes of a RabbitmqCluster, in seconds since the Unix epoch.",
+}, []string{"namespace", "rabbitmqcluster"})
+
+// The metrics below describe each RabbitmqCluster, so that dashboards and SLOs can be built for all RabbitmqClusters
+// managed by the operator. controller-runtime only exports the reconcile metrics of the whole controller.
+var (
+	clusterInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rabbitmqcluster_info",
+		Help: "Information about a RabbitmqCluster. The version is the RabbitMQ version running on its nodes, empty until it is known.",
+	}, []string{"namespace", "name", "version"})
+
+	clusterPaused = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rabbitmqcluster_paused",
+		Help: "Whether the reconciliation of a RabbitmqCluster is paused by a label.",
+	}, []string{"namespace", "name"})
+
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rabbitmqcluster_reconcile_duration_seconds",
+		Help:    "Duration of the reconciliations of a RabbitmqCluster.",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"namespace", "name"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmqcluster_reconcile_errors_total",
+		Help: "Number of reconciliations of a RabbitmqCluster which returned an error.",
+	}, []string{"namespace", "name"})
+
+	childResourcesCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmqcluster_child_resources_created_total",
+		Help: "Number of child resources created for a RabbitmqCluster, by kind.",
+	}, []string{"namespace", "name", "kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(tlsCertificateExpiry, clusterInfo, clusterPaused, reconcileDuration, reconcileErrors, childResourcesCreated)
+}
+
+// setTLSCertificateExpiryMetric sets the certificate expiry metric of the RabbitmqCluster from status.tls.notAfter,
+// or removes it if the expiry is not known.
+func setTLSCertificateExpiryMetric(rmq *rabbitmqv1beta1.RabbitmqCluster) {
+	if rmq.Status.TLS == nil || rmq.Status.TLS.NotAfter == nil {
+		deleteTLSCertificateExpiryMetric(types.NamespacedName{Namespace: rmq.Namespace, Name: rmq.Name})
+		return
+	}
+	tlsCertificateExpiry.WithLabelValues(rmq.Namespace, rmq.Name).Set(float64(rmq.Status.TLS.NotAfter.Unix()))
+}
+
+func deleteTLSCertificateExpiryMetric(name types.NamespacedName) {
+	tlsCertificateExpiry.DeleteLabelValues(name.Namespace, name.Name)
+}
+
+// observeReconcile records a reconciliation of the RabbitmqCluster which took the given duration and returned err.
+func observeReconcile(rmq *rabbitmqv1beta1.RabbitmqCluster, duration time.Duration, err error) {
+	// the previous version is replaced when it changes
+	clusterInfo.DeletePartialMatch(prometheus.Labels{"namespace": rmq.Namespace, "name": rmq.Name})
+	clusterInfo.WithLabelValues(rmq.Namespace, rmq.Name, rmq.Status.RabbitmqVersion).Set(1)
+
+	paused := 0.0
+	if _, ok := reconciliationPausedBy(rmq); ok {
+		paused = 1
+	}
+	clusterPaused.WithLabelValues(rmq.Namespace, rmq.Name).Set(paused)
+
+	reconcileDuration.WithLabelValues(rmq.Namespace, rmq.Name).Observe(duration.Seconds())
+	// the counter is created before the first error, so that the error rate is known for all RabbitmqClusters
+	failures := reconcileErrors.WithLabelValues(rmq.Namespace, rmq.Name)
+	if err != nil {
+		failures.Inc()
+	}
+}
+
+func recordChildResourceCreated(rmq *rabbitmqv1beta1.RabbitmqCluster, kind string) {
+	childResourcesCreated.WithLabelValues(rmq.Namespace, rmq.Name, kind).Inc()
+}
+
+// deleteClusterMetrics removes the metrics of a deleted RabbitmqCluster.
+func deleteClusterMetrics(name types.NamespacedName) {
+	deleteTLSCertificateExpiryMetric(name)
+	labels := prometheus.Labels{"namespace": name.Namespace, "name": name.Name}
+	clusterInfo.DeletePartialMatch(labels)
+	clusterPaused.Delete(labels)
+	reconcileDuration.Delete(labels)
+	reconcileErrors.Delete(labels)
+	childResourcesCreated.DeletePartialMatch(labels)
+}
@@ -0,0 +1,58 @@
+package controllers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Metrics", func() {
+	var cluster *rabbitmqv1beta1.RabbitmqCluster
+
+	// clusterMetrics returns the metric families with at least one series of the RabbitmqCluster
+	clusterMetrics := func() map[string]*dto.MetricFamily {
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		found := map[string]*dto.MetricFamily{}
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				labels := map[string]string{}
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				if labels["namespace"] == cluster.Namespace && labels["name"] == cluster.Name {
+					found[family.GetName()] = family
+				}
+			}
+		}
+		return found
+	}
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-metrics",
+				Namespace: "default",
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+	})
+
+	It("exports the metrics of the RabbitmqCluster until it is deleted", func() {
+		Eventually(clusterMetrics, 5).Should(SatisfyAll(
+			HaveKey("rabbitmqcluster_info"),
+			HaveKey("rabbitmqcluster_paused"),
+			HaveKey("rabbitmqcluster_reconcile_duration_seconds"),
+			HaveKey("rabbitmqcluster_reconcile_errors_total"),
+			HaveKey("rabbitmqcluster_child_resources_created_total"),
+		))
+
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+		Eventually(clusterMetrics, 5).Should(BeEmpty())
+	})
+})
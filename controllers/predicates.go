@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// rabbitmqClusterChangedPredicate ignores updates of RabbitmqClusters which only change their status,
+// including the status updates of the operator itself. Labels and annotations are checked as well, since they
+// pause reconciliation and request operations such as password rotations.
+func rabbitmqClusterChangedPredicate() predicate.Predicate {
+	return predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})
+}
+
+// childResourceChangedPredicate ignores updates of child resources which only change their status or metadata maintained
+// by the API server, e.g. the load balancer status of Services. Child resources without generation such as ConfigMaps
+// are compared as a whole, so that changes made outside the operator are still reverted.
+// StatefulSets are not filtered, since their status drives rollouts.
+func childResourceChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return true
+			}
+			return !equality.Semantic.DeepEqual(withoutStatus(e.ObjectOld), withoutStatus(e.ObjectNew))
+		},
+	}
+}
+
+// withoutStatus returns the content of an object without its status and the metadata which changes on every write.
+func withoutStatus(obj client.Object) map[string]interface{} {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil
+	}
+	delete(content, "status")
+	if objectMeta, ok := content["metadata"].(map[string]interface{}); ok {
+		delete(objectMeta, "resourceVersion")
+		delete(objectMeta, "managedFields")
+	}
+	return content
+}
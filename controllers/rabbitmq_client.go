@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/rabbitmqclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// rabbitmqClient returns a client of the management API at the given host, e.g. rabbitmqclient.PodHost(),
+// which authenticates as the default user. The CA certificate of spec.tls verifies the management API if it only serves TLS.
+func (r *RabbitmqClusterReconciler) rabbitmqClient(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, host string) (rabbitmqclient.Client, error) {
+	credentials := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: rmq.ChildResourceName("default-user")}, credentials); err != nil {
+		return nil, fmt.Errorf("failed to read the default user credentials: %w", err)
+	}
+
+	opts := rabbitmqclient.Options{}
+	if rmq.SecretTLSEnabled() && rmq.DisableNonTLSListeners() {
+		caSecretName := rmq.Spec.TLS.CaSecretName
+		if caSecretName == "" {
+			caSecretName = rmq.Spec.TLS.SecretName
+		}
+		caSecret := &corev1.Secret{}
+		if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: caSecretName}, caSecret); err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificate: %w", err)
+		}
+		opts.CACertificate = caSecret.Data["ca.crt"]
+	}
+
+	newClient := r.RabbitmqClientFactory
+	if newClient == nil {
+		newClient = rabbitmqclient.New
+	}
+	return newClient(rmq, credentials, host, opts)
+}
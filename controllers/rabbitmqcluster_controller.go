@@ -19,12 +19,16 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
 	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"github.com/rabbitmq/cluster-operator/v2/internal/rabbitmqclient"
+	"github.com/rabbitmq/cluster-operator/v2/internal/ratelimit"
 	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
 	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	"github.com/rabbitmq/cluster-operator/v2/internal/tracing"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -32,17 +36,22 @@ import (
 
 	"k8s.io/apimachinery/pkg/types"
 
-	clientretry "k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -55,6 +64,11 @@ const (
 	ownerKey                 = ".metadata.controller"
 	ownerKind                = "RabbitmqCluster"
 	pauseReconciliationLabel = "rabbitmq.com/pauseReconciliation"
+	// legacyPauseReconciliationLabel is the pause label of the operator releases using the rabbitmq.pivotal.io API group
+	legacyPauseReconciliationLabel = "rabbitmq.pivotal.io/pauseReconciliation"
+	// adoptResourcesAnnotation allows the operator to take over child resources which exist without controller,
+	// e.g. after a previous Helm deployment, by setting the RabbitmqCluster as their controller.
+	adoptResourcesAnnotation = "rabbitmq.com/adoptExistingResources"
 )
 
 // RabbitmqClusterReconciler reconciles a RabbitmqCluster object
@@ -71,26 +85,69 @@ type RabbitmqClusterReconciler struct {
 	DefaultUserUpdaterImage string
 	DefaultImagePullSecrets string
 	ControlRabbitmqImage    bool
+	LabelMappings           map[string]string
+	RouteAPIAvailable       bool
+	GatewayAPIAvailable     bool
+	// PrometheusOperatorAvailable is true when the ServiceMonitor, PodMonitor and PrometheusRule resources are served.
+	PrometheusOperatorAvailable bool
+	// HealthPollInterval is the interval at which the alarms and partitions of RabbitmqClusters are read.
+	// Polling is disabled if it is 0.
+	HealthPollInterval time.Duration
+	// RetryBaseDelay and RetryMaxDelay configure the exponential backoff of failing reconciles.
+	// The defaults of the ratelimit package are used if they are 0.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// ResyncInterval is the interval at which RabbitmqClusters are reconciled without changes.
+	// Periodic reconciles are disabled if it is 0, unless RabbitmqClusters set resyncIntervalAnnotation.
+	ResyncInterval time.Duration
+	// DefaultStorageClassName and DefaultResources are set on RabbitmqClusters which do not set them.
+	DefaultStorageClassName string
+	DefaultResources        *corev1.ResourceRequirements
+	// InjectedLabels and InjectedAnnotations are set on all child resources, unless the RabbitmqCluster sets them.
+	InjectedLabels      map[string]string
+	InjectedAnnotations map[string]string
+	// RabbitmqClientFactory creates clients of the management API of RabbitmqClusters. rabbitmqclient.New is used if it is nil.
+	RabbitmqClientFactory rabbitmqclient.Factory
+	// RolloutAnalysisPrometheusURLs are the Prometheus URLs which rollout analyses may query.
+	// Rollout analyses are not run if it is empty.
+	RolloutAnalysisPrometheusURLs []string
+	// WatchNamespaces are the namespaces of the watched RabbitmqClusters. All namespaces are watched if it is empty.
+	WatchNamespaces []string
+	// RemoteClustersEnabled allows RabbitmqClusters to be deployed into the remote clusters of spec.remoteCluster.
+	RemoteClustersEnabled bool
+
+	remoteClients remoteClientCache
 }
 
 // the rbac rule requires an empty row at the end to render
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
-// +kubebuilder:rbac:groups="",resources=pods,verbs=update;get;list;watch
-// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=update;get;list;watch;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=get;watch;list
-// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;delete
-// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes;tlsroutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=rabbitmq.com,resources=rabbitmqclusters,verbs=get;list;watch;create;update
-// +kubebuilder:rbac:groups=rabbitmq.com,resources=rabbitmqclusters/status,verbs=get;update
+// +kubebuilder:rbac:groups=rabbitmq.com,resources=rabbitmqclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=rabbitmq.com,resources=rabbitmqclusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;create;patch
-// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update
-// +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles,verbs=get;list;watch;create;update
-// +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings,verbs=get;list;watch;create;update
-
-func (r *RabbitmqClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+// +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+
+func (r *RabbitmqClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "Reconcile",
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("rabbitmqcluster", req.Name))
+	defer func() {
+		tracing.End(span, err)
+	}()
 	logger := ctrl.LoggerFrom(ctx)
 
 	rabbitmqCluster, err := r.getRabbitmqCluster(ctx, req.NamespacedName)
@@ -99,9 +156,15 @@ func (r *RabbitmqClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	} else if k8serrors.IsNotFound(err) {
 		// No need to requeue if the resource no longer exists
+		deleteClusterMetrics(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
+	start := time.Now()
+	defer func() {
+		observeReconcile(rabbitmqCluster, time.Since(start), err)
+	}()
+
 	// Check if the resource has been marked for deletion
 	if !rabbitmqCluster.ObjectMeta.DeletionTimestamp.IsZero() {
 		logger.Info("Deleting")
@@ -109,17 +172,27 @@ func (r *RabbitmqClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// exit if pause reconciliation label is set to true
-	if v, ok := rabbitmqCluster.Labels[pauseReconciliationLabel]; ok && v == "true" {
+	if label, paused := reconciliationPausedBy(rabbitmqCluster); paused {
 		logger.Info("Not reconciling RabbitmqCluster")
-		r.Recorder.Event(rabbitmqCluster, corev1.EventTypeWarning,
-			"PausedReconciliation", fmt.Sprintf("label '%s' is set to true", pauseReconciliationLabel))
-
-		rabbitmqCluster.Status.SetCondition(status.NoWarnings, corev1.ConditionFalse, "reconciliation paused")
-		if writerErr := r.Status().Update(ctx, rabbitmqCluster); writerErr != nil {
-			logger.Error(writerErr, "Error trying to Update NoWarnings condition state")
+		msg := fmt.Sprintf("label '%s' is set to true", label)
+		r.Recorder.Event(rabbitmqCluster, corev1.EventTypeWarning, "PausedReconciliation", msg)
+
+		if writerErr := r.updateStatus(ctx, rabbitmqCluster, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+			clusterStatus.SetCondition(status.NoWarnings, corev1.ConditionFalse, "reconciliation paused")
+			clusterStatus.SetPaused(msg)
+		}); writerErr != nil {
+			logger.Error(writerErr, "Error trying to Update NoWarnings and Paused condition state")
 		}
 		return ctrl.Result{}, nil
 	}
+	if rabbitmqCluster.Status.DeepCopy().SetResumed() {
+		logger.Info("Resuming reconciliation of RabbitmqCluster")
+		if err := r.updateStatus(ctx, rabbitmqCluster, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+			clusterStatus.SetResumed()
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	if requeueAfter, err := r.reconcileOperatorDefaults(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
 		return ctrl.Result{RequeueAfter: requeueAfter}, err
@@ -130,7 +203,19 @@ func (r *RabbitmqClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	if requeueAfter, err := r.updateStatusConditions(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
+	if rabbitmqCluster.RemoteClusterEnabled() {
+		return r.reconcileRemoteCluster(ctx, rabbitmqCluster)
+	}
+
+	if rabbitmqCluster.Annotations[dryRunAnnotation] == "true" {
+		if err := r.renderDryRun(ctx, rabbitmqCluster); err != nil {
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedDryRun", err.Error())
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if requeueAfter, err := r.updateStatusConditions(ctx, r.Client, rabbitmqCluster); err != nil || requeueAfter > 0 {
 		return ctrl.Result{RequeueAfter: requeueAfter}, err
 	}
 
@@ -142,7 +227,7 @@ func (r *RabbitmqClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// if the secret already exists, ensure it has the labels necessary for being in the controller's cache
-	// otherwise, our attempt to create it will fail (CreateOrUpdate only checks for the existence of the resource in the cache)
+	// otherwise, its credentials are read from the API server on every reconcile
 	defaultUserSecret := &corev1.Secret{}
 	err = r.APIReader.Get(ctx, types.NamespacedName{Namespace: rabbitmqCluster.Namespace, Name: rabbitmqCluster.ChildResourceName("default-user")}, defaultUserSecret)
 	if err == nil {
@@ -173,90 +258,140 @@ func (r *RabbitmqClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 	logger.V(1).Info("RabbitmqCluster", "spec", string(instanceSpec))
 
+	configFrom, err := r.configFrom(ctx, rabbitmqCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defaultUser, err := r.existingAdminCredentials(ctx, rabbitmqCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.checkErlangCookieSecret(ctx, rabbitmqCluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	resourceBuilder := resource.RabbitmqResourceBuilder{
-		Instance: rabbitmqCluster,
-		Scheme:   r.Scheme,
+		Instance:                    rabbitmqCluster,
+		Scheme:                      r.Scheme,
+		LabelMappings:               r.LabelMappings,
+		RouteAPIAvailable:           r.RouteAPIAvailable,
+		GatewayAPIAvailable:         r.GatewayAPIAvailable,
+		PrometheusOperatorAvailable: r.PrometheusOperatorAvailable,
+		ConfigFrom:                  configFrom,
+		DefaultUser:                 defaultUser,
+		InjectedLabels:              r.InjectedLabels,
+		InjectedAnnotations:         r.InjectedAnnotations,
 	}
 
-	builders := resourceBuilder.ResourceBuilders()
+	applied, stop, err := r.applyChildResources(ctx, rabbitmqCluster, r.localCluster(rabbitmqCluster), resourceBuilder.ResourceBuilders())
+	if err != nil || stop {
+		return ctrl.Result{}, err
+	}
 
-	for _, builder := range builders {
-		resource, err := builder.Build()
+	if err := r.deleteDryRun(ctx, rabbitmqCluster); err != nil {
+		r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "Error", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if err := r.garbageCollectChildResources(ctx, rabbitmqCluster, applied); err != nil {
+		r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedGarbageCollection", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if requeueAfter, err := r.restartStatefulSetIfNeeded(ctx, r.Client, logger, rabbitmqCluster); err != nil || requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+
+	if requeueAfter, err := r.updatePodsIfHealthy(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
 		if err != nil {
-			return ctrl.Result{}, err
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedUpdatePods", err.Error())
 		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
 
-		// only StatefulSetBuilder returns true
-		if builder.UpdateMayRequireStsRecreate() {
-			sts := resource.(*appsv1.StatefulSet)
+	if err := r.reconcileStatus(ctx, rabbitmqCluster); err != nil {
+		return ctrl.Result{}, err
+	}
 
-			current, err := r.statefulSet(ctx, rabbitmqCluster)
-			if client.IgnoreNotFound(err) != nil {
-				return ctrl.Result{}, err
-			}
+	if err := r.reportUpgradeProgress(ctx, rabbitmqCluster); err != nil {
+		return ctrl.Result{}, err
+	}
 
-			// only checks for scale down if statefulSet is created
-			// else continue to CreateOrUpdate()
-			if !k8serrors.IsNotFound(err) {
-				if err := builder.Update(sts); err != nil {
-					return ctrl.Result{}, err
-				}
-				if r.scaleDown(ctx, rabbitmqCluster, current, sts) {
-					// return when cluster scale down detected; unsupported operation
-					return ctrl.Result{}, nil
-				}
-			}
+	// By this point the StatefulSet may have finished deploying. Run any
+	// post-deploy steps if so, or requeue until the deployment is finished.
+	if requeueAfter, err := r.runRabbitmqCLICommandsIfAnnotated(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
+		if err != nil {
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedCLICommand", err.Error())
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
 
-			// The PVCs for the StatefulSet may require expanding
-			if err = r.reconcilePVC(ctx, rabbitmqCluster, sts); err != nil {
-				r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedReconcilePVC", err.Error())
-				return ctrl.Result{}, err
-			}
+	if requeueAfter, err := r.reloadTLSCertificateIfRotated(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
+		if err != nil {
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedTLSCertificateReload", err.Error())
 		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
 
-		var operationResult controllerutil.OperationResult
-		err = clientretry.RetryOnConflict(clientretry.DefaultRetry, func() error {
-			var apiError error
-			operationResult, apiError = controllerutil.CreateOrUpdate(ctx, r.Client, resource, func() error {
-				return builder.Update(resource)
-			})
-			return apiError
-		})
-		r.logAndRecordOperationResult(logger, rabbitmqCluster, resource, operationResult, err)
+	if requeueAfter, err := r.rotateDefaultUserPasswordIfAnnotated(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
 		if err != nil {
-			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "Error", err.Error())
-			return ctrl.Result{}, err
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedDefaultUserPasswordRotation", err.Error())
 		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
 
-		if err = r.annotateIfNeeded(ctx, logger, builder, operationResult, rabbitmqCluster); err != nil {
-			return ctrl.Result{}, err
+	if requeueAfter, err := r.rotateErlangCookieIfAnnotated(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
+		if err != nil {
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedErlangCookieRotation", err.Error())
 		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
 	}
 
-	if requeueAfter, err := r.restartStatefulSetIfNeeded(ctx, logger, rabbitmqCluster); err != nil || requeueAfter > 0 {
+	if requeueAfter, err := r.reconcileAdditionalUsers(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
+		if err != nil {
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedReconcileAdditionalUsers", err.Error())
+		}
 		return ctrl.Result{RequeueAfter: requeueAfter}, err
 	}
 
-	if err := r.reconcileStatus(ctx, rabbitmqCluster); err != nil {
-		return ctrl.Result{}, err
+	if requeueAfter, err := r.reconcileVersions(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
+		if err != nil {
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedReconcileVersions", err.Error())
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
 	}
 
-	// By this point the StatefulSet may have finished deploying. Run any
-	// post-deploy steps if so, or requeue until the deployment is finished.
-	if requeueAfter, err := r.runRabbitmqCLICommandsIfAnnotated(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
+	// feature flags cannot be disabled again, so they are only enabled once the upgrade passed the rollout analysis
+	if requeueAfter, err := r.runRolloutAnalysisIfNeeded(ctx, rabbitmqCluster); err != nil || requeueAfter > 0 {
 		if err != nil {
-			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedCLICommand", err.Error())
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedRolloutAnalysis", err.Error())
 		}
 		return ctrl.Result{RequeueAfter: requeueAfter}, err
 	}
 
+	if err := r.enableFeatureFlagsAfterUpgrade(ctx, rabbitmqCluster); err != nil {
+		r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedEnableFeatureFlags", err.Error())
+		return ctrl.Result{}, err
+	}
+
 	// Set ReconcileSuccess to true and update observedGeneration after all reconciliation steps have finished with no error
-	rabbitmqCluster.Status.ObservedGeneration = rabbitmqCluster.GetGeneration()
-	r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionTrue, "Success", "Finish reconciling")
+	r.setReconcileCompleted(ctx, rabbitmqCluster)
 
 	logger.Info("Finished reconciling")
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.resyncAfter(ctx, rabbitmqCluster)}, nil
+}
+
+// reconciliationPausedBy returns the label which pauses reconciliation of the RabbitmqCluster, if any.
+func reconciliationPausedBy(rmq *rabbitmqv1beta1.RabbitmqCluster) (string, bool) {
+	for _, label := range []string{pauseReconciliationLabel, legacyPauseReconciliationLabel} {
+		if rmq.Labels[label] == "true" {
+			return label, true
+		}
+	}
+	return "", false
 }
 
 func (r *RabbitmqClusterReconciler) getRabbitmqCluster(ctx context.Context, namespacedName types.NamespacedName) (*rabbitmqv1beta1.RabbitmqCluster, error) {
@@ -289,29 +424,38 @@ func (r *RabbitmqClusterReconciler) logAndRecordOperationResult(logger logr.Logg
 	}
 
 	if err != nil {
-		var msg string
-		if operation != "unchanged" {
-			msg = fmt.Sprintf("failed to %s resource %s of Type %T: ", operation, resource.(metav1.Object).GetName(), resource.(metav1.Object))
-		}
-		msg = fmt.Sprintf("%s%s", msg, err)
+		msg := r.applyFailureMessage(resource.(client.Object), err)
 		logger.Error(err, msg)
-		r.Recorder.Event(rmq, corev1.EventTypeWarning, fmt.Sprintf("Failed%s", caser.String(operation)), msg)
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, status.ApplyFailureReason(err), msg)
 	}
 }
 
-func (r *RabbitmqClusterReconciler) updateStatusConditions(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
+// applyFailureMessage describes a failed create or update of a child resource, including the error returned by the Kubernetes API.
+func (r *RabbitmqClusterReconciler) applyFailureMessage(resource client.Object, err error) string {
+	kind := fmt.Sprintf("%T", resource)
+	if gvk, gvkErr := apiutil.GVKForObject(resource, r.Scheme); gvkErr == nil {
+		kind = gvk.Kind
+	}
+	return fmt.Sprintf("failed to apply %s %s: %s", kind, resource.GetName(), err)
+}
+
+func (r *RabbitmqClusterReconciler) updateStatusConditions(ctx context.Context, reader client.Reader, rmq *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
 	logger := ctrl.LoggerFrom(ctx)
-	childResources, err := r.getChildResources(ctx, rmq)
+	childResources, err := r.getChildResources(ctx, reader, rmq)
 	if err != nil {
 		return 0, err
 	}
 
-	oldConditions := make([]status.RabbitmqClusterCondition, len(rmq.Status.Conditions))
-	copy(oldConditions, rmq.Status.Conditions)
-	rmq.Status.SetConditions(childResources)
+	warnings := rmq.ConfigurationWarnings()
+	setConditions := func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		clusterStatus.SetConditions(childResources, warnings)
+		clusterStatus.SetReadyReplicas(childResources)
+	}
+	desired := rmq.Status.DeepCopy()
+	setConditions(desired)
 
-	if !reflect.DeepEqual(rmq.Status.Conditions, oldConditions) {
-		if err = r.Status().Update(ctx, rmq); err != nil {
+	if !reflect.DeepEqual(desired.Conditions, rmq.Status.Conditions) || desired.ReadyReplicas != rmq.Status.ReadyReplicas {
+		if err = r.updateStatus(ctx, rmq, setConditions); err != nil {
 			// FIXME: must fetch again to avoid the conflict
 			if k8serrors.IsConflict(err) {
 				logger.Info("failed to update status because of conflict; requeueing...",
@@ -325,11 +469,11 @@ func (r *RabbitmqClusterReconciler) updateStatusConditions(ctx context.Context,
 	return 0, nil
 }
 
-func (r *RabbitmqClusterReconciler) getChildResources(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) ([]runtime.Object, error) {
+func (r *RabbitmqClusterReconciler) getChildResources(ctx context.Context, reader client.Reader, rmq *rabbitmqv1beta1.RabbitmqCluster) ([]runtime.Object, error) {
 	sts := &appsv1.StatefulSet{}
 	endPoints := &corev1.Endpoints{}
 
-	if err := r.Client.Get(ctx,
+	if err := reader.Get(ctx,
 		types.NamespacedName{Name: rmq.ChildResourceName("server"), Namespace: rmq.Namespace},
 		sts); err != nil && !k8serrors.IsNotFound(err) {
 		return nil, err
@@ -337,7 +481,7 @@ func (r *RabbitmqClusterReconciler) getChildResources(ctx context.Context, rmq *
 		sts = nil
 	}
 
-	if err := r.Client.Get(ctx,
+	if err := reader.Get(ctx,
 		types.NamespacedName{Name: rmq.ChildResourceName(resource.ServiceSuffix), Namespace: rmq.Namespace},
 		endPoints); err != nil && !k8serrors.IsNotFound(err) {
 		return nil, err
@@ -349,8 +493,23 @@ func (r *RabbitmqClusterReconciler) getChildResources(ctx context.Context, rmq *
 }
 
 func (r *RabbitmqClusterReconciler) setReconcileSuccess(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster, condition corev1.ConditionStatus, reason, msg string) {
-	rabbitmqCluster.Status.SetCondition(status.ReconcileSuccess, condition, reason, msg)
-	if writerErr := r.Status().Update(ctx, rabbitmqCluster); writerErr != nil {
+	r.updateStatusOrLog(ctx, rabbitmqCluster, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		clusterStatus.SetCondition(status.ReconcileSuccess, condition, reason, msg)
+	})
+}
+
+// setReconcileCompleted sets ReconcileSuccess to true, and status.observedGeneration to the reconciled generation.
+func (r *RabbitmqClusterReconciler) setReconcileCompleted(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster) {
+	generation := rabbitmqCluster.GetGeneration()
+	r.updateStatusOrLog(ctx, rabbitmqCluster, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		clusterStatus.ObservedGeneration = generation
+		clusterStatus.SetCondition(status.ReconcileSuccess, corev1.ConditionTrue, "Success", "Finish reconciling")
+	})
+}
+
+// updateStatusOrLog updates the status like updateStatus, and only logs failures.
+func (r *RabbitmqClusterReconciler) updateStatusOrLog(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster, mutate func(*rabbitmqv1beta1.RabbitmqClusterStatus)) {
+	if writerErr := r.updateStatus(ctx, rabbitmqCluster, mutate); writerErr != nil {
 		ctrl.LoggerFrom(ctx).Error(writerErr, "Failed to update Custom Resource status",
 			"namespace", rabbitmqCluster.Namespace,
 			"name", rabbitmqCluster.Name)
@@ -363,22 +522,69 @@ func (r *RabbitmqClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			return err
 		}
 	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rabbitmqv1beta1.RabbitmqCluster{}, configFromKey, indexConfigFrom); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rabbitmqv1beta1.RabbitmqCluster{}, tlsSecretKey, indexTLSSecret); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rabbitmqv1beta1.RabbitmqCluster{}, erlangCookieSecretKey, indexErlangCookieSecret); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rabbitmqv1beta1.RabbitmqCluster{}, existingAdminSecretKey, indexExistingAdminSecret); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rabbitmqv1beta1.RabbitmqCluster{}, additionalUsersSecretKey, indexAdditionalUsersSecrets); err != nil {
+		return err
+	}
+
+	if r.HealthPollInterval > 0 {
+		if err := mgr.Add(manager.RunnableFunc(r.pollClusterHealth)); err != nil {
+			return err
+		}
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&rabbitmqv1beta1.RabbitmqCluster{}).
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&rabbitmqv1beta1.RabbitmqCluster{}, ctrlbuilder.WithPredicates(rabbitmqClusterChangedPredicate())).
+		WithOptions(controller.Options{
+			// a misconfigured RabbitmqCluster is retried less and less often instead of every few milliseconds
+			RateLimiter: ratelimit.New[reconcile.Request](r.RetryBaseDelay, r.RetryMaxDelay),
+		}).
 		Owns(&appsv1.StatefulSet{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&corev1.Service{}).
-		Owns(&rbacv1.Role{}).
-		Owns(&rbacv1.RoleBinding{}).
-		Owns(&corev1.ServiceAccount{}).
-		Owns(&corev1.Secret{}).
-		Complete(r)
+		Owns(&corev1.ConfigMap{}, ctrlbuilder.WithPredicates(childResourceChangedPredicate())).
+		Owns(&corev1.Service{}, ctrlbuilder.WithPredicates(childResourceChangedPredicate())).
+		Owns(&rbacv1.Role{}, ctrlbuilder.WithPredicates(childResourceChangedPredicate())).
+		Owns(&rbacv1.RoleBinding{}, ctrlbuilder.WithPredicates(childResourceChangedPredicate())).
+		Owns(&corev1.ServiceAccount{}, ctrlbuilder.WithPredicates(childResourceChangedPredicate())).
+		Owns(&corev1.Secret{}, ctrlbuilder.WithPredicates(childResourceChangedPredicate())).
+		Owns(&networkingv1.Ingress{}, ctrlbuilder.WithPredicates(childResourceChangedPredicate()))
+	referencedObjects, err := r.referencedObjectsSources(mgr)
+	if err != nil {
+		return err
+	}
+	for _, src := range referencedObjects {
+		builder = builder.WatchesRawSource(src)
+	}
+	if r.RouteAPIAvailable {
+		builder = builder.Owns(resource.NewRoute("", ""), ctrlbuilder.WithPredicates(childResourceChangedPredicate()))
+	}
+	if r.GatewayAPIAvailable {
+		builder = builder.
+			Owns(resource.NewGatewayRoute(resource.TCPRouteGroupVersionKind, "", ""), ctrlbuilder.WithPredicates(childResourceChangedPredicate())).
+			Owns(resource.NewGatewayRoute(resource.TLSRouteGroupVersionKind, "", ""), ctrlbuilder.WithPredicates(childResourceChangedPredicate()))
+	}
+	if r.PrometheusOperatorAvailable {
+		builder = builder.
+			Owns(resource.NewMonitor(resource.ServiceMonitorGroupVersionKind, "", ""), ctrlbuilder.WithPredicates(childResourceChangedPredicate())).
+			Owns(resource.NewMonitor(resource.PodMonitorGroupVersionKind, "", ""), ctrlbuilder.WithPredicates(childResourceChangedPredicate())).
+			Owns(resource.NewPrometheusRule("", ""), ctrlbuilder.WithPredicates(childResourceChangedPredicate()))
+	}
+	return builder.Complete(r)
 }
 
 func addResourceToIndex(rawObj client.Object) []string {
 	switch resourceObject := rawObj.(type) {
-	case *appsv1.StatefulSet, *corev1.ConfigMap, *corev1.Service, *rbacv1.Role, *rbacv1.RoleBinding, *corev1.ServiceAccount, *corev1.Secret:
+	case *appsv1.StatefulSet, *corev1.ConfigMap, *corev1.Service, *rbacv1.Role, *rbacv1.RoleBinding, *corev1.ServiceAccount, *corev1.Secret, *networkingv1.Ingress:
 		owner := metav1.GetControllerOf(resourceObject)
 		return validateAndGetOwner(owner)
 	default:
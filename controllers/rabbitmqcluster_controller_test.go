@@ -84,6 +84,13 @@ var _ = Describe("RabbitmqClusterController", func() {
 				Expect(sts.Spec.VolumeClaimTemplates[0].Spec.StorageClassName).To(BeNil())
 			})
 
+			By("applying the statefulset with server-side apply", func() {
+				Expect(sts.ManagedFields).To(ContainElement(SatisfyAll(
+					HaveField("Manager", "rabbitmq-cluster-operator"),
+					HaveField("Operation", metav1.ManagedFieldsOperationApply),
+				)))
+			})
+
 			By("setting the default imagePullSecrets", func() {
 				Expect(sts.Spec.Template.Spec.ImagePullSecrets).To(ConsistOf(
 					[]corev1.LocalObjectReference{
@@ -623,6 +630,19 @@ var _ = Describe("RabbitmqClusterController", func() {
 			}, 3).Should(Equal("NodePort"))
 		})
 
+		It("the observed generation is updated", func() {
+			Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+				r.Spec.Service.Type = "NodePort"
+			})).To(Succeed())
+
+			rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+			Eventually(func() bool {
+				Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), rmq)).To(Succeed())
+				return rmq.Status.ObservedGeneration == rmq.Generation
+			}, 5).Should(BeTrue(), "expected status.observedGeneration to equal metadata.generation")
+			Expect(rmq.Generation).To(BeNumerically(">", 1))
+		})
+
 		It("affinity rules are updated", func() {
 			affinity := &corev1.Affinity{
 				NodeAffinity: &corev1.NodeAffinity{
@@ -730,6 +750,17 @@ var _ = Describe("RabbitmqClusterController", func() {
 			}, 5).Should(Not(Equal(oldConfMap.UID)))
 
 		})
+
+		It("reverts changes to child resources", func() {
+			confMap := configMap(ctx, cluster, "server-conf")
+			operatorDefaults := confMap.Data["operatorDefaults.conf"]
+			confMap.Data["operatorDefaults.conf"] = "changed = outside the operator"
+			Expect(client.Update(ctx, confMap)).To(Succeed())
+
+			Eventually(func() string {
+				return configMap(ctx, cluster, "server-conf").Data["operatorDefaults.conf"]
+			}, 5).Should(Equal(operatorDefaults))
+		})
 	})
 
 	Context("RabbitMQ CR ReconcileSuccess condition", func() {
@@ -768,6 +799,64 @@ var _ = Describe("RabbitmqClusterController", func() {
 					return "ReconcileSuccess status: condition not present"
 				}, 5).Should(Equal("ReconcileSuccess status: False"))
 			})
+
+			By("reporting why the child resource could not be applied", func() {
+				someRabbit := &rabbitmqv1beta1.RabbitmqCluster{}
+				Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), someRabbit)).To(Succeed())
+				Expect(someRabbit.Status.Conditions).To(ContainElement(SatisfyAll(
+					HaveField("Type", status.ReconcileSuccess),
+					HaveField("Reason", "InvalidChildResource"),
+					HaveField("Message", ContainSubstring("failed to apply Service irreconcilable")),
+				)))
+				Expect(aggregateEventMsgs(ctx, someRabbit, "InvalidChildResource")).To(ContainSubstring("notValidForK8s"))
+			})
+		})
+	})
+
+	Context("Order of child resources", func() {
+		var serverConf *corev1.ConfigMap
+
+		BeforeEach(func() {
+			cluster = &rabbitmqv1beta1.RabbitmqCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rabbitmq-order",
+					Namespace: defaultNamespace,
+				},
+			}
+			// a ConfigMap which the operator does not take over without adoption
+			serverConf = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      cluster.ChildResourceName("server-conf"),
+					Namespace: defaultNamespace,
+				},
+			}
+			Expect(client.Create(ctx, serverConf)).To(Succeed())
+			Expect(client.Create(ctx, cluster)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(client.Delete(ctx, cluster)).To(Succeed())
+			waitForClusterDeletion(ctx, cluster, client)
+			Expect(runtimeClient.IgnoreNotFound(client.Delete(ctx, serverConf))).To(Succeed())
+		})
+
+		It("only applies the StatefulSet once the resources its Pods depend on are applied", func() {
+			Eventually(func() error {
+				_, err := clientSet.CoreV1().Services(defaultNamespace).Get(ctx, cluster.ChildResourceName(""), metav1.GetOptions{})
+				return err
+			}, 5).Should(Succeed())
+			Consistently(func() bool {
+				_, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}, 3).Should(BeTrue())
+
+			Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+				r.Annotations = map[string]string{"rabbitmq.com/adoptExistingResources": "true"}
+			})).To(Succeed())
+			Eventually(func() error {
+				_, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+				return err
+			}, 5).Should(Succeed())
 		})
 	})
 
@@ -872,9 +961,10 @@ var _ = Describe("RabbitmqClusterController", func() {
 			defaultMode := int32(420)
 
 			Expect(sts.ObjectMeta.Labels).To(Equal(map[string]string{
-				"app.kubernetes.io/name":      "rabbitmq-sts-override",
-				"app.kubernetes.io/component": "rabbitmq",
-				"app.kubernetes.io/part-of":   "rabbitmq",
+				"app.kubernetes.io/name":         "rabbitmq-sts-override",
+				"app.kubernetes.io/component":    "rabbitmq",
+				"app.kubernetes.io/part-of":      "rabbitmq",
+				"rabbitmq.com/managedResourceOf": "rabbitmq-sts-override",
 			}))
 
 			Expect(sts.Spec.ServiceName).To(Equal("rabbitmq-sts-override-nodes"))
@@ -948,6 +1038,14 @@ var _ = Describe("RabbitmqClusterController", func() {
 												Key:  "operatorDefaults.conf",
 												Path: "operatorDefaults.conf",
 											},
+											{
+												Key:  "tls.conf",
+												Path: "tls.conf",
+											},
+											{
+												Key:  "plugins.conf",
+												Path: "plugins.conf",
+											},
 											{
 												Key:  "userDefinedConfiguration.conf",
 												Path: "userDefinedConfiguration.conf",
@@ -1220,6 +1318,14 @@ var _ = Describe("RabbitmqClusterController", func() {
 					}
 					return "NoWarnings status: condition not present"
 				}, 5).Should(Equal("NoWarnings status: False with reason: reconciliation paused"))
+
+				rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+				Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), rmq)).To(Succeed())
+				Expect(rmq.Status.Conditions).To(ContainElement(SatisfyAll(
+					HaveField("Type", status.Paused),
+					HaveField("Status", corev1.ConditionTrue),
+					HaveField("Reason", "ReconciliationPaused"),
+				)))
 			})
 
 			By("resuming reconciliation when label is removed", func() {
@@ -1253,8 +1359,29 @@ var _ = Describe("RabbitmqClusterController", func() {
 					}
 					return "NoWarnings status: condition not present"
 				}, 5).Should(Equal("NoWarnings status: True"))
+
+				rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+				Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), rmq)).To(Succeed())
+				Expect(rmq.Status.Conditions).To(ContainElement(SatisfyAll(
+					HaveField("Type", status.Paused),
+					HaveField("Status", corev1.ConditionFalse),
+					HaveField("Reason", "ReconciliationResumed"),
+				)))
 			})
 		})
+
+		It("honours the rabbitmq.pivotal.io pause label", func() {
+			Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+				r.Labels = map[string]string{"rabbitmq.pivotal.io/pauseReconciliation": "true"}
+				r.Spec.Service.Type = "LoadBalancer"
+			})).To(Succeed())
+
+			Consistently(func() corev1.ServiceType {
+				return service(ctx, cluster, "").Spec.Type
+			}, 5*time.Second).Should(Equal(corev1.ServiceTypeClusterIP))
+			Expect(aggregateEventMsgs(ctx, cluster, "PausedReconciliation")).To(
+				ContainSubstring("label 'rabbitmq.pivotal.io/pauseReconciliation' is set to true"))
+		})
 	})
 
 })
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// additionalUsersSecretKey indexes RabbitmqClusters by the names of the Secrets referenced in spec.rabbitmq.additionalUsers.
+const additionalUsersSecretKey = ".spec.rabbitmq.additionalUsers.secretName"
+
+// additionalUser is a user of spec.rabbitmq.additionalUsers with the credentials of its Secret.
+type additionalUser struct {
+	rabbitmqv1beta1.RabbitmqUser
+	username, password, hash string
+}
+
+// reconcileAdditionalUsers creates and updates the users of spec.rabbitmq.additionalUsers, and deletes the users
+// which were removed from it. Only users which changed since they were last applied, according to status.additionalUsers,
+// are updated.
+func (r *RabbitmqClusterReconciler) reconcileAdditionalUsers(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
+	logger := ctrl.LoggerFrom(ctx)
+
+	users, err := r.additionalUsers(ctx, rmq)
+	if err != nil {
+		return 0, err
+	}
+	desired := make([]rabbitmqv1beta1.RabbitmqClusterUserStatus, 0, len(users))
+	for _, user := range users {
+		desired = append(desired, rabbitmqv1beta1.RabbitmqClusterUserStatus{Username: user.username, Hash: user.hash})
+	}
+	if len(desired) == 0 && len(rmq.Status.AdditionalUsers) == 0 || reflect.DeepEqual(desired, rmq.Status.AdditionalUsers) {
+		return 0, nil
+	}
+
+	sts, err := r.statefulSet(ctx, rmq)
+	if err != nil {
+		return 0, err
+	}
+	if !allReplicasReadyAndUpdated(sts) {
+		logger.V(1).Info("not all replicas ready yet; requeuing request to reconcile additional users")
+		return 15 * time.Second, nil
+	}
+
+	applied := map[string]string{}
+	for _, user := range rmq.Status.AdditionalUsers {
+		applied[user.Username] = user.Hash
+	}
+	var commands []string
+	for _, user := range users {
+		if applied[user.username] != user.hash {
+			commands = append(commands, userCommand(user))
+		}
+		delete(applied, user.username)
+	}
+	for username := range applied {
+		commands = append(commands, fmt.Sprintf("rabbitmqctl delete_user %s", shellQuote(username)))
+	}
+
+	// users are stored cluster-wide, so running the commands on one node is sufficient
+	podName := fmt.Sprintf("%s-0", rmq.ChildResourceName("server"))
+	for _, cmd := range commands {
+		stdout, stderr, err := r.exec(rmq.Namespace, podName, "rabbitmq", "sh", "-c", cmd)
+		if err != nil {
+			msg := "failed to reconcile additional users on pod"
+			logger.Error(err, msg, "pod", podName, "stdout", stdout, "stderr", stderr)
+			r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", fmt.Sprintf("%s %s", msg, podName))
+			return 0, fmt.Errorf("%s %s: %w", msg, podName, err)
+		}
+	}
+
+	logger.Info("successfully reconciled additional users")
+	return 0, r.updateStatus(ctx, rmq, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		clusterStatus.AdditionalUsers = desired
+	})
+}
+
+// additionalUsers reads the credentials of spec.rabbitmq.additionalUsers from their Secrets.
+func (r *RabbitmqClusterReconciler) additionalUsers(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) ([]additionalUser, error) {
+	var users []additionalUser
+	for _, user := range rmq.Spec.Rabbitmq.AdditionalUsers {
+		// the Secret is read from the API server, because only Secrets labelled as part of rabbitmq are cached
+		secret := &corev1.Secret{}
+		if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: user.SecretName}, secret); err != nil {
+			msg := fmt.Sprintf("failed to get Secret %s referenced in spec.rabbitmq.additionalUsers", user.SecretName)
+			r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", msg)
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		for _, key := range []string{"username", "password"} {
+			if len(secret.Data[key]) == 0 {
+				msg := fmt.Sprintf("Secret %s referenced in spec.rabbitmq.additionalUsers has no key %s", user.SecretName, key)
+				r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", msg)
+				return nil, fmt.Errorf("%s", msg)
+			}
+		}
+		u := additionalUser{
+			RabbitmqUser: user,
+			username:     string(secret.Data["username"]),
+			password:     string(secret.Data["password"]),
+		}
+		u.hash = fmt.Sprintf("%x", sha256.Sum256([]byte(userCommand(u))))
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// userCommand creates the user, or updates the password of an existing user, and sets its tags and permissions.
+func userCommand(user additionalUser) string {
+	username, password := shellQuote(user.username), shellQuote(user.password)
+	commands := []string{
+		fmt.Sprintf("if rabbitmqctl list_users --silent | cut -f1 | grep -qxF %s; then rabbitmqctl change_password %s %s; else rabbitmqctl add_user %s %s; fi",
+			username, username, password, username, password),
+		strings.TrimSpace(fmt.Sprintf("rabbitmqctl set_user_tags %s %s", username, strings.Join(quoteAll(user.Tags), " "))),
+	}
+	for _, permissions := range user.Permissions {
+		vhost := permissions.VHost
+		if vhost == "" {
+			vhost = "/"
+		}
+		commands = append(commands, fmt.Sprintf("rabbitmqctl set_permissions -p %s %s %s %s %s", shellQuote(vhost), username,
+			shellQuote(defaultPermission(permissions.Configure)), shellQuote(defaultPermission(permissions.Write)), shellQuote(defaultPermission(permissions.Read))))
+	}
+	return strings.Join(commands, " && ")
+}
+
+func defaultPermission(permission string) string {
+	if permission == "" {
+		return ".*"
+	}
+	return permission
+}
+
+// shellQuote quotes a value for sh, since credentials and permissions are provided by users.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'"'"'`) + "'"
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, shellQuote(value))
+	}
+	return quoted
+}
+
+func indexAdditionalUsersSecrets(rawObj client.Object) []string {
+	rmq := rawObj.(*rabbitmqv1beta1.RabbitmqCluster)
+	var names []string
+	for _, user := range rmq.Spec.Rabbitmq.AdditionalUsers {
+		names = append(names, user.SecretName)
+	}
+	return names
+}
+
+// clustersReferencingAdditionalUserSecret maps a Secret to the RabbitmqClusters that reference it in spec.rabbitmq.additionalUsers.
+func (r *RabbitmqClusterReconciler) clustersReferencingAdditionalUserSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	return r.clustersReferencing(ctx, secret, additionalUsersSecretKey)
+}
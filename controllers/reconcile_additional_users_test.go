@@ -0,0 +1,85 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("AdditionalUsers", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		userSecret       *corev1.Secret
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	BeforeEach(func() {
+		userSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-user",
+				Namespace: defaultNamespace,
+			},
+			Data: map[string][]byte{
+				"username": []byte("app"),
+				"password": []byte("it's-secret"),
+			},
+		}
+		Expect(client.Create(ctx, userSecret)).To(Succeed())
+
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-additional-users",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Rabbitmq: rabbitmqv1beta1.RabbitmqClusterConfigurationSpec{
+					AdditionalUsers: []rabbitmqv1beta1.RabbitmqUser{{
+						SecretName:  userSecret.Name,
+						Tags:        []string{"monitoring"},
+						Permissions: []rabbitmqv1beta1.RabbitmqUserPermissions{{VHost: "/", Write: "^app\\."}},
+					}},
+				},
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+		Expect(client.Delete(ctx, userSecret)).To(Succeed())
+	})
+
+	It("creates the users once the nodes are ready and deletes them when they are removed", func() {
+		Eventually(func() []rabbitmqv1beta1.RabbitmqClusterUserStatus {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Status.AdditionalUsers
+		}, 5).Should(ConsistOf(HaveField("Username", "app")))
+		Expect(fakeExecutor.ExecutedCommands()).To(ContainElement(command{"sh", "-c",
+			`if rabbitmqctl list_users --silent | cut -f1 | grep -qxF 'app'; then rabbitmqctl change_password 'app' 'it'"'"'s-secret'; ` +
+				`else rabbitmqctl add_user 'app' 'it'"'"'s-secret'; fi ` +
+				`&& rabbitmqctl set_user_tags 'app' 'monitoring' ` +
+				`&& rabbitmqctl set_permissions -p '/' 'app' '.*' '^app\.' '.*'`}))
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Spec.Rabbitmq.AdditionalUsers = nil
+		})).To(Succeed())
+		Eventually(func() []rabbitmqv1beta1.RabbitmqClusterUserStatus {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Status.AdditionalUsers
+		}, 5).Should(BeEmpty())
+		Expect(fakeExecutor.ExecutedCommands()).To(ContainElement(command{"sh", "-c", "rabbitmqctl delete_user 'app'"}))
+	})
+})
@@ -0,0 +1,81 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Adoption of existing child resources", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		existing         *corev1.Service
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-adoption",
+				Namespace: defaultNamespace,
+			},
+		}
+		// a Service created by a previous Helm deployment
+		existing = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.ChildResourceName(""),
+				Namespace: defaultNamespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "Helm"},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: "amqp", Port: 5672}},
+			},
+		}
+		Expect(client.Create(ctx, existing)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+		Expect(runtimeClient.IgnoreNotFound(client.Delete(ctx, existing))).To(Succeed())
+	})
+
+	It("only takes over child resources without controller if adoption is enabled", func() {
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+
+		By("setting ReconcileSuccess to 'false'", func() {
+			Eventually(func() string {
+				rabbit := &rabbitmqv1beta1.RabbitmqCluster{}
+				Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), rabbit)).To(Succeed())
+				for i := range rabbit.Status.Conditions {
+					if rabbit.Status.Conditions[i].Type == status.ReconcileSuccess {
+						return fmt.Sprintf("%s %s", rabbit.Status.Conditions[i].Status, rabbit.Status.Conditions[i].Reason)
+					}
+				}
+				return "condition not present"
+			}, 5).Should(Equal("False ChildResourceNotControlled"))
+		})
+
+		By("setting the RabbitmqCluster as controller once adoption is enabled", func() {
+			Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+				r.Annotations = map[string]string{"rabbitmq.com/adoptExistingResources": "true"}
+			})).To(Succeed())
+
+			Eventually(func() *metav1.OwnerReference {
+				svc := &corev1.Service{}
+				Expect(client.Get(ctx, types.NamespacedName{Namespace: defaultNamespace, Name: existing.Name}, svc)).To(Succeed())
+				return metav1.GetControllerOf(svc)
+			}, 5).ShouldNot(BeNil())
+			waitForClusterCreation(ctx, cluster, client)
+		})
+	})
+})
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// csaFieldManagers are the field managers of updates made without server-side apply. Kubernetes names them
+// after the first part of the user agent, which is the name of the operator binary.
+var csaFieldManagers = sets.New(strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0])
+
+// applyChildResource applies a child resource with server-side apply, using resource.FieldManager.
+// obj is the object returned by builder.Build(). The state the builder preserves is read from the existing
+// child resource with c, falling back to reader if it is not in the cache. Fields previously set with updates
+// are transferred to resource.FieldManager, so that fields which are no longer applied are removed.
+// mutate, if not nil, is called after builder.Update(). It returns the existing child resource, or nil if it was created.
+// An existing child resource without controller is only taken over if adopt is true.
+func applyChildResource(ctx context.Context, c client.Client, reader client.Reader, builder resource.ResourceBuilder, obj client.Object, mutate func(client.Object), adopt bool) (controllerutil.OperationResult, client.Object, error) {
+	key := client.ObjectKeyFromObject(obj)
+	existing := obj.DeepCopyObject().(client.Object)
+	err := c.Get(ctx, key, existing)
+	if k8serrors.IsNotFound(err) {
+		err = reader.Get(ctx, key, existing)
+	}
+	if k8serrors.IsNotFound(err) {
+		existing = nil
+	} else if err != nil {
+		return controllerutil.OperationResultNone, nil, err
+	}
+
+	if existing != nil {
+		obj.SetAnnotations(metadata.ReconcileAnnotations(obj.GetAnnotations(), metadata.OperatorAnnotations(existing.GetAnnotations())))
+		if preserver, ok := builder.(resource.StatePreserver); ok {
+			preserver.Preserve(existing, obj)
+		}
+	}
+	if err := builder.Update(obj); err != nil {
+		return controllerutil.OperationResultNone, existing, err
+	}
+	if mutate != nil {
+		mutate(obj)
+	}
+	if existing != nil && !adopt && metav1.GetControllerOf(existing) == nil && metav1.GetControllerOf(obj) != nil {
+		return controllerutil.OperationResultNone, existing, fmt.Errorf("%w: set annotation %s to \"true\" on the RabbitmqCluster to adopt it",
+			status.ErrNotControlled, adoptResourcesAnnotation)
+	}
+
+	if existing != nil {
+		patch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, csaFieldManagers, resource.FieldManager)
+		if err != nil {
+			return controllerutil.OperationResultNone, existing, fmt.Errorf("failed to migrate managed fields: %w", err)
+		}
+		if patch != nil {
+			migrated := existing.DeepCopyObject().(client.Object)
+			if err := c.Patch(ctx, migrated, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+				return controllerutil.OperationResultNone, existing, fmt.Errorf("failed to migrate managed fields: %w", err)
+			}
+			existing = migrated
+		}
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return controllerutil.OperationResultNone, existing, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+	if err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(resource.FieldManager), client.ForceOwnership); err != nil {
+		return controllerutil.OperationResultNone, existing, err
+	}
+
+	if existing == nil {
+		return controllerutil.OperationResultCreated, nil, nil
+	}
+	if obj.GetResourceVersion() != existing.GetResourceVersion() && !onlyManagedFieldsChanged(existing, obj) {
+		return controllerutil.OperationResultUpdated, existing, nil
+	}
+	return controllerutil.OperationResultNone, existing, nil
+}
+
+// onlyManagedFieldsChanged returns true if the objects only differ in their managed fields and resource version,
+// as they do when fields are no longer applied but keep their value.
+func onlyManagedFieldsChanged(previous, current client.Object) bool {
+	previous, current = previous.DeepCopyObject().(client.Object), current.DeepCopyObject().(client.Object)
+	for _, obj := range []client.Object{previous, current} {
+		obj.SetManagedFields(nil)
+		obj.SetResourceVersion("")
+		obj.GetObjectKind().SetGroupVersionKind(previous.GetObjectKind().GroupVersionKind())
+	}
+	return equality.Semantic.DeepEqual(previous, current)
+}
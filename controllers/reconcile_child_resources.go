@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	"github.com/rabbitmq/cluster-operator/v2/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// childCluster is the Kubernetes cluster the child resources of a RabbitmqCluster are applied to: the cluster of the
+// operator, or the remote cluster of spec.remoteCluster.
+type childCluster struct {
+	client.Client
+	// reader reads the child resources which are not in the cache of the client.
+	reader client.Reader
+	// clientset expands the PersistentVolumeClaims of the StatefulSet.
+	clientset kubernetes.Interface
+	// mutate is called on each child resource before it is applied.
+	mutate func(client.Object)
+}
+
+// localCluster returns the cluster of the operator, whose child resources are labelled and owned by the RabbitmqCluster.
+func (r *RabbitmqClusterReconciler) localCluster(rmq *rabbitmqv1beta1.RabbitmqCluster) childCluster {
+	return childCluster{
+		Client:    r.Client,
+		reader:    r.APIReader,
+		clientset: r.Clientset,
+		mutate:    labelManagedResource(rmq),
+	}
+}
+
+// applyChildResources applies the child resources of builders to cluster, and records the image, replica and
+// configuration changes in status.history. The StatefulSet is neither applied when it would scale the RabbitmqCluster
+// down or change its version in an unsupported way, nor when a child resource before it, which its Pods depend on,
+// failed to apply. It returns the keys of the applied child resources, and whether reconciling must stop.
+func (r *RabbitmqClusterReconciler) applyChildResources(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster, cluster childCluster, builders []resource.ResourceBuilder) (sets.Set[string], bool, error) {
+	logger := ctrl.LoggerFrom(ctx)
+	// changes applied to the child resources, which are added to status.history once all child resources are applied
+	var changes rabbitmqv1beta1.RabbitmqClusterStatus
+	applied := sets.New[string]()
+	// child resources which fail to apply do not stop the others from being applied, except for the StatefulSet
+	// which is only applied once all resources before it, which its Pods depend on, were applied
+	var applyErrs []error
+	var applyFailures []string
+
+	for _, builder := range builders {
+		// only StatefulSetBuilder returns true
+		if builder.UpdateMayRequireStsRecreate() && len(applyErrs) > 0 {
+			logger.Info("not applying StatefulSet until the child resources its Pods depend on are applied")
+			continue
+		}
+
+		resource, err := builder.Build()
+		if err != nil {
+			return nil, true, err
+		}
+
+		if builder.UpdateMayRequireStsRecreate() {
+			sts := resource.DeepCopyObject().(*appsv1.StatefulSet)
+
+			current, err := getStatefulSet(ctx, cluster, rabbitmqCluster)
+			if client.IgnoreNotFound(err) != nil {
+				return nil, true, err
+			}
+
+			// only checks for scale down if statefulSet is created
+			// else continue to applying it
+			if !k8serrors.IsNotFound(err) {
+				if err := builder.Update(sts); err != nil {
+					return nil, true, err
+				}
+				if r.scaleDown(ctx, rabbitmqCluster, current, sts) {
+					// return when cluster scale down detected; unsupported operation
+					return nil, true, nil
+				}
+				if r.unsupportedVersionChange(ctx, rabbitmqCluster, current, sts) {
+					// return when downgrade or skipped minor version detected; unsupported operation
+					return nil, true, nil
+				}
+			}
+
+			// The PVCs for the StatefulSet may require expanding
+			if err = r.reconcilePVC(ctx, cluster.clientset, rabbitmqCluster, sts); err != nil {
+				r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "FailedReconcilePVC", err.Error())
+				return nil, true, err
+			}
+		}
+
+		gvk, err := apiutil.GVKForObject(resource, r.Scheme)
+		if err != nil {
+			return nil, true, err
+		}
+		adopt := rabbitmqCluster.Annotations[adoptResourcesAnnotation] == "true"
+		applyCtx, applySpan := tracing.Start(ctx, "apply "+gvk.Kind, attribute.String("k8s.object.name", resource.GetName()))
+		operationResult, previous, err := applyChildResource(applyCtx, cluster, cluster.reader, builder, resource, cluster.mutate, adopt)
+		applySpan.SetAttributes(attribute.String("operation.result", string(operationResult)))
+		tracing.End(applySpan, err)
+		r.logAndRecordOperationResult(logger, rabbitmqCluster, resource, operationResult, err)
+		if err != nil {
+			applyErrs = append(applyErrs, err)
+			applyFailures = append(applyFailures, r.applyFailureMessage(resource, err))
+			continue
+		}
+		// child resources in remote clusters have no controller, so they are never adopted
+		if previous != nil && metav1.GetControllerOf(previous) == nil && metav1.GetControllerOf(resource) != nil {
+			msg := fmt.Sprintf("adopted resource %s of Type %T", resource.GetName(), resource)
+			logger.Info(msg)
+			r.Recorder.Event(rabbitmqCluster, corev1.EventTypeNormal, "SuccessfulAdopt", msg)
+		}
+
+		if err = r.annotateIfNeeded(ctx, cluster, logger, builder, operationResult, rabbitmqCluster); err != nil {
+			return nil, true, err
+		}
+
+		if operationResult == controllerutil.OperationResultCreated {
+			recordChildResourceCreated(rabbitmqCluster, gvk.Kind)
+		}
+
+		recordAppliedChanges(&changes, builder, operationResult, previous, resource)
+
+		key, err := childResourceKey(resource, r.Scheme)
+		if err != nil {
+			return nil, true, err
+		}
+		applied.Insert(key)
+	}
+
+	if len(changes.History) > 0 {
+		if err := r.updateStatus(ctx, rabbitmqCluster, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+			for _, change := range changes.History {
+				clusterStatus.RecordChange(change.Type, change.Previous, change.Current)
+			}
+		}); err != nil {
+			return nil, true, err
+		}
+	}
+
+	if len(applyErrs) > 0 {
+		r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, status.ApplyFailureReason(applyErrs[0]), strings.Join(applyFailures, "; "))
+		return nil, true, errors.Join(applyErrs...)
+	}
+	return applied, false, nil
+}
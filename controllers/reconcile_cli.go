@@ -30,22 +30,24 @@ func (r *RabbitmqClusterReconciler) runRabbitmqCLICommandsIfAnnotated(ctx contex
 	if client.IgnoreNotFound(err) != nil {
 		return 0, err
 	}
-	updatedRecently, err := pluginsConfigUpdatedRecently(pluginsConfig)
-	if err != nil {
-		return 0, err
-	}
-	if updatedRecently {
-		// plugins configMap was updated very recently
-		// give StatefulSet controller some time to trigger restart of StatefulSet if necessary
-		// otherwise, there would be race conditions where we exec into containers losing the connection due to pods being terminated
-		logger.V(1).Info("requeuing request to set plugins")
-		return 2 * time.Second, nil
-	}
-
-	if pluginsConfig.ObjectMeta.Annotations != nil && pluginsConfig.ObjectMeta.Annotations[pluginsUpdateAnnotation] != "" {
-		if err = r.runSetPluginsCommand(ctx, rmq, pluginsConfig); err != nil {
+	if pluginsConfig != nil {
+		updatedRecently, err := pluginsConfigUpdatedRecently(pluginsConfig)
+		if err != nil {
 			return 0, err
 		}
+		if updatedRecently {
+			// plugins configMap was updated very recently
+			// give StatefulSet controller some time to trigger restart of StatefulSet if necessary
+			// otherwise, there would be race conditions where we exec into containers losing the connection due to pods being terminated
+			logger.V(1).Info("requeuing request to set plugins")
+			return 2 * time.Second, nil
+		}
+
+		if pluginsConfig.ObjectMeta.Annotations != nil && pluginsConfig.ObjectMeta.Annotations[pluginsUpdateAnnotation] != "" {
+			if err = r.runSetPluginsCommand(ctx, rmq, pluginsConfig); err != nil {
+				return 0, err
+			}
+		}
 	}
 
 	// If RabbitMQ cluster is newly created, enable all feature flags since some are disabled by default
@@ -86,7 +88,7 @@ func (r *RabbitmqClusterReconciler) runEnableFeatureFlagsCommand(ctx context.Con
 // This method implements the 2nd path.
 func (r *RabbitmqClusterReconciler) runSetPluginsCommand(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, configMap *corev1.ConfigMap) error {
 	logger := ctrl.LoggerFrom(ctx)
-	plugins := resource.NewRabbitmqPlugins(rmq.Spec.Rabbitmq.AdditionalPlugins)
+	plugins := resource.NewRabbitmqPlugins(rmq.RequestedPlugins())
 	for i := int32(0); i < *rmq.Spec.Replicas; i++ {
 		podName := fmt.Sprintf("%s-%d", rmq.ChildResourceName("server"), i)
 		cmd := fmt.Sprintf("rabbitmq-plugins set %s", plugins.AsString(" "))
@@ -117,16 +119,28 @@ func (r *RabbitmqClusterReconciler) runQueueRebalanceCommand(ctx context.Context
 	return r.deleteAnnotation(ctx, rmq, queueRebalanceAnnotation)
 }
 
+// statefulSetNeedsQueueRebalance returns true if the StatefulSet is being updated or is about to be scaled up,
+// so that queue leaders are spread across all nodes once the new replicas joined and became ready.
 func statefulSetNeedsQueueRebalance(sts *appsv1.StatefulSet, rmq *rabbitmqv1beta1.RabbitmqCluster) bool {
-	return statefulSetBeingUpdated(sts) &&
+	return (statefulSetBeingUpdated(sts) || statefulSetScalingUp(sts, rmq)) &&
 		!rmq.Spec.SkipPostDeploySteps &&
 		*rmq.Spec.Replicas > 1
 }
 
+func statefulSetScalingUp(sts *appsv1.StatefulSet, rmq *rabbitmqv1beta1.RabbitmqCluster) bool {
+	return sts.Spec.Replicas != nil && *rmq.Spec.Replicas > *sts.Spec.Replicas
+}
+
 func allReplicasReadyAndUpdated(sts *appsv1.StatefulSet) bool {
 	return sts.Status.ReadyReplicas == *sts.Spec.Replicas && !statefulSetBeingUpdated(sts)
 }
 
+// statefulSetBeingUpdated returns true if not all Pods run the update revision of the StatefulSet.
+// The StatefulSet controller only advances the current revision under the RollingUpdate strategy, so under the
+// OnDelete strategy of health gated updates the updated replicas are counted instead.
 func statefulSetBeingUpdated(sts *appsv1.StatefulSet) bool {
+	if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return sts.Status.UpdatedReplicas != sts.Status.Replicas
+	}
 	return sts.Status.CurrentRevision != sts.Status.UpdateRevision
 }
@@ -65,6 +65,45 @@ var _ = Describe("Reconcile CLI", func() {
 		})
 	})
 
+	When("additional plugins are changed", func() {
+		var sts *appsv1.StatefulSet
+
+		BeforeEach(func() {
+			cluster = &rabbitmqv1beta1.RabbitmqCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rabbitmq-plugins",
+					Namespace: defaultNamespace,
+				},
+				Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+					Replicas: ptr.To(int32(3)),
+				},
+			}
+			Expect(client.Create(ctx, cluster)).To(Succeed())
+			waitForClusterCreation(ctx, cluster, client)
+
+			sts = statefulSet(ctx, cluster)
+			sts.Status.Replicas = 3
+			sts.Status.ReadyReplicas = 3
+			Expect(client.Status().Update(ctx, sts)).To(Succeed())
+
+			Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+				r.Spec.Rabbitmq.AdditionalPlugins = []rabbitmqv1beta1.Plugin{"rabbitmq_shovel"}
+			})).To(Succeed())
+		})
+
+		It("sets the plugins on the running Pods without restarting the StatefulSet", func() {
+			Eventually(func() map[string]string {
+				return configMap(ctx, cluster, "plugins-conf").Annotations
+			}, 10).ShouldNot(HaveKey("rabbitmq.com/pluginsUpdatedAt"))
+			Expect(fakeExecutor.ExecutedCommands()).To(ContainElement(command{"sh", "-c",
+				"rabbitmq-plugins set rabbitmq_peer_discovery_k8s rabbitmq_prometheus rabbitmq_management rabbitmq_shovel"}))
+
+			Consistently(func() map[string]string {
+				return statefulSet(ctx, cluster).Spec.Template.Annotations
+			}, 3, 0.3).ShouldNot(HaveKey("rabbitmq.com/lastRestartAt"))
+		})
+	})
+
 	When("the cluster is configured to run post-deploy steps", func() {
 		BeforeEach(func() {
 			cluster = &rabbitmqv1beta1.RabbitmqCluster{
@@ -137,6 +176,35 @@ var _ = Describe("Reconcile CLI", func() {
 				})
 			})
 		})
+
+		When("the cluster is scaled up", func() {
+			It("runs rabbitmq-queues rebalance all once the new replicas are ready", func() {
+				Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+					r.Spec.Replicas = ptr.To(int32(5))
+				})).To(Succeed())
+
+				By("setting an annotation on the CR", func() {
+					Eventually(func() map[string]string {
+						rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+						Expect(client.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, rmq)).To(Succeed())
+						return rmq.ObjectMeta.Annotations
+					}, 5).Should(HaveKey("rabbitmq.com/queueRebalanceNeededAt"))
+				})
+
+				By("removing the annotation once all Pods are up, and triggering the queue rebalance", func() {
+					sts := statefulSet(ctx, cluster)
+					sts.Status.Replicas = 5
+					sts.Status.ReadyReplicas = 5
+					Expect(client.Status().Update(ctx, sts)).To(Succeed())
+					Eventually(func() map[string]string {
+						rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+						Expect(client.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, rmq)).To(Succeed())
+						return rmq.ObjectMeta.Annotations
+					}, 5).ShouldNot(HaveKey("rabbitmq.com/queueRebalanceNeededAt"))
+					Expect(fakeExecutor.ExecutedCommands()).To(ContainElement(command{"sh", "-c", "rabbitmq-queues rebalance all"}))
+				})
+			})
+		})
 	})
 
 	When("the cluster is not configured to run post-deploy steps", func() {
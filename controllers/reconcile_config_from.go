@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// configFromKey indexes RabbitmqClusters by the name of the ConfigMap referenced in spec.rabbitmq.configFrom.
+const configFromKey = ".spec.rabbitmq.configFrom.configMapRef.name"
+
+// configFrom returns the rabbitmq.conf settings of the ConfigMap referenced by spec.rabbitmq.configFrom.
+func (r *RabbitmqClusterReconciler) configFrom(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (string, error) {
+	source := rmq.Spec.Rabbitmq.ConfigFrom
+	if source == nil {
+		return "", nil
+	}
+
+	// the ConfigMap is read from the API server, because only ConfigMaps labelled as part of rabbitmq are cached
+	configMap := &corev1.ConfigMap{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: source.ConfigMapRef.Name}, configMap); err != nil {
+		msg := fmt.Sprintf("failed to get ConfigMap %s referenced in spec.rabbitmq.configFrom", source.ConfigMapRef.Name)
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", msg)
+		return "", fmt.Errorf("%s: %w", msg, err)
+	}
+	conf, ok := configMap.Data[source.ConfigMapKey()]
+	if !ok {
+		msg := fmt.Sprintf("ConfigMap %s referenced in spec.rabbitmq.configFrom has no key %s", source.ConfigMapRef.Name, source.ConfigMapKey())
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", msg)
+		return "", fmt.Errorf("%s", msg)
+	}
+	return conf, nil
+}
+
+func indexConfigFrom(rawObj client.Object) []string {
+	rmq := rawObj.(*rabbitmqv1beta1.RabbitmqCluster)
+	if rmq.Spec.Rabbitmq.ConfigFrom == nil {
+		return nil
+	}
+	return []string{rmq.Spec.Rabbitmq.ConfigFrom.ConfigMapRef.Name}
+}
+
+// clustersReferencingConfigMap maps a ConfigMap to the RabbitmqClusters that reference it in spec.rabbitmq.configFrom.
+func (r *RabbitmqClusterReconciler) clustersReferencingConfigMap(ctx context.Context, configMap client.Object) []reconcile.Request {
+	return r.clustersReferencing(ctx, configMap, configFromKey)
+}
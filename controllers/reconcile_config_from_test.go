@@ -0,0 +1,68 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ConfigFrom", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		userConfigMap    *corev1.ConfigMap
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	BeforeEach(func() {
+		// the ConfigMap is not labelled as part of rabbitmq, so the manager does not cache it
+		userConfigMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "user-rabbitmq-conf",
+				Namespace: defaultNamespace,
+			},
+			Data: map[string]string{"rabbitmq.conf": "log.console.level = debug"},
+		}
+		Expect(client.Create(ctx, userConfigMap)).To(Succeed())
+
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-config-from",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Rabbitmq: rabbitmqv1beta1.RabbitmqClusterConfigurationSpec{
+					ConfigFrom: &rabbitmqv1beta1.ConfigFromSource{
+						ConfigMapRef: corev1.LocalObjectReference{Name: userConfigMap.Name},
+					},
+				},
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+		Expect(client.Delete(ctx, userConfigMap)).To(Succeed())
+	})
+
+	It("copies the settings into the server configuration and restarts the StatefulSet when they change", func() {
+		Expect(configMap(ctx, cluster, "server-conf").Data).To(HaveKeyWithValue("configFrom.conf", "log.console.level = debug\n"))
+
+		userConfigMap.Data["rabbitmq.conf"] = "log.console.level = info"
+		Expect(client.Update(ctx, userConfigMap)).To(Succeed())
+
+		Eventually(func() map[string]string {
+			return configMap(ctx, cluster, "server-conf").Data
+		}, 5).Should(HaveKeyWithValue("configFrom.conf", "log.console.level = info\n"))
+		Eventually(func() map[string]string {
+			return statefulSet(ctx, cluster).Spec.Template.Annotations
+		}, 5).Should(HaveKey("rabbitmq.com/lastRestartAt"))
+	})
+})
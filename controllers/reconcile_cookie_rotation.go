@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// rotateErlangCookieAnnotation requests a new Erlang cookie. The operator removes the annotation once the cookie was rotated.
+	rotateErlangCookieAnnotation = "rabbitmq.com/rotateErlangCookie"
+	// pendingErlangCookieKey holds the new cookie in the erlang-cookie Secret while the nodes switch to it,
+	// so that a rotation interrupted by an operator restart can be completed with the same cookie.
+	pendingErlangCookieKey = "pending_cookie"
+)
+
+// rotateErlangCookieIfAnnotated replaces the Erlang cookie of running nodes without restarting them,
+// when the RabbitmqCluster is annotated with rabbitmq.com/rotateErlangCookie.
+// Erlang only checks cookies when nodes connect, so established connections between nodes are kept.
+// The rotation uses two cookies, so that nodes can connect to each other at any time:
+//  1. every node uses the new cookie for new connections, but keeps the old cookie for the connections to its peers.
+//     The new cookie is written to the cookie file used by the CLI tools.
+//  2. every node uses the new cookie for the connections to its peers.
+//
+// The erlang-cookie Secret is updated last, for nodes started afterwards.
+func (r *RabbitmqClusterReconciler) rotateErlangCookieIfAnnotated(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
+	if rmq.Annotations[rotateErlangCookieAnnotation] == "" {
+		return 0, nil
+	}
+	logger := ctrl.LoggerFrom(ctx)
+
+	if rmq.ExistingErlangCookieSecretEnabled() {
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "ErlangCookieNotRotated",
+			"the Erlang cookie can only be rotated when the operator generates the cookie")
+		return 0, r.deleteAnnotation(ctx, rmq, rotateErlangCookieAnnotation)
+	}
+
+	sts, err := r.statefulSet(ctx, rmq)
+	if err != nil {
+		return 0, err
+	}
+	if !allReplicasReadyAndUpdated(sts) {
+		logger.V(1).Info("not all replicas ready yet; requeuing request to rotate the Erlang cookie")
+		return 15 * time.Second, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: resource.ErlangCookieSecretName(rmq)}, secret); err != nil {
+		return 0, err
+	}
+	if metadata.ManagedByExternal(secret.Annotations) {
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "ErlangCookieNotRotated",
+			fmt.Sprintf("the Erlang cookie cannot be rotated because Secret %s is managed by another controller", secret.Name))
+		return 0, r.deleteAnnotation(ctx, rmq, rotateErlangCookieAnnotation)
+	}
+	oldCookie := string(secret.Data[resource.ErlangCookieKey])
+	newCookie := string(secret.Data[pendingErlangCookieKey])
+	if newCookie == "" {
+		if newCookie, err = resource.GenerateErlangCookie(); err != nil {
+			return 0, err
+		}
+		secret.Data[pendingErlangCookieKey] = []byte(newCookie)
+		if err := r.Update(ctx, secret); err != nil {
+			return 0, err
+		}
+	}
+
+	commands := []string{
+		fmt.Sprintf(`rabbitmqctl eval 'erlang:set_cookie(list_to_atom("%s")), [erlang:set_cookie(P, list_to_atom("%s")) || P <- nodes()], ok.' `+
+			`&& printf '%%s' '%s' > /var/lib/rabbitmq/.erlang.cookie.new && chmod 600 /var/lib/rabbitmq/.erlang.cookie.new `+
+			`&& mv /var/lib/rabbitmq/.erlang.cookie.new /var/lib/rabbitmq/.erlang.cookie`, newCookie, oldCookie, newCookie),
+		fmt.Sprintf(`rabbitmqctl eval '[erlang:set_cookie(P, list_to_atom("%s")) || P <- nodes()], ok.'`, newCookie),
+	}
+	for _, cmd := range commands {
+		for i := int32(0); i < *rmq.Spec.Replicas; i++ {
+			podName := fmt.Sprintf("%s-%d", rmq.ChildResourceName("server"), i)
+			stdout, stderr, err := r.exec(rmq.Namespace, podName, "rabbitmq", "sh", "-c", cmd)
+			if err != nil {
+				msg := "failed to rotate the Erlang cookie on pod"
+				r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", fmt.Sprintf("%s %s", msg, podName))
+				// the rotation is retried with backoff
+				return 0, fmt.Errorf("%s %s: %w: stdout: %s, stderr: %s", msg, podName, err, stdout, stderr)
+			}
+		}
+	}
+
+	secret.Data[resource.ErlangCookieKey] = []byte(newCookie)
+	delete(secret.Data, pendingErlangCookieKey)
+	if err := r.Update(ctx, secret); err != nil {
+		return 0, err
+	}
+
+	logger.Info("successfully rotated the Erlang cookie")
+	r.Recorder.Event(rmq, corev1.EventTypeNormal, "ErlangCookieRotated", fmt.Sprintf("updated the Erlang cookie in Secret %s", secret.Name))
+	return 0, r.deleteAnnotation(ctx, rmq, rotateErlangCookieAnnotation)
+}
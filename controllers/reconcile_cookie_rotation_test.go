@@ -0,0 +1,64 @@
+package controllers_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Reconcile Erlang cookie rotation", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+	)
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-cookie-rotation",
+				Namespace: defaultNamespace,
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+	})
+
+	It("switches the running nodes to a new cookie before updating the Secret", func() {
+		secretKey := types.NamespacedName{Namespace: defaultNamespace, Name: cluster.ChildResourceName("erlang-cookie")}
+		secret := &corev1.Secret{}
+		Expect(client.Get(ctx, secretKey, secret)).To(Succeed())
+		oldCookie := string(secret.Data[".erlang.cookie"])
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Annotations = map[string]string{"rabbitmq.com/rotateErlangCookie": "true"}
+		})).To(Succeed())
+
+		Eventually(func() map[string]string {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Annotations
+		}, 5).ShouldNot(HaveKey("rabbitmq.com/rotateErlangCookie"))
+
+		Expect(client.Get(ctx, secretKey, secret)).To(Succeed())
+		newCookie := string(secret.Data[".erlang.cookie"])
+		Expect(newCookie).NotTo(Equal(oldCookie))
+		Expect(secret.Data).NotTo(HaveKey("pending_cookie"))
+		Expect(fakeExecutor.ExecutedCommands()).To(ContainElement(command{"sh", "-c",
+			fmt.Sprintf(`rabbitmqctl eval '[erlang:set_cookie(P, list_to_atom("%s")) || P <- nodes()], ok.'`, newCookie)}))
+	})
+})
@@ -0,0 +1,145 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Management Service", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		Eventually(func() bool {
+			rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+			err := client.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, rmq)
+			return apierrors.IsNotFound(err)
+		}, 5).Should(BeTrue())
+	})
+
+	It("creates and deletes the management Service", func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-management-service",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Replicas: ptr.To(int32(1)),
+				ManagementService: &rabbitmqv1beta1.RabbitmqClusterManagementServiceSpec{
+					Type: corev1.ServiceTypeNodePort,
+				},
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		By("creating the management Service", func() {
+			Eventually(func() corev1.ServiceType {
+				svc, err := clientSet.CoreV1().Services(defaultNamespace).Get(ctx, cluster.ChildResourceName("management"), metav1.GetOptions{})
+				if err != nil {
+					return ""
+				}
+				return svc.Spec.Type
+			}, 5).Should(Equal(corev1.ServiceTypeNodePort))
+		})
+
+		By("deleting the management Service when it is removed from the spec", func() {
+			Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+				r.Spec.ManagementService = nil
+			})).To(Succeed())
+			Eventually(func() bool {
+				_, err := clientSet.CoreV1().Services(defaultNamespace).Get(ctx, cluster.ChildResourceName("management"), metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}, 5).Should(BeTrue())
+		})
+	})
+
+	It("creates and deletes the management Ingress", func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-management-ingress",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Replicas: ptr.To(int32(1)),
+				ManagementIngress: &rabbitmqv1beta1.RabbitmqClusterManagementIngressSpec{
+					Host: "rabbitmq.example.com",
+				},
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		By("creating the management Ingress", func() {
+			Eventually(func() string {
+				ingress, err := clientSet.NetworkingV1().Ingresses(defaultNamespace).Get(ctx, cluster.ChildResourceName("management"), metav1.GetOptions{})
+				if err != nil {
+					return ""
+				}
+				return ingress.Spec.Rules[0].Host
+			}, 5).Should(Equal("rabbitmq.example.com"))
+		})
+
+		By("deleting the management Ingress when it is removed from the spec", func() {
+			Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+				r.Spec.ManagementIngress = nil
+			})).To(Succeed())
+			Eventually(func() bool {
+				_, err := clientSet.NetworkingV1().Ingresses(defaultNamespace).Get(ctx, cluster.ChildResourceName("management"), metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}, 5).Should(BeTrue())
+		})
+	})
+	It("creates a Service per Pod and deletes them when disabled", func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-pod-services",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Replicas: ptr.To(int32(2)),
+				PodServices: &rabbitmqv1beta1.RabbitmqClusterPodServicesSpec{
+					Type: corev1.ServiceTypeNodePort,
+				},
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		By("creating a Service selecting each Pod", func() {
+			for _, podName := range []string{cluster.ChildResourceName("server-0"), cluster.ChildResourceName("server-1")} {
+				Eventually(func() map[string]string {
+					svc, err := clientSet.CoreV1().Services(defaultNamespace).Get(ctx, podName, metav1.GetOptions{})
+					if err != nil {
+						return nil
+					}
+					return svc.Spec.Selector
+				}, 5).Should(HaveKeyWithValue("statefulset.kubernetes.io/pod-name", podName))
+			}
+		})
+
+		By("deleting the Services when they are removed from the spec", func() {
+			Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+				r.Spec.PodServices = nil
+			})).To(Succeed())
+			for _, podName := range []string{cluster.ChildResourceName("server-0"), cluster.ChildResourceName("server-1")} {
+				Eventually(func() bool {
+					_, err := clientSet.CoreV1().Services(defaultNamespace).Get(ctx, podName, metav1.GetOptions{})
+					return apierrors.IsNotFound(err)
+				}, 5).Should(BeTrue())
+			}
+		})
+	})
+})
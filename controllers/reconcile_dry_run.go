@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"github.com/rabbitmq/cluster-operator/v2/pkg/snapshot"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// dryRunAnnotation stops the operator from applying child resources. Instead, it renders them to the
+	// dry-run ConfigMap, so that the changes of an operator upgrade or a spec change can be reviewed first.
+	dryRunAnnotation = "rabbitmq.com/dryRun"
+	// dryRunSuffix is the suffix of the ConfigMap holding the rendered child resources.
+	dryRunSuffix = "dry-run"
+	// dryRunManifestsKey is the key of the rendered child resources in the dry-run ConfigMap.
+	dryRunManifestsKey = "manifests.yaml"
+)
+
+// renderDryRun writes the child resources the operator would apply for a RabbitmqCluster as a multi-document YAML
+// to the ConfigMap <name>-dry-run. Secret values are redacted. The ConfigMap is deleted once dryRunAnnotation is removed.
+func (r *RabbitmqClusterReconciler) renderDryRun(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) error {
+	logger := ctrl.LoggerFrom(ctx)
+
+	configFrom, err := r.configFrom(ctx, rmq)
+	if err != nil {
+		return err
+	}
+	manifests, err := snapshot.Children(rmq, snapshot.Options{
+		LabelMappings:               r.LabelMappings,
+		RouteAPIAvailable:           r.RouteAPIAvailable,
+		GatewayAPIAvailable:         r.GatewayAPIAvailable,
+		PrometheusOperatorAvailable: r.PrometheusOperatorAvailable,
+		ConfigFrom:                  configFrom,
+		InjectedLabels:              r.InjectedLabels,
+		InjectedAnnotations:         r.InjectedAnnotations,
+		Scheme:                      r.Scheme,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render child resources: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rmq.ChildResourceName(dryRunSuffix),
+			Namespace: rmq.Namespace,
+		},
+	}
+	operationResult, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		configMap.Labels = metadata.GetLabels(rmq.Name, rmq.Labels)
+		configMap.Data = map[string]string{dryRunManifestsKey: string(manifests)}
+		return controllerutil.SetControllerReference(rmq, configMap, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write ConfigMap %s: %w", configMap.Name, err)
+	}
+	if operationResult != controllerutil.OperationResultNone {
+		msg := fmt.Sprintf("rendered child resources to ConfigMap %s without applying them; annotation '%s' is set to true", configMap.Name, dryRunAnnotation)
+		logger.Info(msg)
+		r.Recorder.Event(rmq, corev1.EventTypeNormal, "DryRun", msg)
+	}
+	return nil
+}
+
+// deleteDryRun deletes the dry-run ConfigMap once dryRunAnnotation is removed. Unlike the child resources, it does not
+// have metadata.ManagedResourceLabel, so it is not garbage collected.
+func (r *RabbitmqClusterReconciler) deleteDryRun(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) error {
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: rmq.ChildResourceName(dryRunSuffix), Namespace: rmq.Namespace}, configMap); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	// a ConfigMap with the same name might have been created by the user
+	if !metav1.IsControlledBy(configMap, rmq) {
+		return nil
+	}
+
+	if err := r.Client.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete ConfigMap %s: %w", configMap.Name, err)
+	}
+	ctrl.LoggerFrom(ctx).Info("deleted ConfigMap", "name", configMap.Name)
+	return nil
+}
@@ -0,0 +1,75 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Dry run", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "rabbitmq-dry-run",
+				Namespace:   defaultNamespace,
+				Annotations: map[string]string{"rabbitmq.com/dryRun": "true"},
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+	})
+
+	It("renders the child resources to a ConfigMap instead of applying them", func() {
+		configMap := &corev1.ConfigMap{}
+		configMapKey := types.NamespacedName{Namespace: defaultNamespace, Name: "rabbitmq-dry-run-dry-run"}
+		Eventually(func() error {
+			return client.Get(ctx, configMapKey, configMap)
+		}, 5).Should(Succeed())
+		Expect(configMap.Data["manifests.yaml"]).To(SatisfyAll(
+			ContainSubstring("kind: StatefulSet"),
+			ContainSubstring("name: rabbitmq-dry-run-server"),
+			ContainSubstring("kind: Secret"),
+			ContainSubstring("REDACTED"),
+		))
+		Expect(metav1.IsControlledBy(configMap, cluster)).To(BeTrue())
+		Expect(aggregateEventMsgs(ctx, cluster, "DryRun")).To(
+			ContainSubstring("rendered child resources to ConfigMap rabbitmq-dry-run-dry-run without applying them"))
+
+		Consistently(func() bool {
+			_, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+			return k8serrors.IsNotFound(err)
+		}, 3).Should(BeTrue())
+	})
+
+	It("applies the child resources and deletes the ConfigMap once the annotation is removed", func() {
+		configMapKey := types.NamespacedName{Namespace: defaultNamespace, Name: "rabbitmq-dry-run-dry-run"}
+		Eventually(func() error {
+			return client.Get(ctx, configMapKey, &corev1.ConfigMap{})
+		}, 5).Should(Succeed())
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			delete(r.Annotations, "rabbitmq.com/dryRun")
+		})).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+		// the operator deletes the ConfigMap with the permissions of its ClusterRole
+		Eventually(func() bool {
+			return k8serrors.IsNotFound(client.Get(ctx, configMapKey, &corev1.ConfigMap{}))
+		}, 5).Should(BeTrue())
+	})
+})
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// erlangCookieSecretKey indexes RabbitmqClusters by the name of the Secret referenced in spec.secretBackend.existingErlangCookieSecret.
+const erlangCookieSecretKey = ".spec.secretBackend.existingErlangCookieSecret.name"
+
+// checkErlangCookieSecret checks that the Secret referenced by spec.secretBackend.existingErlangCookieSecret exists and has the key
+// .erlang.cookie, since Pods mounting it would not start otherwise.
+func (r *RabbitmqClusterReconciler) checkErlangCookieSecret(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) error {
+	if !rmq.ExistingErlangCookieSecretEnabled() {
+		return nil
+	}
+	name := rmq.Spec.SecretBackend.ExistingErlangCookieSecret.Name
+
+	// the Secret is read from the API server, because only Secrets labelled as part of rabbitmq are cached
+	secret := &corev1.Secret{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: name}, secret); err != nil {
+		msg := fmt.Sprintf("failed to get Secret %s referenced in spec.secretBackend.existingErlangCookieSecret", name)
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", msg)
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if len(secret.Data[".erlang.cookie"]) == 0 {
+		msg := fmt.Sprintf("Secret %s referenced in spec.secretBackend.existingErlangCookieSecret has no key .erlang.cookie", name)
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", msg)
+		return fmt.Errorf("%s", msg)
+	}
+	return nil
+}
+
+func indexErlangCookieSecret(rawObj client.Object) []string {
+	rmq := rawObj.(*rabbitmqv1beta1.RabbitmqCluster)
+	if !rmq.ExistingErlangCookieSecretEnabled() {
+		return nil
+	}
+	return []string{rmq.Spec.SecretBackend.ExistingErlangCookieSecret.Name}
+}
+
+// clustersReferencingErlangCookieSecret maps a Secret to the RabbitmqClusters that reference it in spec.secretBackend.existingErlangCookieSecret.
+func (r *RabbitmqClusterReconciler) clustersReferencingErlangCookieSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	return r.clustersReferencing(ctx, secret, erlangCookieSecretKey)
+}
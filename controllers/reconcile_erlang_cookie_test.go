@@ -0,0 +1,69 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("ExistingErlangCookieSecret", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		cookieSecret     *corev1.Secret
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	BeforeEach(func() {
+		cookieSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "shared-erlang-cookie",
+				Namespace: defaultNamespace,
+			},
+		}
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-existing-cookie",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				SecretBackend: rabbitmqv1beta1.SecretBackend{
+					ExistingErlangCookieSecret: corev1.LocalObjectReference{Name: cookieSecret.Name},
+				},
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+		Expect(client.Delete(ctx, cookieSecret)).To(Succeed())
+	})
+
+	It("waits for the referenced Secret and mounts it instead of generating a cookie", func() {
+		Consistently(func() bool {
+			err := client.Get(ctx, types.NamespacedName{Namespace: defaultNamespace, Name: cluster.ChildResourceName("server")}, &appsv1.StatefulSet{})
+			return k8serrors.IsNotFound(err)
+		}, 2).Should(BeTrue())
+
+		cookieSecret.Data = map[string][]byte{".erlang.cookie": []byte("shared-cookie")}
+		Expect(client.Create(ctx, cookieSecret)).To(Succeed())
+
+		// the controller watches the referenced Secret, so the cluster is created without further changes
+		waitForClusterCreation(ctx, cluster, client)
+		Expect(statefulSet(ctx, cluster).Spec.Template.Spec.Volumes).To(ContainElement(SatisfyAll(
+			HaveField("Name", "erlang-cookie-secret"),
+			HaveField("Secret.SecretName", cookieSecret.Name),
+		)))
+		err := client.Get(ctx, types.NamespacedName{Namespace: defaultNamespace, Name: cluster.ChildResourceName("erlang-cookie")}, &corev1.Secret{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+})
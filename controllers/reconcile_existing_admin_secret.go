@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// existingAdminSecretKey indexes RabbitmqClusters by the name of the Secret referenced in spec.secretBackend.existingAdminSecret.
+const existingAdminSecretKey = ".spec.secretBackend.existingAdminSecret.name"
+
+// existingAdminCredentials returns the credentials of the Secret referenced by spec.secretBackend.existingAdminSecret,
+// or nil if it is not set.
+func (r *RabbitmqClusterReconciler) existingAdminCredentials(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (*resource.DefaultUserCredentials, error) {
+	if !rmq.ExistingAdminSecretEnabled() {
+		return nil, nil
+	}
+	name := rmq.Spec.SecretBackend.ExistingAdminSecret.Name
+
+	// the Secret is read from the API server, because only Secrets labelled as part of rabbitmq are cached
+	secret := &corev1.Secret{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: name}, secret); err != nil {
+		msg := fmt.Sprintf("failed to get Secret %s referenced in spec.secretBackend.existingAdminSecret", name)
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", msg)
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	for _, key := range []string{"username", "password"} {
+		if len(secret.Data[key]) == 0 {
+			msg := fmt.Sprintf("Secret %s referenced in spec.secretBackend.existingAdminSecret has no key %s", name, key)
+			r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", msg)
+			return nil, fmt.Errorf("%s", msg)
+		}
+	}
+	return &resource.DefaultUserCredentials{
+		Username: string(secret.Data["username"]),
+		Password: string(secret.Data["password"]),
+	}, nil
+}
+
+func indexExistingAdminSecret(rawObj client.Object) []string {
+	rmq := rawObj.(*rabbitmqv1beta1.RabbitmqCluster)
+	if !rmq.ExistingAdminSecretEnabled() {
+		return nil
+	}
+	return []string{rmq.Spec.SecretBackend.ExistingAdminSecret.Name}
+}
+
+// clustersReferencingExistingAdminSecret maps a Secret to the RabbitmqClusters that reference it in spec.secretBackend.existingAdminSecret.
+func (r *RabbitmqClusterReconciler) clustersReferencingExistingAdminSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	return r.clustersReferencing(ctx, secret, existingAdminSecretKey)
+}
@@ -0,0 +1,74 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("ExistingAdminSecret", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		adminSecret      *corev1.Secret
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	BeforeEach(func() {
+		// the Secret is not labelled as part of rabbitmq, so the manager does not cache it
+		adminSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "corporate-admin",
+				Namespace: defaultNamespace,
+			},
+			Data: map[string][]byte{
+				"username": []byte("corporate-admin"),
+				"password": []byte("corporate-password"),
+			},
+		}
+		Expect(client.Create(ctx, adminSecret)).To(Succeed())
+
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-existing-admin-secret",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				SecretBackend: rabbitmqv1beta1.SecretBackend{
+					ExistingAdminSecret: corev1.LocalObjectReference{Name: adminSecret.Name},
+				},
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+		Expect(client.Delete(ctx, adminSecret)).To(Succeed())
+	})
+
+	It("copies the credentials into the default-user Secret", func() {
+		secret := &corev1.Secret{}
+		Expect(client.Get(ctx, types.NamespacedName{Namespace: defaultNamespace, Name: cluster.ChildResourceName("default-user")}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("username", []byte("corporate-admin")))
+		Expect(secret.Data).To(HaveKeyWithValue("password", []byte("corporate-password")))
+		Expect(secret.Data).To(HaveKeyWithValue("default_user.conf", []byte("default_user = corporate-admin\ndefault_pass = corporate-password\n")))
+	})
+	It("updates the default-user Secret when the existing Secret changes", func() {
+		adminSecret.Data["password"] = []byte("rotated-password")
+		Expect(client.Update(ctx, adminSecret)).To(Succeed())
+
+		Eventually(func() map[string][]byte {
+			secret := &corev1.Secret{}
+			Expect(client.Get(ctx, types.NamespacedName{Namespace: defaultNamespace, Name: cluster.ChildResourceName("default-user")}, secret)).To(Succeed())
+			return secret.Data
+		}, 5).Should(HaveKeyWithValue("password", []byte("rotated-password")))
+	})
+})
@@ -8,8 +8,10 @@ import (
 	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	clientretry "k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,6 +37,27 @@ func (r *RabbitmqClusterReconciler) removeFinalizer(ctx context.Context, rabbitm
 }
 
 func (r *RabbitmqClusterReconciler) prepareForDeletion(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster) error {
+	// without remote clusters, the operator did not create child resources in the remote cluster
+	if controllerutil.ContainsFinalizer(rabbitmqCluster, deletionFinalizer) && rabbitmqCluster.RemoteClusterEnabled() && r.RemoteClustersEnabled {
+		// child resources in remote clusters are not garbage collected, so the finalizer is kept until they are deleted
+		if err := r.deleteRemoteChildResources(ctx, rabbitmqCluster); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				ctrl.LoggerFrom(ctx).Error(err, "Failed to delete child resources in remote cluster")
+				r.Recorder.Event(rabbitmqCluster, corev1.EventTypeWarning, "RemoteDeletionFailed", err.Error())
+				return err
+			}
+			// without the kubeconfig Secret, e.g. when the namespace is being deleted, the remote cluster cannot be reached anymore
+			ctrl.LoggerFrom(ctx).Error(err, "Not deleting child resources in remote cluster")
+			r.Recorder.Event(rabbitmqCluster, corev1.EventTypeWarning, "RemoteDeletionSkipped", err.Error())
+		}
+		r.remoteClients.forget(types.NamespacedName{Namespace: rabbitmqCluster.Namespace, Name: rabbitmqCluster.Spec.RemoteCluster.KubeconfigSecret.Name})
+		if err := r.removeFinalizer(ctx, rabbitmqCluster); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "Failed to remove finalizer for deletion")
+			return err
+		}
+		return nil
+	}
+
 	if controllerutil.ContainsFinalizer(rabbitmqCluster, deletionFinalizer) {
 		if err := clientretry.RetryOnConflict(clientretry.DefaultRetry, func() error {
 			uid, err := r.statefulSetUID(ctx, rabbitmqCluster)
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// labelManagedResource sets metadata.ManagedResourceLabel on a child resource before it is applied.
+func labelManagedResource(rmq *rabbitmqv1beta1.RabbitmqCluster) func(client.Object) {
+	return func(obj client.Object) {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[metadata.ManagedResourceLabel] = rmq.Name
+		obj.SetLabels(labels)
+	}
+}
+
+// childResourceKey identifies a child resource of a RabbitmqCluster by its kind and name.
+func childResourceKey(obj client.Object, scheme *runtime.Scheme) (string, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s", gvk.GroupKind(), obj.GetName()), nil
+}
+
+// garbageCollectChildResources deletes the child resources the operator applied for a RabbitmqCluster which no longer
+// correspond to its spec, e.g. the management Ingress once it is disabled or the Pod Services of removed replicas.
+// applied holds the childResourceKey of every child resource applied in this reconciliation.
+// Only child resources with metadata.ManagedResourceLabel which are controlled by the RabbitmqCluster are deleted.
+// Secrets are never deleted, since they might hold the only copy of credentials.
+func (r *RabbitmqClusterReconciler) garbageCollectChildResources(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, applied sets.Set[string]) error {
+	logger := ctrl.LoggerFrom(ctx)
+
+	for _, list := range r.managedResourceLists() {
+		if err := r.Client.List(ctx, list, client.InNamespace(rmq.Namespace), client.MatchingLabels{metadata.ManagedResourceLabel: rmq.Name}); err != nil {
+			return fmt.Errorf("failed to list %T: %w", list, err)
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			obj := item.(client.Object)
+			key, err := childResourceKey(obj, r.Scheme)
+			if err != nil {
+				return err
+			}
+			if applied.Has(key) || !metav1.IsControlledBy(obj, rmq) || !obj.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+			uid := obj.GetUID()
+			if err := r.Client.Delete(ctx, obj, client.Preconditions{UID: &uid}); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete obsolete child resource %s: %w", key, err)
+			}
+			msg := fmt.Sprintf("deleted obsolete child resource %s", key)
+			logger.Info(msg)
+			r.Recorder.Event(rmq, corev1.EventTypeNormal, "SuccessfulDelete", msg)
+		}
+	}
+	return nil
+}
+
+// managedResourceLists returns an empty list of every kind of child resource which is garbage collected.
+func (r *RabbitmqClusterReconciler) managedResourceLists() []client.ObjectList {
+	lists := []client.ObjectList{
+		&appsv1.StatefulSetList{},
+		&corev1.ServiceList{},
+		&corev1.ConfigMapList{},
+		&corev1.ServiceAccountList{},
+		&rbacv1.RoleList{},
+		&rbacv1.RoleBindingList{},
+		&networkingv1.IngressList{},
+	}
+	var unstructuredKinds []schema.GroupVersionKind
+	if r.RouteAPIAvailable {
+		unstructuredKinds = append(unstructuredKinds, resource.RouteGroupVersionKind)
+	}
+	if r.GatewayAPIAvailable {
+		unstructuredKinds = append(unstructuredKinds, resource.TCPRouteGroupVersionKind, resource.TLSRouteGroupVersionKind)
+	}
+	if r.PrometheusOperatorAvailable {
+		unstructuredKinds = append(unstructuredKinds, resource.ServiceMonitorGroupVersionKind, resource.PodMonitorGroupVersionKind, resource.PrometheusRuleGroupVersionKind)
+	}
+	for _, gvk := range unstructuredKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		lists = append(lists, list)
+	}
+	return lists
+}
@@ -0,0 +1,78 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Garbage collection of obsolete child resources", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-gc",
+				Namespace: defaultNamespace,
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+	})
+
+	It("labels child resources and deletes the ones which no longer correspond to the spec", func() {
+		svc := &corev1.Service{}
+		Expect(client.Get(ctx, types.NamespacedName{Namespace: defaultNamespace, Name: "rabbitmq-gc"}, svc)).To(Succeed())
+		Expect(svc.Labels).To(HaveKeyWithValue("rabbitmq.com/managedResourceOf", "rabbitmq-gc"))
+
+		// a resource created by the user with the label of the operator
+		unmanaged := &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-gc-user-role",
+				Namespace: defaultNamespace,
+				Labels:    map[string]string{"rabbitmq.com/managedResourceOf": "rabbitmq-gc"},
+			},
+		}
+		Expect(client.Create(ctx, unmanaged)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(runtimeClient.IgnoreNotFound(client.Delete(ctx, unmanaged))).To(Succeed())
+		})
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Spec.ServiceAccountName = "existing-service-account"
+		})).To(Succeed())
+
+		for _, obj := range []runtimeClient.Object{
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "rabbitmq-gc-server"}},
+			&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "rabbitmq-gc-peer-discovery"}},
+			&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "rabbitmq-gc-server"}},
+		} {
+			key := types.NamespacedName{Namespace: defaultNamespace, Name: obj.GetName()}
+			Eventually(func() bool {
+				return k8serrors.IsNotFound(client.Get(ctx, key, obj))
+			}, 5).Should(BeTrue(), "%T %s was not deleted", obj, key.Name)
+		}
+		Expect(aggregateEventMsgs(ctx, cluster, "SuccessfulDelete")).To(
+			ContainSubstring("deleted obsolete child resource ServiceAccount/rabbitmq-gc-server"))
+
+		Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(unmanaged), &rbacv1.Role{})).To(Succeed())
+		Expect(client.Get(ctx, types.NamespacedName{Namespace: defaultNamespace, Name: "rabbitmq-gc"}, &corev1.Service{})).To(Succeed())
+	})
+})
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/health"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pollClusterHealth updates status.nodes and the NoAlarms and NoPartitions conditions of all RabbitmqClusters every
+// HealthPollInterval, since cluster membership, alarms and partitions do not change any Kubernetes object which would
+// trigger a reconcile.
+// It runs in the background until ctx is cancelled.
+func (r *RabbitmqClusterReconciler) pollClusterHealth(ctx context.Context) error {
+	logger := ctrl.LoggerFrom(ctx).WithName("health")
+	ticker := time.NewTicker(r.HealthPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			clusters := &rabbitmqv1beta1.RabbitmqClusterList{}
+			if err := r.List(ctx, clusters); err != nil {
+				logger.Error(err, "failed to list RabbitmqClusters")
+				continue
+			}
+			for i := range clusters.Items {
+				rmq := &clusters.Items[i]
+				if err := r.updateClusterHealth(ctx, rmq); err != nil {
+					logger.Info("failed to update cluster health", "namespace", rmq.Namespace, "name", rmq.Name, "error", err.Error())
+				}
+			}
+		}
+	}
+}
+
+// updateClusterHealth records the readiness and cluster membership of the nodes of a RabbitmqCluster in status.nodes.
+// The cluster status is read from the first ready Pod. Its alarms and partitions are recorded in the NoAlarms and
+// NoPartitions conditions once all replicas are ready, and Events are emitted when alarms or partitions appear or clear.
+func (r *RabbitmqClusterReconciler) updateClusterHealth(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) error {
+	if rmq.RemoteClusterEnabled() || !rmq.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	sts, err := r.statefulSet(ctx, rmq)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(rmq.Namespace), client.MatchingLabels(metadata.LabelSelector(rmq.Name))); err != nil {
+		return err
+	}
+
+	var clusterStatus *health.ClusterStatus
+	var clusterStatusErr error
+	for _, pod := range pods.Items {
+		if !health.PodReady(pod) {
+			continue
+		}
+		clusterStatus, clusterStatusErr = r.clusterStatus(rmq.Namespace, pod.Name)
+		break
+	}
+
+	nodes := health.Nodes(pods.Items, clusterStatus)
+	healthKnown := clusterStatus != nil && allReplicasReadyAndUpdated(sts)
+	var alarms, partitions string
+	if healthKnown {
+		alarms, partitions = clusterStatus.AlarmsMessage(), clusterStatus.PartitionsMessage()
+	}
+	setHealth := func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		clusterStatus.Nodes = nodes
+		if healthKnown {
+			clusterStatus.SetClusterHealth(alarms, partitions)
+		}
+	}
+	desired := rmq.Status.DeepCopy()
+	setHealth(desired)
+	if reflect.DeepEqual(desired.Conditions, rmq.Status.Conditions) && reflect.DeepEqual(desired.Nodes, rmq.Status.Nodes) {
+		return clusterStatusErr
+	}
+
+	if healthKnown {
+		r.recordHealthEvent(rmq, rmq.Status.Conditions, status.NoAlarms, alarms, "AlarmsRaised", "AlarmsCleared", "all resource alarms cleared")
+		r.recordHealthEvent(rmq, rmq.Status.Conditions, status.NoPartitions, partitions, "PartitionsDetected", "PartitionsHealed", "all nodes can reach each other again")
+	}
+	if err := r.updateStatus(ctx, rmq, setHealth); err != nil {
+		return err
+	}
+	return clusterStatusErr
+}
+
+// clusterStatus reads the cluster status from the RabbitMQ node of a Pod.
+func (r *RabbitmqClusterReconciler) clusterStatus(namespace, podName string) (*health.ClusterStatus, error) {
+	stdout, stderr, err := r.exec(namespace, podName, "rabbitmq", "sh", "-c", health.ClusterStatusCommand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cluster status from pod %s: %s: %w", podName, stderr, err)
+	}
+	return health.ParseClusterStatus(stdout)
+}
+
+// recordHealthEvent emits a Warning Event when the description of a problem changes, and a Normal Event
+// once a problem reported before is gone.
+func (r *RabbitmqClusterReconciler) recordHealthEvent(rmq *rabbitmqv1beta1.RabbitmqCluster, oldConditions []status.RabbitmqClusterCondition,
+	conditionType status.RabbitmqClusterConditionType, problem, raisedReason, clearedReason, clearedMessage string) {
+	var old *status.RabbitmqClusterCondition
+	for i := range oldConditions {
+		if oldConditions[i].Type == conditionType {
+			old = &oldConditions[i]
+		}
+	}
+	switch {
+	case problem != "" && (old == nil || old.Message != problem):
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, raisedReason, problem)
+	case problem == "" && old != nil && old.Status == corev1.ConditionFalse:
+		r.Recorder.Event(rmq, corev1.EventTypeNormal, clearedReason, clearedMessage)
+	}
+}
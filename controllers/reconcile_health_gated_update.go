@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/health"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"github.com/rabbitmq/cluster-operator/v2/internal/rabbitmqclient"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updatePodsIfHealthy replaces the outdated Pods of a RabbitmqCluster using the HealthGated update strategy.
+// The StatefulSet uses the OnDelete strategy, so Pods are only updated when the operator deletes them. One Pod is deleted
+// at a time, starting with the highest ordinal like a rolling update, once all Pods are ready and the health checks pass.
+func (r *RabbitmqClusterReconciler) updatePodsIfHealthy(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
+	if !rmq.HealthGatedUpdates() {
+		return 0, nil
+	}
+	logger := ctrl.LoggerFrom(ctx)
+
+	sts, err := r.statefulSet(ctx, rmq)
+	if err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	if sts.Status.UpdateRevision == "" || sts.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType {
+		return 0, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(rmq.Namespace), client.MatchingLabels(metadata.LabelSelector(rmq.Name))); err != nil {
+		return 0, err
+	}
+	var outdated []corev1.Pod
+	for _, pod := range pods.Items {
+		if !pod.DeletionTimestamp.IsZero() || !health.PodReady(pod) {
+			logger.V(1).Info("not all Pods ready yet; requeuing request to update Pods", "pod", pod.Name)
+			return 15 * time.Second, nil
+		}
+		if pod.Labels[appsv1.ControllerRevisionHashLabelKey] != sts.Status.UpdateRevision {
+			outdated = append(outdated, pod)
+		}
+	}
+	if len(outdated) == 0 {
+		return 0, nil
+	}
+	if int32(len(pods.Items)) < *sts.Spec.Replicas {
+		logger.V(1).Info("not all Pods created yet; requeuing request to update Pods")
+		return 15 * time.Second, nil
+	}
+
+	sort.Slice(outdated, func(i, j int) bool {
+		return podOrdinal(outdated[i].Name) > podOrdinal(outdated[j].Name)
+	})
+	pod := outdated[0]
+
+	if err := r.checkPodHealth(ctx, rmq, pod.Name, *sts.Spec.Replicas); err != nil {
+		if !errors.Is(err, rabbitmqclient.ErrHealthCheckFailed) {
+			return 0, fmt.Errorf("failed to check health of Pod %s: %w", pod.Name, err)
+		}
+		msg := fmt.Sprintf("not updating Pod %s yet: %s", pod.Name, err)
+		logger.Info(msg)
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "UpdateBlocked", msg)
+		return 15 * time.Second, nil
+	}
+
+	if err := r.Delete(ctx, &pod, client.Preconditions{UID: &pod.UID}); client.IgnoreNotFound(err) != nil {
+		return 0, fmt.Errorf("failed to delete outdated Pod %s: %w", pod.Name, err)
+	}
+	msg := fmt.Sprintf("deleted Pod %s to update it to StatefulSet revision %s", pod.Name, sts.Status.UpdateRevision)
+	logger.Info(msg)
+	r.Recorder.Event(rmq, corev1.EventTypeNormal, "PodUpdated", msg)
+	return 15 * time.Second, nil
+}
+
+// checkPodHealth returns an error wrapping rabbitmqclient.ErrHealthCheckFailed if a resource alarm is raised in the cluster,
+// or if stopping the node of the Pod would make quorum queues or streams lose their quorum. Nodes of single node clusters
+// are always quorum critical, so that check only runs in clusters with several nodes.
+func (r *RabbitmqClusterReconciler) checkPodHealth(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, podName string, replicas int32) error {
+	rabbitmqClient, err := r.rabbitmqClient(ctx, rmq, rabbitmqclient.PodHost(rmq, podName))
+	if err != nil {
+		return err
+	}
+	if replicas > 1 {
+		if err := rabbitmqClient.CheckNodeIsQuorumCritical(ctx); err != nil {
+			return err
+		}
+	}
+	return rabbitmqClient.CheckAlarms(ctx)
+}
+
+// podOrdinal returns the ordinal of a StatefulSet Pod, or -1 if the name does not end with one.
+func podOrdinal(podName string) int {
+	ordinal, err := strconv.Atoi(podName[strings.LastIndex(podName, "-")+1:])
+	if err != nil {
+		return -1
+	}
+	return ordinal
+}
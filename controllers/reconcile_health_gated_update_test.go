@@ -0,0 +1,111 @@
+package controllers_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Health gated updates", func() {
+	var cluster *rabbitmqv1beta1.RabbitmqCluster
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-health-gated",
+				Namespace: "default",
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Replicas:       ptr.To(int32(3)),
+				UpdateStrategy: rabbitmqv1beta1.HealthGatedStrategy,
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+	})
+
+	It("deletes one outdated Pod at a time, starting with the highest ordinal", func() {
+		Expect(statefulSet(ctx, cluster).Spec.UpdateStrategy.Type).To(Equal(appsv1.OnDeleteStatefulSetStrategyType))
+
+		for i := 0; i < 3; i++ {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("rabbitmq-health-gated-server-%d", i),
+					Namespace: "default",
+					Labels: map[string]string{
+						"app.kubernetes.io/name":              cluster.Name,
+						"app.kubernetes.io/part-of":           "rabbitmq",
+						appsv1.ControllerRevisionHashLabelKey: "first-revision",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "rabbitmq", Image: "rabbitmq"}},
+				},
+			}
+			Expect(client.Create(ctx, pod)).To(Succeed())
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(client.Status().Update(ctx, pod)).To(Succeed())
+		}
+		DeferCleanup(func() {
+			for i := 0; i < 3; i++ {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("rabbitmq-health-gated-server-%d", i), Namespace: "default"}}
+				Expect(runtimeClient.IgnoreNotFound(client.Delete(ctx, pod))).To(Succeed())
+			}
+		})
+
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 3
+		sts.Status.ReadyReplicas = 3
+		sts.Status.CurrentRevision = "first-revision"
+		sts.Status.UpdateRevision = "second-revision"
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+
+		Eventually(func() bool {
+			err := client.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rabbitmq-health-gated-server-2"}, &corev1.Pod{})
+			return k8serrors.IsNotFound(err)
+		}, 5).Should(BeTrue())
+		Expect(fakeRabbitmq.Calls()).To(ContainElements(
+			"Connect rabbitmq-health-gated-server-2.rabbitmq-health-gated-nodes.default.svc",
+			"CheckNodeIsQuorumCritical",
+			"CheckAlarms",
+		))
+
+		By("waiting for the deleted Pod to be ready again", func() {
+			Consistently(func() error {
+				return client.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rabbitmq-health-gated-server-1"}, &corev1.Pod{})
+			}, 3).Should(Succeed())
+		})
+	})
+
+	It("considers the StatefulSet updated once all Pods run the update revision", func() {
+		fakeExecutor.SetStdout(`rabbitmqctl eval 'io:format("~s~n~s~n", [rabbit_misc:version(), rabbit_misc:otp_version()]).'`, "4.0.5\n27.2\nok\n")
+
+		// the current revision does not advance under the OnDelete strategy
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 3
+		sts.Status.ReadyReplicas = 3
+		sts.Status.UpdatedReplicas = 3
+		sts.Status.CurrentRevision = "first-revision"
+		sts.Status.UpdateRevision = "second-revision"
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+
+		Eventually(func() string {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Status.VersionsRevision
+		}, 5).Should(Equal("second-revision"))
+	})
+})
@@ -0,0 +1,92 @@
+package controllers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Cluster health", func() {
+	var (
+		cluster *rabbitmqv1beta1.RabbitmqCluster
+		pod     *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-health",
+				Namespace: "default",
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-health-server-0",
+				Namespace: "default",
+				Labels:    map[string]string{"app.kubernetes.io/name": "rabbitmq-health"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "rabbitmq", Image: "rabbitmq"}},
+			},
+		}
+		Expect(client.Create(ctx, pod)).To(Succeed())
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		Expect(client.Status().Update(ctx, pod)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, pod)).To(Succeed())
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+	})
+
+	It("sets status.nodes and the NoAlarms and NoPartitions conditions from the cluster status", func() {
+		fakeExecutor.SetStdout("rabbitmqctl cluster_status --formatter json",
+			`{"running_nodes": ["rabbit@rabbitmq-health-server-0.rabbitmq-health-nodes.default"],
+			  "alarms": [{"node": "rabbit@rabbitmq-health-server-0.rabbitmq-health-nodes.default", "type": "resource_limit", "resource": "memory"}],
+			  "partitions": {}}`)
+
+		Eventually(func() []status.RabbitmqClusterCondition {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Status.Conditions
+		}, 5).Should(ContainElements(
+			SatisfyAll(
+				HaveField("Type", status.NoAlarms),
+				HaveField("Status", corev1.ConditionFalse),
+				HaveField("Message", "memory alarm on rabbit@rabbitmq-health-server-0.rabbitmq-health-nodes.default"),
+			),
+			SatisfyAll(
+				HaveField("Type", status.NoPartitions),
+				HaveField("Status", corev1.ConditionTrue),
+			),
+		))
+
+		Expect(cluster.Status.Nodes).To(Equal([]rabbitmqv1beta1.RabbitmqClusterNodeStatus{{
+			Pod:        "rabbitmq-health-server-0",
+			Node:       "rabbit@rabbitmq-health-server-0.rabbitmq-health-nodes.default",
+			Ready:      true,
+			Membership: rabbitmqv1beta1.NodeRunning,
+		}}))
+
+		fakeExecutor.SetStdout("rabbitmqctl cluster_status --formatter json", `{"alarms": [], "partitions": {}}`)
+		Eventually(func() []status.RabbitmqClusterCondition {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Status.Conditions
+		}, 5).Should(ContainElement(SatisfyAll(
+			HaveField("Type", status.NoAlarms),
+			HaveField("Status", corev1.ConditionTrue),
+		)))
+	})
+})
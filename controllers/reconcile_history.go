@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strconv"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// recordAppliedChanges adds the image, replica and configuration changes applied to a child resource to the history of
+// clusterStatus. previous is the child resource before, and current the child resource after the update.
+func recordAppliedChanges(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus, builder resource.ResourceBuilder, operationResult controllerutil.OperationResult, previous, current client.Object) {
+	if operationResult != controllerutil.OperationResultUpdated {
+		return
+	}
+	record := func(changeType rabbitmqv1beta1.RabbitmqClusterChangeType, previousValue, currentValue string) {
+		if previousValue != currentValue {
+			clusterStatus.RecordChange(changeType, previousValue, currentValue)
+		}
+	}
+
+	switch b := builder.(type) {
+	case *resource.StatefulSetBuilder:
+		previousSts, currentSts := previous.(*appsv1.StatefulSet), current.(*appsv1.StatefulSet)
+		record(rabbitmqv1beta1.ImageChange, rabbitmqImage(previousSts), rabbitmqImage(currentSts))
+		record(rabbitmqv1beta1.ReplicasChange, stsReplicas(previousSts), stsReplicas(currentSts))
+	case *resource.ServerConfigMapBuilder:
+		if b.UpdateRequiresStsRestart {
+			record(rabbitmqv1beta1.ConfigurationChange, configHash(previous.(*corev1.ConfigMap).Data), configHash(current.(*corev1.ConfigMap).Data))
+		}
+	}
+}
+
+func rabbitmqImage(sts *appsv1.StatefulSet) string {
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name == "rabbitmq" {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+func stsReplicas(sts *appsv1.StatefulSet) string {
+	if sts.Spec.Replicas == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*sts.Spec.Replicas))
+}
+
+// configHash returns a short hash of the ConfigMap data, which identifies a configuration in status.history.
+func configHash(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s=%s\n", key, data[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))[:16]
+}
@@ -0,0 +1,80 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Change history", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	history := func() []rabbitmqv1beta1.RabbitmqClusterChange {
+		rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+		Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), rmq)).To(Succeed())
+		return rmq.Status.History
+	}
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-history",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Replicas: ptr.To(int32(1)),
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		Eventually(func() bool {
+			err := client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), &rabbitmqv1beta1.RabbitmqCluster{})
+			return apierrors.IsNotFound(err)
+		}, 5).Should(BeTrue())
+	})
+
+	It("does not record the creation of the cluster", func() {
+		Consistently(history, 2).Should(BeEmpty())
+	})
+
+	It("records image, replica and configuration changes", func() {
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Spec.Image = "rabbitmq:a-new-image"
+			r.Spec.Replicas = ptr.To(int32(3))
+			r.Spec.Rabbitmq.AdditionalConfig = "log.console.level = debug"
+		})).To(Succeed())
+
+		Eventually(history, 5).Should(ContainElements(
+			MatchFields(IgnoreExtras, Fields{
+				"Type":     Equal(rabbitmqv1beta1.ImageChange),
+				"Previous": Equal(defaultRabbitmqImage),
+				"Current":  Equal("rabbitmq:a-new-image"),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Type":     Equal(rabbitmqv1beta1.ReplicasChange),
+				"Previous": Equal("1"),
+				"Current":  Equal("3"),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Type":     Equal(rabbitmqv1beta1.ConfigurationChange),
+				"Previous": Not(BeEmpty()),
+				"Current":  Not(BeEmpty()),
+			}),
+		))
+	})
+})
@@ -3,45 +3,62 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
+
 	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/operatorconfig"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"strings"
-	"time"
 )
 
-// reconcileOperatorDefaults updates current rabbitmqCluster with operator defaults from the Reconciler
-// it handles RabbitMQ image, imagePullSecrets, and user updater image
+// reconcileOperatorDefaults updates current rabbitmqCluster with the defaults of the CRD schema and of the operator
+// configuration, which are usually set by the defaulting webhook. It covers RabbitmqClusters created while the webhook
+// was not reachable, and RabbitmqClusters whose image is controlled by the operator.
 func (r *RabbitmqClusterReconciler) reconcileOperatorDefaults(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
-	// image will be updated image isn't set yet or the image controlled by the operator (experimental).
-	if rabbitmqCluster.Spec.Image == "" || r.ControlRabbitmqImage {
-		rabbitmqCluster.Spec.Image = r.DefaultRabbitmqImage
-		if requeue, err := r.updateRabbitmqCluster(ctx, rabbitmqCluster, "image"); err != nil {
-			return requeue, err
+	defaulted := rabbitmqCluster.DeepCopy()
+	defaulted.SetDefaults()
+
+	// the storage class of volume claim templates cannot change, so it is only defaulted before the StatefulSet is created.
+	setStorageClass := false
+	if defaulted.Spec.Persistence.StorageClassName == nil && r.DefaultStorageClassName != "" && !defaulted.RemoteClusterEnabled() {
+		if _, err := r.statefulSet(ctx, rabbitmqCluster); k8serrors.IsNotFound(err) {
+			setStorageClass = true
+		} else if err != nil {
+			return 0, err
 		}
 	}
+	defaulted.SetOperatorDefaults(r.OperatorDefaults(), setStorageClass)
 
-	if rabbitmqCluster.Spec.ImagePullSecrets == nil {
-		// split the comma separated list of default image pull secrets from
-		// the 'DEFAULT_IMAGE_PULL_SECRETS' env var, but ignore empty strings.
-		for _, reference := range strings.Split(r.DefaultImagePullSecrets, ",") {
-			if len(reference) > 0 {
-				rabbitmqCluster.Spec.ImagePullSecrets = append(rabbitmqCluster.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: reference})
-			}
-		}
-		if requeue, err := r.updateRabbitmqCluster(ctx, rabbitmqCluster, "image pull secrets"); err != nil {
-			return requeue, err
-		}
+	if equality.Semantic.DeepEqual(defaulted.Spec, rabbitmqCluster.Spec) {
+		return 0, nil
 	}
+	rabbitmqCluster.Spec = defaulted.Spec
+	return r.updateRabbitmqCluster(ctx, rabbitmqCluster, "defaults")
+}
 
-	if rabbitmqCluster.UsesDefaultUserUpdaterImage(r.ControlRabbitmqImage) {
-		rabbitmqCluster.Spec.SecretBackend.Vault.DefaultUserUpdaterImage = &r.DefaultUserUpdaterImage
-		if requeue, err := r.updateRabbitmqCluster(ctx, rabbitmqCluster, "default user image"); err != nil {
-			return requeue, err
+// OperatorDefaults returns the defaults of the operator configuration, which the defaulting webhook sets as well.
+func (r *RabbitmqClusterReconciler) OperatorDefaults() rabbitmqv1beta1.OperatorDefaults {
+	defaults := rabbitmqv1beta1.OperatorDefaults{
+		RabbitmqImage:        r.DefaultRabbitmqImage,
+		UserUpdaterImage:     r.DefaultUserUpdaterImage,
+		StorageClassName:     r.DefaultStorageClassName,
+		Resources:            r.DefaultResources,
+		ControlRabbitmqImage: r.ControlRabbitmqImage,
+	}
+	if defaults.Resources == nil {
+		defaults.Resources = operatorconfig.DefaultResources()
+	}
+	// split the comma separated list of default image pull secrets from
+	// the 'DEFAULT_IMAGE_PULL_SECRETS' env var, but ignore empty strings.
+	for _, reference := range strings.Split(r.DefaultImagePullSecrets, ",") {
+		if len(reference) > 0 {
+			defaults.ImagePullSecrets = append(defaults.ImagePullSecrets, corev1.LocalObjectReference{Name: reference})
 		}
 	}
-	return 0, nil
+	return defaults
 }
 
 // updateRabbitmqCluster updates a RabbitmqCluster with the given definition
@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/operatorconfig"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -60,5 +61,8 @@ var _ = Describe("ReconcileOperatorDefaults", func() {
 				},
 			},
 		))
+
+		By("setting the default resources")
+		Expect(fetchedCluster.Spec.Resources).To(Equal(operatorconfig.DefaultResources()))
 	})
 })
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// rotateDefaultUserPasswordAnnotation requests a new password for the default user.
+	// The operator removes the annotation once the password was rotated.
+	rotateDefaultUserPasswordAnnotation = "rabbitmq.com/rotateDefaultUserPassword"
+	// pendingPasswordKey holds the new password in the default-user Secret while it is changed in RabbitMQ,
+	// so that a rotation interrupted by an operator restart can be completed with the same password.
+	pendingPasswordKey = "pending_password"
+)
+
+// rotateDefaultUserPasswordIfAnnotated changes the password of the default user in RabbitMQ and then in the default-user Secret,
+// when the RabbitmqCluster is annotated with rabbitmq.com/rotateDefaultUserPassword.
+// The DefaultUserPasswordRotated condition reports the progress, so that clients know when to read the new credentials.
+func (r *RabbitmqClusterReconciler) rotateDefaultUserPasswordIfAnnotated(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
+	if rmq.Annotations[rotateDefaultUserPasswordAnnotation] == "" {
+		return 0, nil
+	}
+	logger := ctrl.LoggerFrom(ctx)
+
+	if rmq.VaultDefaultUserSecretEnabled() || rmq.ExternalSecretEnabled() || rmq.CSIDefaultUserEnabled() || rmq.ExistingAdminSecretEnabled() {
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "DefaultUserPasswordNotRotated",
+			"the password of the default user can only be rotated when the operator generates the credentials")
+		return 0, r.deleteAnnotation(ctx, rmq, rotateDefaultUserPasswordAnnotation)
+	}
+
+	sts, err := r.statefulSet(ctx, rmq)
+	if err != nil {
+		return 0, err
+	}
+	if !allReplicasReadyAndUpdated(sts) {
+		logger.V(1).Info("not all replicas ready yet; requeuing request to rotate the default user password")
+		return 15 * time.Second, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: rmq.ChildResourceName(resource.DefaultUserSecretName)}, secret); err != nil {
+		return 0, err
+	}
+	if metadata.ManagedByExternal(secret.Annotations) {
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "DefaultUserPasswordNotRotated",
+			fmt.Sprintf("the password of the default user cannot be rotated because Secret %s is managed by another controller", secret.Name))
+		return 0, r.deleteAnnotation(ctx, rmq, rotateDefaultUserPasswordAnnotation)
+	}
+	password := string(secret.Data[pendingPasswordKey])
+	if password == "" {
+		if password, err = resource.GeneratePassword(); err != nil {
+			return 0, err
+		}
+		secret.Data[pendingPasswordKey] = []byte(password)
+		if err := r.Update(ctx, secret); err != nil {
+			return 0, err
+		}
+		if err := r.updateStatus(ctx, rmq, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+			clusterStatus.SetDefaultUserPasswordRotated(corev1.ConditionUnknown, "RotationInProgress", "changing the password of the default user")
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	// users are stored cluster-wide, so changing the password on one node is sufficient
+	podName := fmt.Sprintf("%s-0", rmq.ChildResourceName("server"))
+	cmd := fmt.Sprintf("rabbitmqctl change_password '%s' '%s'", secret.Data["username"], password)
+	stdout, stderr, err := r.exec(rmq.Namespace, podName, "rabbitmq", "sh", "-c", cmd)
+	if err != nil {
+		msg := "failed to change the password of the default user on pod"
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", fmt.Sprintf("%s %s", msg, podName))
+		if err := r.updateStatus(ctx, rmq, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+			clusterStatus.SetDefaultUserPasswordRotated(corev1.ConditionFalse, "RotationFailed", fmt.Sprintf("%s %s", msg, podName))
+		}); err != nil {
+			return 0, err
+		}
+		// the rotation is retried with backoff
+		return 0, fmt.Errorf("%s %s: %w: stdout: %s, stderr: %s", msg, podName, err, stdout, stderr)
+	}
+
+	// the new credentials replace the previous ones in a single update of the Secret
+	builder := &resource.RabbitmqResourceBuilder{Instance: rmq, Scheme: r.Scheme}
+	if err := builder.DefaultUserSecret().UpdatePassword(secret, password); err != nil {
+		return 0, err
+	}
+	delete(secret.Data, pendingPasswordKey)
+	if err := r.Update(ctx, secret); err != nil {
+		return 0, err
+	}
+
+	logger.Info("successfully rotated the default user password")
+	r.Recorder.Event(rmq, corev1.EventTypeNormal, "DefaultUserPasswordRotated", fmt.Sprintf("updated the password in Secret %s", secret.Name))
+	if err := r.updateStatus(ctx, rmq, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		clusterStatus.SetDefaultUserPasswordRotated(corev1.ConditionTrue, "PasswordRotated", fmt.Sprintf("Secret %s contains the new password", secret.Name))
+	}); err != nil {
+		return 0, err
+	}
+	return 0, r.deleteAnnotation(ctx, rmq, rotateDefaultUserPasswordAnnotation)
+}
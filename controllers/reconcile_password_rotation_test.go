@@ -0,0 +1,72 @@
+package controllers_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Reconcile default user password rotation", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+	)
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-password-rotation",
+				Namespace: defaultNamespace,
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+	})
+
+	It("changes the password in RabbitMQ and then in the default-user Secret", func() {
+		secretKey := types.NamespacedName{Namespace: defaultNamespace, Name: cluster.ChildResourceName("default-user")}
+		secret := &corev1.Secret{}
+		Expect(client.Get(ctx, secretKey, secret)).To(Succeed())
+		username := string(secret.Data["username"])
+		previousPassword := string(secret.Data["password"])
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Annotations = map[string]string{"rabbitmq.com/rotateDefaultUserPassword": "2026-10-16"}
+		})).To(Succeed())
+
+		Eventually(func() map[string]string {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Annotations
+		}, 5).ShouldNot(HaveKey("rabbitmq.com/rotateDefaultUserPassword"))
+		Expect(cluster.Status.Conditions).To(ContainElement(SatisfyAll(
+			HaveField("Type", status.DefaultUserPasswordRotated),
+			HaveField("Status", corev1.ConditionTrue),
+			HaveField("Reason", "PasswordRotated"),
+		)))
+
+		Expect(client.Get(ctx, secretKey, secret)).To(Succeed())
+		password := string(secret.Data["password"])
+		Expect(password).NotTo(Equal(previousPassword))
+		Expect(secret.Data).NotTo(HaveKey("pending_password"))
+		Expect(secret.Data).To(HaveKeyWithValue("default_user.conf", []byte(fmt.Sprintf("default_user = %s\ndefault_pass = %s\n", username, password))))
+		Expect(fakeExecutor.ExecutedCommands()).To(ContainElement(command{"sh", "-c",
+			fmt.Sprintf("rabbitmqctl change_password '%s' '%s'", username, password)}))
+	})
+})
@@ -9,13 +9,15 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-func (r *RabbitmqClusterReconciler) reconcilePVC(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, desiredSts *appsv1.StatefulSet) error {
+// reconcilePVC expands the PersistentVolumeClaims of the StatefulSet with clientset, which may access a remote cluster.
+func (r *RabbitmqClusterReconciler) reconcilePVC(ctx context.Context, clientset kubernetes.Interface, rmq *rabbitmqv1beta1.RabbitmqCluster, desiredSts *appsv1.StatefulSet) error {
 	logger := ctrl.LoggerFrom(ctx)
 	desiredCapacity := persistenceStorageCapacity(desiredSts.Spec.VolumeClaimTemplates)
-	err := scaling.NewPersistenceScaler(r.Clientset).Scale(ctx, *rmq, desiredCapacity)
+	err := scaling.NewPersistenceScaler(clientset).Scale(ctx, *rmq, desiredCapacity)
 	if err != nil {
 		msg := fmt.Sprintf("Failed to scale PVCs: %s", err.Error())
 		logger.Error(fmt.Errorf("hit an error while scaling PVC capacity: %w", err), msg)
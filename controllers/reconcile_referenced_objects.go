@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// clustersReferencing maps a Secret or ConfigMap to the RabbitmqClusters in its namespace whose field index indexKey
+// contains the name of the object.
+func (r *RabbitmqClusterReconciler) clustersReferencing(ctx context.Context, obj client.Object, indexKey string) []reconcile.Request {
+	clusters := &rabbitmqv1beta1.RabbitmqClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(obj.GetNamespace()), client.MatchingFields{indexKey: obj.GetName()}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list RabbitmqClusters referencing object", "name", obj.GetName(), "index", indexKey)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}})
+	}
+	return requests
+}
+
+// referencedObjectsSources returns the sources of Secrets and ConfigMaps referenced by RabbitmqClusters.
+// The cache of the manager only holds objects labelled as part of rabbitmq, which referenced objects usually are not,
+// so they are watched through a cache of their metadata in all watched namespaces. The cache does not hold the data
+// of the objects, which the reconciler reads with the APIReader.
+func (r *RabbitmqClusterReconciler) referencedObjectsSources(mgr ctrl.Manager) ([]source.Source, error) {
+	options := cache.Options{
+		HTTPClient:       mgr.GetHTTPClient(),
+		Scheme:           mgr.GetScheme(),
+		Mapper:           mgr.GetRESTMapper(),
+		DefaultTransform: cache.TransformStripManagedFields(),
+	}
+	if len(r.WatchNamespaces) > 0 {
+		options.DefaultNamespaces = make(map[string]cache.Config)
+		for _, namespace := range r.WatchNamespaces {
+			options.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+	metadataCache, err := cache.New(mgr.GetConfig(), options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache of referenced objects: %w", err)
+	}
+	if err := mgr.Add(metadataCache); err != nil {
+		return nil, fmt.Errorf("failed to add cache of referenced objects: %w", err)
+	}
+	return []source.Source{
+		source.Kind(metadataCache, objectMetadata(corev1.SchemeGroupVersion.WithKind("ConfigMap")),
+			enqueueClustersReferencing(r.clustersReferencingConfigMap)),
+		source.Kind(metadataCache, objectMetadata(corev1.SchemeGroupVersion.WithKind("Secret")),
+			enqueueClustersReferencing(
+				r.clustersReferencingTLSSecret,
+				r.clustersReferencingErlangCookieSecret,
+				r.clustersReferencingExistingAdminSecret,
+				r.clustersReferencingAdditionalUserSecret,
+			)),
+	}, nil
+}
+
+func objectMetadata(gvk schema.GroupVersionKind) *metav1.PartialObjectMetadata {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+// enqueueClustersReferencing enqueues the RabbitmqClusters returned by any of the map functions.
+func enqueueClustersReferencing(mapFuncs ...handler.MapFunc) handler.TypedEventHandler[*metav1.PartialObjectMetadata, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, obj *metav1.PartialObjectMetadata) []reconcile.Request {
+		var requests []reconcile.Request
+		for _, mapFunc := range mapFuncs {
+			requests = append(requests, mapFunc(ctx, obj)...)
+		}
+		return requests
+	})
+}
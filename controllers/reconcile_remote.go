@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/remotecluster"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	kubeconfigKey = "kubeconfig"
+	// child resources in remote clusters are not watched, so they are synced periodically
+	remoteClusterSyncInterval = time.Minute
+)
+
+// reconcileRemoteCluster applies the child resources of a RabbitmqCluster to the remote cluster
+// referenced by spec.remoteCluster, and updates the status conditions from the remote StatefulSet and Endpoints.
+func (r *RabbitmqClusterReconciler) reconcileRemoteCluster(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster) (ctrl.Result, error) {
+	logger := ctrl.LoggerFrom(ctx).WithValues("kubeconfigSecret", rabbitmqCluster.Spec.RemoteCluster.KubeconfigSecret.Name)
+	ctx = ctrl.LoggerInto(ctx, logger)
+
+	if !r.RemoteClustersEnabled {
+		msg := "remote clusters are disabled; set REMOTE_CLUSTERS to true on the operator to enable spec.remoteCluster"
+		logger.Info(msg)
+		r.Recorder.Event(rabbitmqCluster, corev1.EventTypeWarning, "RemoteClustersDisabled", msg)
+		r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "RemoteClustersDisabled", msg)
+		return ctrl.Result{}, nil
+	}
+
+	remoteCluster, err := r.remoteCluster(ctx, rabbitmqCluster)
+	if err != nil {
+		logger.Error(err, "Failed to create client for remote cluster")
+		r.Recorder.Event(rabbitmqCluster, corev1.EventTypeWarning, "RemoteClusterError", err.Error())
+		r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "RemoteClusterError", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if requeueAfter, err := r.updateStatusConditions(ctx, remoteCluster, rabbitmqCluster); err != nil || requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+
+	configFrom, err := r.configFrom(ctx, rabbitmqCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defaultUser, err := r.existingAdminCredentials(ctx, rabbitmqCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Start reconciling in remote cluster")
+
+	resourceBuilder := resource.RabbitmqResourceBuilder{
+		Instance:            rabbitmqCluster,
+		Scheme:              r.Scheme,
+		LabelMappings:       r.LabelMappings,
+		ConfigFrom:          configFrom,
+		DefaultUser:         defaultUser,
+		InjectedLabels:      r.InjectedLabels,
+		InjectedAnnotations: r.InjectedAnnotations,
+	}
+
+	if _, stop, err := r.applyChildResources(ctx, rabbitmqCluster, remoteCluster, resourceBuilder.ResourceBuilders()); err != nil || stop {
+		return ctrl.Result{}, err
+	}
+
+	if requeueAfter, err := r.restartStatefulSetIfNeeded(ctx, remoteCluster, logger, rabbitmqCluster); err != nil || requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+
+	r.setReconcileCompleted(ctx, rabbitmqCluster)
+
+	logger.Info("Finished reconciling in remote cluster")
+
+	return ctrl.Result{RequeueAfter: remoteClusterSyncInterval}, nil
+}
+
+// deleteRemoteChildResources deletes the child resources of a RabbitmqCluster from its remote cluster.
+func (r *RabbitmqClusterReconciler) deleteRemoteChildResources(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster) error {
+	remoteCluster, err := r.remoteCluster(ctx, rabbitmqCluster)
+	if err != nil {
+		return err
+	}
+
+	resourceBuilder := resource.RabbitmqResourceBuilder{
+		Instance: rabbitmqCluster,
+		Scheme:   r.Scheme,
+	}
+	for _, builder := range resourceBuilder.ResourceBuilders() {
+		obj, err := builder.Build()
+		if err != nil {
+			return err
+		}
+		if err := remoteCluster.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("cannot delete %T %s in remote cluster: %w", obj, obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// remoteCluster returns the remote cluster of spec.remoteCluster. Its child resources are not owned by the
+// RabbitmqCluster, which does not exist there.
+func (r *RabbitmqClusterReconciler) remoteCluster(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster) (childCluster, error) {
+	secretName := rabbitmqCluster.Spec.RemoteCluster.KubeconfigSecret.Name
+
+	// the kubeconfig Secret is provided by the user, so it is not cached by the controller
+	secret := &corev1.Secret{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: rabbitmqCluster.Namespace, Name: secretName}, secret); err != nil {
+		return childCluster{}, fmt.Errorf("failed to get kubeconfig secret %s in namespace %s: %w", secretName, rabbitmqCluster.Namespace, err)
+	}
+
+	return r.remoteClients.get(secret, func() (childCluster, error) {
+		kubeconfig, ok := secret.Data[kubeconfigKey]
+		if !ok {
+			return childCluster{}, fmt.Errorf("kubeconfig secret %s in namespace %s does not have the field %s", secretName, rabbitmqCluster.Namespace, kubeconfigKey)
+		}
+
+		config, err := remotecluster.RESTConfig(kubeconfig)
+		if err != nil {
+			return childCluster{}, fmt.Errorf("failed to parse kubeconfig in secret %s: %w", secretName, err)
+		}
+
+		remoteClient, err := client.New(config, client.Options{Scheme: r.Scheme})
+		if err != nil {
+			return childCluster{}, err
+		}
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return childCluster{}, err
+		}
+		// the remote client does not cache, so it also reads the child resources
+		return childCluster{
+			Client:    remoteClient,
+			reader:    remoteClient,
+			clientset: clientset,
+			mutate:    removeOwnerReferences,
+		}, nil
+	})
+}
+
+// remoteClientCache holds the clients of remote clusters by kubeconfig Secret, so that the discovery of the remote
+// API is not repeated on every reconcile. A client is created again when the UID or the resourceVersion of its
+// Secret changes.
+type remoteClientCache struct {
+	mu      sync.Mutex
+	clients map[types.NamespacedName]remoteClientCacheEntry
+}
+
+type remoteClientCacheEntry struct {
+	uid             types.UID
+	resourceVersion string
+	cluster         childCluster
+}
+
+// get returns the cached clients of the kubeconfig Secret, or the clients returned by newCluster if the Secret changed.
+func (c *remoteClientCache) get(secret *corev1.Secret, newCluster func() (childCluster, error)) (childCluster, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := client.ObjectKeyFromObject(secret)
+	if entry, ok := c.clients[key]; ok && entry.uid == secret.UID && entry.resourceVersion == secret.ResourceVersion {
+		return entry.cluster, nil
+	}
+	delete(c.clients, key)
+
+	remoteCluster, err := newCluster()
+	if err != nil {
+		return childCluster{}, err
+	}
+	if c.clients == nil {
+		c.clients = map[types.NamespacedName]remoteClientCacheEntry{}
+	}
+	c.clients[key] = remoteClientCacheEntry{uid: secret.UID, resourceVersion: secret.ResourceVersion, cluster: remoteCluster}
+	return remoteCluster, nil
+}
+
+// forget removes the client of the kubeconfig Secret from the cache.
+func (c *remoteClientCache) forget(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clients, key)
+}
+
+// removeOwnerReferences removes references to the RabbitmqCluster, since it does not exist in the remote cluster.
+// The garbage collector of the remote cluster would delete objects referencing an owner it cannot find.
+func removeOwnerReferences(obj client.Object) {
+	obj.SetOwnerReferences(nil)
+	if sts, ok := obj.(*appsv1.StatefulSet); ok {
+		for i := range sts.Spec.VolumeClaimTemplates {
+			sts.Spec.VolumeClaimTemplates[i].OwnerReferences = nil
+		}
+	}
+}
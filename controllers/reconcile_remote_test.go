@@ -0,0 +1,197 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/ptr"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+var _ = Describe("Remote cluster", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		kubeconfigSecret *corev1.Secret
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	BeforeEach(func() {
+		// the test environment API server stands in for the remote cluster
+		user, err := testEnv.ControlPlane.AddUser(envtest.User{Name: "remote-cluster-admin", Groups: []string{"system:masters"}}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		kubeconfig, err := user.KubeConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		kubeconfigSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: defaultNamespace},
+			Data:       map[string][]byte{"kubeconfig": kubeconfig},
+		}
+		Expect(client.Create(ctx, kubeconfigSecret)).To(Succeed())
+
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "rabbitmq-remote", Namespace: defaultNamespace},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Replicas: ptr.To(int32(1)),
+				RemoteCluster: &rabbitmqv1beta1.RemoteClusterSpec{
+					KubeconfigSecret: corev1.LocalObjectReference{Name: kubeconfigSecret.Name},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(runtimeClient.IgnoreNotFound(client.Delete(ctx, cluster))).To(Succeed())
+		Eventually(func() bool {
+			err := client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), &rabbitmqv1beta1.RabbitmqCluster{})
+			return apierrors.IsNotFound(err)
+		}, 5).Should(BeTrue())
+		Expect(client.Delete(ctx, kubeconfigSecret)).To(Succeed())
+	})
+
+	It("creates the child resources without owner references and deletes them with the RabbitmqCluster", func() {
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+
+		By("creating the StatefulSet without owner references", func() {
+			Eventually(func() error {
+				_, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+				return err
+			}, 5).Should(Succeed())
+			sts, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sts.OwnerReferences).To(BeEmpty())
+			Expect(sts.Spec.VolumeClaimTemplates[0].OwnerReferences).To(BeEmpty())
+		})
+
+		By("setting ReconcileSuccess to 'true'", func() {
+			Eventually(func() string {
+				rabbit := &rabbitmqv1beta1.RabbitmqCluster{}
+				Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), rabbit)).To(Succeed())
+				for i := range rabbit.Status.Conditions {
+					if rabbit.Status.Conditions[i].Type == status.ReconcileSuccess {
+						return fmt.Sprintf("%s %s", rabbit.Status.Conditions[i].Status, rabbit.Status.Conditions[i].Reason)
+					}
+				}
+				return "condition not present"
+			}, 5).Should(Equal("True Success"))
+		})
+
+		By("deleting the child resources on deletion", func() {
+			Expect(client.Delete(ctx, cluster)).To(Succeed())
+			Eventually(func() bool {
+				_, err := clientSet.CoreV1().Services(defaultNamespace).Get(ctx, cluster.ChildResourceName(""), metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}, 5).Should(BeTrue())
+		})
+	})
+
+	It("refuses to scale down the StatefulSet in the remote cluster", func() {
+		cluster.Name = "rabbitmq-remote-scale-down"
+		cluster.Spec.Replicas = ptr.To(int32(3))
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		Eventually(func() error {
+			_, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+			return err
+		}, 5).Should(Succeed())
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Spec.Replicas = ptr.To(int32(1))
+		})).To(Succeed())
+
+		Eventually(func() string {
+			return aggregateEventMsgs(ctx, cluster, "UnsupportedOperation")
+		}, 5).Should(ContainSubstring("Cluster Scale down not supported"))
+		sts, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sts.Spec.Replicas).To(Equal(ptr.To(int32(3))))
+	})
+
+	It("keeps the RabbitmqCluster until the child resources in the remote cluster are deleted", func() {
+		cluster.Name = "rabbitmq-remote-unreachable"
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		Eventually(func() error {
+			_, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+			return err
+		}, 5).Should(Succeed())
+
+		By("retrying the deletion while the remote cluster is unreachable", func() {
+			kubeconfig := kubeconfigSecret.Data["kubeconfig"]
+			unreachable := clientcmdapi.NewConfig()
+			unreachable.Clusters["unreachable"] = &clientcmdapi.Cluster{Server: "https://127.0.0.1:1"}
+			unreachable.Contexts["unreachable"] = &clientcmdapi.Context{Cluster: "unreachable"}
+			unreachable.CurrentContext = "unreachable"
+			data, err := clientcmd.Write(*unreachable)
+			Expect(err).NotTo(HaveOccurred())
+			kubeconfigSecret.Data["kubeconfig"] = data
+			Expect(client.Update(ctx, kubeconfigSecret)).To(Succeed())
+
+			Expect(client.Delete(ctx, cluster)).To(Succeed())
+			Eventually(func() string {
+				return aggregateEventMsgs(ctx, cluster, "RemoteDeletionFailed")
+			}, 10).ShouldNot(BeEmpty())
+			Consistently(func() error {
+				return client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), &rabbitmqv1beta1.RabbitmqCluster{})
+			}, 2).Should(Succeed())
+
+			kubeconfigSecret.Data["kubeconfig"] = kubeconfig
+			Expect(client.Update(ctx, kubeconfigSecret)).To(Succeed())
+		})
+
+		By("deleting the child resources once the remote cluster is reachable", func() {
+			Eventually(func() bool {
+				_, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}, 30).Should(BeTrue())
+		})
+	})
+
+	When("the kubeconfig runs a credential plugin", func() {
+		It("does not create a client for the remote cluster", func() {
+			config := clientcmdapi.NewConfig()
+			config.Clusters["remote"] = &clientcmdapi.Cluster{Server: "https://127.0.0.1:1"}
+			config.AuthInfos["remote"] = &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{
+				Command:    "/bin/sh",
+				APIVersion: "client.authentication.k8s.io/v1",
+			}}
+			config.Contexts["remote"] = &clientcmdapi.Context{Cluster: "remote", AuthInfo: "remote"}
+			config.CurrentContext = "remote"
+			data, err := clientcmd.Write(*config)
+			Expect(err).NotTo(HaveOccurred())
+			kubeconfig := kubeconfigSecret.Data["kubeconfig"]
+			kubeconfigSecret.Data["kubeconfig"] = data
+			Expect(client.Update(ctx, kubeconfigSecret)).To(Succeed())
+
+			cluster.Name = "rabbitmq-remote-exec-plugin"
+			Expect(client.Create(ctx, cluster)).To(Succeed())
+			Eventually(func() string {
+				return aggregateEventMsgs(ctx, cluster, "RemoteClusterError")
+			}, 5).Should(ContainSubstring("exec credential plugins are not supported"))
+
+			// the finalizer deletes the child resources in the remote cluster, so it must be reachable again
+			kubeconfigSecret.Data["kubeconfig"] = kubeconfig
+			Expect(client.Update(ctx, kubeconfigSecret)).To(Succeed())
+		})
+	})
+
+	When("the kubeconfig Secret does not exist", func() {
+		It("sets ReconcileSuccess to 'false'", func() {
+			cluster.Name = "rabbitmq-remote-missing-kubeconfig"
+			cluster.Spec.RemoteCluster.KubeconfigSecret.Name = "does-not-exist"
+			Expect(client.Create(ctx, cluster)).To(Succeed())
+
+			Eventually(func() string {
+				return aggregateEventMsgs(ctx, cluster, "RemoteClusterError")
+			}, 5).Should(ContainSubstring("failed to get kubeconfig secret does-not-exist"))
+		})
+	})
+})
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// resyncIntervalAnnotation overrides ResyncInterval for a RabbitmqCluster, as a duration such as "10m".
+// "0s" disables periodic reconciles of the RabbitmqCluster.
+const resyncIntervalAnnotation = "rabbitmq.com/resyncInterval"
+
+// resyncAfter returns the delay after which a successfully reconciled RabbitmqCluster is reconciled again, so that
+// changes to child resources which do not cause events, e.g. while the operator was down, are reverted.
+// The delay is jittered by up to 10%, so that the reconciles of many RabbitmqClusters spread out. It is 0 if periodic
+// reconciles are disabled.
+func (r *RabbitmqClusterReconciler) resyncAfter(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) time.Duration {
+	interval := r.ResyncInterval
+	if value, ok := rmq.Annotations[resyncIntervalAnnotation]; ok {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			ctrl.LoggerFrom(ctx).Info("ignoring invalid annotation; it must be a duration such as 10m",
+				"annotation", resyncIntervalAnnotation, "value", value)
+		} else {
+			interval = parsed
+		}
+	}
+	if interval <= 0 {
+		return 0
+	}
+	return wait.Jitter(interval, 0.1)
+}
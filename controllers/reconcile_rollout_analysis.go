@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/analysis"
+	"github.com/rabbitmq/cluster-operator/v2/internal/versionskew"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// StatefulSet revision and image which last passed the rollout analysis
+	analyzedRevisionAnnotation = "rabbitmq.com/analyzedRevision"
+	analyzedImageAnnotation    = "rabbitmq.com/analyzedImage"
+	// interval at which a rollout is checked until it can be analysed
+	rolloutAnalysisRequeueInterval = 15 * time.Second
+)
+
+// runRolloutAnalysisIfNeeded analyses every new StatefulSet revision once it is rolled out, and
+// spec.rolloutAnalysis.initialDelaySeconds passed since its last Pod became ready.
+// The first revision of a RabbitmqCluster is recorded without running the analysis.
+// It returns a non-zero requeue duration while the analysis is pending, and after it failed, so that
+// the remaining reconcile steps only run for revisions which passed the analysis.
+func (r *RabbitmqClusterReconciler) runRolloutAnalysisIfNeeded(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
+	if rmq.Spec.RolloutAnalysis == nil {
+		return 0, nil
+	}
+	logger := ctrl.LoggerFrom(ctx)
+
+	sts, err := r.statefulSet(ctx, rmq)
+	if err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	revision := sts.Status.UpdateRevision
+	analyzedRevision := rmq.Annotations[analyzedRevisionAnnotation]
+	if revision == "" || revision == analyzedRevision {
+		return 0, nil
+	}
+	if !allReplicasReadyAndUpdated(sts) {
+		logger.V(1).Info("not all replicas ready yet; requeuing request to run rollout analysis")
+		return rolloutAnalysisRequeueInterval, nil
+	}
+
+	if analyzedRevision != "" {
+		if delay, err := r.rolloutAnalysisDelay(ctx, rmq, sts); err != nil || delay > 0 {
+			logger.V(1).Info("waiting for the initial delay of the rollout analysis", "delay", delay)
+			return delay, err
+		}
+		err := analysis.Run(ctx, rmq.Spec.RolloutAnalysis, r.RolloutAnalysisPrometheusURLs)
+		if errors.Is(err, analysis.ErrAnalysisFailed) {
+			return r.handleFailedRolloutAnalysis(ctx, rmq, err)
+		}
+		if errors.Is(err, analysis.ErrPrometheusURLNotAllowed) {
+			// retrying does not help until the RabbitmqCluster or the operator configuration changes
+			r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedRolloutAnalysis", err.Error())
+			r.setReconcileSuccess(ctx, rmq, corev1.ConditionFalse, "FailedRolloutAnalysis", err.Error())
+			return time.Minute, nil
+		}
+		if err != nil {
+			r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedRolloutAnalysis", err.Error())
+			return 0, err
+		}
+		msg := fmt.Sprintf("StatefulSet revision %s passed the rollout analysis", revision)
+		logger.Info(msg)
+		r.Recorder.Event(rmq, corev1.EventTypeNormal, "RolloutAnalysisSuccessful", msg)
+	}
+
+	if rmq.Annotations == nil {
+		rmq.Annotations = map[string]string{}
+	}
+	rmq.Annotations[analyzedRevisionAnnotation] = revision
+	rmq.Annotations[analyzedImageAnnotation] = rmq.Spec.Image
+	return r.updateRabbitmqCluster(ctx, rmq, "rollout analysis annotations")
+}
+
+// rolloutAnalysisDelay returns the time left until spec.rolloutAnalysis.initialDelaySeconds passed since the last Pod
+// of the StatefulSet became ready.
+func (r *RabbitmqClusterReconciler) rolloutAnalysisDelay(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, sts *appsv1.StatefulSet) (time.Duration, error) {
+	initialDelay := time.Duration(ptr.Deref(rmq.Spec.RolloutAnalysis.InitialDelaySeconds, 0)) * time.Second
+	if initialDelay == 0 || sts.Spec.Selector == nil {
+		return 0, nil
+	}
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, client.InNamespace(sts.Namespace), client.MatchingLabels(sts.Spec.Selector.MatchLabels)); err != nil {
+		return 0, err
+	}
+	var readySince time.Time
+	for i := range pods.Items {
+		for _, condition := range pods.Items[i].Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue && condition.LastTransitionTime.After(readySince) {
+				readySince = condition.LastTransitionTime.Time
+			}
+		}
+	}
+	return max(time.Until(readySince.Add(initialDelay)), 0), nil
+}
+
+// handleFailedRolloutAnalysis rolls back the image to the last image which passed the analysis, if the RabbitMQ
+// versions of the images only differ in their patch version. Otherwise, it pauses reconciliation.
+func (r *RabbitmqClusterReconciler) handleFailedRolloutAnalysis(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, analysisErr error) (time.Duration, error) {
+	logger := ctrl.LoggerFrom(ctx)
+	previousImage := rmq.Annotations[analyzedImageAnnotation]
+
+	if rmq.Spec.RolloutAnalysis.OnFailure == rabbitmqv1beta1.RolloutAnalysisRollback && previousImage != "" && previousImage != rmq.Spec.Image {
+		if patchVersionChange(previousImage, rmq.Spec.Image) {
+			msg := fmt.Sprintf("%s; rolling back image %s to %s", analysisErr.Error(), rmq.Spec.Image, previousImage)
+			logger.Info(msg)
+			r.Recorder.Event(rmq, corev1.EventTypeWarning, "RolloutAnalysisFailed", msg)
+			rmq.Spec.Image = previousImage
+			if requeueAfter, err := r.updateRabbitmqCluster(ctx, rmq, "image rollback"); err != nil || requeueAfter > 0 {
+				return requeueAfter, err
+			}
+			return rolloutAnalysisRequeueInterval, nil
+		}
+		logger.Info("not rolling back image, since RabbitMQ does not support downgrades to a previous minor version",
+			"image", rmq.Spec.Image, "previousImage", previousImage)
+	}
+
+	msg := fmt.Sprintf("%s; pausing reconciliation", analysisErr.Error())
+	logger.Info(msg)
+	r.Recorder.Event(rmq, corev1.EventTypeWarning, "RolloutAnalysisFailed", msg)
+	if rmq.Labels == nil {
+		rmq.Labels = map[string]string{}
+	}
+	rmq.Labels[pauseReconciliationLabel] = "true"
+	if requeueAfter, err := r.updateRabbitmqCluster(ctx, rmq, "pause reconciliation label"); err != nil || requeueAfter > 0 {
+		return requeueAfter, err
+	}
+	return rolloutAnalysisRequeueInterval, nil
+}
+
+// patchVersionChange returns true if the RabbitMQ versions of the images only differ in their patch version.
+func patchVersionChange(image, otherImage string) bool {
+	version, ok := versionskew.ImageVersion(image)
+	if !ok {
+		return false
+	}
+	otherVersion, ok := versionskew.ImageVersion(otherImage)
+	return ok && versionskew.SameMinor(version, otherVersion)
+}
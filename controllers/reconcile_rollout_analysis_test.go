@@ -0,0 +1,146 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Rollout analysis", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	rollOut := func(revision string) {
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 1
+		sts.Status.CurrentReplicas = 1
+		sts.Status.UpdatedReplicas = 1
+		sts.Status.ReadyReplicas = 1
+		sts.Status.CurrentRevision = revision
+		sts.Status.UpdateRevision = revision
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+	}
+
+	getCluster := func() *rabbitmqv1beta1.RabbitmqCluster {
+		rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+		Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), rmq)).To(Succeed())
+		return rmq
+	}
+
+	BeforeEach(func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-rollout-analysis",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Replicas: ptr.To(int32(1)),
+				Image:    "rabbitmq:4.0.5",
+				RolloutAnalysis: &rabbitmqv1beta1.RolloutAnalysisSpec{
+					PrometheusURL: prometheus.URL,
+					Queries: []rabbitmqv1beta1.RolloutAnalysisQuery{
+						{Name: "errors", Query: "sum(errors)", Max: ptr.To(k8sresource.MustParse("0"))},
+					},
+					InitialDelaySeconds: ptr.To(int32(0)),
+					OnFailure:           rabbitmqv1beta1.RolloutAnalysisRollback,
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		Eventually(func() bool {
+			err := client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), &rabbitmqv1beta1.RabbitmqCluster{})
+			return apierrors.IsNotFound(err)
+		}, 5).Should(BeTrue())
+	})
+
+	// changeImage changes the image of the RabbitmqCluster, and rolls out the next StatefulSet revision
+	changeImage := func(image, revision string) {
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Spec.Image = image
+		})).To(Succeed())
+		Eventually(func() string {
+			return statefulSet(ctx, cluster).Spec.Template.Spec.Containers[0].Image
+		}, 5).Should(Equal(image))
+		rollOut(revision)
+	}
+
+	recordFirstRevision := func() {
+		rollOut("revision-1")
+		Eventually(func() map[string]string {
+			return getCluster().Annotations
+		}, 5).Should(SatisfyAll(
+			HaveKeyWithValue("rabbitmq.com/analyzedRevision", "revision-1"),
+			HaveKeyWithValue("rabbitmq.com/analyzedImage", "rabbitmq:4.0.5"),
+		))
+	}
+
+	It("rolls back the image when the analysis of a patch upgrade fails", func() {
+		By("recording the first revision without running the analysis", recordFirstRevision)
+
+		By("rolling back the image once the new revision is rolled out", func() {
+			changeImage("rabbitmq:4.0.7", "revision-2")
+
+			Eventually(func() string {
+				return getCluster().Spec.Image
+			}, 5).Should(Equal("rabbitmq:4.0.5"))
+			Eventually(func() string {
+				return aggregateEventMsgs(ctx, cluster, "RolloutAnalysisFailed")
+			}, 5).Should(ContainSubstring("query errors returned 3, above the maximum of 0; rolling back image rabbitmq:4.0.7 to rabbitmq:4.0.5"))
+		})
+
+		By("applying the previous image despite the downgrade", func() {
+			Eventually(func() string {
+				return statefulSet(ctx, cluster).Spec.Template.Spec.Containers[0].Image
+			}, 5).Should(Equal("rabbitmq:4.0.5"))
+		})
+	})
+
+	It("pauses reconciliation when the analysis of a minor upgrade fails", func() {
+		By("recording the first revision without running the analysis", recordFirstRevision)
+
+		By("pausing reconciliation instead of downgrading to the previous minor version", func() {
+			changeImage("rabbitmq:4.1.0", "revision-2")
+
+			Eventually(func() map[string]string {
+				return getCluster().Labels
+			}, 5).Should(HaveKeyWithValue("rabbitmq.com/pauseReconciliation", "true"))
+			Expect(getCluster().Spec.Image).To(Equal("rabbitmq:4.1.0"))
+			Expect(aggregateEventMsgs(ctx, cluster, "RolloutAnalysisFailed")).To(ContainSubstring("pausing reconciliation"))
+		})
+	})
+
+	When("the Prometheus URL is not allowed", func() {
+		BeforeEach(func() {
+			cluster.Name = "rabbitmq-rollout-analysis-not-allowed"
+			cluster.Spec.RolloutAnalysis.PrometheusURL = "http://169.254.169.254"
+		})
+
+		It("does not run the analysis", func() {
+			recordFirstRevision()
+			changeImage("rabbitmq:4.0.7", "revision-2")
+
+			Eventually(func() string {
+				return aggregateEventMsgs(ctx, cluster, "FailedRolloutAnalysis")
+			}, 5).Should(ContainSubstring("http://169.254.169.254 is not one of the Prometheus URLs allowed by the operator"))
+			Expect(getCluster().Annotations).To(HaveKeyWithValue("rabbitmq.com/analyzedRevision", "revision-1"))
+		})
+	})
+})
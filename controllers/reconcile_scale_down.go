@@ -12,7 +12,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// cluster scale down not supported
+// unsafeScaleDownAnnotation allows reducing the replicas of a RabbitmqCluster. Nodes are removed without
+// transferring their quorum queue and stream members, so their data is lost.
+const unsafeScaleDownAnnotation = "rabbitmq.com/unsafeScaleDown"
+
+// cluster scale down not supported, unless unsafeScaleDownAnnotation is set to "true"
 // log error, publish warning event, and set ReconcileSuccess to false when scale down request detected
 func (r *RabbitmqClusterReconciler) scaleDown(ctx context.Context, cluster *v1beta1.RabbitmqCluster, current, sts *appsv1.StatefulSet) bool {
 	logger := ctrl.LoggerFrom(ctx)
@@ -20,12 +24,19 @@ func (r *RabbitmqClusterReconciler) scaleDown(ctx context.Context, cluster *v1be
 	currentReplicas := *current.Spec.Replicas
 	desiredReplicas := *sts.Spec.Replicas
 	if currentReplicas > desiredReplicas {
+		if cluster.Annotations[unsafeScaleDownAnnotation] == "true" {
+			msg := fmt.Sprintf("Scaling down cluster from %d nodes to %d nodes; annotation '%s' is set to true", currentReplicas, desiredReplicas, unsafeScaleDownAnnotation)
+			logger.Info(msg)
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "UnsafeScaleDown", msg)
+			return false
+		}
 		msg := fmt.Sprintf("Cluster Scale down not supported; tried to scale cluster from %d nodes to %d nodes", currentReplicas, desiredReplicas)
 		reason := "UnsupportedOperation"
 		logger.Error(errors.New(reason), msg)
 		r.Recorder.Event(cluster, corev1.EventTypeWarning, reason, msg)
-		cluster.Status.SetCondition(status.ReconcileSuccess, corev1.ConditionFalse, reason, msg)
-		if statusErr := r.Status().Update(ctx, cluster); statusErr != nil {
+		if statusErr := r.updateStatus(ctx, cluster, func(clusterStatus *v1beta1.RabbitmqClusterStatus) {
+			clusterStatus.SetCondition(status.ReconcileSuccess, corev1.ConditionFalse, reason, msg)
+		}); statusErr != nil {
 			logger.Error(statusErr, "Failed to update ReconcileSuccess condition state")
 		}
 		return true
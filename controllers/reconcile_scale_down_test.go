@@ -83,4 +83,30 @@ var _ = Describe("Cluster scale down", func() {
 				"and message: Cluster Scale down not supported; tried to scale cluster from 5 nodes to 3 nodes"))
 		})
 	})
+
+	It("scales down if the unsafeScaleDown annotation is set", func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "rabbitmq-unsafe-shrink",
+				Namespace:   defaultNamespace,
+				Annotations: map[string]string{"rabbitmq.com/unsafeScaleDown": "true"},
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Replicas: ptr.To(int32(5)),
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Spec.Replicas = ptr.To(int32(3))
+		})).To(Succeed())
+		Eventually(func() int32 {
+			sts, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			return *sts.Spec.Replicas
+		}, 10, 1).Should(Equal(int32(3)))
+		Expect(aggregateEventMsgs(ctx, cluster, "UnsafeScaleDown")).To(
+			ContainSubstring("Scaling down cluster from 5 nodes to 3 nodes"))
+	})
 })
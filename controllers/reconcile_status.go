@@ -9,9 +9,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// reconcileStatus sets status.defaultUser (secret and service reference) and status.binding.
-// when vault is used as secret backend for default user, no user secret object is created
-// therefore only status.defaultUser.serviceReference is set.
+// reconcileStatus sets status.defaultUser (secret and service reference), status.binding and the TLS listeners in status.tls.
+// when vault or the Secrets Store CSI driver is used as secret backend for default user, no user secret object is created
+// therefore only status.defaultUser.serviceReference is set. When an external secret is used, it is referenced instead
+// of the default user secret.
 // status.binding exposes the default user secret which contains the binding
 // information for this RabbitmqCluster.
 // Default user secret implements the service binding Provisioned Service
@@ -26,34 +27,38 @@ func (r *RabbitmqClusterReconciler) reconcileStatus(ctx context.Context, rmq *ra
 		},
 	}
 
-	if !rmq.VaultDefaultUserSecretEnabled() {
+	if !rmq.VaultDefaultUserSecretEnabled() && !rmq.CSIDefaultUserEnabled() {
+		// the external Secret replaces the default-user Secret, which is not created
+		secretName := rmq.ChildResourceName(resource.DefaultUserSecretName)
+		if rmq.ExternalSecretEnabled() {
+			secretName = rmq.Spec.SecretBackend.ExternalSecret.Name
+		}
 		defaultUserStatus.SecretReference = &rabbitmqv1beta1.RabbitmqClusterSecretReference{
-			Name:      rmq.ChildResourceName(resource.DefaultUserSecretName),
+			Name:      secretName,
 			Namespace: rmq.Namespace,
 			Keys: map[string]string{
 				"username": "username",
 				"password": "password",
+				"host":     "host",
+				"port":     "port",
 			},
 		}
-		if !rmq.ExternalSecretEnabled() {
-			binding = &corev1.LocalObjectReference{
-				Name: rmq.ChildResourceName(resource.DefaultUserSecretName),
-			}
-		} else {
-			binding = &corev1.LocalObjectReference{
-				Name: rmq.Spec.SecretBackend.ExternalSecret.Name,
-			}
-
-		}
+		binding = &corev1.LocalObjectReference{Name: secretName}
 	}
 
-	if !reflect.DeepEqual(rmq.Status.DefaultUser, defaultUserStatus) || !reflect.DeepEqual(rmq.Status.Binding, binding) {
-		rmq.Status.DefaultUser = defaultUserStatus
-		rmq.Status.Binding = binding
-		if err := r.Status().Update(ctx, rmq); err != nil {
-			return err
-		}
+	listeners := resource.TLSListeners(rmq)
+	setStatus := func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		clusterStatus.SetTLSListeners(listeners)
+		clusterStatus.DefaultUser = defaultUserStatus.DeepCopy()
+		clusterStatus.Binding = binding.DeepCopy()
 	}
+	desired := rmq.Status.DeepCopy()
+	setStatus(desired)
 
-	return nil
+	var err error
+	if !reflect.DeepEqual(desired, &rmq.Status) {
+		err = r.updateStatus(ctx, rmq, setStatus)
+	}
+	setTLSCertificateExpiryMetric(rmq)
+	return err
 }
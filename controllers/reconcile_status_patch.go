@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"context"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateStatus applies mutate to the status of rmq, and writes it with a merge patch owned by resource.FieldManager.
+// mutate is applied again to the status of the latest RabbitmqCluster, and the patch is guarded by its resourceVersion,
+// so that the fields which mutate does not set keep their concurrent writes, e.g. those of the health poller.
+// It is retried on conflicts, which happen whenever the RabbitmqCluster changed since it was read, reading the
+// RabbitmqCluster from the API server since the cache might be stale. mutate must therefore only depend on its argument
+// and on values computed beforehand.
+// The resourceVersion of rmq is updated if rmq was the latest RabbitmqCluster, so that it can be updated afterwards.
+func (r *RabbitmqClusterReconciler) updateStatus(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, mutate func(*rabbitmqv1beta1.RabbitmqClusterStatus)) error {
+	mutate(&rmq.Status)
+	var reader client.Reader = r.Client
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &rabbitmqv1beta1.RabbitmqCluster{}
+		if err := reader.Get(ctx, client.ObjectKeyFromObject(rmq), latest); err != nil {
+			return err
+		}
+		reader = r.APIReader
+
+		readVersion := latest.ResourceVersion
+		patch := client.MergeFromWithOptions(latest.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		mutate(&latest.Status)
+		if err := r.Status().Patch(ctx, latest, patch, client.FieldOwner(resource.FieldManager)); err != nil {
+			return err
+		}
+		if rmq.ResourceVersion == readVersion {
+			rmq.ResourceVersion = latest.ResourceVersion
+		}
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+package controllers_test
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/controllers"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Status updates", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	BeforeEach(func() {
+		// the paused cluster is only written by the reconciler of the manager to set the Paused condition
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-status-writers",
+				Namespace: defaultNamespace,
+				Labels:    map[string]string{"rabbitmq.com/pauseReconciliation": "true"},
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		Eventually(func() []status.RabbitmqClusterCondition {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Status.Conditions
+		}, 5).Should(ContainElement(HaveField("Type", status.Paused)))
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+	})
+
+	It("keeps the writes of concurrent writers which read the same RabbitmqCluster", func() {
+		reconciler := &controllers.RabbitmqClusterReconciler{
+			Client:    client,
+			APIReader: client,
+			Scheme:    scheme.Scheme,
+		}
+		nodes := []rabbitmqv1beta1.RabbitmqClusterNodeStatus{{
+			Pod:        "rabbitmq-status-writers-server-0",
+			Node:       "rabbit@rabbitmq-status-writers-server-0",
+			Ready:      true,
+			Membership: rabbitmqv1beta1.NodeRunning,
+		}}
+
+		// both writers start from the same RabbitmqCluster, like the health poller and the reconciler
+		healthPollerCopy, reconcilerCopy := cluster.DeepCopy(), cluster.DeepCopy()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			Expect(reconciler.UpdateStatus(ctx, healthPollerCopy, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+				clusterStatus.Nodes = nodes
+			})).To(Succeed())
+		}()
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			Expect(reconciler.UpdateStatus(ctx, reconcilerCopy, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+				clusterStatus.SetCondition(status.ReconcileSuccess, corev1.ConditionTrue, "Success", "Finish reconciling")
+			})).To(Succeed())
+		}()
+		wg.Wait()
+
+		rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+		Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), rmq)).To(Succeed())
+		Expect(rmq.Status.Nodes).To(Equal(nodes))
+		Expect(rmq.Status.Conditions).To(ContainElements(
+			HaveField("Type", status.ReconcileSuccess),
+			HaveField("Type", status.Paused),
+		))
+	})
+})
@@ -59,6 +59,8 @@ var _ = Describe("Reconcile status", func() {
 		Expect(secretRef.Namespace).To(Equal(rmq.Namespace))
 		Expect(secretRef.Keys).To(HaveKeyWithValue("username", "username"))
 		Expect(secretRef.Keys).To(HaveKeyWithValue("password", "password"))
+		Expect(secretRef.Keys).To(HaveKeyWithValue("host", "host"))
+		Expect(secretRef.Keys).To(HaveKeyWithValue("port", "port"))
 
 		By("setting the service details")
 		rmq = &rabbitmqv1beta1.RabbitmqCluster{}
@@ -95,6 +97,54 @@ var _ = Describe("Reconcile status", func() {
 		Expect(binding.Name).To(Equal(rmq.ChildResourceName(resource.DefaultUserSecretName)))
 	})
 
+	It("sets status.readyReplicas from the StatefulSet", func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-ready-replicas",
+				Namespace: defaultNamespace,
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+
+		rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+		Eventually(func() int32 {
+			Expect(client.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, rmq)).To(Succeed())
+			return rmq.Status.ReadyReplicas
+		}, 5).Should(Equal(int32(1)))
+	})
+
+	When("an external secret is used", func() {
+		It("references the external secret", func() {
+			cluster = &rabbitmqv1beta1.RabbitmqCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rabbitmq-external-secret-status",
+					Namespace: defaultNamespace,
+				},
+				Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+					SecretBackend: rabbitmqv1beta1.SecretBackend{
+						ExternalSecret: corev1.LocalObjectReference{Name: "my-secret"},
+					},
+				},
+			}
+			Expect(client.Create(ctx, cluster)).To(Succeed())
+			waitForClusterCreation(ctx, cluster, client)
+
+			rmq := &rabbitmqv1beta1.RabbitmqCluster{}
+			Eventually(func() *rabbitmqv1beta1.RabbitmqClusterDefaultUser {
+				Expect(client.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, rmq)).To(Succeed())
+				return rmq.Status.DefaultUser
+			}, 5).ShouldNot(BeNil())
+			Expect(rmq.Status.DefaultUser.SecretReference).To(HaveField("Name", "my-secret"))
+			Expect(rmq.Status.Binding).To(Equal(&corev1.LocalObjectReference{Name: "my-secret"}))
+		})
+	})
+
 	When("secret backend vault is enabled", func() {
 		It("sets service reference status correctly", func() {
 			cluster = &rabbitmqv1beta1.RabbitmqCluster{
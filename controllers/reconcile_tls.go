@@ -31,6 +31,13 @@ func (r *RabbitmqClusterReconciler) reconcileTLS(ctx context.Context, rabbitmqCl
 			return err
 		}
 	}
+
+	if rabbitmqCluster.InterNodeTLSEnabled() {
+		if err := r.checkDistributionTLSSecret(ctx, rabbitmqCluster); err != nil {
+			r.setReconcileSuccess(ctx, rabbitmqCluster, corev1.ConditionFalse, "TLSError", err.Error())
+			return err
+		}
+	}
 	return nil
 }
 
@@ -85,3 +92,27 @@ func (r *RabbitmqClusterReconciler) checkTLSSecrets(ctx context.Context, rabbitm
 	}
 	return nil
 }
+
+// checkDistributionTLSSecret checks that the Secret of the inter-node TLS exists and has the keys mounted into the rabbitmq container,
+// since nodes started without them cannot join the cluster.
+func (r *RabbitmqClusterReconciler) checkDistributionTLSSecret(ctx context.Context, rabbitmqCluster *rabbitmqv1beta1.RabbitmqCluster) error {
+	logger := ctrl.LoggerFrom(ctx)
+	secretName := rabbitmqCluster.Spec.TLS.Distribution.SecretName
+
+	secret := &corev1.Secret{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: rabbitmqCluster.Namespace, Name: secretName}, secret); err != nil {
+		r.Recorder.Event(rabbitmqCluster, corev1.EventTypeWarning, "TLSError",
+			fmt.Sprintf("Failed to get inter-node TLS secret %s in namespace %s: %v", secretName, rabbitmqCluster.Namespace, err.Error()))
+		logger.Error(err, "Error setting up inter-node TLS")
+		return err
+	}
+	for _, key := range []string{"ca.crt", "tls.crt", "tls.key"} {
+		if _, ok := secret.Data[key]; !ok {
+			err := k8serrors.NewBadRequest(fmt.Sprintf("inter-node TLS secret %s in namespace %s does not have the fields ca.crt, tls.crt and tls.key", secretName, rabbitmqCluster.Namespace))
+			r.Recorder.Event(rabbitmqCluster, corev1.EventTypeWarning, "TLSError", err.Error())
+			logger.Error(err, "Error setting up inter-node TLS")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// tlsSecretKey indexes RabbitmqClusters by the name of the Secret referenced in spec.tls.secretName.
+const tlsSecretKey = ".spec.tls.secretName"
+
+// reloadTLSCertificateIfRotated makes running nodes load the certificate of spec.tls.secretName after the Secret changed.
+// Kubelet updates the mounted certificate files, but RabbitMQ caches certificates it has read, so new connections would
+// keep using the previous certificate until the nodes are restarted. Clearing the PEM cache makes RabbitMQ read the files again.
+func (r *RabbitmqClusterReconciler) reloadTLSCertificateIfRotated(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
+	if !rmq.SecretTLSEnabled() {
+		return 0, nil
+	}
+	logger := ctrl.LoggerFrom(ctx)
+
+	secret := &corev1.Secret{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: rmq.Spec.TLS.SecretName}, secret); err != nil {
+		return 0, err
+	}
+	certificate := secret.Data["tls.crt"]
+	hash := fmt.Sprintf("%x", sha256.Sum256(certificate))
+
+	if rmq.Status.TLS != nil && rmq.Status.TLS.CertificateHash == hash {
+		return 0, nil
+	}
+
+	// nodes load the current certificate when they start, so only a certificate which changed afterwards must be reloaded
+	if rmq.Status.TLS != nil && rmq.Status.TLS.CertificateHash != "" {
+		sts, err := r.statefulSet(ctx, rmq)
+		if err != nil {
+			return 0, err
+		}
+		if !allReplicasReadyAndUpdated(sts) {
+			logger.V(1).Info("not all replicas ready yet; requeuing request to reload TLS certificate")
+			return 15 * time.Second, nil
+		}
+		for i := int32(0); i < *rmq.Spec.Replicas; i++ {
+			podName := fmt.Sprintf("%s-%d", rmq.ChildResourceName("server"), i)
+			// the PEM cache is only cleared once kubelet updated the mounted certificate
+			cmd := fmt.Sprintf("echo '%s  /etc/rabbitmq-tls/tls.crt' | sha256sum --check --status && rabbitmqctl eval 'ssl:clear_pem_cache().'", hash)
+			stdout, stderr, err := r.exec(rmq.Namespace, podName, "rabbitmq", "sh", "-c", cmd)
+			if err != nil {
+				logger.Info("TLS certificate not reloaded yet; requeuing request", "pod", podName, "stdout", stdout, "stderr", stderr, "error", err.Error())
+				return 15 * time.Second, nil
+			}
+		}
+		logger.Info("successfully reloaded TLS certificate")
+		r.Recorder.Event(rmq, corev1.EventTypeNormal, "TLSCertificateReloaded", fmt.Sprintf("reloaded the certificate of Secret %s", secret.Name))
+	}
+
+	notAfter := certificateNotAfter(certificate)
+	err := r.updateStatus(ctx, rmq, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		clusterStatus.SetTLSCertificate(hash, notAfter)
+	})
+	setTLSCertificateExpiryMetric(rmq)
+	return 0, err
+}
+
+// certificateNotAfter returns the expiry of the first certificate of a PEM encoded certificate chain,
+// or nil if it cannot be parsed.
+func certificateNotAfter(certificate []byte) *metav1.Time {
+	block, _ := pem.Decode(certificate)
+	if block == nil {
+		return nil
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return &metav1.Time{Time: parsed.NotAfter}
+}
+
+func indexTLSSecret(rawObj client.Object) []string {
+	rmq := rawObj.(*rabbitmqv1beta1.RabbitmqCluster)
+	if !rmq.SecretTLSEnabled() {
+		return nil
+	}
+	return []string{rmq.Spec.TLS.SecretName}
+}
+
+// clustersReferencingTLSSecret maps a Secret to the RabbitmqClusters that reference it in spec.tls.secretName.
+func (r *RabbitmqClusterReconciler) clustersReferencingTLSSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	return r.clustersReferencing(ctx, secret, tlsSecretKey)
+}
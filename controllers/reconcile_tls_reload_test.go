@@ -0,0 +1,74 @@
+package controllers_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Reconcile TLS certificate reload", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+	)
+
+	BeforeEach(func() {
+		tlsSecretWithoutCACert(ctx, "tls-reload-secret", defaultNamespace)
+		cluster = rabbitmqClusterWithTLS(ctx, "rabbitmq-tls-reload", defaultNamespace, rabbitmqv1beta1.TLSSpec{
+			SecretName: "tls-reload-secret",
+		})
+		waitForClusterCreation(ctx, cluster, client)
+
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+	})
+
+	It("records the loaded certificate and reloads it on running nodes when the Secret changes", func() {
+		Eventually(func() string {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			if cluster.Status.TLS == nil {
+				return ""
+			}
+			return cluster.Status.TLS.CertificateHash
+		}, 5).ShouldNot(BeEmpty())
+		Expect(cluster.Status.TLS.Enabled).To(BeTrue())
+		Expect(cluster.Status.TLS.Listeners).To(Equal([]string{"amqps", "management-tls", "prometheus-tls"}))
+		Expect(cluster.Status.TLS.NotAfter).To(BeNil())
+		Expect(cluster.Status.Conditions).To(ContainElement(SatisfyAll(
+			HaveField("Type", status.TLSCertificateLoaded),
+			HaveField("Status", corev1.ConditionUnknown),
+		)))
+		Expect(fakeExecutor.ExecutedCommands()).NotTo(ContainElement(ContainElement(ContainSubstring("ssl:clear_pem_cache()"))))
+
+		secret := &corev1.Secret{}
+		Expect(client.Get(ctx, types.NamespacedName{Namespace: defaultNamespace, Name: "tls-reload-secret"}, secret)).To(Succeed())
+		secret.Data["tls.crt"] = []byte("this is a rotated tls cert")
+		Expect(client.Update(ctx, secret)).To(Succeed())
+		// the Secret is not labelled as part of rabbitmq, so the change is picked up on the next reconcile
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Annotations = map[string]string{"test": "rotate"}
+		})).To(Succeed())
+
+		hash := fmt.Sprintf("%x", sha256.Sum256([]byte("this is a rotated tls cert")))
+		Eventually(func() string {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Status.TLS.CertificateHash
+		}, 5).Should(Equal(hash))
+		Expect(fakeExecutor.ExecutedCommands()).To(ContainElement(command{"sh", "-c",
+			fmt.Sprintf("echo '%s  /etc/rabbitmq-tls/tls.crt' | sha256sum --check --status && rabbitmqctl eval 'ssl:clear_pem_cache().'", hash)}))
+	})
+})
@@ -240,6 +240,36 @@ var _ = Describe("Reconcile TLS", func() {
 		})
 	})
 
+	When("inter-node TLS is enabled", func() {
+		AfterEach(func() {
+			Expect(client.Delete(ctx, cluster)).To(Succeed())
+			waitForClusterDeletion(ctx, cluster, client)
+		})
+
+		It("fails to deploy the RabbitmqCluster until the secret has the expected keys", func() {
+			tlsSecretWithoutCACert(ctx, "distribution-tls-secret", defaultNamespace)
+			tlsSpec := rabbitmqv1beta1.TLSSpec{
+				Distribution: &rabbitmqv1beta1.DistributionTLSSpec{SecretName: "distribution-tls-secret"},
+			}
+			cluster = rabbitmqClusterWithTLS(ctx, "rabbitmq-distribution-tls", defaultNamespace, tlsSpec)
+
+			verifyTLSErrorEvents(ctx, cluster, fmt.Sprintf("inter-node TLS secret distribution-tls-secret in namespace %s does not have the fields ca.crt, tls.crt and tls.key", defaultNamespace))
+			verifyReconcileSuccessFalse(cluster.Name, cluster.Namespace)
+
+			secret := &corev1.Secret{}
+			Expect(client.Get(ctx, types.NamespacedName{Name: "distribution-tls-secret", Namespace: defaultNamespace}, secret)).To(Succeed())
+			secret.Data["ca.crt"] = []byte("this is a ca cert")
+			Expect(client.Update(ctx, secret)).To(Succeed())
+
+			waitForClusterCreation(ctx, cluster, client)
+			Expect(extractContainer(statefulSet(ctx, cluster).Spec.Template.Spec.Containers, "rabbitmq").VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "rabbitmq-distribution-tls",
+				MountPath: "/etc/rabbitmq-distribution-tls/",
+				ReadOnly:  true,
+			}))
+		})
+	})
+
 	When("DiableNonTLSListeners set to true", func() {
 		It("logs TLSError and set ReconcileSuccess to false when TLS is not enabled", func() {
 			tlsSpec := rabbitmqv1beta1.TLSSpec{
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	"github.com/rabbitmq/cluster-operator/v2/internal/versionskew"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// unsafeVersionChangeAnnotation allows changing the RabbitMQ image to a version which RabbitMQ does not support
+// upgrading to from the running version, e.g. to roll back a failed upgrade.
+const unsafeVersionChangeAnnotation = "rabbitmq.com/unsafeVersionChange"
+
+// unsupportedVersionChange returns true if the RabbitMQ image of the StatefulSet would change to a version which
+// RabbitMQ does not support upgrading to from the running version: downgrades and upgrades skipping a minor version.
+// Such changes leave nodes crash looping, so they are refused with a warning event and ReconcileSuccess set to false,
+// unless unsafeVersionChangeAnnotation is set to "true".
+// Patch downgrades to the last image which passed the rollout analysis are allowed, so that failed rollouts can be
+// rolled back.
+// The running version is status.rabbitmqVersion, or the version of the current image if it was not read yet.
+// Images without a version in their tag are not checked.
+func (r *RabbitmqClusterReconciler) unsupportedVersionChange(ctx context.Context, cluster *v1beta1.RabbitmqCluster, current, sts *appsv1.StatefulSet) bool {
+	logger := ctrl.LoggerFrom(ctx)
+
+	currentImage, desiredImage := rabbitmqImage(current), rabbitmqImage(sts)
+	if currentImage == desiredImage {
+		return false
+	}
+	desiredVersion, ok := versionskew.ImageVersion(desiredImage)
+	if !ok {
+		return false
+	}
+	runningVersion, ok := versionskew.Parse(cluster.Status.RabbitmqVersion)
+	if !ok {
+		if runningVersion, ok = versionskew.ImageVersion(currentImage); !ok {
+			return false
+		}
+	}
+
+	err := versionskew.Check(runningVersion, desiredVersion)
+	if err == nil {
+		return false
+	}
+	if cluster.Spec.RolloutAnalysis != nil && desiredImage == cluster.Annotations[analyzedImageAnnotation] &&
+		versionskew.SameMinor(runningVersion, desiredVersion) {
+		logger.Info(fmt.Sprintf("Rolling back image to %s, which passed the rollout analysis", desiredImage))
+		return false
+	}
+	if cluster.Annotations[unsafeVersionChangeAnnotation] == "true" {
+		msg := fmt.Sprintf("Changing image to %s: %s; annotation '%s' is set to true", desiredImage, err, unsafeVersionChangeAnnotation)
+		logger.Info(msg)
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "UnsafeVersionChange", msg)
+		return false
+	}
+	msg := fmt.Sprintf("Refusing to change image to %s: %s", desiredImage, err)
+	reason := "UnsupportedVersionChange"
+	logger.Error(err, msg)
+	r.Recorder.Event(cluster, corev1.EventTypeWarning, reason, msg)
+	if statusErr := r.updateStatus(ctx, cluster, func(clusterStatus *v1beta1.RabbitmqClusterStatus) {
+		clusterStatus.SetCondition(status.ReconcileSuccess, corev1.ConditionFalse, reason, msg)
+	}); statusErr != nil {
+		logger.Error(statusErr, "Failed to update ReconcileSuccess condition state")
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Version skew protection", func() {
+	var (
+		cluster          *rabbitmqv1beta1.RabbitmqCluster
+		defaultNamespace = "default"
+		ctx              = context.Background()
+	)
+
+	stsImage := func() string {
+		sts, err := clientSet.AppsV1().StatefulSets(defaultNamespace).Get(ctx, cluster.ChildResourceName("server"), metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		return sts.Spec.Template.Spec.Containers[0].Image
+	}
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+	})
+
+	It("refuses upgrades skipping a minor version", func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-skip-minor",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Image: "rabbitmq:3.12.14-management",
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Spec.Image = "rabbitmq:4.0.5-management"
+		})).To(Succeed())
+		Consistently(stsImage, 5, 1).Should(Equal("rabbitmq:3.12.14-management"))
+
+		Expect(aggregateEventMsgs(ctx, cluster, "UnsupportedVersionChange")).To(
+			ContainSubstring("upgrade from 3.12.14 to 4.0.5 skips a minor version"))
+		Eventually(func() string {
+			rabbit := &rabbitmqv1beta1.RabbitmqCluster{}
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), rabbit)).To(Succeed())
+			for i := range rabbit.Status.Conditions {
+				if rabbit.Status.Conditions[i].Type == status.ReconcileSuccess {
+					return fmt.Sprintf("%s %s", rabbit.Status.Conditions[i].Status, rabbit.Status.Conditions[i].Reason)
+				}
+			}
+			return "condition not present"
+		}, 5).Should(Equal("False UnsupportedVersionChange"))
+
+		By("allowing the upgrade to the next minor version", func() {
+			Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+				r.Spec.Image = "rabbitmq:3.13.7-management"
+			})).To(Succeed())
+			Eventually(stsImage, 5).Should(Equal("rabbitmq:3.13.7-management"))
+		})
+	})
+
+	It("refuses downgrades unless the unsafeVersionChange annotation is set", func() {
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-downgrade",
+				Namespace: defaultNamespace,
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Image: "rabbitmq:4.1.0",
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Spec.Image = "rabbitmq:4.0.5"
+		})).To(Succeed())
+		Consistently(stsImage, 5, 1).Should(Equal("rabbitmq:4.1.0"))
+		Expect(aggregateEventMsgs(ctx, cluster, "UnsupportedVersionChange")).To(
+			ContainSubstring("downgrade from 4.1.0 to 4.0.5"))
+
+		Expect(updateWithRetry(cluster, func(r *rabbitmqv1beta1.RabbitmqCluster) {
+			r.Annotations = map[string]string{"rabbitmq.com/unsafeVersionChange": "true"}
+		})).To(Succeed())
+		Eventually(stsImage, 5).Should(Equal("rabbitmq:4.0.5"))
+		Expect(aggregateEventMsgs(ctx, cluster, "UnsafeVersionChange")).To(
+			ContainSubstring("annotation 'rabbitmq.com/unsafeVersionChange' is set to true"))
+	})
+})
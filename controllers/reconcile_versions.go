@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/health"
+	"github.com/rabbitmq/cluster-operator/v2/internal/rabbitmqclient"
+	"golang.org/x/mod/semver"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// versionsCommand prints the RabbitMQ version and the Erlang/OTP version of a node on separate lines.
+const versionsCommand = `rabbitmqctl eval 'io:format("~s~n~s~n", [rabbit_misc:version(), rabbit_misc:otp_version()]).'`
+
+// reconcileVersions records the RabbitMQ and Erlang versions of the running nodes in status.rabbitmqVersion and
+// status.erlangVersion. The versions are read once per StatefulSet revision, after all replicas were updated.
+func (r *RabbitmqClusterReconciler) reconcileVersions(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (time.Duration, error) {
+	sts, err := r.statefulSet(ctx, rmq)
+	if err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	revision := sts.Status.UpdateRevision
+	if revision == "" || revision == rmq.Status.VersionsRevision || !allReplicasReadyAndUpdated(sts) {
+		return 0, nil
+	}
+
+	podName := fmt.Sprintf("%s-0", rmq.ChildResourceName("server"))
+	rabbitmqVersion, erlangVersion, err := r.readVersions(rmq, podName)
+	if err != nil {
+		// reading the versions is retried with backoff
+		return 0, fmt.Errorf("failed to read the RabbitMQ and Erlang versions of pod %s: %w", podName, err)
+	}
+
+	return 0, r.updateStatus(ctx, rmq, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		upgrade := clusterStatus.Upgrade
+		if upgrade != nil && upgrade.Revision == revision {
+			upgrade.ToVersion = rabbitmqVersion
+			upgrade.UpdatedReplicas = sts.Status.UpdatedReplicas
+			upgrade.State = rabbitmqv1beta1.UpgradeCompleted
+		} else if clusterStatus.RabbitmqVersion != "" && rabbitmqVersion != "" && rabbitmqVersion != clusterStatus.RabbitmqVersion {
+			// the rollout finished before its progress was reported
+			clusterStatus.Upgrade = &rabbitmqv1beta1.RabbitmqClusterUpgradeStatus{
+				FromVersion:     clusterStatus.RabbitmqVersion,
+				ToVersion:       rabbitmqVersion,
+				UpdatedReplicas: sts.Status.UpdatedReplicas,
+				State:           rabbitmqv1beta1.UpgradeCompleted,
+				Revision:        revision,
+			}
+		}
+
+		clusterStatus.RabbitmqVersion = rabbitmqVersion
+		clusterStatus.ErlangVersion = erlangVersion
+		clusterStatus.VersionsRevision = revision
+	})
+}
+
+// enableFeatureFlagsAfterUpgrade enables all feature flags once an upgrade to a newer RabbitMQ version completed.
+// Feature flags introduced by the new version are disabled until they are enabled explicitly, and upgrading
+// to the next version requires them to be enabled.
+func (r *RabbitmqClusterReconciler) enableFeatureFlagsAfterUpgrade(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) error {
+	upgrade := rmq.Status.Upgrade
+	if upgrade == nil || upgrade.State != rabbitmqv1beta1.UpgradeCompleted || upgrade.FeatureFlagsEnabled ||
+		semver.Compare("v"+upgrade.ToVersion, "v"+upgrade.FromVersion) <= 0 {
+		return nil
+	}
+	logger := ctrl.LoggerFrom(ctx)
+
+	if err := r.enableStableFeatureFlags(ctx, rmq); err != nil {
+		msg := "failed to enable all feature flags after upgrade"
+		logger.Error(err, msg)
+		r.Recorder.Event(rmq, corev1.EventTypeWarning, "FailedReconcile", fmt.Sprintf("%s: %s", msg, err))
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	msg := fmt.Sprintf("enabled all feature flags after upgrade from RabbitMQ %s to %s", upgrade.FromVersion, upgrade.ToVersion)
+	logger.Info(msg)
+	r.Recorder.Event(rmq, corev1.EventTypeNormal, "FeatureFlagsEnabled", msg)
+	return r.updateStatus(ctx, rmq, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		if clusterStatus.Upgrade != nil {
+			clusterStatus.Upgrade.FeatureFlagsEnabled = true
+		}
+	})
+}
+
+// enableStableFeatureFlags enables the stable feature flags of a RabbitmqCluster which are disabled.
+// Experimental feature flags can break the cluster, so they are left for users to enable.
+func (r *RabbitmqClusterReconciler) enableStableFeatureFlags(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) error {
+	rabbitmqClient, err := r.rabbitmqClient(ctx, rmq, rabbitmqclient.ServiceHost(rmq))
+	if err != nil {
+		return err
+	}
+	featureFlags, err := rabbitmqClient.ListFeatureFlags(ctx)
+	if err != nil {
+		return err
+	}
+	for _, featureFlag := range featureFlags {
+		if featureFlag.State != "disabled" || featureFlag.Stability != "stable" {
+			continue
+		}
+		if err := rabbitmqClient.EnableFeatureFlag(ctx, featureFlag.Name); err != nil {
+			return fmt.Errorf("failed to enable feature flag %s: %w", featureFlag.Name, err)
+		}
+	}
+	return nil
+}
+
+// reportUpgradeProgress sets status.upgrade while the StatefulSet rolls out a revision which changes the RabbitMQ version.
+// Pods are updated from the highest ordinal down, so the new version is read from the Pod with the highest ordinal
+// once it runs the new revision.
+func (r *RabbitmqClusterReconciler) reportUpgradeProgress(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) error {
+	logger := ctrl.LoggerFrom(ctx)
+
+	sts, err := r.statefulSet(ctx, rmq)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	revision := sts.Status.UpdateRevision
+	// versions of the previous revision are needed to tell whether a rollout is an upgrade
+	if revision == "" || revision == rmq.Status.VersionsRevision || rmq.Status.RabbitmqVersion == "" || allReplicasReadyAndUpdated(sts) {
+		return nil
+	}
+
+	if upgrade := rmq.Status.Upgrade; upgrade != nil && upgrade.Revision == revision {
+		if upgrade.UpdatedReplicas == sts.Status.UpdatedReplicas {
+			return nil
+		}
+		return r.updateStatus(ctx, rmq, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+			if clusterStatus.Upgrade != nil {
+				clusterStatus.Upgrade.UpdatedReplicas = sts.Status.UpdatedReplicas
+			}
+		})
+	}
+
+	podName, updated, err := r.newestUpdatedPod(ctx, rmq, sts)
+	if err != nil || !updated {
+		return err
+	}
+	rabbitmqVersion, _, err := r.readVersions(rmq, podName)
+	if err != nil {
+		logger.V(1).Info("failed to read the RabbitMQ version of an updated node", "pod", podName, "error", err.Error())
+		return nil
+	}
+	if rabbitmqVersion == "" || rabbitmqVersion == rmq.Status.RabbitmqVersion {
+		return nil
+	}
+
+	logger.Info("RabbitMQ upgrade in progress", "fromVersion", rmq.Status.RabbitmqVersion, "toVersion", rabbitmqVersion)
+	upgrade := &rabbitmqv1beta1.RabbitmqClusterUpgradeStatus{
+		FromVersion:     rmq.Status.RabbitmqVersion,
+		ToVersion:       rabbitmqVersion,
+		UpdatedReplicas: sts.Status.UpdatedReplicas,
+		State:           rabbitmqv1beta1.UpgradeInProgress,
+		Revision:        revision,
+	}
+	return r.updateStatus(ctx, rmq, func(clusterStatus *rabbitmqv1beta1.RabbitmqClusterStatus) {
+		clusterStatus.Upgrade = upgrade.DeepCopy()
+	})
+}
+
+// newestUpdatedPod returns the name of the Pod with the highest ordinal, and whether it is ready and runs the update revision of the StatefulSet.
+func (r *RabbitmqClusterReconciler) newestUpdatedPod(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, sts *appsv1.StatefulSet) (string, bool, error) {
+	if sts.Spec.Replicas == nil || *sts.Spec.Replicas == 0 {
+		return "", false, nil
+	}
+	pod := &corev1.Pod{}
+	podName := fmt.Sprintf("%s-%d", rmq.ChildResourceName("server"), *sts.Spec.Replicas-1)
+	if err := r.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: podName}, pod); err != nil {
+		return podName, false, client.IgnoreNotFound(err)
+	}
+	return podName, pod.Labels[appsv1.ControllerRevisionHashLabelKey] == sts.Status.UpdateRevision && health.PodReady(*pod), nil
+}
+
+// readVersions returns the RabbitMQ and Erlang versions of the node running in the given Pod.
+// Both are empty if the output of versionsCommand cannot be parsed, since reading them again would fail the same way.
+func (r *RabbitmqClusterReconciler) readVersions(rmq *rabbitmqv1beta1.RabbitmqCluster, podName string) (string, string, error) {
+	stdout, stderr, err := r.exec(rmq.Namespace, podName, "rabbitmq", "sh", "-c", versionsCommand)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: stdout: %s, stderr: %s", err, stdout, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) < 2 {
+		return "", "", nil
+	}
+	return strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1]), nil
+}
@@ -0,0 +1,135 @@
+package controllers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/rabbitmqclient"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Reconcile versions", func() {
+	var cluster *rabbitmqv1beta1.RabbitmqCluster
+
+	BeforeEach(func() {
+		fakeExecutor.SetStdout(`rabbitmqctl eval 'io:format("~s~n~s~n", [rabbit_misc:version(), rabbit_misc:otp_version()]).'`, "4.0.5\n27.2\nok\n")
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-versions",
+				Namespace: "default",
+			},
+		}
+		Expect(client.Create(ctx, cluster)).To(Succeed())
+		waitForClusterCreation(ctx, cluster, client)
+	})
+
+	AfterEach(func() {
+		Expect(client.Delete(ctx, cluster)).To(Succeed())
+		waitForClusterDeletion(ctx, cluster, client)
+	})
+
+	It("records the versions of the running nodes once all replicas are ready", func() {
+		Expect(cluster.Status.RabbitmqVersion).To(BeEmpty())
+
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		sts.Status.CurrentRevision = "first-revision"
+		sts.Status.UpdateRevision = "first-revision"
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+
+		Eventually(func() rabbitmqv1beta1.RabbitmqClusterStatus {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Status
+		}, 5).Should(SatisfyAll(
+			HaveField("RabbitmqVersion", "4.0.5"),
+			HaveField("ErlangVersion", "27.2"),
+			HaveField("VersionsRevision", "first-revision"),
+		))
+	})
+
+	It("reports the progress of a rollout which changes the RabbitMQ version", func() {
+		sts := statefulSet(ctx, cluster)
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		sts.Status.UpdatedReplicas = 1
+		sts.Status.CurrentRevision = "first-revision"
+		sts.Status.UpdateRevision = "first-revision"
+		Expect(client.Status().Update(ctx, sts)).To(Succeed())
+		Eventually(func() string {
+			Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			return cluster.Status.VersionsRevision
+		}, 5).Should(Equal("first-revision"))
+
+		By("reading the new version from the first updated Pod", func() {
+			fakeExecutor.SetStdout(`rabbitmqctl eval 'io:format("~s~n~s~n", [rabbit_misc:version(), rabbit_misc:otp_version()]).'`, "4.1.0\n27.3\nok\n")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rabbitmq-versions-server-0",
+					Namespace: "default",
+					Labels:    map[string]string{appsv1.ControllerRevisionHashLabelKey: "second-revision"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "rabbitmq", Image: "rabbitmq"}},
+				},
+			}
+			Expect(client.Create(ctx, pod)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.Delete(ctx, pod)).To(Succeed())
+			})
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(client.Status().Update(ctx, pod)).To(Succeed())
+
+			sts = statefulSet(ctx, cluster)
+			sts.Status.UpdatedReplicas = 0
+			sts.Status.UpdateRevision = "second-revision"
+			Expect(client.Status().Update(ctx, sts)).To(Succeed())
+
+			Eventually(func() *rabbitmqv1beta1.RabbitmqClusterUpgradeStatus {
+				Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+				return cluster.Status.Upgrade
+			}, 5).Should(Equal(&rabbitmqv1beta1.RabbitmqClusterUpgradeStatus{
+				FromVersion:     "4.0.5",
+				ToVersion:       "4.1.0",
+				UpdatedReplicas: 0,
+				State:           rabbitmqv1beta1.UpgradeInProgress,
+				Revision:        "second-revision",
+			}))
+		})
+
+		By("completing the upgrade once all replicas run the new revision", func() {
+			fakeRabbitmq.SetFeatureFlags([]rabbitmqclient.FeatureFlag{
+				{Name: "message_containers_deaths_v2", State: "disabled", Stability: "stable"},
+				{Name: "khepri_db", State: "disabled", Stability: "experimental"},
+				{Name: "quorum_queue", State: "enabled", Stability: "required"},
+			})
+			sts = statefulSet(ctx, cluster)
+			sts.Status.UpdatedReplicas = 1
+			sts.Status.CurrentRevision = "second-revision"
+			Expect(client.Status().Update(ctx, sts)).To(Succeed())
+
+			Eventually(func() rabbitmqv1beta1.RabbitmqClusterStatus {
+				Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+				return cluster.Status
+			}, 5).Should(SatisfyAll(
+				HaveField("RabbitmqVersion", "4.1.0"),
+				HaveField("Upgrade.State", rabbitmqv1beta1.UpgradeCompleted),
+				HaveField("Upgrade.UpdatedReplicas", int32(1)),
+			))
+		})
+
+		By("enabling all stable feature flags once the upgrade completed", func() {
+			Eventually(func() bool {
+				Expect(client.Get(ctx, runtimeClient.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+				return cluster.Status.Upgrade.FeatureFlagsEnabled
+			}, 5).Should(BeTrue())
+			Expect(fakeRabbitmq.Calls()).To(ContainElement("EnableFeatureFlag message_containers_deaths_v2"))
+			Expect(fakeRabbitmq.Calls()).NotTo(ContainElement("EnableFeatureFlag khepri_db"))
+			Expect(aggregateEventMsgs(ctx, cluster, "FeatureFlagsEnabled")).To(
+				ContainSubstring("enabled all feature flags after upgrade from RabbitMQ 4.0.5 to 4.1.0"))
+		})
+	})
+})
@@ -11,11 +11,18 @@ This product may include a number of subcomponents with separate copyright notic
 package controllers_test
 
 import (
+	"bytes"
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/yaml"
 
 	"k8s.io/client-go/util/retry"
 
@@ -23,11 +30,17 @@ import (
 	. "github.com/onsi/gomega"
 	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	"github.com/rabbitmq/cluster-operator/v2/controllers"
+	"github.com/rabbitmq/cluster-operator/v2/internal/rabbitmqclient"
 
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -47,6 +60,8 @@ var (
 	client          runtimeClient.Client
 	clientSet       *kubernetes.Clientset
 	fakeExecutor    *fakePodExecutor
+	fakeRabbitmq    *rabbitmqclient.Fake
+	prometheus      *httptest.Server
 	ctx             context.Context
 	cancel          context.CancelFunc
 	updateWithRetry = func(cr *rabbitmqv1beta1.RabbitmqCluster, mutateFn func(r *rabbitmqv1beta1.RabbitmqCluster)) error {
@@ -84,28 +99,53 @@ var _ = BeforeSuite(func() {
 
 	clientSet, err = kubernetes.NewForConfig(cfg)
 	Expect(err).NotTo(HaveOccurred())
+	// the tests read objects which are not cached by the manager
+	client, err = runtimeClient.New(cfg, runtimeClient.Options{Scheme: scheme.Scheme})
+	Expect(err).ToNot(HaveOccurred())
+
+	// the operator runs with the permissions of its ClusterRole, so that permissions missing from the RBAC markers
+	// fail the tests
+	operatorCfg := operatorConfig(ctx)
+	operatorClientSet, err := kubernetes.NewForConfig(operatorCfg)
+	Expect(err).NotTo(HaveOccurred())
 
-	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+	// like the operator, only cache Secrets and ConfigMaps which are labelled as part of rabbitmq
+	rmqSelector := labels.SelectorFromSet(labels.Set{"app.kubernetes.io/part-of": "rabbitmq"})
+	mgr, err := ctrl.NewManager(operatorCfg, ctrl.Options{
 		Scheme: scheme.Scheme,
 		Metrics: server.Options{
 			BindAddress: "0",
 		},
+		Cache: cache.Options{
+			ByObject: map[runtimeClient.Object]cache.ByObject{
+				&corev1.Secret{}:    {Label: rmqSelector},
+				&corev1.ConfigMap{}: {Label: rmqSelector},
+			},
+		},
 	})
 	Expect(err).ToNot(HaveOccurred())
 
 	fakeExecutor = &fakePodExecutor{}
+	fakeRabbitmq = rabbitmqclient.NewFake()
+	prometheus = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1700000000,"3"]}}`))
+	}))
 	err = (&controllers.RabbitmqClusterReconciler{
-		Client:                  mgr.GetClient(),
-		APIReader:               mgr.GetAPIReader(),
-		Scheme:                  mgr.GetScheme(),
-		Recorder:                mgr.GetEventRecorderFor(controllerName),
-		Namespace:               "rabbitmq-system",
-		Clientset:               clientSet,
-		PodExecutor:             fakeExecutor,
-		DefaultRabbitmqImage:    defaultRabbitmqImage,
-		ControlRabbitmqImage:    false,
-		DefaultUserUpdaterImage: defaultUserUpdaterImage,
-		DefaultImagePullSecrets: defaultImagePullSecrets,
+		Client:                        mgr.GetClient(),
+		APIReader:                     mgr.GetAPIReader(),
+		Scheme:                        mgr.GetScheme(),
+		Recorder:                      mgr.GetEventRecorderFor(controllerName),
+		Namespace:                     "rabbitmq-system",
+		Clientset:                     operatorClientSet,
+		PodExecutor:                   fakeExecutor,
+		DefaultRabbitmqImage:          defaultRabbitmqImage,
+		ControlRabbitmqImage:          false,
+		DefaultUserUpdaterImage:       defaultUserUpdaterImage,
+		DefaultImagePullSecrets:       defaultImagePullSecrets,
+		HealthPollInterval:            time.Second,
+		RabbitmqClientFactory:         fakeRabbitmq.Factory(),
+		RolloutAnalysisPrometheusURLs: []string{prometheus.URL},
+		RemoteClustersEnabled:         true,
 	}).SetupWithManager(mgr)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -113,30 +153,75 @@ var _ = BeforeSuite(func() {
 		err = mgr.Start(ctx)
 		Expect(err).ToNot(HaveOccurred())
 	}()
-
-	client = mgr.GetClient()
-	Expect(client).ToNot(BeNil())
 })
 
+// operatorConfig returns the config of a user bound to the ClusterRole generated from the RBAC markers.
+func operatorConfig(ctx context.Context) *rest.Config {
+	roleYAML, err := os.ReadFile(filepath.Join("..", "config", "rbac", "role.yaml"))
+	Expect(err).NotTo(HaveOccurred())
+	// the document follows the license header
+	_, roleYAML, _ = bytes.Cut(roleYAML, []byte("\n---\n"))
+	role := &rbacv1.ClusterRole{}
+	Expect(yaml.Unmarshal(roleYAML, role)).To(Succeed())
+	Expect(client.Create(ctx, role)).To(Succeed())
+
+	user, err := testEnv.ControlPlane.AddUser(envtest.User{Name: "rabbitmq-cluster-operator"}, nil)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(client.Create(ctx, &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "rabbitmq-cluster-operator"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role.Name},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "rabbitmq-cluster-operator"}},
+	})).To(Succeed())
+	return user.Config()
+}
+
 var _ = AfterSuite(func() {
 	cancel()
+	prometheus.Close()
 	By("tearing down the test environment")
 	Expect(testEnv.Stop()).To(Succeed())
 })
 
 type fakePodExecutor struct {
+	// commands are executed by the reconciler and the health poller concurrently
+	mutex            sync.Mutex
 	executedCommands []command
+	stdout           map[string]string
 }
 
 type command []string
 
 func (f *fakePodExecutor) Exec(clientset *kubernetes.Clientset, clusterConfig *rest.Config, namespace, podName, containerName string, command ...string) (string, string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
 	f.executedCommands = append(f.executedCommands, command)
-	return "", "", nil
+	return f.stdout[command[len(command)-1]], "", nil
+}
+
+// SetStdout sets the output of commands whose last argument is cmd.
+func (f *fakePodExecutor) SetStdout(cmd, stdout string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.stdout == nil {
+		f.stdout = map[string]string{}
+	}
+	f.stdout[cmd] = stdout
 }
 
-func (f *fakePodExecutor) ExecutedCommands() []command { return f.executedCommands }
+func (f *fakePodExecutor) ExecutedCommands() []command {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]command{}, f.executedCommands...)
+}
 
-func (f *fakePodExecutor) ResetExecutedCommands() { f.executedCommands = []command{} }
+func (f *fakePodExecutor) ResetExecutedCommands() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.executedCommands = []command{}
+	f.stdout = nil
+}
 
-var _ = AfterEach(func() { fakeExecutor.ResetExecutedCommands() })
+var _ = AfterEach(func() {
+	fakeExecutor.ResetExecutedCommands()
+	fakeRabbitmq.Reset()
+})
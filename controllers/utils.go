@@ -33,12 +33,13 @@ func (r *RabbitmqClusterReconciler) deleteAnnotation(ctx context.Context, obj cl
 	return r.Update(ctx, obj)
 }
 
-func (r *RabbitmqClusterReconciler) updateAnnotation(ctx context.Context, obj client.Object, namespace, objName, key, value string) error {
+// updateAnnotation sets an annotation of the object with the given name, reading and updating it with c.
+func updateAnnotation(ctx context.Context, c client.Client, obj client.Object, namespace, objName, key, value string) error {
 	return retry.OnError(
 		retry.DefaultRetry,
 		errorIsConflictOrNotFound, // StatefulSet needs time to be found after it got created
 		func() error {
-			if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: objName}, obj); err != nil {
+			if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: objName}, obj); err != nil {
 				return err
 			}
 			accessor, err := meta.Accessor(obj)
@@ -51,7 +52,7 @@ func (r *RabbitmqClusterReconciler) updateAnnotation(ctx context.Context, obj cl
 			}
 			annotations[key] = value
 			accessor.SetAnnotations(annotations)
-			return r.Update(ctx, obj)
+			return c.Update(ctx, obj)
 		})
 }
 
@@ -60,8 +61,13 @@ func errorIsConflictOrNotFound(err error) bool {
 }
 
 func (r *RabbitmqClusterReconciler) statefulSet(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster) (*appsv1.StatefulSet, error) {
+	return getStatefulSet(ctx, r.Client, rmq)
+}
+
+// getStatefulSet reads the StatefulSet of a RabbitmqCluster with reader, which may read from a remote cluster.
+func getStatefulSet(ctx context.Context, reader client.Reader, rmq *rabbitmqv1beta1.RabbitmqCluster) (*appsv1.StatefulSet, error) {
 	sts := &appsv1.StatefulSet{}
-	if err := r.Get(ctx, types.NamespacedName{Name: rmq.ChildResourceName("server"), Namespace: rmq.Namespace}, sts); err != nil {
+	if err := reader.Get(ctx, types.NamespacedName{Name: rmq.ChildResourceName("server"), Namespace: rmq.Namespace}, sts); err != nil {
 		return nil, err
 	}
 	return sts, nil
@@ -85,8 +91,13 @@ func (r *RabbitmqClusterReconciler) statefulSetUID(ctx context.Context, rmq *rab
 }
 
 func (r *RabbitmqClusterReconciler) configMap(ctx context.Context, rmq *rabbitmqv1beta1.RabbitmqCluster, name string) (*corev1.ConfigMap, error) {
+	return getConfigMap(ctx, r.Client, rmq, name)
+}
+
+// getConfigMap reads a ConfigMap in the namespace of a RabbitmqCluster with reader, which may read from a remote cluster.
+func getConfigMap(ctx context.Context, reader client.Reader, rmq *rabbitmqv1beta1.RabbitmqCluster, name string) (*corev1.ConfigMap, error) {
 	configMap := &corev1.ConfigMap{}
-	if err := r.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: name}, configMap); err != nil {
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: rmq.Namespace, Name: name}, configMap); err != nil {
 		return nil, err
 	}
 	return configMap, nil
@@ -1,5 +1,5 @@
 #!/bin/bash
 
 tmp=$(mktemp)
-yj -yj < config/crd/bases/rabbitmq.com_rabbitmqclusters.yaml | jq 'delpaths([.. | paths(scalars)|select(contains(["spec","versions",0,"schema","openAPIV3Schema","properties","spec","properties","override","description"]))])' | yj -jy > "$tmp"
+yj -yj < config/crd/bases/rabbitmq.com_rabbitmqclusters.yaml | jq '.spec.versions |= map(delpaths([paths(scalars)|select(contains(["schema","openAPIV3Schema","properties","spec","properties","override","description"]))]))' | yj -jy > "$tmp"
 mv "$tmp" config/crd/bases/rabbitmq.com_rabbitmqclusters.yaml
\ No newline at end of file
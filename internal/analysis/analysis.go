@@ -0,0 +1,152 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+)
+
+// ErrAnalysisFailed is returned when a query result does not satisfy its thresholds.
+var ErrAnalysisFailed = errors.New("rollout analysis failed")
+
+// ErrPrometheusURLNotAllowed is returned when the Prometheus URL of an analysis is not allowed by the operator.
+var ErrPrometheusURLNotAllowed = errors.New("prometheus URL not allowed")
+
+var httpClient = &http.Client{
+	Timeout: 30 * time.Second,
+	// redirects could lead to URLs which are not allowed
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type vectorSample struct {
+	Value []interface{} `json:"value"`
+}
+
+// Run evaluates all queries of the analysis against Prometheus.
+// The Prometheus URL is set by the users of RabbitmqClusters, so it must be one of allowedURLs, which are configured
+// by the operator administrator. Otherwise, users could make the operator send requests to any endpoint it can reach.
+// It returns an error wrapping ErrPrometheusURLNotAllowed if the URL is not allowed, an error wrapping ErrAnalysisFailed
+// if a query result violates its thresholds, and any other error if a query cannot be evaluated.
+func Run(ctx context.Context, spec *rabbitmqv1beta1.RolloutAnalysisSpec, allowedURLs []string) error {
+	if !urlAllowed(spec.PrometheusURL, allowedURLs) {
+		return fmt.Errorf("%w: %s is not one of the Prometheus URLs allowed by the operator", ErrPrometheusURLNotAllowed, spec.PrometheusURL)
+	}
+	for _, query := range spec.Queries {
+		values, err := instantQuery(ctx, spec.PrometheusURL, query.Query)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate query %s: %w", query.Name, err)
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("%w: query %s returned no data", ErrAnalysisFailed, query.Name)
+		}
+		for _, value := range values {
+			if query.Min != nil && value < query.Min.AsApproximateFloat64() {
+				return fmt.Errorf("%w: query %s returned %v, below the minimum of %s", ErrAnalysisFailed, query.Name, value, query.Min.String())
+			}
+			if query.Max != nil && value > query.Max.AsApproximateFloat64() {
+				return fmt.Errorf("%w: query %s returned %v, above the maximum of %s", ErrAnalysisFailed, query.Name, value, query.Max.String())
+			}
+		}
+	}
+	return nil
+}
+
+// urlAllowed returns true if the URL equals one of the allowed URLs, ignoring trailing slashes.
+func urlAllowed(prometheusURL string, allowedURLs []string) bool {
+	for _, allowed := range allowedURLs {
+		if strings.TrimSuffix(prometheusURL, "/") == strings.TrimSuffix(allowed, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+func instantQuery(ctx context.Context, prometheusURL, query string) ([]float64, error) {
+	endpoint := strings.TrimSuffix(prometheusURL, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := queryResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode Prometheus response with status %s: %w", resp.Status, err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("prometheus returned status %s: %s", response.Status, response.Error)
+	}
+
+	switch response.Data.ResultType {
+	case "scalar":
+		var sample []interface{}
+		if err := json.Unmarshal(response.Data.Result, &sample); err != nil {
+			return nil, err
+		}
+		value, err := sampleValue(sample)
+		if err != nil {
+			return nil, err
+		}
+		return []float64{value}, nil
+	case "vector":
+		var samples []vectorSample
+		if err := json.Unmarshal(response.Data.Result, &samples); err != nil {
+			return nil, err
+		}
+		values := make([]float64, 0, len(samples))
+		for _, sample := range samples {
+			value, err := sampleValue(sample.Value)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unsupported result type %s; query must return a scalar or an instant vector", response.Data.ResultType)
+	}
+}
+
+// sampleValue parses a Prometheus sample of the form [<unix time>, "<value>"]
+func sampleValue(sample []interface{}) (float64, error) {
+	if len(sample) != 2 {
+		return 0, fmt.Errorf("unexpected sample %v", sample)
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected sample value %v", sample[1])
+	}
+	return strconv.ParseFloat(value, 64)
+}
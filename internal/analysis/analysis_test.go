@@ -0,0 +1,105 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package analysis_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/analysis"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Run", func() {
+	var (
+		server      *httptest.Server
+		responses   map[string]string
+		spec        *rabbitmqv1beta1.RolloutAnalysisSpec
+		allowedURLs []string
+	)
+
+	BeforeEach(func() {
+		responses = map[string]string{
+			"publish_rate": `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"pod":"a"},"value":[1700000000,"120.5"]},{"metric":{"pod":"b"},"value":[1700000000,"80"]}]}}`,
+			"error_count":  `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"0"]}}`,
+			"no_data":      `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			"bad_query":    `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+		}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/api/v1/query"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(responses[r.URL.Query().Get("query")]))
+		}))
+		allowedURLs = []string{"http://prometheus.monitoring.svc:9090", server.URL}
+		spec = &rabbitmqv1beta1.RolloutAnalysisSpec{
+			PrometheusURL: server.URL + "/",
+			Queries: []rabbitmqv1beta1.RolloutAnalysisQuery{
+				{Name: "publish rate", Query: "publish_rate", Min: ptr.To(k8sresource.MustParse("50"))},
+				{Name: "errors", Query: "error_count", Max: ptr.To(k8sresource.MustParse("0"))},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("succeeds when all samples satisfy the thresholds", func() {
+		Expect(analysis.Run(context.Background(), spec, allowedURLs)).To(Succeed())
+	})
+
+	It("fails when a sample is below the minimum", func() {
+		spec.Queries[0].Min = ptr.To(k8sresource.MustParse("100"))
+		err := analysis.Run(context.Background(), spec, allowedURLs)
+		Expect(errors.Is(err, analysis.ErrAnalysisFailed)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("query publish rate returned 80, below the minimum of 100")))
+	})
+
+	It("fails when a sample is above the maximum", func() {
+		spec.Queries[0].Max = ptr.To(k8sresource.MustParse("100m"))
+		spec.Queries[0].Min = nil
+		err := analysis.Run(context.Background(), spec, allowedURLs)
+		Expect(errors.Is(err, analysis.ErrAnalysisFailed)).To(BeTrue())
+	})
+
+	It("fails when a query returns no data", func() {
+		spec.Queries[0].Query = "no_data"
+		err := analysis.Run(context.Background(), spec, allowedURLs)
+		Expect(errors.Is(err, analysis.ErrAnalysisFailed)).To(BeTrue())
+	})
+
+	It("does not send queries to Prometheus URLs which are not allowed", func() {
+		allowedURLs = []string{"http://prometheus.monitoring.svc:9090"}
+		err := analysis.Run(context.Background(), spec, allowedURLs)
+		Expect(errors.Is(err, analysis.ErrPrometheusURLNotAllowed)).To(BeTrue())
+		Expect(errors.Is(analysis.Run(context.Background(), spec, nil), analysis.ErrPrometheusURLNotAllowed)).To(BeTrue())
+	})
+
+	It("does not follow redirects", func() {
+		redirect := httptest.NewServer(http.RedirectHandler(server.URL+"/api/v1/query", http.StatusFound))
+		defer redirect.Close()
+		spec.PrometheusURL = redirect.URL
+		err := analysis.Run(context.Background(), spec, []string{redirect.URL})
+		Expect(err).To(MatchError(ContainSubstring("302 Found")))
+	})
+
+	It("returns an error when a query cannot be evaluated", func() {
+		spec.Queries[0].Query = "bad_query"
+		err := analysis.Run(context.Background(), spec, allowedURLs)
+		Expect(err).To(MatchError(ContainSubstring("parse error")))
+		Expect(errors.Is(err, analysis.ErrAnalysisFailed)).To(BeFalse())
+	})
+})
@@ -0,0 +1,324 @@
+// Package configsyntax checks the syntax of RabbitMQ configuration files, so that syntax errors are reported
+// when a RabbitmqCluster is submitted instead of by crashing RabbitMQ nodes.
+package configsyntax
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrSyntax is wrapped by the errors of syntax errors.
+var ErrSyntax = errors.New("syntax error")
+
+var confKey = regexp.MustCompile(`^[A-Za-z0-9_.$-]+$`)
+
+// CheckRabbitmqConf checks a rabbitmq.conf snippet in the sysctl format: one "key = value" setting per line,
+// and comment lines starting with #.
+func CheckRabbitmqConf(conf string) error {
+	for i, line := range strings.Split(conf, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("%w on line %d: expected key = value, got %q", ErrSyntax, i+1, line)
+		}
+		if key = strings.TrimSpace(key); !confKey.MatchString(key) {
+			return fmt.Errorf("%w on line %d: invalid key %q", ErrSyntax, i+1, key)
+		}
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%w on line %d: no value for key %s", ErrSyntax, i+1, key)
+		}
+	}
+	return nil
+}
+
+// CheckAdvancedConfig checks an advanced.config file, which is a single Erlang term followed by a full stop.
+// An empty file is valid.
+func CheckAdvancedConfig(config string) error {
+	p := &erlangParser{input: config}
+	p.skipSpace()
+	if p.done() {
+		return nil
+	}
+	if err := p.term(); err != nil {
+		return err
+	}
+	p.skipSpace()
+	if !p.consume(".") {
+		return p.errorf("expected . after the term")
+	}
+	p.skipSpace()
+	if !p.done() {
+		return p.errorf("expected a single term")
+	}
+	return nil
+}
+
+// erlangParser parses the subset of Erlang terms which can be read by file:consult/1, i.e. literals without variables.
+type erlangParser struct {
+	input string
+	pos   int
+}
+
+func (p *erlangParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *erlangParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *erlangParser) consume(token string) bool {
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *erlangParser) errorf(format string, args ...interface{}) error {
+	line := strings.Count(p.input[:p.pos], "\n") + 1
+	return fmt.Errorf("%w on line %d: %s", ErrSyntax, line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips white space and comments.
+func (p *erlangParser) skipSpace() {
+	for !p.done() {
+		switch c := p.peek(); {
+		case c == '%':
+			for !p.done() && p.peek() != '\n' {
+				p.pos++
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *erlangParser) term() error {
+	p.skipSpace()
+	c := p.peek()
+	switch {
+	case p.done():
+		return p.errorf("unexpected end of input")
+	case c == '{':
+		p.pos++
+		return p.sequence("}", p.term)
+	case c == '[':
+		p.pos++
+		return p.list()
+	case c == '#':
+		p.pos++
+		if !p.consume("{") {
+			return p.errorf("expected { after #")
+		}
+		return p.sequence("}", p.association)
+	case p.consume("<<"):
+		return p.sequence(">>", p.segment)
+	case c == '"':
+		for p.peek() == '"' {
+			if err := p.quoted('"'); err != nil {
+				return err
+			}
+			p.skipSpace()
+		}
+		return nil
+	case c == '\'':
+		return p.quoted('\'')
+	case c == '$':
+		p.pos++
+		if p.consume("\\") {
+			return p.escape()
+		}
+		if p.done() {
+			return p.errorf("unexpected end of input")
+		}
+		p.pos++
+		return nil
+	case c == '-' || c == '+' || isDigit(c):
+		return p.number()
+	case c >= 'a' && c <= 'z':
+		p.name()
+		return nil
+	case c >= 'A' && c <= 'Z' || c == '_':
+		return p.errorf("unexpected variable %s, atoms starting with an upper case letter must be quoted", p.name())
+	default:
+		return p.errorf("unexpected %q", c)
+	}
+}
+
+// sequence parses comma separated elements until the closing token.
+func (p *erlangParser) sequence(closing string, element func() error) error {
+	p.skipSpace()
+	if p.consume(closing) {
+		return nil
+	}
+	for {
+		if err := element(); err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.consume(closing) {
+			return nil
+		}
+		if !p.consume(",") {
+			return p.errorf("expected , or %s", closing)
+		}
+	}
+}
+
+func (p *erlangParser) list() error {
+	p.skipSpace()
+	if p.consume("]") {
+		return nil
+	}
+	for {
+		if err := p.term(); err != nil {
+			return err
+		}
+		p.skipSpace()
+		switch {
+		case p.consume("]"):
+			return nil
+		case p.consume("|"):
+			if err := p.term(); err != nil {
+				return err
+			}
+			p.skipSpace()
+			if !p.consume("]") {
+				return p.errorf("expected ] after the tail of the list")
+			}
+			return nil
+		case !p.consume(","):
+			return p.errorf("expected , or ]")
+		}
+	}
+}
+
+func (p *erlangParser) association() error {
+	if err := p.term(); err != nil {
+		return err
+	}
+	p.skipSpace()
+	if !p.consume("=>") && !p.consume(":=") {
+		return p.errorf("expected => in map")
+	}
+	return p.term()
+}
+
+// segment parses a segment of a binary, e.g. 1, "abc", 16#FF:16 or 1/integer-unit:8.
+func (p *erlangParser) segment() error {
+	if err := p.term(); err != nil {
+		return err
+	}
+	p.skipSpace()
+	if p.consume(":") {
+		p.skipSpace()
+		if err := p.number(); err != nil {
+			return err
+		}
+		p.skipSpace()
+	}
+	if p.consume("/") {
+		for {
+			p.skipSpace()
+			if p.name() == "" {
+				return p.errorf("expected a type specifier")
+			}
+			// the unit specifier has a value, e.g. unit:8
+			if p.consume(":") {
+				if err := p.number(); err != nil {
+					return err
+				}
+			}
+			if !p.consume("-") {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (p *erlangParser) quoted(quote byte) error {
+	p.pos++
+	for !p.done() {
+		c := p.peek()
+		p.pos++
+		switch c {
+		case quote:
+			return nil
+		case '\\':
+			if err := p.escape(); err != nil {
+				return err
+			}
+		}
+	}
+	return p.errorf("missing closing %c", quote)
+}
+
+func (p *erlangParser) escape() error {
+	if p.done() {
+		return p.errorf("unexpected end of input")
+	}
+	p.pos++
+	return nil
+}
+
+func (p *erlangParser) number() error {
+	start := p.pos
+	if p.peek() == '-' || p.peek() == '+' {
+		p.pos++
+	}
+	if !isDigit(p.peek()) {
+		return p.errorf("expected a number")
+	}
+	p.digits()
+	if p.consume("#") {
+		// the digits of a number with a base, e.g. 16#FF
+		if p.name() == "" {
+			return p.errorf("expected digits after #")
+		}
+	} else if p.pos+1 < len(p.input) && p.peek() == '.' && isDigit(p.input[p.pos+1]) {
+		p.pos++
+		p.digits()
+		if p.peek() == 'e' || p.peek() == 'E' {
+			p.pos++
+			if p.peek() == '-' || p.peek() == '+' {
+				p.pos++
+			}
+			if !isDigit(p.peek()) {
+				return p.errorf("invalid float %s", p.input[start:p.pos])
+			}
+			p.digits()
+		}
+	}
+	return nil
+}
+
+func (p *erlangParser) digits() {
+	for isDigit(p.peek()) || p.peek() == '_' {
+		p.pos++
+	}
+}
+
+// name consumes and returns an unquoted atom or variable name.
+func (p *erlangParser) name() string {
+	start := p.pos
+	for c := p.peek(); isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '@'; c = p.peek() {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
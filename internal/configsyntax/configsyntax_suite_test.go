@@ -0,0 +1,13 @@
+package configsyntax_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigsyntax(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Configsyntax Suite")
+}
@@ -0,0 +1,70 @@
+package configsyntax_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/configsyntax"
+)
+
+var _ = Describe("CheckRabbitmqConf", func() {
+	It("accepts settings and comments", func() {
+		Expect(configsyntax.CheckRabbitmqConf(`
+# logging
+log.console.level = debug
+auth_backends.1 = ldap
+default_permissions.configure = .*
+`)).To(Succeed())
+	})
+
+	DescribeTable("rejects invalid lines",
+		func(conf, msg string) {
+			Expect(configsyntax.CheckRabbitmqConf(conf)).To(And(
+				MatchError(configsyntax.ErrSyntax),
+				MatchError(ContainSubstring(msg)),
+			))
+		},
+		Entry("missing =", "log.console.level = debug\nlog.console true", "line 2: expected key = value"),
+		Entry("invalid key", "log console = true", `invalid key "log console"`),
+		Entry("missing value", "log.console =", "no value for key log.console"),
+		Entry("ini section", "[default]", "expected key = value"),
+	)
+})
+
+var _ = Describe("CheckAdvancedConfig", func() {
+	It("accepts an empty file", func() {
+		Expect(configsyntax.CheckAdvancedConfig(" \n% nothing to configure\n")).To(Succeed())
+	})
+
+	It("accepts a term with all kinds of literals", func() {
+		Expect(configsyntax.CheckAdvancedConfig(`
+[
+  {rabbit, [
+    {tcp_listen_options, [{backlog, 128}, {nodelay, true}]},
+    {vm_memory_high_watermark, 0.4},
+    {'Quoted Atom', "a string" " continued"},
+    {binary, <<"bytes", 16#FF:8, 1/integer-unit:8>>},
+    {map, #{key => value, $a => -1.5e-3}},
+    {improper, [a | b]}
+  ]},
+  {rabbitmq_auth_backend_ldap, [
+    {dn_lookup_base, "DC=gopivotal,DC=com"}
+  ]}
+].
+`)).To(Succeed())
+	})
+
+	DescribeTable("rejects invalid terms",
+		func(config, msg string) {
+			Expect(configsyntax.CheckAdvancedConfig(config)).To(And(
+				MatchError(configsyntax.ErrSyntax),
+				MatchError(ContainSubstring(msg)),
+			))
+		},
+		Entry("missing full stop", "[{rabbit, []}]", "expected . after the term"),
+		Entry("missing comma", "[{rabbit, []}\n {ra, []}].", "line 2: expected , or ]"),
+		Entry("unbalanced brackets", "[{rabbit, [}].", `unexpected '}'`),
+		Entry("variable", "[{rabbit, [{Key, value}]}].", "unexpected variable Key"),
+		Entry("unterminated string", `[{rabbit, "abc}].`, `missing closing "`),
+		Entry("several terms", "[]. [].", "expected a single term"),
+	)
+})
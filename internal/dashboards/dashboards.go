@@ -0,0 +1,141 @@
+// Package dashboards provisions the RabbitMQ Grafana dashboards as ConfigMaps, which the dashboard sidecar of Grafana
+// loads into Grafana.
+//
+// The ConfigMaps hold the JSON of the dashboards published with RabbitMQ, which is embedded in the operator, so that
+// Grafana does not need to download the dashboards. go generate downloads them from the pinned RabbitMQ commit.
+// Dashboards which were not downloaded are referenced by their URL instead, which the sidecar downloads.
+package dashboards
+
+//go:generate -command download curl --fail --silent --show-error --location --output
+//go:generate download json/RabbitMQ-Overview.json https://github.com/rabbitmq/rabbitmq-server/raw/e57c579d1a71b283469defdd0d6d45313e6d6daf/deps/rabbitmq_prometheus/docker/grafana/dashboards/RabbitMQ-Overview.json
+//go:generate download json/Erlang-Distribution.json https://github.com/rabbitmq/rabbitmq-server/raw/e57c579d1a71b283469defdd0d6d45313e6d6daf/deps/rabbitmq_prometheus/docker/grafana/dashboards/Erlang-Distribution.json
+//go:generate download json/RabbitMQ-Quorum-Queues-Raft.json https://github.com/rabbitmq/rabbitmq-server/raw/e57c579d1a71b283469defdd0d6d45313e6d6daf/deps/rabbitmq_prometheus/docker/grafana/dashboards/RabbitMQ-Quorum-Queues-Raft.json
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// DefaultLabel is the label by which the Grafana sidecar discovers dashboard ConfigMaps.
+var DefaultLabel = map[string]string{"grafana_dashboard": "true"}
+
+const baseURL = "https://github.com/rabbitmq/rabbitmq-server/raw/e57c579d1a71b283469defdd0d6d45313e6d6daf/deps/rabbitmq_prometheus/docker/grafana/dashboards/"
+
+//go:embed json
+var embedded embed.FS
+
+// Dashboard is a Grafana dashboard published with RabbitMQ.
+type Dashboard struct {
+	// Name of the ConfigMap.
+	Name string
+	// File of the dashboard JSON in the dashboards of RabbitMQ.
+	File string
+}
+
+// URL returns the URL of the dashboard JSON published with RabbitMQ.
+func (d Dashboard) URL() string {
+	return baseURL + d.File
+}
+
+// JSON returns the embedded dashboard JSON, or nil if it was not downloaded.
+func (d Dashboard) JSON() []byte {
+	data, err := fs.ReadFile(embedded, path.Join("json", d.File))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// Dashboards are the dashboards provisioned by the operator.
+var Dashboards = []Dashboard{
+	// https://grafana.com/grafana/dashboards/10991
+	{Name: "rabbitmq-overview-dashboard", File: "RabbitMQ-Overview.json"},
+	// https://grafana.com/grafana/dashboards/11352
+	{Name: "erlang-distribution-dashboard", File: "Erlang-Distribution.json"},
+	// https://grafana.com/grafana/dashboards/11340
+	{Name: "rabbitmq-quorum-queues-raft-dashboard", File: "RabbitMQ-Quorum-Queues-Raft.json"},
+}
+
+func updateConfigMap(configMap *corev1.ConfigMap, dashboard Dashboard, labels map[string]string) {
+	if configMap.Labels == nil {
+		configMap.Labels = map[string]string{}
+	}
+	for label, value := range labels {
+		configMap.Labels[label] = value
+	}
+	if data := dashboard.JSON(); data != nil {
+		configMap.Data = map[string]string{dashboard.Name + ".json": string(data)}
+		return
+	}
+	configMap.Data = map[string]string{dashboard.Name + ".json.url": dashboard.URL()}
+}
+
+// Provisioner creates or updates the dashboard ConfigMaps once the operator becomes the leader. Its client must not
+// read ConfigMaps from a cache filtered by labels.
+type Provisioner struct {
+	Client client.Client
+	// Namespace of the ConfigMaps.
+	Namespace string
+	// Labels of the ConfigMaps. Defaults to DefaultLabel.
+	Labels map[string]string
+	// RetryInterval is the interval at which failed attempts are retried. Defaults to one minute.
+	RetryInterval time.Duration
+}
+
+// Start implements manager.Runnable. It provisions the dashboards, retrying until it succeeds or the context is done.
+func (p *Provisioner) Start(ctx context.Context) error {
+	logger := ctrl.LoggerFrom(ctx).WithName("dashboards")
+	interval := p.RetryInterval
+	if interval == 0 {
+		interval = time.Minute
+	}
+	// the condition never fails, so polling only stops early when the context is done
+	_ = wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		if err := p.Sync(ctx); err != nil {
+			logger.Error(err, "failed to provision Grafana dashboards; retrying", "namespace", p.Namespace)
+			return false, nil
+		}
+		logger.Info("provisioned Grafana dashboards", "namespace", p.Namespace)
+		return true, nil
+	})
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that only one replica writes the ConfigMaps.
+func (p *Provisioner) NeedLeaderElection() bool {
+	return true
+}
+
+// Sync creates or updates the ConfigMaps of the Dashboards. Labels which are set by others are kept.
+func (p *Provisioner) Sync(ctx context.Context) error {
+	labels := p.Labels
+	if len(labels) == 0 {
+		labels = DefaultLabel
+	}
+	for _, dashboard := range Dashboards {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      dashboard.Name,
+				Namespace: p.Namespace,
+			},
+		}
+		if _, err := controllerutil.CreateOrUpdate(ctx, p.Client, configMap, func() error {
+			updateConfigMap(configMap, dashboard, labels)
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to provision ConfigMap %s: %w", dashboard.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package dashboards_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/dashboards"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Provisioner", func() {
+	var (
+		ctx         = context.Background()
+		fakeClient  client.Client
+		provisioner *dashboards.Provisioner
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+		provisioner = &dashboards.Provisioner{
+			Client:    fakeClient,
+			Namespace: "monitoring",
+		}
+	})
+
+	configMap := func(name string) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "monitoring", Name: name}, configMap)).To(Succeed())
+		return configMap
+	}
+
+	It("creates a ConfigMap with the JSON of each dashboard", func() {
+		Expect(provisioner.Sync(ctx)).To(Succeed())
+		Expect(dashboards.Dashboards).NotTo(BeEmpty())
+		for _, dashboard := range dashboards.Dashboards {
+			if data := dashboard.JSON(); data != nil {
+				Expect(json.Valid(data)).To(BeTrue(), dashboard.File)
+				Expect(configMap(dashboard.Name).Data).To(Equal(map[string]string{
+					dashboard.Name + ".json": string(data),
+				}))
+				continue
+			}
+			By("referencing the dashboards which were not downloaded by their URL")
+			Expect(configMap(dashboard.Name).Data).To(Equal(map[string]string{
+				dashboard.Name + ".json.url": dashboard.URL(),
+			}))
+		}
+	})
+
+	It("provisions the overview, Erlang and Raft dashboards", func() {
+		var names []string
+		for _, dashboard := range dashboards.Dashboards {
+			names = append(names, dashboard.Name)
+		}
+		Expect(names).To(ConsistOf(
+			"rabbitmq-overview-dashboard",
+			"erlang-distribution-dashboard",
+			"rabbitmq-quorum-queues-raft-dashboard",
+		))
+	})
+
+	It("labels the ConfigMaps for the Grafana sidecar", func() {
+		Expect(provisioner.Sync(ctx)).To(Succeed())
+		Expect(configMap("rabbitmq-overview-dashboard").Labels).To(Equal(map[string]string{"grafana_dashboard": "true"}))
+	})
+
+	It("uses the configured labels", func() {
+		provisioner.Labels = map[string]string{"grafana_dashboard": "1"}
+		Expect(provisioner.Sync(ctx)).To(Succeed())
+		Expect(configMap("rabbitmq-overview-dashboard").Labels).To(Equal(map[string]string{"grafana_dashboard": "1"}))
+	})
+
+	It("updates existing ConfigMaps and keeps their other labels", func() {
+		Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rabbitmq-overview-dashboard",
+				Namespace: "monitoring",
+				Labels:    map[string]string{"team": "messaging"},
+			},
+			Data: map[string]string{"outdated.json": "{}"},
+		})).To(Succeed())
+
+		Expect(provisioner.Sync(ctx)).To(Succeed())
+		updated := configMap("rabbitmq-overview-dashboard")
+		Expect(updated.Labels).To(Equal(map[string]string{"team": "messaging", "grafana_dashboard": "true"}))
+		Expect(updated.Data).To(HaveLen(1))
+		Expect(updated.Data).NotTo(HaveKey("outdated.json"))
+	})
+
+	It("stops when the context is done", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(provisioner.Start(ctx)).To(Succeed())
+	})
+
+	It("only runs on the leader", func() {
+		Expect(provisioner.NeedLeaderElection()).To(BeTrue())
+	})
+})
@@ -0,0 +1,78 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+// Package health interprets the cluster status reported by the RabbitMQ nodes.
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ClusterStatusCommand prints the cluster status of a RabbitMQ node as JSON.
+const ClusterStatusCommand = "rabbitmqctl cluster_status --formatter json"
+
+// ClusterStatus is the output of ClusterStatusCommand.
+type ClusterStatus struct {
+	DiskNodes    []string `json:"disk_nodes"`
+	RunningNodes []string `json:"running_nodes"`
+	Alarms       []Alarm  `json:"alarms"`
+	// Partitions maps a node to the nodes it cannot reach.
+	Partitions map[string][]string `json:"partitions"`
+}
+
+// Alarm is a resource alarm raised by a node.
+type Alarm struct {
+	Node string `json:"node"`
+	// Type is resource_limit for memory and disk alarms, or file_descriptor_limit.
+	Type string `json:"type"`
+	// Resource is memory or disk for resource_limit alarms.
+	Resource string `json:"resource"`
+}
+
+func (alarm Alarm) String() string {
+	if alarm.Resource != "" {
+		return fmt.Sprintf("%s alarm on %s", alarm.Resource, alarm.Node)
+	}
+	return fmt.Sprintf("%s alarm on %s", alarm.Type, alarm.Node)
+}
+
+// ParseClusterStatus parses the output of ClusterStatusCommand.
+func ParseClusterStatus(output string) (*ClusterStatus, error) {
+	clusterStatus := &ClusterStatus{}
+	if err := json.Unmarshal([]byte(output), clusterStatus); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster status: %w", err)
+	}
+	return clusterStatus, nil
+}
+
+// AlarmsMessage describes the alarms, sorted by node, or returns an empty string if there are none.
+func (clusterStatus *ClusterStatus) AlarmsMessage() string {
+	var alarms []string
+	for _, alarm := range clusterStatus.Alarms {
+		alarms = append(alarms, alarm.String())
+	}
+	sort.Strings(alarms)
+	return strings.Join(alarms, "; ")
+}
+
+// PartitionsMessage describes the network partitions, sorted by node, or returns an empty string if there are none.
+func (clusterStatus *ClusterStatus) PartitionsMessage() string {
+	var partitions []string
+	for node, unreachable := range clusterStatus.Partitions {
+		if len(unreachable) == 0 {
+			continue
+		}
+		partitions = append(partitions, fmt.Sprintf("%s cannot reach %s", node, strings.Join(unreachable, ", ")))
+	}
+	sort.Strings(partitions)
+	return strings.Join(partitions, "; ")
+}
@@ -0,0 +1,46 @@
+package health_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/health"
+)
+
+var _ = Describe("ClusterStatus", func() {
+	It("parses a healthy cluster", func() {
+		clusterStatus, err := health.ParseClusterStatus(`{
+			"disk_nodes": ["rabbit@server-0", "rabbit@server-1"],
+			"running_nodes": ["rabbit@server-0", "rabbit@server-1"],
+			"alarms": [],
+			"partitions": {}
+		}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusterStatus.RunningNodes).To(ConsistOf("rabbit@server-0", "rabbit@server-1"))
+		Expect(clusterStatus.AlarmsMessage()).To(BeEmpty())
+		Expect(clusterStatus.PartitionsMessage()).To(BeEmpty())
+	})
+
+	It("describes alarms and partitions by node", func() {
+		clusterStatus, err := health.ParseClusterStatus(`{
+			"alarms": [
+				{"node": "rabbit@server-1", "type": "resource_limit", "resource": "disk"},
+				{"node": "rabbit@server-0", "type": "resource_limit", "resource": "memory"},
+				{"node": "rabbit@server-2", "type": "file_descriptor_limit"}
+			],
+			"partitions": {
+				"rabbit@server-1": ["rabbit@server-0"],
+				"rabbit@server-0": ["rabbit@server-1"]
+			}
+		}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusterStatus.AlarmsMessage()).To(Equal(
+			"disk alarm on rabbit@server-1; file_descriptor_limit alarm on rabbit@server-2; memory alarm on rabbit@server-0"))
+		Expect(clusterStatus.PartitionsMessage()).To(Equal(
+			"rabbit@server-0 cannot reach rabbit@server-1; rabbit@server-1 cannot reach rabbit@server-0"))
+	})
+
+	It("fails on output which is not JSON", func() {
+		_, err := health.ParseClusterStatus("Error: unable to perform an operation on node")
+		Expect(err).To(MatchError(ContainSubstring("failed to parse cluster status")))
+	})
+})
@@ -0,0 +1,95 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package health
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Nodes returns the status of the RabbitMQ node of every Pod, sorted by Pod name, followed by the cluster members
+// without a Pod. The membership of the nodes is Unknown if clusterStatus is nil.
+func Nodes(pods []corev1.Pod, clusterStatus *ClusterStatus) []rabbitmqv1beta1.RabbitmqClusterNodeStatus {
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	var members []string
+	if clusterStatus != nil {
+		members = append(members, clusterStatus.DiskNodes...)
+		for _, node := range clusterStatus.RunningNodes {
+			if !slices.Contains(members, node) {
+				members = append(members, node)
+			}
+		}
+		sort.Strings(members)
+	}
+
+	var nodes []rabbitmqv1beta1.RabbitmqClusterNodeStatus
+	matched := map[string]bool{}
+	for _, pod := range pods {
+		node := rabbitmqv1beta1.RabbitmqClusterNodeStatus{
+			Pod:        pod.Name,
+			Ready:      PodReady(pod),
+			Membership: rabbitmqv1beta1.NodeMembershipUnknown,
+		}
+		if clusterStatus != nil {
+			node.Membership = rabbitmqv1beta1.NodeNotJoined
+			for _, member := range members {
+				if runsOn(member, pod) {
+					node.Node = member
+					node.Membership = membership(member, clusterStatus)
+					matched[member] = true
+				}
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	for _, member := range members {
+		if !matched[member] {
+			nodes = append(nodes, rabbitmqv1beta1.RabbitmqClusterNodeStatus{
+				Node:       member,
+				Membership: membership(member, clusterStatus),
+			})
+		}
+	}
+	return nodes
+}
+
+// runsOn returns true if the RabbitMQ node runs on the Pod. Nodes are named after the hostname
+// or the IP address of their Pod, depending on spec.rabbitmq.peerDiscovery.
+func runsOn(node string, pod corev1.Pod) bool {
+	_, host, found := strings.Cut(node, "@")
+	if !found {
+		return false
+	}
+	return host == pod.Name || strings.HasPrefix(host, pod.Name+".") || (pod.Status.PodIP != "" && host == pod.Status.PodIP)
+}
+
+func membership(node string, clusterStatus *ClusterStatus) rabbitmqv1beta1.NodeMembership {
+	if slices.Contains(clusterStatus.RunningNodes, node) {
+		return rabbitmqv1beta1.NodeRunning
+	}
+	return rabbitmqv1beta1.NodeStopped
+}
+
+// PodReady returns true if the Pod has the Ready condition.
+func PodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package health_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/health"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Nodes", func() {
+	pod := func(name string, ready corev1.ConditionStatus) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	It("reports the readiness and membership of the node of every Pod", func() {
+		pods := []corev1.Pod{
+			pod("rabbit-server-2", corev1.ConditionFalse),
+			pod("rabbit-server-0", corev1.ConditionTrue),
+			pod("rabbit-server-1", corev1.ConditionTrue),
+		}
+		clusterStatus := &health.ClusterStatus{
+			DiskNodes: []string{
+				"rabbit@rabbit-server-0.rabbit-nodes.ns",
+				"rabbit@rabbit-server-1.rabbit-nodes.ns",
+				"rabbit@rabbit-server-3.rabbit-nodes.ns",
+			},
+			RunningNodes: []string{"rabbit@rabbit-server-0.rabbit-nodes.ns"},
+		}
+
+		Expect(health.Nodes(pods, clusterStatus)).To(Equal([]rabbitmqv1beta1.RabbitmqClusterNodeStatus{
+			{Pod: "rabbit-server-0", Node: "rabbit@rabbit-server-0.rabbit-nodes.ns", Ready: true, Membership: rabbitmqv1beta1.NodeRunning},
+			{Pod: "rabbit-server-1", Node: "rabbit@rabbit-server-1.rabbit-nodes.ns", Ready: true, Membership: rabbitmqv1beta1.NodeStopped},
+			{Pod: "rabbit-server-2", Ready: false, Membership: rabbitmqv1beta1.NodeNotJoined},
+			{Node: "rabbit@rabbit-server-3.rabbit-nodes.ns", Membership: rabbitmqv1beta1.NodeStopped},
+		}))
+	})
+
+	It("matches nodes named after the Pod IP", func() {
+		p := pod("rabbit-server-0", corev1.ConditionTrue)
+		p.Status.PodIP = "10.0.0.1"
+		clusterStatus := &health.ClusterStatus{RunningNodes: []string{"rabbit@10.0.0.1"}}
+
+		Expect(health.Nodes([]corev1.Pod{p}, clusterStatus)).To(Equal([]rabbitmqv1beta1.RabbitmqClusterNodeStatus{
+			{Pod: "rabbit-server-0", Node: "rabbit@10.0.0.1", Ready: true, Membership: rabbitmqv1beta1.NodeRunning},
+		}))
+	})
+
+	It("reports an unknown membership without cluster status", func() {
+		Expect(health.Nodes([]corev1.Pod{pod("rabbit-server-0", corev1.ConditionFalse)}, nil)).To(Equal([]rabbitmqv1beta1.RabbitmqClusterNodeStatus{
+			{Pod: "rabbit-server-0", Ready: false, Membership: rabbitmqv1beta1.NodeMembershipUnknown},
+		}))
+	})
+})
@@ -11,6 +11,31 @@ package metadata
 
 import "strings"
 
+// ManagedByExternalAnnotation marks a child Secret whose data is managed by another controller, such as
+// External Secrets or Sealed Secrets. The operator only maintains the labels, annotations and owner
+// reference of such a Secret.
+const ManagedByExternalAnnotation = "operator.rabbitmq.com/managed-by-external"
+
+// ManagedByExternal returns true if the annotations contain ManagedByExternalAnnotation set to "true".
+func ManagedByExternal(annotations map[string]string) bool {
+	return strings.EqualFold(annotations[ManagedByExternalAnnotation], "true")
+}
+
+// operatorAnnotationPrefix is the prefix of the annotations the operator sets on child resources to track
+// pending actions, such as restarting the StatefulSet after the server configuration changed.
+const operatorAnnotationPrefix = "rabbitmq.com/"
+
+// OperatorAnnotations returns the annotations whose key starts with "rabbitmq.com/".
+func OperatorAnnotations(annotations map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range annotations {
+		if strings.HasPrefix(k, operatorAnnotationPrefix) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
 func ReconcileAnnotations(existing map[string]string, defaults ...map[string]string) map[string]string {
 	return mergeWithFilter(func(k string) bool { return true }, existing, defaults...)
 }
@@ -87,4 +87,22 @@ var _ = Describe("Annotation", func() {
 				"k8s.io.annotation":  "annot",
 			}, defaultOne, defaultAnnotationsWithK8s),
 	)
+	DescribeTable("ManagedByExternal",
+		func(annotations map[string]string, expected bool) {
+			Expect(internalmetadata.ManagedByExternal(annotations)).To(Equal(expected))
+		},
+		Entry("nil annotations", nil, false),
+		Entry("annotation not set", map[string]string{"foo": "bar"}, false),
+		Entry("annotation set to true", map[string]string{"operator.rabbitmq.com/managed-by-external": "true"}, true),
+		Entry("annotation set to false", map[string]string{"operator.rabbitmq.com/managed-by-external": "false"}, false),
+	)
+
+	It("OperatorAnnotations only returns annotations with the rabbitmq.com prefix", func() {
+		Expect(internalmetadata.OperatorAnnotations(map[string]string{
+			"rabbitmq.com/serverConfUpdatedAt":          "2024-01-01T00:00:00Z",
+			"operator.rabbitmq.com/managed-by-external": "true",
+			"foo": "bar",
+		})).To(Equal(map[string]string{"rabbitmq.com/serverConfUpdatedAt": "2024-01-01T00:00:00Z"}))
+		Expect(internalmetadata.OperatorAnnotations(nil)).To(BeEmpty())
+	})
 })
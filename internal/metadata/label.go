@@ -10,9 +10,16 @@
 package metadata
 
 import (
+	"fmt"
 	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// ManagedResourceLabel is set on the child resources the operator applies for a RabbitmqCluster, with the name of the
+// RabbitmqCluster as value. Child resources with the label are deleted once they no longer correspond to the spec.
+const ManagedResourceLabel = "rabbitmq.com/managedResourceOf"
+
 type label map[string]string
 
 func Label(instanceName string) label {
@@ -40,3 +47,40 @@ func LabelSelector(instanceName string) label {
 		"app.kubernetes.io/name": instanceName,
 	}
 }
+
+// ParseLabelMappings parses comma-separated label mappings of the form "source=target",
+// e.g. "team=example.com/team,cost-center=example.com/cost-center".
+func ParseLabelMappings(mappings string) (map[string]string, error) {
+	parsed := map[string]string{}
+	for _, mapping := range strings.Split(mappings, ",") {
+		mapping = strings.TrimSpace(mapping)
+		if mapping == "" {
+			continue
+		}
+		source, target, found := strings.Cut(mapping, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid label mapping %q: must be of the form source=target", mapping)
+		}
+		for _, key := range []string{source, target} {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid label mapping %q: %s", mapping, strings.Join(errs, "; "))
+			}
+		}
+		if strings.HasPrefix(target, "app.kubernetes.io") {
+			return nil, fmt.Errorf("invalid label mapping %q: target must not use the reserved app.kubernetes.io prefix", mapping)
+		}
+		parsed[source] = target
+	}
+	return parsed, nil
+}
+
+// MapLabels returns the value of every instance label with a mapping, keyed by the mapping target.
+func MapLabels(instanceLabels, mappings map[string]string) map[string]string {
+	mapped := map[string]string{}
+	for source, target := range mappings {
+		if value, ok := instanceLabels[source]; ok {
+			mapped[target] = value
+		}
+	}
+	return mapped
+}
@@ -0,0 +1,38 @@
+package metadata_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	internalmetadata "github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+)
+
+var _ = Describe("Label", func() {
+	Context("ParseLabelMappings", func() {
+		It("parses comma-separated mappings", func() {
+			mappings, err := internalmetadata.ParseLabelMappings("team=example.com/team, cost-center=example.com/cost-center,")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mappings).To(Equal(map[string]string{
+				"team":        "example.com/team",
+				"cost-center": "example.com/cost-center",
+			}))
+		})
+
+		DescribeTable("rejects invalid mappings",
+			func(mappings string) {
+				_, err := internalmetadata.ParseLabelMappings(mappings)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("missing target", "team"),
+			Entry("invalid label key", "team=not a label"),
+			Entry("reserved target prefix", "team=app.kubernetes.io/name"),
+		)
+	})
+
+	It("maps instance labels to their mapping target", func() {
+		mapped := internalmetadata.MapLabels(
+			map[string]string{"team": "messaging", "unmapped": "value"},
+			map[string]string{"team": "example.com/team", "cost-center": "example.com/cost-center"},
+		)
+		Expect(mapped).To(Equal(map[string]string{"example.com/team": "messaging"}))
+	})
+})
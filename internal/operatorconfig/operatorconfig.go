@@ -0,0 +1,87 @@
+// Package operatorconfig reads the operator configuration file, which holds the defaults of all RabbitmqClusters
+// managed by an operator deployment.
+package operatorconfig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the content of the operator configuration file. Defaults are only applied to RabbitmqClusters
+// which leave the corresponding field empty.
+type Config struct {
+	// DefaultRabbitmqImage is the image of RabbitmqClusters which do not set spec.image.
+	DefaultRabbitmqImage string `json:"defaultRabbitmqImage,omitempty"`
+	// DefaultImagePullSecrets are the image pull secrets of RabbitmqClusters which do not set spec.imagePullSecrets.
+	DefaultImagePullSecrets []string `json:"defaultImagePullSecrets,omitempty"`
+	// DefaultStorageClassName is the StorageClass of new RabbitmqClusters which do not set spec.persistence.storageClassName.
+	DefaultStorageClassName string `json:"defaultStorageClassName,omitempty"`
+	// DefaultResources are the compute resources of RabbitmqClusters which do not set spec.resources.
+	DefaultResources *corev1.ResourceRequirements `json:"defaultResources,omitempty"`
+	// Labels are added to all child resources and Pods. Labels of the RabbitmqCluster take precedence.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to all child resources. Annotations of the RabbitmqCluster take precedence.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DefaultResources returns the compute resources of RabbitmqClusters if neither the RabbitmqCluster
+// nor the operator configuration sets them.
+func DefaultResources() *corev1.ResourceRequirements {
+	return &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    k8sresource.MustParse("2000m"),
+			corev1.ResourceMemory: k8sresource.MustParse("2Gi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    k8sresource.MustParse("1000m"),
+			corev1.ResourceMemory: k8sresource.MustParse("2Gi"),
+		},
+	}
+}
+
+// Load reads the operator configuration file at the given path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operator configuration: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses an operator configuration in YAML or JSON. Unknown fields are rejected.
+func Parse(data []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("invalid operator configuration: %w", err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid operator configuration: %w", err)
+	}
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	for key, value := range c.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("label %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("label %q: %s", key, strings.Join(errs, "; "))
+		}
+		if strings.HasPrefix(key, "app.kubernetes.io") {
+			return fmt.Errorf("label %q: must not use the reserved app.kubernetes.io prefix", key)
+		}
+	}
+	for key := range c.Annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("annotation %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package operatorconfig_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOperatorconfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Operatorconfig Suite")
+}
@@ -0,0 +1,56 @@
+package operatorconfig_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/operatorconfig"
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("Operator configuration", func() {
+	It("parses all settings", func() {
+		config, err := operatorconfig.Parse([]byte(`
+defaultRabbitmqImage: registry.example.com/rabbitmq:4.0
+defaultImagePullSecrets: [registry-credentials]
+defaultStorageClassName: fast
+defaultResources:
+  requests:
+    memory: 4Gi
+labels:
+  example.com/team: messaging
+annotations:
+  example.com/owner: platform
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.DefaultRabbitmqImage).To(Equal("registry.example.com/rabbitmq:4.0"))
+		Expect(config.DefaultImagePullSecrets).To(Equal([]string{"registry-credentials"}))
+		Expect(config.DefaultStorageClassName).To(Equal("fast"))
+		Expect(config.DefaultResources.Requests[corev1.ResourceMemory]).To(Equal(k8sresource.MustParse("4Gi")))
+		Expect(config.Labels).To(Equal(map[string]string{"example.com/team": "messaging"}))
+		Expect(config.Annotations).To(Equal(map[string]string{"example.com/owner": "platform"}))
+	})
+
+	It("loads the configuration from a file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte("defaultStorageClassName: fast\n"), 0o600)).To(Succeed())
+		config, err := operatorconfig.Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.DefaultStorageClassName).To(Equal("fast"))
+	})
+
+	DescribeTable("rejects invalid configurations",
+		func(content, expectedError string) {
+			_, err := operatorconfig.Parse([]byte(content))
+			Expect(err).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("unknown field", "defaultImage: rabbitmq", `unknown field "defaultImage"`),
+		Entry("invalid label key", "labels: {'not a key': value}", `label "not a key"`),
+		Entry("invalid label value", "labels: {team: 'not a value'}", `label "team"`),
+		Entry("reserved label prefix", "labels: {app.kubernetes.io/name: rabbitmq}", "reserved app.kubernetes.io prefix"),
+		Entry("invalid annotation key", "annotations: {'not a key': value}", `annotation "not a key"`),
+	)
+})
@@ -0,0 +1,77 @@
+// Package plugins knows the plugins shipped with RabbitMQ, so that misspelled plugin names are reported when a
+// RabbitmqCluster is submitted instead of by RabbitMQ nodes failing to boot.
+package plugins
+
+import (
+	"sort"
+
+	"golang.org/x/mod/semver"
+)
+
+// since maps the plugins shipped with RabbitMQ to the first version shipping them. Plugins shipped with all
+// versions supported by the operator have no version.
+var since = map[string]string{
+	"rabbitmq_amqp1_0":                        "",
+	"rabbitmq_amqp_client":                    "4.0.0",
+	"rabbitmq_auth_backend_cache":             "",
+	"rabbitmq_auth_backend_http":              "",
+	"rabbitmq_auth_backend_internal_loopback": "4.1.0",
+	"rabbitmq_auth_backend_ldap":              "",
+	"rabbitmq_auth_backend_oauth2":            "",
+	"rabbitmq_auth_mechanism_ssl":             "",
+	"rabbitmq_aws":                            "",
+	"rabbitmq_consistent_hash_exchange":       "",
+	"rabbitmq_event_exchange":                 "",
+	"rabbitmq_federation":                     "",
+	"rabbitmq_federation_management":          "",
+	"rabbitmq_federation_prometheus":          "4.1.0",
+	"rabbitmq_jms_topic_exchange":             "",
+	"rabbitmq_management":                     "",
+	"rabbitmq_management_agent":               "",
+	"rabbitmq_mqtt":                           "",
+	"rabbitmq_peer_discovery_aws":             "",
+	"rabbitmq_peer_discovery_common":          "",
+	"rabbitmq_peer_discovery_consul":          "",
+	"rabbitmq_peer_discovery_etcd":            "",
+	"rabbitmq_peer_discovery_k8s":             "",
+	"rabbitmq_prometheus":                     "",
+	"rabbitmq_random_exchange":                "",
+	"rabbitmq_recent_history_exchange":        "",
+	"rabbitmq_sharding":                       "",
+	"rabbitmq_shovel":                         "",
+	"rabbitmq_shovel_management":              "",
+	"rabbitmq_shovel_prometheus":              "4.1.0",
+	"rabbitmq_stomp":                          "",
+	"rabbitmq_stream":                         "3.9.0",
+	"rabbitmq_stream_management":              "3.9.0",
+	"rabbitmq_top":                            "",
+	"rabbitmq_tracing":                        "",
+	"rabbitmq_trust_store":                    "",
+	"rabbitmq_web_dispatch":                   "",
+	"rabbitmq_web_mqtt":                       "",
+	"rabbitmq_web_mqtt_examples":              "",
+	"rabbitmq_web_stomp":                      "",
+	"rabbitmq_web_stomp_examples":             "",
+}
+
+// Shipped returns true if the plugin is shipped with the given RabbitMQ version, e.g. 4.1.0.
+// If the version is empty, as for images tagged latest, it returns true for plugins shipped with any version.
+func Shipped(plugin, version string) bool {
+	first, ok := since[plugin]
+	if !ok {
+		return false
+	}
+	return first == "" || version == "" || semver.Compare("v"+version, "v"+first) >= 0
+}
+
+// List returns the names of the plugins shipped with the given RabbitMQ version in alphabetical order.
+func List(version string) []string {
+	var names []string
+	for name := range since {
+		if Shipped(name, version) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,32 @@
+package plugins_test
+
+import (
+	"sort"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/plugins"
+)
+
+var _ = Describe("Shipped", func() {
+	DescribeTable("tells whether a plugin is shipped with a RabbitMQ version",
+		func(plugin, version string, expected bool) {
+			Expect(plugins.Shipped(plugin, version)).To(Equal(expected))
+		},
+		Entry("plugin of all versions", "rabbitmq_shovel", "3.13.7", true),
+		Entry("plugin of later versions", "rabbitmq_shovel_prometheus", "4.1.0", true),
+		Entry("plugin of a later version than the target", "rabbitmq_shovel_prometheus", "4.0.5", false),
+		Entry("plugin of later versions and an unknown version", "rabbitmq_shovel_prometheus", "", true),
+		Entry("misspelled plugin", "rabbitmq_shovels", "4.1.0", false),
+		Entry("community plugin", "rabbitmq_delayed_message_exchange", "", false),
+	)
+})
+
+var _ = Describe("List", func() {
+	It("returns the plugins of a version in alphabetical order", func() {
+		names := plugins.List("4.0.0")
+		Expect(names).To(ContainElements("rabbitmq_amqp_client", "rabbitmq_management", "rabbitmq_stream"))
+		Expect(names).NotTo(ContainElement("rabbitmq_shovel_prometheus"))
+		Expect(sort.StringsAreSorted(names)).To(BeTrue())
+	})
+})
@@ -0,0 +1,228 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	MaxClustersKey = "maxClusters"
+	MaxReplicasKey = "maxReplicas"
+	MaxStorageKey  = "maxStorage"
+)
+
+var ErrQuotaExceeded = errors.New("namespace quota exceeded")
+
+// Limits are the totals allowed for all RabbitmqClusters of a single namespace.
+// A nil limit is not enforced.
+type Limits struct {
+	MaxClusters *int
+	MaxReplicas *int32
+	MaxStorage  *k8sresource.Quantity
+}
+
+// Policy holds the limits applied to every namespace, and per-namespace limits
+// which take precedence over them.
+// Policies are read from ConfigMap data, for example:
+//
+//	maxClusters: "5"
+//	maxReplicas: "15"
+//	maxStorage: "500Gi"
+//	team-a.maxReplicas: "30"
+type Policy struct {
+	Default    Limits
+	Namespaces map[string]Limits
+}
+
+// Usage is the sum of resources requested by a set of RabbitmqClusters.
+type Usage struct {
+	Clusters int
+	Replicas int32
+	Storage  k8sresource.Quantity
+}
+
+// NewPolicy parses a quota policy from ConfigMap data.
+func NewPolicy(data map[string]string) (*Policy, error) {
+	policy := &Policy{Namespaces: map[string]Limits{}}
+	for key, value := range data {
+		namespace, limit := "", key
+		if i := strings.LastIndex(key, "."); i >= 0 {
+			namespace, limit = key[:i], key[i+1:]
+		}
+
+		limits := policy.Default
+		if namespace != "" {
+			limits = policy.Namespaces[namespace]
+		}
+
+		value = strings.TrimSpace(value)
+		switch limit {
+		case MaxClustersKey:
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid value %q for quota policy key %s: must be a non-negative integer", value, key)
+			}
+			limits.MaxClusters = &n
+		case MaxReplicasKey:
+			n, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid value %q for quota policy key %s: must be a non-negative integer", value, key)
+			}
+			limits.MaxReplicas = ptr.To(int32(n))
+		case MaxStorageKey:
+			q, err := k8sresource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q for quota policy key %s: %w", value, key, err)
+			}
+			limits.MaxStorage = &q
+		default:
+			return nil, fmt.Errorf("unknown quota policy key %s", key)
+		}
+
+		if namespace != "" {
+			policy.Namespaces[namespace] = limits
+		} else {
+			policy.Default = limits
+		}
+	}
+	return policy, nil
+}
+
+// LimitsFor returns the limits enforced in the given namespace.
+// Limits not set for the namespace fall back to the policy defaults.
+func (p *Policy) LimitsFor(namespace string) Limits {
+	limits := p.Default
+	override, ok := p.Namespaces[namespace]
+	if !ok {
+		return limits
+	}
+	if override.MaxClusters != nil {
+		limits.MaxClusters = override.MaxClusters
+	}
+	if override.MaxReplicas != nil {
+		limits.MaxReplicas = override.MaxReplicas
+	}
+	if override.MaxStorage != nil {
+		limits.MaxStorage = override.MaxStorage
+	}
+	return limits
+}
+
+// Check verifies that cluster fits into the quota of its namespace: the usage of cluster is added to the usage
+// of all other clusters in the same namespace. Clusters being deleted do not count towards the quota.
+func (p *Policy) Check(cluster *rabbitmqv1beta1.RabbitmqCluster, clusters []rabbitmqv1beta1.RabbitmqCluster) error {
+	admitted := []rabbitmqv1beta1.RabbitmqCluster{*cluster}
+	for i := range clusters {
+		c := clusters[i]
+		if c.Namespace != cluster.Namespace || isSameCluster(&c, cluster) || !c.DeletionTimestamp.IsZero() {
+			continue
+		}
+		admitted = append(admitted, c)
+	}
+
+	return p.checkUsage(cluster.Namespace, UsageOf(admitted))
+}
+
+// checkUsage returns an error wrapping ErrQuotaExceeded if usage exceeds the limits of the namespace.
+func (p *Policy) checkUsage(namespace string, usage Usage) error {
+	limits := p.LimitsFor(namespace)
+	if limits.MaxClusters != nil && usage.Clusters > *limits.MaxClusters {
+		return fmt.Errorf("%w: namespace %s would have %d RabbitmqClusters, but at most %d are allowed", ErrQuotaExceeded, namespace, usage.Clusters, *limits.MaxClusters)
+	}
+	if limits.MaxReplicas != nil && usage.Replicas > *limits.MaxReplicas {
+		return fmt.Errorf("%w: namespace %s would have %d RabbitmqCluster replicas, but at most %d are allowed", ErrQuotaExceeded, namespace, usage.Replicas, *limits.MaxReplicas)
+	}
+	if limits.MaxStorage != nil && usage.Storage.Cmp(*limits.MaxStorage) > 0 {
+		return fmt.Errorf("%w: namespace %s would request %s of RabbitmqCluster storage, but at most %s is allowed", ErrQuotaExceeded, namespace, usage.Storage.String(), limits.MaxStorage.String())
+	}
+	return nil
+}
+
+// UsageOf sums the number of clusters, replicas and persistent storage requested by clusters.
+func UsageOf(clusters []rabbitmqv1beta1.RabbitmqCluster) Usage {
+	usage := Usage{Storage: k8sresource.MustParse("0")}
+	for i := range clusters {
+		replicas := ptr.Deref(clusters[i].Spec.Replicas, 1)
+		usage.Clusters++
+		usage.Replicas += replicas
+		if storage := clusters[i].Spec.Persistence.Storage; storage != nil {
+			for r := int32(0); r < replicas; r++ {
+				usage.Storage.Add(*storage)
+			}
+		}
+	}
+	return usage
+}
+
+// isSameCluster returns true if a and b are the same cluster. Clusters which are being created have no UID yet.
+func isSameCluster(a, b *rabbitmqv1beta1.RabbitmqCluster) bool {
+	if a.UID != "" && b.UID != "" {
+		return a.UID == b.UID
+	}
+	return a.Name == b.Name
+}
+
+// Checker checks RabbitmqClusters against the quota policy in a ConfigMap. The validating webhook uses it to
+// reject RabbitmqClusters exceeding the quota of their namespace at admission time.
+type Checker struct {
+	// Client reads the policy ConfigMap and lists the RabbitmqClusters of the namespace. It should read from the
+	// API server rather than from a cache, which only holds the ConfigMaps labelled as part of rabbitmq.
+	Client client.Reader
+	// Namespace and Name of the policy ConfigMap. Quotas are not enforced while the ConfigMap does not exist.
+	Namespace string
+	Name      string
+}
+
+// Check returns an error wrapping ErrQuotaExceeded if cluster exceeds the quota of its namespace. old is the
+// cluster before an update, or nil if cluster is being created. Updates are only checked if they increase the
+// replicas or the storage of the cluster, so that clusters which exceed a policy tightened after their creation
+// can still be updated, e.g. to scale them down.
+func (c *Checker) Check(ctx context.Context, cluster, old *rabbitmqv1beta1.RabbitmqCluster) error {
+	if old != nil && !increasesUsage(old, cluster) {
+		return nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: c.Namespace, Name: c.Name}, configMap); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read quota policy ConfigMap %s: %w", c.Name, err)
+	}
+	policy, err := NewPolicy(configMap.Data)
+	if err != nil {
+		return fmt.Errorf("invalid quota policy ConfigMap %s: %w", c.Name, err)
+	}
+
+	clusters := &rabbitmqv1beta1.RabbitmqClusterList{}
+	if err := c.Client.List(ctx, clusters, client.InNamespace(cluster.Namespace)); err != nil {
+		return fmt.Errorf("failed to list RabbitmqClusters: %w", err)
+	}
+	return policy.Check(cluster, clusters.Items)
+}
+
+func increasesUsage(old, cluster *rabbitmqv1beta1.RabbitmqCluster) bool {
+	oldUsage := UsageOf([]rabbitmqv1beta1.RabbitmqCluster{*old})
+	usage := UsageOf([]rabbitmqv1beta1.RabbitmqCluster{*cluster})
+	return usage.Replicas > oldUsage.Replicas || usage.Storage.Cmp(oldUsage.Storage) > 0
+}
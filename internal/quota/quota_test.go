@@ -0,0 +1,200 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package quota_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/quota"
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Quota", func() {
+	Context("NewPolicy", func() {
+		It("parses default and per-namespace limits", func() {
+			policy, err := quota.NewPolicy(map[string]string{
+				"maxClusters":        "2",
+				"maxReplicas":        "5",
+				"maxStorage":         "100Gi",
+				"team-a.maxReplicas": "9",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			limits := policy.LimitsFor("team-a")
+			Expect(*limits.MaxClusters).To(Equal(2))
+			Expect(*limits.MaxReplicas).To(Equal(int32(9)))
+			Expect(limits.MaxStorage.String()).To(Equal("100Gi"))
+
+			limits = policy.LimitsFor("team-b")
+			Expect(*limits.MaxReplicas).To(Equal(int32(5)))
+		})
+
+		It("leaves unset limits unenforced", func() {
+			policy, err := quota.NewPolicy(map[string]string{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.LimitsFor("any")).To(Equal(quota.Limits{}))
+		})
+
+		DescribeTable("rejects invalid policies",
+			func(key, value string) {
+				_, err := quota.NewPolicy(map[string]string{key: value})
+				Expect(err).To(MatchError(ContainSubstring(key)))
+			},
+			Entry("negative cluster count", "maxClusters", "-1"),
+			Entry("non-numeric replicas", "team-a.maxReplicas", "many"),
+			Entry("invalid storage", "maxStorage", "lots"),
+			Entry("unknown key", "maxQueues", "10"),
+		)
+	})
+
+	Context("Check", func() {
+		var (
+			policy   *quota.Policy
+			existing []rabbitmqv1beta1.RabbitmqCluster
+			now      = time.Now()
+		)
+
+		cluster := func(name string, age time.Duration, replicas int32, storage string) rabbitmqv1beta1.RabbitmqCluster {
+			return rabbitmqv1beta1.RabbitmqCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         "team-a",
+					UID:               types.UID(name),
+					CreationTimestamp: metav1.NewTime(now.Add(-age)),
+				},
+				Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+					Replicas: ptr.To(replicas),
+					Persistence: rabbitmqv1beta1.RabbitmqClusterPersistenceSpec{
+						Storage: ptr.To(k8sresource.MustParse(storage)),
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			var err error
+			policy, err = quota.NewPolicy(map[string]string{
+				"maxClusters": "2",
+				"maxReplicas": "4",
+				"maxStorage":  "40Gi",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			existing = []rabbitmqv1beta1.RabbitmqCluster{
+				cluster("first", time.Hour, 3, "10Gi"),
+				cluster("second", time.Minute, 1, "10Gi"),
+			}
+		})
+
+		It("admits clusters within the quota", func() {
+			Expect(policy.Check(&existing[0], existing)).To(Succeed())
+			Expect(policy.Check(&existing[1], existing)).To(Succeed())
+		})
+
+		It("rejects clusters created after the cluster count is reached", func() {
+			third := cluster("third", 0, 0, "0")
+			err := policy.Check(&third, append(existing, third))
+			Expect(err).To(MatchError(quota.ErrQuotaExceeded))
+			Expect(err).To(MatchError(ContainSubstring("at most 2 are allowed")))
+		})
+
+		It("counts clusters which are being created", func() {
+			third := cluster("third", 0, 0, "0")
+			third.UID = ""
+			third.CreationTimestamp = metav1.Time{}
+			err := policy.Check(&third, existing)
+			Expect(err).To(MatchError(quota.ErrQuotaExceeded))
+			Expect(err).To(MatchError(ContainSubstring("at most 2 are allowed")))
+		})
+
+		It("rejects clusters exceeding the replica limit", func() {
+			existing[1].Spec.Replicas = ptr.To(int32(2))
+			err := policy.Check(&existing[1], existing)
+			Expect(err).To(MatchError(ContainSubstring("would have 5 RabbitmqCluster replicas")))
+		})
+
+		It("rejects clusters exceeding the storage limit", func() {
+			existing[1].Spec.Persistence.Storage = ptr.To(k8sresource.MustParse("20Gi"))
+			err := policy.Check(&existing[1], existing)
+			Expect(err).To(MatchError(ContainSubstring("would request 50Gi of RabbitmqCluster storage")))
+		})
+
+		It("ignores clusters being deleted and clusters in other namespaces", func() {
+			existing[0].DeletionTimestamp = ptr.To(metav1.Now())
+			other := cluster("other", 2*time.Hour, 3, "10Gi")
+			other.Namespace = "team-b"
+			third := cluster("third", 0, 1, "10Gi")
+			Expect(policy.Check(&third, append(existing, other, third))).To(Succeed())
+		})
+	})
+
+	Context("Checker", func() {
+		var (
+			checker  *quota.Checker
+			existing *rabbitmqv1beta1.RabbitmqCluster
+			ctx      = context.Background()
+		)
+
+		newCluster := func(name string, replicas int32) *rabbitmqv1beta1.RabbitmqCluster {
+			return &rabbitmqv1beta1.RabbitmqCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+				Spec:       rabbitmqv1beta1.RabbitmqClusterSpec{Replicas: ptr.To(replicas)},
+			}
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+			existing = newCluster("existing", 3)
+			policy := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "quota-policy", Namespace: "rabbitmq-system"},
+				Data:       map[string]string{"maxClusters": "2", "maxReplicas": "4"},
+			}
+			checker = &quota.Checker{
+				Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing, policy).Build(),
+				Namespace: "rabbitmq-system",
+				Name:      "quota-policy",
+			}
+		})
+
+		It("rejects new clusters exceeding the quota", func() {
+			Expect(checker.Check(ctx, newCluster("new", 1), nil)).To(Succeed())
+			Expect(checker.Check(ctx, newCluster("new", 2), nil)).To(MatchError(quota.ErrQuotaExceeded))
+		})
+
+		It("only checks updates which increase the usage", func() {
+			scaledUp := existing.DeepCopy()
+			scaledUp.Spec.Replicas = ptr.To(int32(5))
+			Expect(checker.Check(ctx, scaledUp, existing)).To(MatchError(quota.ErrQuotaExceeded))
+
+			By("accepting updates of clusters which exceed the quota already")
+			existing.Spec.Replicas = ptr.To(int32(7))
+			scaledDown := existing.DeepCopy()
+			scaledDown.Spec.Replicas = ptr.To(int32(6))
+			Expect(checker.Check(ctx, scaledDown, existing)).To(Succeed())
+		})
+
+		It("does not enforce quotas without a policy ConfigMap", func() {
+			checker.Name = "missing"
+			Expect(checker.Check(ctx, newCluster("new", 5), nil)).To(Succeed())
+		})
+	})
+})
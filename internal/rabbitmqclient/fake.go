@@ -0,0 +1,124 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package rabbitmqclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Fake is a Client for tests. It records the calls it receives, and returns the errors set with SetError.
+type Fake struct {
+	mu           sync.Mutex
+	calls        []string
+	errors       map[string]error
+	featureFlags []FeatureFlag
+}
+
+// NewFake returns a Fake which succeeds on every call.
+func NewFake() *Fake {
+	return &Fake{errors: map[string]error{}}
+}
+
+// Factory returns a Factory which returns the Fake for every host. The host is recorded as a call.
+func (f *Fake) Factory() Factory {
+	return func(_ *rabbitmqv1beta1.RabbitmqCluster, _ *corev1.Secret, host string, _ Options) (Client, error) {
+		f.record("Connect " + host)
+		return f, nil
+	}
+}
+
+// SetError makes the method with the given name, e.g. CheckAlarms, return err.
+func (f *Fake) SetError(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors[method] = err
+}
+
+// SetFeatureFlags sets the feature flags returned by ListFeatureFlags.
+func (f *Fake) SetFeatureFlags(featureFlags []FeatureFlag) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.featureFlags = featureFlags
+}
+
+// Calls returns the calls received so far, as the method name followed by its arguments, e.g. "DeleteUser alice".
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// Reset forgets the calls received so far, the errors and the feature flags.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+	f.errors = map[string]error{}
+	f.featureFlags = nil
+}
+
+func (f *Fake) record(call string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
+
+func (f *Fake) result(method string, args ...interface{}) error {
+	call := method
+	for _, arg := range args {
+		call += fmt.Sprintf(" %v", arg)
+	}
+	f.record(call)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.errors[method]
+}
+
+func (f *Fake) CheckAlarms(context.Context) error {
+	return f.result("CheckAlarms")
+}
+
+func (f *Fake) CheckNodeIsQuorumCritical(context.Context) error {
+	return f.result("CheckNodeIsQuorumCritical")
+}
+
+func (f *Fake) RebalanceQueues(context.Context) error {
+	return f.result("RebalanceQueues")
+}
+
+func (f *Fake) ListFeatureFlags(context.Context) ([]FeatureFlag, error) {
+	if err := f.result("ListFeatureFlags"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FeatureFlag(nil), f.featureFlags...), nil
+}
+
+func (f *Fake) EnableFeatureFlag(_ context.Context, name string) error {
+	return f.result("EnableFeatureFlag", name)
+}
+
+func (f *Fake) PutUser(_ context.Context, name, _ string, tags []string) error {
+	return f.result("PutUser", name, tags)
+}
+
+func (f *Fake) DeleteUser(_ context.Context, name string) error {
+	return f.result("DeleteUser", name)
+}
+
+func (f *Fake) PutPermissions(_ context.Context, vhost, user string, _ Permissions) error {
+	return f.result("PutPermissions", vhost, user)
+}
@@ -0,0 +1,245 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+// Package rabbitmqclient calls the HTTP API of the management plugin of a RabbitmqCluster.
+package rabbitmqclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/tracing"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrHealthCheckFailed is returned when a health check of the management API fails.
+var ErrHealthCheckFailed = errors.New("health check failed")
+
+// Client calls the management API of a RabbitMQ node or cluster.
+type Client interface {
+	// CheckAlarms returns an error wrapping ErrHealthCheckFailed if any node of the cluster raised a resource alarm.
+	CheckAlarms(ctx context.Context) error
+	// CheckNodeIsQuorumCritical returns an error wrapping ErrHealthCheckFailed if stopping the node serving the request
+	// would make quorum queues or streams lose their quorum.
+	CheckNodeIsQuorumCritical(ctx context.Context) error
+	// RebalanceQueues moves queue leaders so that they are spread evenly across the nodes.
+	RebalanceQueues(ctx context.Context) error
+	// ListFeatureFlags returns the feature flags of the cluster.
+	ListFeatureFlags(ctx context.Context) ([]FeatureFlag, error)
+	// EnableFeatureFlag enables a feature flag on all nodes.
+	EnableFeatureFlag(ctx context.Context, name string) error
+	// PutUser creates or updates a user.
+	PutUser(ctx context.Context, name, password string, tags []string) error
+	// DeleteUser deletes a user. It succeeds if the user does not exist.
+	DeleteUser(ctx context.Context, name string) error
+	// PutPermissions sets the permissions of a user in a vhost.
+	PutPermissions(ctx context.Context, vhost, user string, permissions Permissions) error
+}
+
+// FeatureFlag is a feature flag as returned by the management API.
+type FeatureFlag struct {
+	Name string `json:"name"`
+	// State is enabled, disabled or state_changing.
+	State     string `json:"state"`
+	Stability string `json:"stability"`
+}
+
+// Permissions are the configure, write and read permissions of a user in a vhost, as regular expressions of resource names.
+type Permissions struct {
+	Configure string `json:"configure"`
+	Write     string `json:"write"`
+	Read      string `json:"read"`
+}
+
+// Options configure how the management API is reached.
+type Options struct {
+	// CACertificate verifies the certificate of the management API if only TLS listeners are enabled.
+	// The system roots are used if it is empty.
+	CACertificate []byte
+	// Port of the management API. Defaults to 15671 for HTTPS and 15672 for HTTP.
+	Port int
+	// Timeout of each request. Defaults to 30 seconds.
+	Timeout time.Duration
+}
+
+// Factory returns a Client for the management API at the given host.
+// The reconciler uses a Factory so that tests can replace the HTTP client with a fake.
+type Factory func(rmq *rabbitmqv1beta1.RabbitmqCluster, credentials *corev1.Secret, host string, opts Options) (Client, error)
+
+// ServiceHost returns the DNS name of the client Service of a RabbitmqCluster, which balances requests across all ready nodes.
+func ServiceHost(rmq *rabbitmqv1beta1.RabbitmqCluster) string {
+	return fmt.Sprintf("%s.%s.svc", rmq.ChildResourceName(""), rmq.Namespace)
+}
+
+// PodHost returns the DNS name of a Pod of a RabbitmqCluster through its headless Service.
+func PodHost(rmq *rabbitmqv1beta1.RabbitmqCluster, podName string) string {
+	return fmt.Sprintf("%s.%s.%s.svc", podName, rmq.ChildResourceName("nodes"), rmq.Namespace)
+}
+
+type client struct {
+	baseURL  string
+	username string
+	password string
+	http     *http.Client
+}
+
+// New returns a Client for the management API at the given host, authenticating with the username and password of
+// the given default user Secret. HTTPS on port 15671 is used if the RabbitmqCluster disables its non-TLS listeners,
+// HTTP on port 15672 otherwise.
+func New(rmq *rabbitmqv1beta1.RabbitmqCluster, credentials *corev1.Secret, host string, opts Options) (Client, error) {
+	username, password := string(credentials.Data["username"]), string(credentials.Data["password"])
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("secret %s does not contain a username and password", credentials.Name)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	scheme, port := "http", 15672
+	if rmq.TLSEnabled() && rmq.DisableNonTLSListeners() {
+		scheme, port = "https", 15671
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: host}
+		if len(opts.CACertificate) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(opts.CACertificate) {
+				return nil, errors.New("failed to parse CA certificate")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	if opts.Port != 0 {
+		port = opts.Port
+	}
+
+	return &client{
+		baseURL:  fmt.Sprintf("%s://%s:%d", scheme, host, port),
+		username: username,
+		password: password,
+		http:     &http.Client{Timeout: opts.Timeout, Transport: tracing.Transport("management-api", transport)},
+	}, nil
+}
+
+func (c *client) CheckAlarms(ctx context.Context) error {
+	return c.healthCheck(ctx, "/api/health/checks/alarms")
+}
+
+func (c *client) CheckNodeIsQuorumCritical(ctx context.Context) error {
+	return c.healthCheck(ctx, "/api/health/checks/node-is-quorum-critical")
+}
+
+func (c *client) RebalanceQueues(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/api/rebalance/queues", nil, nil)
+}
+
+func (c *client) ListFeatureFlags(ctx context.Context) ([]FeatureFlag, error) {
+	var featureFlags []FeatureFlag
+	if err := c.do(ctx, http.MethodGet, "/api/feature-flags", nil, &featureFlags); err != nil {
+		return nil, err
+	}
+	return featureFlags, nil
+}
+
+func (c *client) EnableFeatureFlag(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPut, "/api/feature-flags/"+url.PathEscape(name)+"/enable", struct{}{}, nil)
+}
+
+func (c *client) PutUser(ctx context.Context, name, password string, tags []string) error {
+	body := map[string]string{"password": password, "tags": strings.Join(tags, ",")}
+	return c.do(ctx, http.MethodPut, "/api/users/"+url.PathEscape(name), body, nil)
+}
+
+func (c *client) DeleteUser(ctx context.Context, name string) error {
+	err := c.do(ctx, http.MethodDelete, "/api/users/"+url.PathEscape(name), nil, nil)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (c *client) PutPermissions(ctx context.Context, vhost, user string, permissions Permissions) error {
+	return c.do(ctx, http.MethodPut, "/api/permissions/"+url.PathEscape(vhost)+"/"+url.PathEscape(user), permissions, nil)
+}
+
+// StatusError is returned when the management API responds with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("management API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// healthCheck calls a health check endpoint, which responds with status 503 and a reason if the check fails.
+func (c *client) healthCheck(ctx context.Context, path string) error {
+	err := c.do(ctx, http.MethodGet, path, nil, nil)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusServiceUnavailable {
+		result := struct {
+			Reason string `json:"reason"`
+		}{}
+		if json.Unmarshal([]byte(statusErr.Body), &result) != nil || result.Reason == "" {
+			result.Reason = statusErr.Body
+		}
+		return fmt.Errorf("%w: %s", ErrHealthCheckFailed, result.Reason)
+	}
+	return err
+}
+
+func (c *client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+	if result != nil {
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package rabbitmqclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/rabbitmqclient"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server    *httptest.Server
+		requests  []string
+		bodies    []string
+		responses map[string]response
+		rmq       *rabbitmqv1beta1.RabbitmqCluster
+		secret    *corev1.Secret
+		client    rabbitmqclient.Client
+		ctx       = context.Background()
+	)
+
+	BeforeEach(func() {
+		requests, bodies = nil, nil
+		responses = map[string]response{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("admin"))
+			Expect(password).To(Equal("secret"))
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+			bodies = append(bodies, string(body))
+
+			resp, ok := responses[r.Method+" "+r.URL.EscapedPath()]
+			if !ok {
+				resp = response{status: http.StatusNoContent}
+			}
+			w.WriteHeader(resp.status)
+			_, _ = w.Write([]byte(resp.body))
+		}))
+		serverURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		port, err := strconv.Atoi(serverURL.Port())
+		Expect(err).NotTo(HaveOccurred())
+
+		rmq = &rabbitmqv1beta1.RabbitmqCluster{ObjectMeta: metav1.ObjectMeta{Name: "rabbit", Namespace: "ns"}}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rabbit-default-user"},
+			Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+		}
+		client, err = rabbitmqclient.New(rmq, secret, serverURL.Hostname(), rabbitmqclient.Options{Port: port})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the DNS names of the client Service and the Pods", func() {
+		Expect(rabbitmqclient.ServiceHost(rmq)).To(Equal("rabbit.ns.svc"))
+		Expect(rabbitmqclient.PodHost(rmq, "rabbit-server-0")).To(Equal("rabbit-server-0.rabbit-nodes.ns.svc"))
+	})
+
+	It("requires the credentials of the default user", func() {
+		_, err := rabbitmqclient.New(rmq, &corev1.Secret{}, "localhost", rabbitmqclient.Options{})
+		Expect(err).To(MatchError(ContainSubstring("does not contain a username and password")))
+	})
+
+	It("passes health checks which respond with status 200", func() {
+		responses["GET /api/health/checks/alarms"] = response{status: http.StatusOK, body: `{"status":"ok"}`}
+		Expect(client.CheckAlarms(ctx)).To(Succeed())
+	})
+
+	It("fails health checks which respond with status 503 with their reason", func() {
+		responses["GET /api/health/checks/node-is-quorum-critical"] = response{
+			status: http.StatusServiceUnavailable,
+			body:   `{"status":"failed","reason":"there are quorum queues that would lose their quorum"}`,
+		}
+		err := client.CheckNodeIsQuorumCritical(ctx)
+		Expect(err).To(MatchError(rabbitmqclient.ErrHealthCheckFailed))
+		Expect(err).To(MatchError(ContainSubstring("there are quorum queues that would lose their quorum")))
+	})
+
+	It("returns other error responses as StatusError", func() {
+		responses["POST /api/rebalance/queues"] = response{status: http.StatusUnauthorized, body: "not authorised"}
+		err := client.RebalanceQueues(ctx)
+		var statusErr *rabbitmqclient.StatusError
+		Expect(err).To(BeAssignableToTypeOf(statusErr))
+		Expect(err.(*rabbitmqclient.StatusError).StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("lists and enables feature flags", func() {
+		responses["GET /api/feature-flags"] = response{status: http.StatusOK, body: `[{"name":"khepri_db","state":"disabled","stability":"stable"}]`}
+		Expect(client.ListFeatureFlags(ctx)).To(Equal([]rabbitmqclient.FeatureFlag{{Name: "khepri_db", State: "disabled", Stability: "stable"}}))
+		Expect(client.EnableFeatureFlag(ctx, "khepri_db")).To(Succeed())
+		Expect(requests).To(ContainElement("PUT /api/feature-flags/khepri_db/enable"))
+	})
+
+	It("manages users and their permissions", func() {
+		Expect(client.PutUser(ctx, "alice", "pa55", []string{"monitoring", "management"})).To(Succeed())
+		Expect(client.PutPermissions(ctx, "/", "alice", rabbitmqclient.Permissions{Configure: ".*", Write: ".*", Read: ".*"})).To(Succeed())
+		Expect(requests).To(Equal([]string{"PUT /api/users/alice", "PUT /api/permissions/%2F/alice"}))
+		Expect(bodies[0]).To(MatchJSON(`{"password":"pa55","tags":"monitoring,management"}`))
+		Expect(bodies[1]).To(MatchJSON(`{"configure":".*","write":".*","read":".*"}`))
+	})
+
+	It("succeeds deleting a user which does not exist", func() {
+		responses["DELETE /api/users/bob"] = response{status: http.StatusNotFound, body: `{"error":"Object Not Found"}`}
+		Expect(client.DeleteUser(ctx, "bob")).To(Succeed())
+	})
+})
+
+type response struct {
+	status int
+	body   string
+}
@@ -0,0 +1,91 @@
+// Package ratelimit provides the rate limiter of the RabbitmqCluster controller.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// DefaultBaseDelay is the delay before the first retry of a failing item.
+	DefaultBaseDelay = time.Second
+	// DefaultMaxDelay caps the delay between retries of a failing item.
+	DefaultMaxDelay = 5 * time.Minute
+)
+
+// New returns a rate limiter which retries each failing item with an exponential backoff, and limits the overall
+// retry rate like the default controller rate limiter. Zero delays are replaced by DefaultBaseDelay and DefaultMaxDelay.
+func New[T comparable](baseDelay, maxDelay time.Duration) workqueue.TypedRateLimiter[T] {
+	if baseDelay <= 0 {
+		baseDelay = DefaultBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxDelay
+	}
+	return workqueue.NewTypedMaxOfRateLimiter[T](
+		NewJitteredExponentialRateLimiter[T](baseDelay, maxDelay),
+		// 10 qps, 100 bucket size, as in workqueue.DefaultTypedControllerRateLimiter
+		&workqueue.TypedBucketRateLimiter[T]{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
+// JitteredExponentialRateLimiter delays the n-th retry of an item by baseDelay*2^n, capped at maxDelay.
+// The delay is jittered between half and the full value, so that items which failed together, such as all
+// RabbitmqClusters referencing a missing Secret, are not retried together.
+type JitteredExponentialRateLimiter[T comparable] struct {
+	mu       sync.Mutex
+	failures map[T]int
+
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+var _ workqueue.TypedRateLimiter[string] = &JitteredExponentialRateLimiter[string]{}
+
+func NewJitteredExponentialRateLimiter[T comparable](baseDelay, maxDelay time.Duration) *JitteredExponentialRateLimiter[T] {
+	return &JitteredExponentialRateLimiter[T]{
+		failures:  map[T]int{},
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+	}
+}
+
+// When returns the delay before the item is retried, and counts the retry.
+func (r *JitteredExponentialRateLimiter[T]) When(item T) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exp := r.failures[item]
+	r.failures[item]++
+	return wait.Jitter(r.backoff(exp)/2, 1)
+}
+
+// backoff returns baseDelay*2^exp, capped at maxDelay.
+func (r *JitteredExponentialRateLimiter[T]) backoff(exp int) time.Duration {
+	backoff := float64(r.baseDelay.Nanoseconds()) * math.Pow(2, float64(exp))
+	if backoff > float64(r.maxDelay.Nanoseconds()) {
+		return r.maxDelay
+	}
+	return time.Duration(backoff)
+}
+
+// NumRequeues returns the number of retries of the item since it was last forgotten.
+func (r *JitteredExponentialRateLimiter[T]) NumRequeues(item T) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.failures[item]
+}
+
+// Forget resets the backoff of the item, once it was reconciled successfully.
+func (r *JitteredExponentialRateLimiter[T]) Forget(item T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.failures, item)
+}
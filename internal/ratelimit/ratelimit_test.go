@@ -0,0 +1,59 @@
+package ratelimit_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/ratelimit"
+)
+
+var _ = Describe("JitteredExponentialRateLimiter", func() {
+	var limiter *ratelimit.JitteredExponentialRateLimiter[string]
+
+	BeforeEach(func() {
+		limiter = ratelimit.NewJitteredExponentialRateLimiter[string](time.Second, 10*time.Second)
+	})
+
+	It("doubles the delay of each retry, jittered between half and the full delay", func() {
+		for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second} {
+			Expect(limiter.When("cluster")).To(SatisfyAll(
+				BeNumerically(">=", expected/2),
+				BeNumerically("<=", expected),
+			))
+		}
+		Expect(limiter.NumRequeues("cluster")).To(Equal(4))
+	})
+
+	It("caps the delay", func() {
+		for i := 0; i < 100; i++ {
+			Expect(limiter.When("cluster")).To(BeNumerically("<=", 10*time.Second))
+		}
+		Expect(limiter.When("cluster")).To(BeNumerically(">=", 5*time.Second))
+	})
+
+	It("tracks items separately", func() {
+		limiter.When("failing")
+		limiter.When("failing")
+		Expect(limiter.When("other")).To(BeNumerically("<=", time.Second))
+		Expect(limiter.NumRequeues("failing")).To(Equal(2))
+	})
+
+	It("resets the delay when the item is forgotten", func() {
+		limiter.When("cluster")
+		limiter.When("cluster")
+		limiter.Forget("cluster")
+		Expect(limiter.NumRequeues("cluster")).To(Equal(0))
+		Expect(limiter.When("cluster")).To(BeNumerically("<=", time.Second))
+	})
+})
+
+var _ = Describe("New", func() {
+	It("uses the default delays when none are configured", func() {
+		limiter := ratelimit.New[string](0, 0)
+		Expect(limiter.When("cluster")).To(SatisfyAll(
+			BeNumerically(">=", ratelimit.DefaultBaseDelay/2),
+			BeNumerically("<=", ratelimit.DefaultBaseDelay),
+		))
+	})
+})
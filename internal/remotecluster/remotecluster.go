@@ -0,0 +1,66 @@
+// Package remotecluster creates the clients of remote Kubernetes clusters from the kubeconfigs of RabbitmqClusters.
+//
+// The kubeconfigs are read from Secrets which the tenants of a namespace can write, so they must not make the operator
+// run commands or read files: only inline credentials and certificates are accepted.
+package remotecluster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// RESTConfig returns the configuration of the current context of a kubeconfig. It returns an error if any cluster or
+// user of the kubeconfig runs a credential plugin or references a file, since these would run with the permissions of
+// the operator.
+func RESTConfig(kubeconfig []byte) (*rest.Config, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+	return clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
+
+func validate(config *clientcmdapi.Config) error {
+	var errs []error
+	for _, name := range sortedKeys(config.Clusters) {
+		if config.Clusters[name].CertificateAuthority != "" {
+			errs = append(errs, fmt.Errorf("cluster %s: certificate-authority is not supported; use certificate-authority-data", name))
+		}
+	}
+	for _, name := range sortedKeys(config.AuthInfos) {
+		user := config.AuthInfos[name]
+		if user.Exec != nil {
+			errs = append(errs, fmt.Errorf("user %s: exec credential plugins are not supported; use token", name))
+		}
+		if user.AuthProvider != nil {
+			errs = append(errs, fmt.Errorf("user %s: auth-provider is not supported; use token", name))
+		}
+		if user.TokenFile != "" {
+			errs = append(errs, fmt.Errorf("user %s: tokenFile is not supported; use token", name))
+		}
+		if user.ClientCertificate != "" {
+			errs = append(errs, fmt.Errorf("user %s: client-certificate is not supported; use client-certificate-data", name))
+		}
+		if user.ClientKey != "" {
+			errs = append(errs, fmt.Errorf("user %s: client-key is not supported; use client-key-data", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
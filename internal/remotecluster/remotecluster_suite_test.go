@@ -0,0 +1,13 @@
+package remotecluster_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRemoteCluster(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Remote Cluster Suite")
+}
@@ -0,0 +1,93 @@
+package remotecluster_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/remotecluster"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var _ = Describe("RESTConfig", func() {
+	var (
+		cluster *clientcmdapi.Cluster
+		user    *clientcmdapi.AuthInfo
+	)
+
+	kubeconfig := func() []byte {
+		config := clientcmdapi.NewConfig()
+		config.Clusters["remote"] = cluster
+		config.AuthInfos["operator"] = user
+		config.Contexts["remote"] = &clientcmdapi.Context{Cluster: "remote", AuthInfo: "operator"}
+		config.CurrentContext = "remote"
+		data, err := clientcmd.Write(*config)
+		Expect(err).NotTo(HaveOccurred())
+		return data
+	}
+
+	BeforeEach(func() {
+		cluster = &clientcmdapi.Cluster{
+			Server:                   "https://remote.example.com:6443",
+			CertificateAuthorityData: []byte("ca"),
+		}
+		user = &clientcmdapi.AuthInfo{Token: "token"}
+	})
+
+	It("accepts inline credentials", func() {
+		config, err := remotecluster.RESTConfig(kubeconfig())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Host).To(Equal("https://remote.example.com:6443"))
+		Expect(config.BearerToken).To(Equal("token"))
+		Expect(config.CAData).To(Equal([]byte("ca")))
+
+		user = &clientcmdapi.AuthInfo{ClientCertificateData: []byte("cert"), ClientKeyData: []byte("key")}
+		config, err = remotecluster.RESTConfig(kubeconfig())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.CertData).To(Equal([]byte("cert")))
+		Expect(config.KeyData).To(Equal([]byte("key")))
+	})
+
+	It("rejects invalid kubeconfigs", func() {
+		_, err := remotecluster.RESTConfig([]byte("clusters: ["))
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("rejects settings which run commands or read files",
+		func(configure func(), msg string) {
+			configure()
+			_, err := remotecluster.RESTConfig(kubeconfig())
+			Expect(err).To(MatchError(ContainSubstring(msg)))
+		},
+		Entry("exec", func() {
+			user.Exec = &clientcmdapi.ExecConfig{Command: "/bin/sh", APIVersion: "client.authentication.k8s.io/v1"}
+		}, "user operator: exec credential plugins are not supported"),
+		Entry("auth-provider", func() {
+			user.AuthProvider = &clientcmdapi.AuthProviderConfig{Name: "oidc"}
+		}, "user operator: auth-provider is not supported"),
+		Entry("tokenFile", func() {
+			user.TokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+		}, "user operator: tokenFile is not supported"),
+		Entry("client-certificate", func() {
+			user.ClientCertificate = "/etc/tls/tls.crt"
+		}, "user operator: client-certificate is not supported"),
+		Entry("client-key", func() {
+			user.ClientKey = "/etc/tls/tls.key"
+		}, "user operator: client-key is not supported"),
+		Entry("certificate-authority", func() {
+			cluster.CertificateAuthority = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+		}, "cluster remote: certificate-authority is not supported"),
+	)
+
+	It("rejects settings of users and clusters outside the current context", func() {
+		config := clientcmdapi.NewConfig()
+		config.Clusters["remote"] = cluster
+		config.AuthInfos["operator"] = user
+		config.AuthInfos["other"] = &clientcmdapi.AuthInfo{TokenFile: "/etc/token"}
+		config.Contexts["remote"] = &clientcmdapi.Context{Cluster: "remote", AuthInfo: "operator"}
+		config.CurrentContext = "remote"
+		data, err := clientcmd.Write(*config)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = remotecluster.RESTConfig(data)
+		Expect(err).To(MatchError(ContainSubstring("user other: tokenFile is not supported")))
+	})
+})
@@ -20,7 +20,7 @@ import (
 
 	"gopkg.in/ini.v1"
 
-	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,13 +28,18 @@ import (
 
 const (
 	ServerConfigMapName = "server-conf"
-	defaultRabbitmqConf = `
-queue_master_locator = min-masters
+	// Keys of the server ConfigMap which are mounted into /etc/rabbitmq/conf.d
+	OperatorDefaultsConfKey         = "operatorDefaults.conf"
+	TLSConfKey                      = "tls.conf"
+	PluginsConfKey                  = "plugins.conf"
+	UserDefinedConfigurationConfKey = "userDefinedConfiguration.conf"
+	ConfigFromConfKey               = "configFrom.conf"
+	defaultRabbitmqConf             = `
+queue_leader_locator = balanced
 disk_free_limit.absolute = 2GB
 cluster_partition_handling = pause_minority
 cluster_formation.peer_discovery_backend = rabbit_peer_discovery_k8s
-cluster_formation.k8s.host = kubernetes.default
-cluster_formation.k8s.address_type = hostname`
+cluster_formation.k8s.host = kubernetes.default`
 
 	defaultTLSConf = `
 ssl_options.certfile = /etc/rabbitmq-tls/tls.crt
@@ -49,13 +54,43 @@ prometheus.ssl.certfile  = /etc/rabbitmq-tls/tls.crt
 prometheus.ssl.keyfile   = /etc/rabbitmq-tls/tls.key
 prometheus.ssl.port      = 15691
 `
-	tlsCertDir      = "/etc/rabbitmq-tls/"
-	caCertFilename  = "ca.crt"
-	caCertPath      = tlsCertDir + caCertFilename
-	tlsCertFilename = "tls.crt"
-	tlsCertPath     = tlsCertDir + tlsCertFilename
-	tlsKeyFilename  = "tls.key"
-	tlsKeyPath      = tlsCertDir + tlsKeyFilename
+	oauth2Dir            = "/etc/rabbitmq-oauth2/"
+	oauth2SigningKeyPath = oauth2Dir + "signing-key.pem"
+	definitionsDir       = "/etc/rabbitmq-definitions/"
+	definitionsPath      = definitionsDir + "definitions.json"
+	tlsCertDir           = "/etc/rabbitmq-tls/"
+	caCertFilename       = "ca.crt"
+	caCertPath           = tlsCertDir + caCertFilename
+	tlsCertFilename      = "tls.crt"
+	tlsCertPath          = tlsCertDir + tlsCertFilename
+	tlsKeyFilename       = "tls.key"
+	tlsKeyPath           = tlsCertDir + tlsKeyFilename
+
+	InterNodeTLSConfigKey  = "inter_node_tls.config"
+	interNodeTLSConfigPath = "/etc/rabbitmq/" + InterNodeTLSConfigKey
+	distributionTLSDir     = "/etc/rabbitmq-distribution-tls/"
+	// interNodeTLSConfig is passed to the Erlang VM with -ssl_dist_optfile to configure TLS of the Erlang distribution
+	interNodeTLSConfig = `[
+  {server, [
+    {cacertfile, "/etc/rabbitmq-distribution-tls/ca.crt"},
+    {certfile,   "/etc/rabbitmq-distribution-tls/tls.crt"},
+    {keyfile,    "/etc/rabbitmq-distribution-tls/tls.key"},
+    {secure_renegotiate, true},
+    {fail_if_no_peer_cert, true},
+    {verify, verify_peer}
+  ]},
+  {client, [
+    {cacertfile, "/etc/rabbitmq-distribution-tls/ca.crt"},
+    {certfile,   "/etc/rabbitmq-distribution-tls/tls.crt"},
+    {keyfile,    "/etc/rabbitmq-distribution-tls/tls.key"},
+    {secure_renegotiate, true},
+    {verify, verify_peer},
+    {customize_hostname_check, [
+      {match_fun, public_key:pkix_verify_hostname_match_fun(https)}
+    ]}
+  ]}
+].
+`
 )
 
 type ServerConfigMapBuilder struct {
@@ -72,8 +107,8 @@ func (builder *ServerConfigMapBuilder) Build() (client.Object, error) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        builder.Instance.ChildResourceName(ServerConfigMapName),
 			Namespace:   builder.Instance.Namespace,
-			Labels:      metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels),
-			Annotations: metadata.ReconcileAndFilterAnnotations(nil, builder.Instance.Annotations),
+			Labels:      builder.childLabels(),
+			Annotations: builder.childAnnotations(nil),
 		},
 	}, nil
 }
@@ -93,177 +128,173 @@ func (builder *ServerConfigMapBuilder) Update(object client.Object) error {
 	}
 	defaultSection := operatorConfiguration.Section("")
 
-	if _, err := defaultSection.NewKey("cluster_formation.target_cluster_size_hint", strconv.Itoa(int(*builder.Instance.Spec.Replicas))); err != nil {
+	peerDiscovery := builder.Instance.Spec.Rabbitmq.PeerDiscovery
+	addressType := "hostname"
+	if builder.Instance.PeerDiscoveryByIP() {
+		addressType = "ip"
+	}
+	if _, err := defaultSection.NewKey("cluster_formation.k8s.address_type", addressType); err != nil {
+		return err
+	}
+	// the randomized startup delay is not configured: RabbitMQ only applies it to backends which cannot serialize
+	// cluster formation, and the k8s backend does so with a lock
+	if _, err := defaultSection.NewKey("cluster_formation.k8s.service_name", builder.Instance.ChildResourceName(headlessServiceSuffix)); err != nil {
 		return err
 	}
 
-	if _, err := defaultSection.NewKey("cluster_name", builder.Instance.Name); err != nil {
+	if _, err := defaultSection.NewKey("cluster_formation.target_cluster_size_hint", strconv.Itoa(int(*builder.Instance.Spec.Replicas))); err != nil {
 		return err
 	}
 
-	rmqProperties := builder.Instance.Spec.Rabbitmq
-	authMechsConfigured, err := areAuthMechanismsConfigued(rmqProperties.AdditionalConfig)
-	if err != nil {
+	if _, err := defaultSection.NewKey("cluster_name", builder.Instance.Name); err != nil {
 		return err
 	}
-	// By default, RabbitMQ configures the following SASL mechanisms:
-	// auth_mechanisms.1 = PLAIN
-	// auth_mechanisms.2 = AMQPLAIN
-	// auth_mechanisms.3 = ANONYMOUS
-	if !authMechsConfigured {
-		// Since the user didn't explicitly configure auth mechanisms, we disable
-		// ANONYMOUS logins because they should be disabled in production.
-		if _, err := defaultSection.NewKey("auth_mechanisms.1", "PLAIN"); err != nil {
+
+	if builder.Instance.Spec.Rabbitmq.Definitions != nil {
+		if _, err := defaultSection.NewKey("load_definitions", definitionsPath); err != nil {
 			return err
 		}
-		if _, err := defaultSection.NewKey("auth_mechanisms.2", "AMQPLAIN"); err != nil {
+	}
+
+	if queueLeaderLocator := builder.Instance.Spec.Rabbitmq.QueueLeaderLocator; queueLeaderLocator != "" {
+		defaultSection.Key("queue_leader_locator").SetValue(queueLeaderLocator)
+	}
+
+	if defaultQueueType := builder.Instance.Spec.Rabbitmq.DefaultQueueType; defaultQueueType != "" {
+		if _, err := defaultSection.NewKey("default_queue_type", defaultQueueType); err != nil {
 			return err
 		}
 	}
 
-	userConfiguration := ini.Empty()
-	userConfigurationSection := userConfiguration.Section("")
-
-	if builder.Instance.TLSEnabled() {
-		if err := userConfiguration.Append([]byte(defaultTLSConf)); err != nil {
+	// RabbitMQ only tags the default user with administrator if no tag is configured
+	for _, tag := range builder.Instance.Spec.Rabbitmq.DefaultUserTags {
+		if _, err := defaultSection.NewKey(fmt.Sprintf("default_user_tags.%s", tag), "true"); err != nil {
 			return err
 		}
-		if builder.Instance.DisableNonTLSListeners() {
-			if _, err := userConfigurationSection.NewKey("listeners.tcp", "none"); err != nil {
-				return err
-			}
-		} else {
-			// management plugin does not have a *.listeners.tcp settings like other plugins
-			// management tcp listener can be disabled by setting management.ssl.port without setting management.tcp.port
-			// we set management tcp listener only if tls is enabled and disableNonTLSListeners is false
-			if _, err := userConfigurationSection.NewKey("management.tcp.port", "15672"); err != nil {
-				return err
-			}
+	}
 
-			if _, err := userConfigurationSection.NewKey("prometheus.tcp.port", "15692"); err != nil {
-				return err
-			}
+	if peerDiscovery.DiscoveryRetryLimit != nil {
+		if _, err := defaultSection.NewKey("cluster_formation.discovery_retry_limit", strconv.Itoa(int(*peerDiscovery.DiscoveryRetryLimit))); err != nil {
+			return err
 		}
-		if builder.Instance.AdditionalPluginEnabled("rabbitmq_mqtt") {
-			if _, err := userConfigurationSection.NewKey("mqtt.listeners.ssl.default", "8883"); err != nil {
-				return err
-			}
-			if builder.Instance.DisableNonTLSListeners() {
-				if _, err := userConfigurationSection.NewKey("mqtt.listeners.tcp", "none"); err != nil {
-					return err
-				}
-			}
+	}
+	if peerDiscovery.DiscoveryRetryIntervalMilliseconds != nil {
+		if _, err := defaultSection.NewKey("cluster_formation.discovery_retry_interval", strconv.Itoa(int(*peerDiscovery.DiscoveryRetryIntervalMilliseconds))); err != nil {
+			return err
 		}
-		if builder.Instance.AdditionalPluginEnabled("rabbitmq_stomp") {
-			if _, err := userConfigurationSection.NewKey("stomp.listeners.ssl.1", "61614"); err != nil {
-				return err
-			}
-			if builder.Instance.DisableNonTLSListeners() {
-				if _, err := userConfigurationSection.NewKey("stomp.listeners.tcp", "none"); err != nil {
-					return err
-				}
-			}
+	}
+
+	if builder.Instance.IPv6Enabled() {
+		// management and prometheus listeners bind to 0.0.0.0 by default
+		// binding to :: accepts both IPv6 and IPv4 connections
+		listenerIPs := []string{"management.tcp.ip", "prometheus.tcp.ip"}
+		if builder.Instance.TLSEnabled() {
+			listenerIPs = append(listenerIPs, "management.ssl.ip", "prometheus.ssl.ip")
 		}
-		if builder.Instance.AdditionalPluginEnabled("rabbitmq_stream") {
-			if _, err := userConfigurationSection.NewKey("stream.listeners.ssl.default", "5551"); err != nil {
+		for _, key := range listenerIPs {
+			if _, err := defaultSection.NewKey(key, "::"); err != nil {
 				return err
 			}
-			if builder.Instance.DisableNonTLSListeners() {
-				if _, err := userConfigurationSection.NewKey("stream.listeners.tcp", "none"); err != nil {
-					return err
-				}
-			}
 		}
 	}
 
-	if builder.Instance.MutualTLSEnabled() {
-		if _, err := userConfigurationSection.NewKey("ssl_options.cacertfile", caCertPath); err != nil {
+	rmqProperties := builder.Instance.Spec.Rabbitmq
+	authMechsConfigured, err := areAuthMechanismsConfigued(rmqProperties.AdditionalConfig)
+	if err != nil {
+		return err
+	}
+	// By default, RabbitMQ configures the following SASL mechanisms:
+	// auth_mechanisms.1 = PLAIN
+	// auth_mechanisms.2 = AMQPLAIN
+	// auth_mechanisms.3 = ANONYMOUS
+	if !authMechsConfigured {
+		// Since the user didn't explicitly configure auth mechanisms, we disable
+		// ANONYMOUS logins because they should be disabled in production.
+		if _, err := defaultSection.NewKey("auth_mechanisms.1", "PLAIN"); err != nil {
 			return err
 		}
-		if _, err := userConfigurationSection.NewKey("ssl_options.verify", "verify_peer"); err != nil {
+		if _, err := defaultSection.NewKey("auth_mechanisms.2", "AMQPLAIN"); err != nil {
 			return err
 		}
+	}
 
-		if _, err := userConfigurationSection.NewKey("management.ssl.cacertfile", caCertPath); err != nil {
+	if builder.Instance.MemoryLimited() {
+		if _, err := defaultSection.NewKey("total_memory_available_override_value", fmt.Sprintf("%d", removeHeadroom(builder.Instance.Spec.Resources.Limits.Memory().Value()))); err != nil {
 			return err
 		}
+	}
 
-		if _, err := userConfigurationSection.NewKey("prometheus.ssl.cacertfile", caCertPath); err != nil {
-			return err
-		}
+	if err := setResourceAlarmThreshold(defaultSection, "disk_free_limit", builder.Instance.Spec.Rabbitmq.DiskFreeLimit, 0); err != nil {
+		return err
+	}
+	if err := setResourceAlarmThreshold(defaultSection, "vm_memory_high_watermark", builder.Instance.Spec.Rabbitmq.MemoryHighWatermark, 1); err != nil {
+		return err
+	}
 
-		if builder.Instance.AdditionalPluginEnabled("rabbitmq_web_mqtt") {
-			if _, err := userConfigurationSection.NewKey("web_mqtt.ssl.port", "15676"); err != nil {
-				return err
-			}
-			if _, err := userConfigurationSection.NewKey("web_mqtt.ssl.cacertfile", caCertPath); err != nil {
-				return err
-			}
-			if _, err := userConfigurationSection.NewKey("web_mqtt.ssl.certfile", tlsCertPath); err != nil {
-				return err
-			}
-			if _, err := userConfigurationSection.NewKey("web_mqtt.ssl.keyfile", tlsKeyPath); err != nil {
-				return err
-			}
-			if builder.Instance.DisableNonTLSListeners() {
-				if _, err := userConfigurationSection.NewKey("web_mqtt.tcp.listener", "none"); err != nil {
-					return err
-				}
-			}
-		}
-		if builder.Instance.AdditionalPluginEnabled("rabbitmq_web_stomp") {
-			if _, err := userConfigurationSection.NewKey("web_stomp.ssl.port", "15673"); err != nil {
-				return err
-			}
-			if _, err := userConfigurationSection.NewKey("web_stomp.ssl.cacertfile", caCertPath); err != nil {
-				return err
-			}
-			if _, err := userConfigurationSection.NewKey("web_stomp.ssl.certfile", tlsCertPath); err != nil {
-				return err
-			}
-			if _, err := userConfigurationSection.NewKey("web_stomp.ssl.keyfile", tlsKeyPath); err != nil {
-				return err
-			}
-			if builder.Instance.DisableNonTLSListeners() {
-				if _, err := userConfigurationSection.NewKey("web_stomp.tcp.listener", "none"); err != nil {
-					return err
-				}
-			}
-		}
+	if err := newKeys(defaultSection, builder.loggingSettings()); err != nil {
+		return err
+	}
+	if err := newKeys(defaultSection, builder.metricsSettings()); err != nil {
+		return err
 	}
 
-	if builder.Instance.MemoryLimited() {
-		if _, err := userConfigurationSection.NewKey("total_memory_available_override_value", fmt.Sprintf("%d", removeHeadroom(builder.Instance.Spec.Resources.Limits.Memory().Value()))); err != nil {
-			return err
-		}
+	tlsConfiguration, err := builder.tlsConfiguration()
+	if err != nil {
+		return err
 	}
 
-	var rmqConfBuffer strings.Builder
-	if _, err := operatorConfiguration.WriteTo(&rmqConfBuffer); err != nil {
+	pluginsConfiguration, err := builder.pluginsConfiguration()
+	if err != nil {
 		return err
 	}
 
+	userConfiguration, err := ini.Load([]byte(rmqProperties.AdditionalConfig))
+	if err != nil {
+		return fmt.Errorf("failed to load spec.rabbitmq.additionalConfig: %w", err)
+	}
+
 	if configMap.Data == nil {
 		configMap.Data = make(map[string]string)
 	}
 
-	configMap.Data["operatorDefaults.conf"] = rmqConfBuffer.String()
-
-	rmqConfBuffer.Reset()
-
-	if err := userConfiguration.Append([]byte(rmqProperties.AdditionalConfig)); err != nil {
-		return fmt.Errorf("failed to append spec.rabbitmq.additionalConfig: %w", err)
+	if builder.Instance.Spec.Rabbitmq.ConfigFrom != nil {
+		// the key is mounted whenever configFrom is set, so it must exist even if the referenced ConfigMap key is empty
+		configFromConfiguration, err := ini.Load([]byte(builder.ConfigFrom))
+		if err != nil {
+			return fmt.Errorf("failed to load spec.rabbitmq.configFrom: %w", err)
+		}
+		var configFromBuffer strings.Builder
+		if _, err := configFromConfiguration.WriteTo(&configFromBuffer); err != nil {
+			return err
+		}
+		configMap.Data[ConfigFromConfKey] = configFromBuffer.String()
+	} else {
+		delete(configMap.Data, ConfigFromConfKey)
 	}
 
-	if _, err := userConfiguration.WriteTo(&rmqConfBuffer); err != nil {
-		return err
+	// Each file is mounted into conf.d, where RabbitMQ loads them in alphabetical order of their mount paths.
+	// Settings in files loaded later override settings in files loaded earlier.
+	for key, conf := range map[string]*ini.File{
+		OperatorDefaultsConfKey:         operatorConfiguration,
+		TLSConfKey:                      tlsConfiguration,
+		PluginsConfKey:                  pluginsConfiguration,
+		UserDefinedConfigurationConfKey: userConfiguration,
+	} {
+		var rmqConfBuffer strings.Builder
+		if _, err := conf.WriteTo(&rmqConfBuffer); err != nil {
+			return err
+		}
+		configMap.Data[key] = rmqConfBuffer.String()
 	}
 
-	configMap.Data["userDefinedConfiguration.conf"] = rmqConfBuffer.String()
-
 	updateProperty(configMap.Data, "advanced.config", rmqProperties.AdvancedConfig)
 	updateProperty(configMap.Data, "rabbitmq-env.conf", rmqProperties.EnvConfig)
 	updateProperty(configMap.Data, "erl_inetrc", rmqProperties.ErlangInetConfig)
+	if builder.Instance.InterNodeTLSEnabled() {
+		configMap.Data[InterNodeTLSConfigKey] = interNodeTLSConfig
+	} else {
+		delete(configMap.Data, InterNodeTLSConfigKey)
+	}
 
 	if err := controllerutil.SetControllerReference(builder.Instance, configMap, builder.Scheme); err != nil {
 		return fmt.Errorf("failed setting controller reference: %w", err)
@@ -276,18 +307,221 @@ func (builder *ServerConfigMapBuilder) Update(object client.Object) error {
 	if err := removeConfigNotRequiringNodeRestart(updatedConfigMap); err != nil {
 		return err
 	}
-	if equality.Semantic.DeepEqual(previousConfigMap, updatedConfigMap) {
+	if equality.Semantic.DeepEqual(previousConfigMap.Data, updatedConfigMap.Data) {
 		builder.UpdateRequiresStsRestart = false
 	}
 
 	return nil
 }
 
+// Preserve keeps the data of the existing ConfigMap, so that Update can tell whether the change requires a restart.
+func (builder *ServerConfigMapBuilder) Preserve(existing, desired client.Object) {
+	existingData := existing.(*corev1.ConfigMap).Data
+	if existingData == nil {
+		return
+	}
+	configMap := desired.(*corev1.ConfigMap)
+	configMap.Data = make(map[string]string, len(existingData))
+	for key, value := range existingData {
+		configMap.Data[key] = value
+	}
+}
+
+// tlsConfiguration returns the listener and certificate settings of RabbitMQ, the management plugin and the prometheus plugin.
+func (builder *ServerConfigMapBuilder) tlsConfiguration() (*ini.File, error) {
+	tlsConfiguration := ini.Empty()
+	if !builder.Instance.TLSEnabled() {
+		return tlsConfiguration, nil
+	}
+	if err := tlsConfiguration.Append([]byte(defaultTLSConf)); err != nil {
+		return nil, err
+	}
+
+	settings := [][2]string{}
+	if builder.Instance.DisableNonTLSListeners() {
+		settings = append(settings, [2]string{"listeners.tcp", "none"})
+	} else {
+		// management plugin does not have a *.listeners.tcp settings like other plugins
+		// management tcp listener can be disabled by setting management.ssl.port without setting management.tcp.port
+		// we set management tcp listener only if tls is enabled and disableNonTLSListeners is false
+		settings = append(settings,
+			[2]string{"management.tcp.port", "15672"},
+			[2]string{"prometheus.tcp.port", "15692"},
+		)
+	}
+	if builder.Instance.MutualTLSEnabled() {
+		settings = append(settings,
+			[2]string{"ssl_options.cacertfile", caCertPath},
+			[2]string{"ssl_options.verify", builder.Instance.TLSPeerVerification()},
+			[2]string{"management.ssl.cacertfile", caCertPath},
+			[2]string{"prometheus.ssl.cacertfile", caCertPath},
+		)
+		if builder.Instance.Spec.TLS.FailIfNoPeerCert {
+			settings = append(settings, [2]string{"ssl_options.fail_if_no_peer_cert", "true"})
+		}
+	}
+	return tlsConfiguration, newKeys(tlsConfiguration.Section(""), settings)
+}
+
+// pluginsConfiguration returns the TLS listener settings of the additional plugins.
+func (builder *ServerConfigMapBuilder) pluginsConfiguration() (*ini.File, error) {
+	pluginsConfiguration := ini.Empty()
+	settings := append(builder.oauth2Settings(), builder.messagingPluginSettings()...)
+	if !builder.Instance.TLSEnabled() {
+		return pluginsConfiguration, newKeys(pluginsConfiguration.Section(""), settings)
+	}
+
+	disableNonTLSListeners := builder.Instance.DisableNonTLSListeners()
+	for _, listener := range []struct {
+		plugin                  rabbitmqv1beta1.Plugin
+		sslKey, sslPort, tcpKey string
+	}{
+		{"rabbitmq_mqtt", "mqtt.listeners.ssl.default", "8883", "mqtt.listeners.tcp"},
+		{"rabbitmq_stomp", "stomp.listeners.ssl.1", "61614", "stomp.listeners.tcp"},
+		{"rabbitmq_stream", "stream.listeners.ssl.default", "5551", "stream.listeners.tcp"},
+	} {
+		if !builder.Instance.AdditionalPluginEnabled(listener.plugin) {
+			continue
+		}
+		settings = append(settings, [2]string{listener.sslKey, listener.sslPort})
+		if disableNonTLSListeners {
+			settings = append(settings, [2]string{listener.tcpKey, "none"})
+		}
+	}
+
+	if builder.Instance.MutualTLSEnabled() {
+		for _, listener := range []struct {
+			plugin          rabbitmqv1beta1.Plugin
+			prefix, sslPort string
+		}{
+			{"rabbitmq_web_mqtt", "web_mqtt", "15676"},
+			{"rabbitmq_web_stomp", "web_stomp", "15673"},
+		} {
+			if !builder.Instance.AdditionalPluginEnabled(listener.plugin) {
+				continue
+			}
+			settings = append(settings,
+				[2]string{listener.prefix + ".ssl.port", listener.sslPort},
+				[2]string{listener.prefix + ".ssl.cacertfile", caCertPath},
+				[2]string{listener.prefix + ".ssl.certfile", tlsCertPath},
+				[2]string{listener.prefix + ".ssl.keyfile", tlsKeyPath},
+			)
+			if disableNonTLSListeners {
+				settings = append(settings, [2]string{listener.prefix + ".tcp.listener", "none"})
+			}
+		}
+	}
+	return pluginsConfiguration, newKeys(pluginsConfiguration.Section(""), settings)
+}
+
+// messagingPluginSettings returns the settings of spec.rabbitmq.mqtt and spec.rabbitmq.stomp.
+func (builder *ServerConfigMapBuilder) messagingPluginSettings() [][2]string {
+	var settings [][2]string
+	if mqtt := builder.Instance.Spec.Rabbitmq.MQTT; mqtt != nil {
+		if mqtt.VHost != "" {
+			settings = append(settings, [2]string{"mqtt.vhost", mqtt.VHost})
+		}
+		if mqtt.Exchange != "" {
+			settings = append(settings, [2]string{"mqtt.exchange", mqtt.Exchange})
+		}
+	}
+	if stomp := builder.Instance.Spec.Rabbitmq.STOMP; stomp != nil && stomp.VHost != "" {
+		settings = append(settings, [2]string{"stomp.default_vhost", stomp.VHost})
+	}
+	return settings
+}
+
+// loggingSettings returns the settings of the console log. Logs are formatted as JSON unless plaintext is requested.
+func (builder *ServerConfigMapBuilder) loggingSettings() [][2]string {
+	logging := builder.Instance.Spec.Rabbitmq.Logging
+	settings := [][2]string{{"log.console", "true"}}
+	if logging.Level != "" {
+		settings = append(settings, [2]string{"log.console.level", string(logging.Level)})
+	}
+	if logging.Format != "plaintext" {
+		settings = append(settings, [2]string{"log.console.formatter", "json"})
+	}
+	for _, category := range logging.Categories {
+		settings = append(settings, [2]string{fmt.Sprintf("log.%s.level", category.Name), string(category.Level)})
+	}
+	return settings
+}
+
+// metricsSettings returns the settings of the Prometheus endpoint. RabbitMQ defaults are not written,
+// so that the configuration of existing clusters does not change.
+func (builder *ServerConfigMapBuilder) metricsSettings() [][2]string {
+	metrics := builder.Instance.Spec.Rabbitmq.Metrics
+	var settings [][2]string
+	if metrics.PerObject {
+		settings = append(settings, [2]string{"prometheus.return_per_object_metrics", "true"})
+	}
+	if metrics.Path != "" {
+		settings = append(settings, [2]string{"prometheus.path", metrics.Path})
+	}
+	return settings
+}
+
+// oauth2Settings returns the settings of the rabbitmq_auth_backend_oauth2 plugin.
+func (builder *ServerConfigMapBuilder) oauth2Settings() [][2]string {
+	if !builder.Instance.OAuth2Enabled() {
+		return nil
+	}
+	oauth2 := builder.Instance.Spec.Rabbitmq.Auth.OAuth2
+	settings := [][2]string{
+		{"auth_backends.1", "rabbit_auth_backend_oauth2"},
+		{"auth_backends.2", "rabbit_auth_backend_internal"},
+		{"auth_oauth2.resource_server_id", oauth2.ResourceServerID},
+	}
+	optionalSettings := [][2]string{
+		{"auth_oauth2.issuer", oauth2.IssuerURL},
+		{"auth_oauth2.jwks_url", oauth2.JWKSURL},
+		{"auth_oauth2.additional_scopes_key", oauth2.AdditionalScopesKey},
+	}
+	if oauth2.ScopePrefix != nil {
+		settings = append(settings, [2]string{"auth_oauth2.scope_prefix", *oauth2.ScopePrefix})
+	}
+	if oauth2.SigningKey != nil {
+		keyID := oauth2.SigningKey.KeyID
+		if keyID == "" {
+			keyID = "default"
+		}
+		settings = append(settings,
+			[2]string{"auth_oauth2.default_key", keyID},
+			[2]string{"auth_oauth2.signing_keys." + keyID, oauth2SigningKeyPath},
+		)
+	}
+	if management := oauth2.Management; management != nil {
+		settings = append(settings,
+			[2]string{"management.oauth_enabled", "true"},
+			[2]string{"management.oauth_client_id", management.ClientID},
+		)
+		optionalSettings = append(optionalSettings,
+			[2]string{"management.oauth_provider_url", management.ProviderURL},
+			[2]string{"management.oauth_scopes", management.Scopes},
+		)
+	}
+	for _, setting := range optionalSettings {
+		if setting[1] != "" {
+			settings = append(settings, setting)
+		}
+	}
+	return settings
+}
+
+func newKeys(section *ini.Section, settings [][2]string) error {
+	for _, setting := range settings {
+		if _, err := section.NewKey(setting[0], setting[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // removeConfigNotRequiringNodeRestart removes configuration data that does not require a restart of RabbitMQ nodes.
 // For example, the target cluster size hint changes after adding nodes to a cluster, but there's no reason
-// to restart already running nodes.
+// to restart already running nodes. Similarly, the default user tags only apply when the default user is created.
 func removeConfigNotRequiringNodeRestart(configMap *corev1.ConfigMap) error {
-	operatorConf := configMap.Data["operatorDefaults.conf"]
+	operatorConf := configMap.Data[OperatorDefaultsConfKey]
 	if operatorConf == "" {
 		return nil
 	}
@@ -297,7 +531,7 @@ func removeConfigNotRequiringNodeRestart(configMap *corev1.ConfigMap) error {
 	}
 	defaultSection := conf.Section("")
 	for _, key := range defaultSection.KeyStrings() {
-		if strings.HasPrefix(key, "cluster_formation.target_cluster_size_hint") {
+		if strings.HasPrefix(key, "cluster_formation.target_cluster_size_hint") || strings.HasPrefix(key, "default_user_tags.") {
 			defaultSection.DeleteKey(key)
 		}
 	}
@@ -305,7 +539,7 @@ func removeConfigNotRequiringNodeRestart(configMap *corev1.ConfigMap) error {
 	if _, err := conf.WriteTo(&b); err != nil {
 		return fmt.Errorf("failed to write operatorDefaults.conf when deciding whether to restart STS: %w", err)
 	}
-	configMap.Data["operatorDefaults.conf"] = b.String()
+	configMap.Data[OperatorDefaultsConfKey] = b.String()
 	return nil
 }
 
@@ -317,6 +551,34 @@ func updateProperty(configMapData map[string]string, key string, value string) {
 	}
 }
 
+// setResourceAlarmThreshold replaces the default threshold of a resource alarm with the configured one.
+// Thresholds which the webhook rejects, e.g. of RabbitmqClusters created before it validated them, are ignored,
+// so that RabbitMQ keeps the default threshold.
+func setResourceAlarmThreshold(section *ini.Section, key string, threshold *rabbitmqv1beta1.ResourceAlarmThreshold, maxRelative float64) error {
+	if threshold == nil {
+		return nil
+	}
+	var name, value string
+	switch {
+	case threshold.Absolute != nil && threshold.Relative == nil && threshold.Absolute.Sign() > 0:
+		name, value = key+".absolute", strconv.FormatInt(threshold.Absolute.Value(), 10)
+	case threshold.Relative != nil && threshold.Absolute == nil:
+		relative := threshold.Relative.AsApproximateFloat64()
+		if relative <= 0 || (maxRelative != 0 && relative > maxRelative) {
+			return nil
+		}
+		relativeDec := threshold.Relative.DeepCopy()
+		name, value = key+".relative", relativeDec.AsDec().String()
+	default:
+		return nil
+	}
+
+	section.DeleteKey(key + ".absolute")
+	section.DeleteKey(key + ".relative")
+	_, err := section.NewKey(name, value)
+	return err
+}
+
 // The Erlang VM needs headroom above Rabbit to avoid being OOM killed
 // We set the headroom to be the smaller amount of 20% memory or 2GiB
 func removeHeadroom(memLimit int64) int64 {
@@ -29,16 +29,19 @@ import (
 
 func defaultRabbitmqConf(instanceName string) string {
 	return iniString(`
-queue_master_locator                       = min-masters
+queue_leader_locator                       = balanced
 disk_free_limit.absolute                   = 2GB
 cluster_partition_handling                 = pause_minority
 cluster_formation.peer_discovery_backend   = rabbit_peer_discovery_k8s
 cluster_formation.k8s.host                 = kubernetes.default
 cluster_formation.k8s.address_type         = hostname
+cluster_formation.k8s.service_name         = ` + instanceName + `-nodes
 cluster_formation.target_cluster_size_hint = 1
 cluster_name                               = ` + instanceName + `
 auth_mechanisms.1                          = PLAIN
 auth_mechanisms.2                          = AMQPLAIN
+log.console                                = true
+log.console.formatter                      = json
 `)
 }
 
@@ -159,6 +162,258 @@ var _ = Describe("GenerateServerConfigMap", func() {
 			Expect(operatorDefaultConf.Section("").KeysHash()).To(HaveKeyWithValue("cluster_formation.target_cluster_size_hint", "100"))
 		})
 
+		It("sets the default queue type when configured", func() {
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			Expect(configMap.Data["operatorDefaults.conf"]).NotTo(ContainSubstring("default_queue_type"))
+
+			builder.Instance.Spec.Rabbitmq.DefaultQueueType = "quorum"
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			operatorDefaultConf, err := ini.Load([]byte(configMap.Data["operatorDefaults.conf"]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operatorDefaultConf.Section("").KeysHash()).To(HaveKeyWithValue("default_queue_type", "quorum"))
+		})
+
+		It("sets the default user tags when configured", func() {
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			Expect(configMap.Data["operatorDefaults.conf"]).NotTo(ContainSubstring("default_user_tags"))
+
+			builder.Instance.Spec.Rabbitmq.DefaultUserTags = []rabbitmqv1beta1.UserTag{"monitoring", "management"}
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			operatorDefaultConf, err := ini.Load([]byte(configMap.Data["operatorDefaults.conf"]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operatorDefaultConf.Section("").KeysHash()).To(HaveKeyWithValue("default_user_tags.monitoring", "true"))
+			Expect(operatorDefaultConf.Section("").KeysHash()).To(HaveKeyWithValue("default_user_tags.management", "true"))
+			Expect(operatorDefaultConf.Section("").KeysHash()).NotTo(HaveKey("default_user_tags.administrator"))
+		})
+
+		It("loads definitions when configured", func() {
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			Expect(configMap.Data["operatorDefaults.conf"]).NotTo(ContainSubstring("load_definitions"))
+
+			builder.Instance.Spec.Rabbitmq.Definitions = &rabbitmqv1beta1.DefinitionsSource{
+				ConfigMapRef: &corev1.LocalObjectReference{Name: "definitions"},
+			}
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			operatorDefaultConf, err := ini.Load([]byte(configMap.Data["operatorDefaults.conf"]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operatorDefaultConf.Section("").KeysHash()).To(HaveKeyWithValue("load_definitions", "/etc/rabbitmq-definitions/definitions.json"))
+		})
+
+		It("configures MQTT and STOMP", func() {
+			builder.Instance.Spec.Rabbitmq.MQTT = &rabbitmqv1beta1.MQTTSpec{VHost: "iot", Exchange: "sensors"}
+			builder.Instance.Spec.Rabbitmq.STOMP = &rabbitmqv1beta1.STOMPSpec{VHost: "web"}
+
+			expectedConfiguration := iniString(`mqtt.vhost = iot
+				mqtt.exchange = sensors
+				stomp.default_vhost = web`)
+
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("plugins.conf", expectedConfiguration))
+		})
+
+		It("configures the queue leader locator", func() {
+			builder.Instance.Spec.Rabbitmq.QueueLeaderLocator = "client-local"
+
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			Expect(configMap.Data["operatorDefaults.conf"]).To(HavePrefix("queue_leader_locator                       = client-local\n"))
+		})
+
+		It("configures the console log", func() {
+			builder.Instance.Spec.Rabbitmq.Logging = rabbitmqv1beta1.RabbitmqClusterLoggingSpec{
+				Level:  "warning",
+				Format: "plaintext",
+				Categories: []rabbitmqv1beta1.LogCategory{
+					{Name: "connection", Level: "error"},
+					{Name: "queue", Level: "debug"},
+				},
+			}
+
+			expectedConfiguration := iniString(`
+queue_leader_locator                       = balanced
+disk_free_limit.absolute                   = 2GB
+cluster_partition_handling                 = pause_minority
+cluster_formation.peer_discovery_backend   = rabbit_peer_discovery_k8s
+cluster_formation.k8s.host                 = kubernetes.default
+cluster_formation.k8s.address_type         = hostname
+cluster_formation.k8s.service_name         = foo-nodes
+cluster_formation.target_cluster_size_hint = 1
+cluster_name                               = foo
+auth_mechanisms.1                          = PLAIN
+auth_mechanisms.2                          = AMQPLAIN
+log.console                                = true
+log.console.level                          = warning
+log.connection.level                       = error
+log.queue.level                            = debug
+`)
+
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("operatorDefaults.conf", expectedConfiguration))
+		})
+
+		It("configures the Prometheus endpoint", func() {
+			builder.Instance.Spec.Rabbitmq.Metrics = rabbitmqv1beta1.RabbitmqClusterMetricsSpec{
+				PerObject: true,
+				Path:      "/rabbitmq/metrics",
+			}
+
+			expectedConfiguration := iniString(`
+queue_leader_locator                       = balanced
+disk_free_limit.absolute                   = 2GB
+cluster_partition_handling                 = pause_minority
+cluster_formation.peer_discovery_backend   = rabbit_peer_discovery_k8s
+cluster_formation.k8s.host                 = kubernetes.default
+cluster_formation.k8s.address_type         = hostname
+cluster_formation.k8s.service_name         = foo-nodes
+cluster_formation.target_cluster_size_hint = 1
+cluster_name                               = foo
+auth_mechanisms.1                          = PLAIN
+auth_mechanisms.2                          = AMQPLAIN
+log.console                                = true
+log.console.formatter                      = json
+prometheus.return_per_object_metrics       = true
+prometheus.path                            = /rabbitmq/metrics
+`)
+
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("operatorDefaults.conf", expectedConfiguration))
+		})
+
+		Context("OAuth2", func() {
+			It("configures the OAuth2 backend with the internal backend as fallback", func() {
+				builder.Instance.Spec.Rabbitmq.Auth.OAuth2 = &rabbitmqv1beta1.OAuth2Spec{
+					ResourceServerID: "rabbitmq",
+					IssuerURL:        "https://keycloak.example.com/realms/rabbitmq",
+					ScopePrefix:      ptr.To("rabbitmq."),
+					Management: &rabbitmqv1beta1.OAuth2ManagementSpec{
+						ClientID: "rabbitmq-management",
+						Scopes:   "openid profile rabbitmq.tag:administrator",
+					},
+				}
+
+				expectedConfiguration := iniString(`auth_backends.1 = rabbit_auth_backend_oauth2
+					auth_backends.2 = rabbit_auth_backend_internal
+					auth_oauth2.resource_server_id = rabbitmq
+					auth_oauth2.scope_prefix = rabbitmq.
+					management.oauth_enabled = true
+					management.oauth_client_id = rabbitmq-management
+					auth_oauth2.issuer = https://keycloak.example.com/realms/rabbitmq
+					management.oauth_scopes = openid profile rabbitmq.tag:administrator`)
+
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(configMap.Data).To(HaveKeyWithValue("plugins.conf", expectedConfiguration))
+			})
+
+			It("references the mounted signing key", func() {
+				builder.Instance.Spec.Rabbitmq.Auth.OAuth2 = &rabbitmqv1beta1.OAuth2Spec{
+					ResourceServerID: "rabbitmq",
+					SigningKey: &rabbitmqv1beta1.OAuth2SigningKey{
+						SecretRef: corev1.LocalObjectReference{Name: "uaa-signing-key"},
+						KeyID:     "legacy-token-key",
+					},
+				}
+
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				pluginsConf, err := ini.Load([]byte(configMap.Data["plugins.conf"]))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pluginsConf.Section("").KeysHash()).To(SatisfyAll(
+					HaveKeyWithValue("auth_oauth2.default_key", "legacy-token-key"),
+					HaveKeyWithValue("auth_oauth2.signing_keys.legacy-token-key", "/etc/rabbitmq-oauth2/signing-key.pem"),
+				))
+			})
+		})
+
+		Context("configFrom", func() {
+			It("writes the settings of the referenced ConfigMap", func() {
+				builder.Instance.Spec.Rabbitmq.ConfigFrom = &rabbitmqv1beta1.ConfigFromSource{
+					ConfigMapRef: corev1.LocalObjectReference{Name: "user-conf"},
+				}
+				builder.ConfigFrom = "log.console.level = debug"
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(configMap.Data).To(HaveKeyWithValue("configFrom.conf", "log.console.level = debug\n"))
+
+				builder.Instance.Spec.Rabbitmq.ConfigFrom = nil
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(configMap.Data).NotTo(HaveKey("configFrom.conf"))
+			})
+
+			It("writes the key when the settings are empty", func() {
+				builder.Instance.Spec.Rabbitmq.ConfigFrom = &rabbitmqv1beta1.ConfigFromSource{
+					ConfigMapRef: corev1.LocalObjectReference{Name: "user-conf"},
+				}
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(configMap.Data).To(HaveKeyWithValue("configFrom.conf", ""))
+			})
+
+			It("returns an error when the settings are invalid", func() {
+				builder.Instance.Spec.Rabbitmq.ConfigFrom = &rabbitmqv1beta1.ConfigFromSource{
+					ConfigMapRef: corev1.LocalObjectReference{Name: "user-conf"},
+				}
+				builder.ConfigFrom = "[invalid"
+				Expect(configMapBuilder.Update(configMap)).To(MatchError(ContainSubstring("failed to load spec.rabbitmq.configFrom")))
+			})
+		})
+
+		Context("resource alarm thresholds", func() {
+			operatorDefaults := func() map[string]string {
+				operatorDefaultConf, err := ini.Load([]byte(configMap.Data["operatorDefaults.conf"]))
+				Expect(err).NotTo(HaveOccurred())
+				return operatorDefaultConf.Section("").KeysHash()
+			}
+
+			It("replaces the default disk free limit", func() {
+				builder.Instance.Spec.Rabbitmq.DiskFreeLimit = &rabbitmqv1beta1.ResourceAlarmThreshold{Relative: ptr.To(k8sresource.MustParse("1.5"))}
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(operatorDefaults()).To(HaveKeyWithValue("disk_free_limit.relative", "1.5"))
+				Expect(operatorDefaults()).NotTo(HaveKey("disk_free_limit.absolute"))
+
+				builder.Instance.Spec.Rabbitmq.DiskFreeLimit = &rabbitmqv1beta1.ResourceAlarmThreshold{Absolute: ptr.To(k8sresource.MustParse("4Gi"))}
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(operatorDefaults()).To(HaveKeyWithValue("disk_free_limit.absolute", "4294967296"))
+				Expect(operatorDefaults()).NotTo(HaveKey("disk_free_limit.relative"))
+			})
+
+			It("sets the memory high watermark", func() {
+				builder.Instance.Spec.Rabbitmq.MemoryHighWatermark = &rabbitmqv1beta1.ResourceAlarmThreshold{Relative: ptr.To(k8sresource.MustParse("0.6"))}
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(operatorDefaults()).To(HaveKeyWithValue("vm_memory_high_watermark.relative", "0.6"))
+				Expect(operatorDefaults()).To(HaveKeyWithValue("disk_free_limit.absolute", "2GB"))
+			})
+
+			It("keeps the default thresholds when the configured ones are invalid", func() {
+				builder.Instance.Spec.Rabbitmq.DiskFreeLimit = &rabbitmqv1beta1.ResourceAlarmThreshold{
+					Absolute: ptr.To(k8sresource.MustParse("4Gi")),
+					Relative: ptr.To(k8sresource.MustParse("1.5")),
+				}
+				builder.Instance.Spec.Rabbitmq.MemoryHighWatermark = &rabbitmqv1beta1.ResourceAlarmThreshold{Relative: ptr.To(k8sresource.MustParse("1.2"))}
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(operatorDefaults()).To(HaveKeyWithValue("disk_free_limit.absolute", "2GB"))
+				Expect(operatorDefaults()).NotTo(HaveKey("disk_free_limit.relative"))
+				Expect(operatorDefaults()).NotTo(HaveKey("vm_memory_high_watermark.relative"))
+			})
+		})
+
+		It("sets the peer discovery address type", func() {
+			builder.Instance.Spec.Rabbitmq.PeerDiscovery.AddressType = "ip"
+
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			operatorDefaultConf, err := ini.Load([]byte(configMap.Data["operatorDefaults.conf"]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operatorDefaultConf.Section("").KeysHash()).To(HaveKeyWithValue("cluster_formation.k8s.address_type", "ip"))
+		})
+
+		It("sets the peer discovery retries when configured", func() {
+			builder.Instance.Spec.Rabbitmq.PeerDiscovery.DiscoveryRetryLimit = ptr.To(int32(30))
+			builder.Instance.Spec.Rabbitmq.PeerDiscovery.DiscoveryRetryIntervalMilliseconds = ptr.To(int32(1000))
+
+			Expect(configMapBuilder.Update(configMap)).To(Succeed())
+			operatorDefaultConf, err := ini.Load([]byte(configMap.Data["operatorDefaults.conf"]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operatorDefaultConf.Section("").KeysHash()).To(SatisfyAll(
+				HaveKeyWithValue("cluster_formation.discovery_retry_limit", "30"),
+				HaveKeyWithValue("cluster_formation.discovery_retry_interval", "1000"),
+			))
+		})
+
 		When("valid userDefinedConfiguration is provided", func() {
 			It("adds configurations in a new rabbitmq configuration", func() {
 				userDefinedConfiguration := "cluster_formation.peer_discovery_backend = my-backend\n" +
@@ -276,7 +531,9 @@ CONSOLE_LOG=new`
 					prometheus.tcp.port       = 15692`)
 
 				Expect(configMapBuilder.Update(configMap)).To(Succeed())
-				Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", expectedConfiguration))
+				Expect(configMap.Data).To(HaveKeyWithValue("tls.conf", expectedConfiguration))
+				Expect(configMap.Data).To(HaveKeyWithValue("plugins.conf", ""))
+				Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", ""))
 			})
 
 			When("MQTT, STOMP, AMQP 1.0 and Stream plugins are enabled", func() {
@@ -287,54 +544,61 @@ CONSOLE_LOG=new`
 					instance.Spec.TLS.SecretName = "tls-secret"
 					instance.Spec.Rabbitmq.AdditionalPlugins = additionalPlugins
 
-					expectedConfiguration := iniString(`ssl_options.certfile  = /etc/rabbitmq-tls/tls.crt
-						ssl_options.keyfile   = /etc/rabbitmq-tls/tls.key
-						listeners.ssl.default = 5671
-						management.ssl.certfile   = /etc/rabbitmq-tls/tls.crt
-						management.ssl.keyfile    = /etc/rabbitmq-tls/tls.key
-						management.ssl.port       = 15671
-						prometheus.ssl.certfile = /etc/rabbitmq-tls/tls.crt
-						prometheus.ssl.keyfile   = /etc/rabbitmq-tls/tls.key
-						prometheus.ssl.port       = 15691
-						management.tcp.port     = 15672
-						prometheus.tcp.port       = 15692
-						mqtt.listeners.ssl.default = 8883
+					expectedConfiguration := iniString(`mqtt.listeners.ssl.default = 8883
 						stomp.listeners.ssl.1 = 61614
 						stream.listeners.ssl.default = 5551`)
 
 					Expect(configMapBuilder.Update(configMap)).To(Succeed())
-					Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", expectedConfiguration))
+					Expect(configMap.Data).To(HaveKeyWithValue("plugins.conf", expectedConfiguration))
 				})
 			})
 
-			It("preserves user configuration over Operator generated settings", func() {
+			It("keeps user configuration in a separate file loaded after Operator generated settings", func() {
 				instance.ObjectMeta.Name = "rabbit-tls-with-user-conf"
 				instance.Spec.TLS.SecretName = "tls-secret"
 				instance.Spec.Rabbitmq.AdditionalConfig = "listeners.ssl.default = 12345"
 
-				expectedConfiguration := iniString(`ssl_options.certfile  = /etc/rabbitmq-tls/tls.crt
-					ssl_options.keyfile   = /etc/rabbitmq-tls/tls.key
-					listeners.ssl.default = 12345
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				tlsConf, err := ini.Load([]byte(configMap.Data["tls.conf"]))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tlsConf.Section("").KeysHash()).To(HaveKeyWithValue("listeners.ssl.default", "5671"))
+				Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", iniString("listeners.ssl.default = 12345")))
+			})
+		})
+
+		Context("Mutual TLS", func() {
+			It("adds TLS config when TLS is enabled", func() {
+				instance.ObjectMeta.Name = "rabbit-tls"
+				instance.Spec.TLS.SecretName = "tls-secret"
+				instance.Spec.TLS.CaSecretName = "tls-mutual-secret"
+
+				expectedConfiguration := iniString(`ssl_options.certfile   = /etc/rabbitmq-tls/tls.crt
+					ssl_options.keyfile    = /etc/rabbitmq-tls/tls.key
+					listeners.ssl.default  = 5671
 					management.ssl.certfile   = /etc/rabbitmq-tls/tls.crt
 					management.ssl.keyfile    = /etc/rabbitmq-tls/tls.key
 					management.ssl.port       = 15671
 					prometheus.ssl.certfile = /etc/rabbitmq-tls/tls.crt
-					prometheus.ssl.keyfile  = /etc/rabbitmq-tls/tls.key
-					prometheus.ssl.port     = 15691
+					prometheus.ssl.keyfile   = /etc/rabbitmq-tls/tls.key
+					prometheus.ssl.port       = 15691
 					management.tcp.port     = 15672
-					prometheus.tcp.port       = 15692`)
+					prometheus.tcp.port       = 15692
+					ssl_options.cacertfile = /etc/rabbitmq-tls/ca.crt
+					ssl_options.verify     = verify_peer
+					management.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt
+					prometheus.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt`)
 
 				Expect(configMapBuilder.Update(configMap)).To(Succeed())
-				Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", expectedConfiguration))
+				Expect(configMap.Data).To(HaveKeyWithValue("tls.conf", expectedConfiguration))
 			})
-		})
 
-		Context("Mutual TLS", func() {
-			It("adds TLS config when TLS is enabled", func() {
+			It("configures the verification of client certificates", func() {
 				instance.ObjectMeta.Name = "rabbit-tls"
 				instance.Spec.TLS.SecretName = "tls-secret"
 				instance.Spec.TLS.CaSecretName = "tls-mutual-secret"
+				instance.Spec.TLS.FailIfNoPeerCert = true
 
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
 				expectedConfiguration := iniString(`ssl_options.certfile   = /etc/rabbitmq-tls/tls.crt
 					ssl_options.keyfile    = /etc/rabbitmq-tls/tls.key
 					listeners.ssl.default  = 5671
@@ -349,10 +613,15 @@ CONSOLE_LOG=new`
 					ssl_options.cacertfile = /etc/rabbitmq-tls/ca.crt
 					ssl_options.verify     = verify_peer
 					management.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt
-					prometheus.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt`)
+					prometheus.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt
+					ssl_options.fail_if_no_peer_cert = true`)
+				Expect(configMap.Data).To(HaveKeyWithValue("tls.conf", expectedConfiguration))
 
+				instance.Spec.TLS.Verify = "verify_none"
+				instance.Spec.TLS.FailIfNoPeerCert = false
 				Expect(configMapBuilder.Update(configMap)).To(Succeed())
-				Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", expectedConfiguration))
+				Expect(configMap.Data["tls.conf"]).To(ContainSubstring("verify_none"))
+				Expect(configMap.Data["tls.conf"]).NotTo(ContainSubstring("fail_if_no_peer_cert"))
 			})
 
 			When("Web MQTT and Web STOMP are enabled", func() {
@@ -364,27 +633,7 @@ CONSOLE_LOG=new`
 					instance.Spec.TLS.CaSecretName = "tls-mutual-secret"
 					instance.Spec.Rabbitmq.AdditionalPlugins = additionalPlugins
 
-					expectedConfiguration := iniString(`ssl_options.certfile   = /etc/rabbitmq-tls/tls.crt
-						ssl_options.keyfile    = /etc/rabbitmq-tls/tls.key
-						listeners.ssl.default  = 5671
-
-						management.ssl.certfile   = /etc/rabbitmq-tls/tls.crt
-						management.ssl.keyfile    = /etc/rabbitmq-tls/tls.key
-						management.ssl.port       = 15671
-
-						prometheus.ssl.certfile = /etc/rabbitmq-tls/tls.crt
-						prometheus.ssl.keyfile   = /etc/rabbitmq-tls/tls.key
-						prometheus.ssl.port       = 15691
-
-						management.tcp.port       = 15672
-						prometheus.tcp.port       = 15692
-
-						ssl_options.cacertfile = /etc/rabbitmq-tls/ca.crt
-						ssl_options.verify     = verify_peer
-						management.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt
-						prometheus.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt
-
-						web_mqtt.ssl.port       = 15676
+					expectedConfiguration := iniString(`web_mqtt.ssl.port       = 15676
 						web_mqtt.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt
 						web_mqtt.ssl.certfile   = /etc/rabbitmq-tls/tls.crt
 						web_mqtt.ssl.keyfile    = /etc/rabbitmq-tls/tls.key
@@ -395,7 +644,7 @@ CONSOLE_LOG=new`
 						web_stomp.ssl.keyfile    = /etc/rabbitmq-tls/tls.key`)
 
 					Expect(configMapBuilder.Update(configMap)).To(Succeed())
-					Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", expectedConfiguration))
+					Expect(configMap.Data).To(HaveKeyWithValue("plugins.conf", expectedConfiguration))
 				})
 			})
 		})
@@ -430,7 +679,7 @@ CONSOLE_LOG=new`
 					listeners.tcp = none`)
 
 				Expect(configMapBuilder.Update(configMap)).To(Succeed())
-				Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", expectedConfiguration))
+				Expect(configMap.Data).To(HaveKeyWithValue("tls.conf", expectedConfiguration))
 			})
 
 			It("disables non tls listeners for mqtt, stomp and stream plugins if enabled", func() {
@@ -454,21 +703,7 @@ CONSOLE_LOG=new`
 					},
 				}
 
-				expectedConfiguration := iniString(`ssl_options.certfile   = /etc/rabbitmq-tls/tls.crt
-					ssl_options.keyfile    = /etc/rabbitmq-tls/tls.key
-					listeners.ssl.default  = 5671
-
-					management.ssl.certfile   = /etc/rabbitmq-tls/tls.crt
-					management.ssl.keyfile    = /etc/rabbitmq-tls/tls.key
-					management.ssl.port       = 15671
-
-					prometheus.ssl.certfile = /etc/rabbitmq-tls/tls.crt
-					prometheus.ssl.keyfile   = /etc/rabbitmq-tls/tls.key
-					prometheus.ssl.port       = 15691
-
-					listeners.tcp = none
-
-					mqtt.listeners.ssl.default = 8883
+				expectedConfiguration := iniString(`mqtt.listeners.ssl.default = 8883
 					mqtt.listeners.tcp   = none
 
 					stomp.listeners.ssl.1 = 61614
@@ -478,7 +713,7 @@ CONSOLE_LOG=new`
 					stream.listeners.tcp = none`)
 
 				Expect(configMapBuilder.Update(configMap)).To(Succeed())
-				Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", expectedConfiguration))
+				Expect(configMap.Data).To(HaveKeyWithValue("plugins.conf", expectedConfiguration))
 			})
 
 			It("disables non tls listeners for web mqtt and web stomp when enabled", func() {
@@ -502,26 +737,7 @@ CONSOLE_LOG=new`
 					},
 				}
 
-				expectedConfiguration := iniString(`ssl_options.certfile   = /etc/rabbitmq-tls/tls.crt
-					ssl_options.keyfile    = /etc/rabbitmq-tls/tls.key
-					listeners.ssl.default  = 5671
-
-					management.ssl.certfile = /etc/rabbitmq-tls/tls.crt
-					management.ssl.keyfile  = /etc/rabbitmq-tls/tls.key
-					management.ssl.port     = 15671
-
-					prometheus.ssl.certfile = /etc/rabbitmq-tls/tls.crt
-					prometheus.ssl.keyfile  = /etc/rabbitmq-tls/tls.key
-					prometheus.ssl.port     = 15691
-
-					listeners.tcp = none
-
-					ssl_options.cacertfile = /etc/rabbitmq-tls/ca.crt
-					ssl_options.verify     = verify_peer
-					management.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt
-					prometheus.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt
-
-					web_mqtt.ssl.port       = 15676
+				expectedConfiguration := iniString(`web_mqtt.ssl.port       = 15676
 					web_mqtt.ssl.cacertfile = /etc/rabbitmq-tls/ca.crt
 					web_mqtt.ssl.certfile   = /etc/rabbitmq-tls/tls.crt
 					web_mqtt.ssl.keyfile    = /etc/rabbitmq-tls/tls.key
@@ -534,7 +750,45 @@ CONSOLE_LOG=new`
 					web_stomp.tcp.listener = none`)
 
 				Expect(configMapBuilder.Update(configMap)).To(Succeed())
-				Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", expectedConfiguration))
+				Expect(configMap.Data).To(HaveKeyWithValue("plugins.conf", expectedConfiguration))
+			})
+		})
+
+		When("TLS is disabled", func() {
+			It("generates empty TLS and plugins configuration files", func() {
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(configMap.Data).To(SatisfyAll(
+					HaveKeyWithValue("tls.conf", ""),
+					HaveKeyWithValue("plugins.conf", ""),
+				))
+			})
+		})
+
+		Context("IPv6", func() {
+			It("binds the management and prometheus listeners to all IPv6 and IPv4 addresses", func() {
+				instance.Spec.Service.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				operatorDefaultConf, err := ini.Load([]byte(configMap.Data["operatorDefaults.conf"]))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(operatorDefaultConf.Section("").KeysHash()).To(SatisfyAll(
+					HaveKeyWithValue("management.tcp.ip", "::"),
+					HaveKeyWithValue("prometheus.tcp.ip", "::"),
+					Not(HaveKey("management.ssl.ip")),
+				))
+			})
+
+			It("binds the TLS listeners when TLS is enabled", func() {
+				instance.Spec.Service.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+				instance.Spec.TLS.SecretName = "tls-secret"
+
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				operatorDefaultConf, err := ini.Load([]byte(configMap.Data["operatorDefaults.conf"]))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(operatorDefaultConf.Section("").KeysHash()).To(SatisfyAll(
+					HaveKeyWithValue("management.ssl.ip", "::"),
+					HaveKeyWithValue("prometheus.ssl.ip", "::"),
+				))
 			})
 		})
 
@@ -544,10 +798,10 @@ CONSOLE_LOG=new`
 				instance.ObjectMeta.Name = "rabbit-mem-limit"
 				instance.Spec.Resources.Limits = map[corev1.ResourceName]k8sresource.Quantity{corev1.ResourceMemory: k8sresource.MustParse("10Gi")}
 
-				expectedConfiguration := iniString(fmt.Sprintf("total_memory_available_override_value = %d", 8*GiB))
-
 				Expect(configMapBuilder.Update(configMap)).To(Succeed())
-				Expect(configMap.Data).To(HaveKeyWithValue("userDefinedConfiguration.conf", expectedConfiguration))
+				operatorDefaultConf, err := ini.Load([]byte(configMap.Data["operatorDefaults.conf"]))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(operatorDefaultConf.Section("").KeysHash()).To(HaveKeyWithValue("total_memory_available_override_value", fmt.Sprintf("%d", 8*GiB)))
 			})
 		})
 
@@ -600,6 +854,22 @@ CONSOLE_LOG=new`
 			})
 		})
 
+		Context("Inter-node TLS", func() {
+			It("writes the TLS options of the Erlang distribution", func() {
+				instance.Spec.TLS.Distribution = &rabbitmqv1beta1.DistributionTLSSpec{SecretName: "distribution-tls"}
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(configMap.Data).To(HaveKeyWithValue("inter_node_tls.config", SatisfyAll(
+					ContainSubstring(`{certfile,   "/etc/rabbitmq-distribution-tls/tls.crt"}`),
+					ContainSubstring("{fail_if_no_peer_cert, true}"),
+					ContainSubstring("{verify, verify_peer}"),
+				)))
+
+				instance.Spec.TLS.Distribution = nil
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(configMap.Data).NotTo(HaveKey("inter_node_tls.config"))
+			})
+		})
+
 		Describe("UpdateRequiresStsRestart", func() {
 			BeforeEach(func() {
 				Expect(configMapBuilder.Update(configMap)).To(Succeed())
@@ -618,6 +888,13 @@ CONSOLE_LOG=new`
 					Expect(configMapBuilder.UpdateRequiresStsRestart).To(BeFalse())
 				})
 			})
+			When("the only config change is the default user tags", func() {
+				It("does not require the StatefulSet to be restarted", func() {
+					instance.Spec.Rabbitmq.DefaultUserTags = []rabbitmqv1beta1.UserTag{"monitoring"}
+					Expect(configMapBuilder.Update(configMap)).To(Succeed())
+					Expect(configMapBuilder.UpdateRequiresStsRestart).To(BeFalse())
+				})
+			})
 			When("config change includes more than cluster formation nodes", func() {
 				It("requires the StatefulSet to be restarted", func() {
 					instance.Spec.Replicas = ptr.To(int32(3))
@@ -626,6 +903,35 @@ CONSOLE_LOG=new`
 					Expect(configMapBuilder.UpdateRequiresStsRestart).To(BeTrue())
 				})
 			})
+			When("rabbitmq-env.conf changes", func() {
+				It("requires the StatefulSet to be restarted", func() {
+					instance.Spec.Rabbitmq.EnvConfig = "SERVER_ADDITIONAL_ERL_ARGS=\"+sbwt none\""
+					Expect(configMapBuilder.Update(configMap)).To(Succeed())
+					Expect(configMapBuilder.UpdateRequiresStsRestart).To(BeTrue())
+				})
+			})
+			When("advanced.config changes", func() {
+				It("requires the StatefulSet to be restarted", func() {
+					instance.Spec.Rabbitmq.AdvancedConfig = "[{rabbit, [{auth_backends, [rabbit_auth_backend_ldap]}]}]."
+					Expect(configMapBuilder.Update(configMap)).To(Succeed())
+					Expect(configMapBuilder.UpdateRequiresStsRestart).To(BeTrue())
+				})
+			})
+		})
+	})
+
+	Context("Preserve", func() {
+		It("does not require a restart when the existing configuration is unchanged", func() {
+			existing, err := configMapBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configMapBuilder.Update(existing)).To(Succeed())
+
+			obj, err := configMapBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			configMapBuilder.Preserve(existing, obj)
+			Expect(configMapBuilder.Update(obj)).To(Succeed())
+			Expect(configMapBuilder.UpdateRequiresStsRestart).To(BeFalse())
+			Expect(obj.(*corev1.ConfigMap).Data).To(Equal(existing.(*corev1.ConfigMap).Data))
 		})
 	})
 
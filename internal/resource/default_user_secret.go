@@ -32,6 +32,12 @@ const (
 	usernamePrefix        = "default_user_"
 )
 
+// DefaultUserCredentials are the username and password of the default user.
+type DefaultUserCredentials struct {
+	Username string
+	Password string
+}
+
 type DefaultUserSecretBuilder struct {
 	*RabbitmqResourceBuilder
 }
@@ -41,12 +47,7 @@ func (builder *RabbitmqResourceBuilder) DefaultUserSecret() *DefaultUserSecretBu
 }
 
 func (builder *DefaultUserSecretBuilder) Build() (client.Object, error) {
-	username, err := generateUsername(24)
-	if err != nil {
-		return nil, err
-	}
-
-	password, err := randomEncodedString(24)
+	username, password, err := builder.credentials()
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +57,7 @@ func (builder *DefaultUserSecretBuilder) Build() (client.Object, error) {
 		return nil, err
 	}
 
-	// Default user secret implements the service binding Provisioned Service
+	// Default user secret implements the service binding Provisioned Service and is referenced in status.binding
 	// See: https://k8s-service-bindings.github.io/spec/#provisioned-service
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -75,6 +76,7 @@ func (builder *DefaultUserSecretBuilder) Build() (client.Object, error) {
 	}
 	builder.updatePorts(secret)
 	builder.updateConnectionString(secret)
+	builder.updateURIs(secret)
 
 	return secret, nil
 }
@@ -85,10 +87,17 @@ func (builder *DefaultUserSecretBuilder) UpdateMayRequireStsRecreate() bool {
 
 func (builder *DefaultUserSecretBuilder) Update(object client.Object) error {
 	secret := object.(*corev1.Secret)
-	secret.Labels = metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels)
-	secret.Annotations = metadata.ReconcileAndFilterAnnotations(secret.GetAnnotations(), builder.Instance.Annotations)
-	builder.updatePorts(secret)
-	builder.updateConnectionString(secret)
+	secret.Labels = builder.childLabels()
+	secret.Annotations = builder.childAnnotations(secret.GetAnnotations())
+	// the data of a Secret managed by another controller would be reset by that controller
+	if !metadata.ManagedByExternal(secret.Annotations) {
+		if err := builder.updateCredentials(secret); err != nil {
+			return err
+		}
+		builder.updatePorts(secret)
+		builder.updateConnectionString(secret)
+		builder.updateURIs(secret)
+	}
 
 	if err := controllerutil.SetControllerReference(builder.Instance, secret, builder.Scheme); err != nil {
 		return fmt.Errorf("failed setting controller reference: %w", err)
@@ -97,6 +106,78 @@ func (builder *DefaultUserSecretBuilder) Update(object client.Object) error {
 	return nil
 }
 
+// credentials returns the credentials of spec.secretBackend.existingAdminSecret, or random credentials if it is not set.
+func (builder *DefaultUserSecretBuilder) credentials() (string, string, error) {
+	if builder.DefaultUser != nil {
+		return builder.DefaultUser.Username, builder.DefaultUser.Password, nil
+	}
+
+	username, err := generateUsername(24)
+	if err != nil {
+		return "", "", err
+	}
+
+	password, err := GeneratePassword()
+	if err != nil {
+		return "", "", err
+	}
+	return username, password, nil
+}
+
+// updateCredentials copies the credentials of spec.secretBackend.existingAdminSecret, so that changes to that Secret
+// are propagated. Generated credentials are kept.
+func (builder *DefaultUserSecretBuilder) updateCredentials(secret *corev1.Secret) error {
+	if builder.DefaultUser == nil {
+		return nil
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	defaultUserConf, err := generateDefaultUserConf(builder.DefaultUser.Username, builder.DefaultUser.Password)
+	if err != nil {
+		return err
+	}
+	secret.Data["username"] = []byte(builder.DefaultUser.Username)
+	secret.Data["password"] = []byte(builder.DefaultUser.Password)
+	secret.Data["default_user.conf"] = defaultUserConf
+	return nil
+}
+
+// Preserve keeps the data of the existing Secret, so that generated credentials do not change.
+// The data of a Secret managed by another controller is left to that controller.
+func (builder *DefaultUserSecretBuilder) Preserve(existing, desired client.Object) {
+	existingSecret, secret := existing.(*corev1.Secret), desired.(*corev1.Secret)
+	if metadata.ManagedByExternal(existingSecret.Annotations) {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[metadata.ManagedByExternalAnnotation] = existingSecret.Annotations[metadata.ManagedByExternalAnnotation]
+		secret.Data = nil
+		return
+	}
+	for key, value := range existingSecret.Data {
+		secret.Data[key] = value
+	}
+}
+
+// UpdatePassword replaces the password of the default user in the default-user Secret.
+func (builder *DefaultUserSecretBuilder) UpdatePassword(secret *corev1.Secret, password string) error {
+	defaultUserConf, err := generateDefaultUserConf(string(secret.Data["username"]), password)
+	if err != nil {
+		return err
+	}
+	secret.Data["password"] = []byte(password)
+	secret.Data["default_user.conf"] = defaultUserConf
+	builder.updateConnectionString(secret)
+	builder.updateURIs(secret)
+	return nil
+}
+
+// GeneratePassword returns a random password for the default user.
+func GeneratePassword() (string, error) {
+	return randomEncodedString(24)
+}
+
 func (builder *DefaultUserSecretBuilder) updatePorts(secret *corev1.Secret) {
 	const (
 		AMQPPort  = "5672"
@@ -153,6 +234,51 @@ func (builder *DefaultUserSecretBuilder) updateConnectionString(secret *corev1.S
 	} else {
 		secret.Data["connection_string"] = []byte(fmt.Sprintf("amqp://%s:%s@%s:%s/", secret.Data["username"], secret.Data["password"], secret.Data["host"], secret.Data["port"]))
 	}
+	// uri is the well-known entry of the Service Binding specification for the connection string
+	secret.Data["uri"] = secret.Data["connection_string"]
+}
+
+// updateURIs sets a URI for every protocol served by the cluster, so that clients do not need to assemble them from
+// the host, port and credentials. URIs of protocols which are not served are removed.
+func (builder *DefaultUserSecretBuilder) updateURIs(secret *corev1.Secret) {
+	tls := builder.Instance.TLSEnabled()
+	nonTLS := !tls || !builder.Instance.DisableNonTLSListeners()
+	credentials := fmt.Sprintf("%s:%s", secret.Data["username"], secret.Data["password"])
+	host := string(secret.Data["host"])
+
+	uris := map[string]string{}
+	if nonTLS {
+		uris["amqp_uri"] = fmt.Sprintf("amqp://%s@%s:5672/", credentials, host)
+		uris["management_uri"] = fmt.Sprintf("http://%s:15672/", host)
+	}
+	if tls {
+		uris["amqps_uri"] = fmt.Sprintf("amqps://%s@%s:5671/", credentials, host)
+		uris["management_uri"] = fmt.Sprintf("https://%s:15671/", host)
+	}
+	if builder.Instance.StreamNeeded() {
+		if nonTLS {
+			uris["stream_uri"] = fmt.Sprintf("rabbitmq-stream://%s@%s:5552/", credentials, host)
+		}
+		if tls {
+			uris["stream_uri"] = fmt.Sprintf("rabbitmq-stream+tls://%s@%s:5551/", credentials, host)
+		}
+	}
+	if builder.pluginEnabled("rabbitmq_mqtt") {
+		if nonTLS {
+			uris["mqtt_uri"] = fmt.Sprintf("mqtt://%s@%s:1883", credentials, host)
+		}
+		if tls {
+			uris["mqtt_uri"] = fmt.Sprintf("mqtts://%s@%s:8883", credentials, host)
+		}
+	}
+
+	for _, key := range []string{"amqp_uri", "amqps_uri", "management_uri", "stream_uri", "mqtt_uri"} {
+		if uri, ok := uris[key]; ok {
+			secret.Data[key] = []byte(uri)
+		} else {
+			delete(secret.Data, key)
+		}
+	}
 }
 
 // generateUsername returns a base64 string that has "default_user_" as prefix
@@ -168,12 +294,7 @@ func generateUsername(l int) (string, error) {
 }
 
 func (builder *DefaultUserSecretBuilder) pluginEnabled(plugin v1beta1.Plugin) bool {
-	for _, value := range builder.Instance.Spec.Rabbitmq.AdditionalPlugins {
-		if value == plugin {
-			return true
-		}
-	}
-	return false
+	return builder.Instance.AdditionalPluginEnabled(plugin)
 }
 
 func generateDefaultUserConf(username, password string) ([]byte, error) {
@@ -110,6 +110,10 @@ var _ = Describe("DefaultUserSecret", func() {
 				Expect(secret.Data).To(HaveKeyWithValue("connection_string", expectedConnectionString))
 			})
 
+			By("Setting the Service Binding uri entry to the connection string", func() {
+				Expect(secret.Data).To(HaveKeyWithValue("uri", secret.Data["connection_string"]))
+			})
+
 			By("creating a default_user.conf file that contains the correct sysctl config format to be parsed by RabbitMQ", func() {
 				defaultUserConf, ok := secret.Data["default_user.conf"]
 				Expect(ok).To(BeTrue(), "Failed to find a key \"default_user.conf\" in the generated Secret")
@@ -363,6 +367,176 @@ var _ = Describe("DefaultUserSecret", func() {
 		})
 	})
 
+	Context("when spec.secretBackend.existingAdminSecret is set", func() {
+		BeforeEach(func() {
+			builder.DefaultUser = &resource.DefaultUserCredentials{
+				Username: "corporate-admin",
+				Password: "corporate-password",
+			}
+		})
+
+		It("uses the credentials of the existing Secret", func() {
+			obj, err := defaultUserSecretBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			secret = obj.(*corev1.Secret)
+
+			Expect(secret.Data).To(HaveKeyWithValue("username", []byte("corporate-admin")))
+			Expect(secret.Data).To(HaveKeyWithValue("password", []byte("corporate-password")))
+			Expect(secret.Data).To(HaveKeyWithValue("default_user.conf", []byte("default_user = corporate-admin\ndefault_pass = corporate-password\n")))
+			Expect(secret.Data).To(HaveKeyWithValue("connection_string", []byte("amqp://corporate-admin:corporate-password@a name.a namespace.svc:5672/")))
+		})
+
+		It("updates the credentials when the existing Secret changes", func() {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "a namespace",
+				},
+				Data: map[string][]byte{
+					"username":          []byte("corporate-admin"),
+					"password":          []byte("previous-password"),
+					"default_user.conf": []byte("default_user = corporate-admin\ndefault_pass = previous-password\n"),
+					"host":              []byte("a name.a namespace.svc"),
+				},
+			}
+			Expect(defaultUserSecretBuilder.Update(secret)).To(Succeed())
+
+			Expect(secret.Data).To(HaveKeyWithValue("password", []byte("corporate-password")))
+			Expect(secret.Data).To(HaveKeyWithValue("default_user.conf", []byte("default_user = corporate-admin\ndefault_pass = corporate-password\n")))
+			Expect(secret.Data).To(HaveKeyWithValue("connection_string", []byte("amqp://corporate-admin:corporate-password@a name.a namespace.svc:5672/")))
+		})
+	})
+
+	Context("when the Secret is annotated with operator.rabbitmq.com/managed-by-external", func() {
+		It("only updates the metadata", func() {
+			builder.DefaultUser = &resource.DefaultUserCredentials{Username: "user", Password: "pass"}
+			instance.Labels = map[string]string{"foo": "bar"}
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "a namespace",
+					Annotations: map[string]string{"operator.rabbitmq.com/managed-by-external": "true"},
+				},
+				Data: map[string][]byte{
+					"username": []byte("external-user"),
+					"password": []byte("external-password"),
+					"port":     []byte("5672"),
+				},
+			}
+			Expect(defaultUserSecretBuilder.Update(secret)).To(Succeed())
+
+			Expect(secret.Data).To(Equal(map[string][]byte{
+				"username": []byte("external-user"),
+				"password": []byte("external-password"),
+				"port":     []byte("5672"),
+			}))
+			Expect(secret.Labels).To(HaveKeyWithValue("foo", "bar"))
+			Expect(secret.Annotations).To(HaveKeyWithValue("operator.rabbitmq.com/managed-by-external", "true"))
+			Expect(secret.OwnerReferences).To(HaveLen(1))
+		})
+
+		It("does not apply any data", func() {
+			obj, err := defaultUserSecretBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			existing := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"operator.rabbitmq.com/managed-by-external": "true"},
+				},
+				Data: map[string][]byte{"username": []byte("external-user")},
+			}
+			defaultUserSecretBuilder.Preserve(existing, obj)
+			Expect(defaultUserSecretBuilder.Update(obj)).To(Succeed())
+
+			secret = obj.(*corev1.Secret)
+			Expect(secret.Data).To(BeEmpty())
+			Expect(secret.Annotations).To(HaveKeyWithValue("operator.rabbitmq.com/managed-by-external", "true"))
+		})
+	})
+
+	Context("Preserve", func() {
+		It("keeps the credentials of the existing Secret", func() {
+			obj, err := defaultUserSecretBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			existing := &corev1.Secret{
+				Data: map[string][]byte{
+					"username":          []byte("existing-user"),
+					"password":          []byte("existing-password"),
+					"default_user.conf": []byte("default_user = existing-user\ndefault_pass = existing-password\n"),
+				},
+			}
+			defaultUserSecretBuilder.Preserve(existing, obj)
+			Expect(defaultUserSecretBuilder.Update(obj)).To(Succeed())
+
+			secret = obj.(*corev1.Secret)
+			Expect(secret.Data).To(HaveKeyWithValue("username", []byte("existing-user")))
+			Expect(secret.Data).To(HaveKeyWithValue("password", []byte("existing-password")))
+			Expect(secret.Data).To(HaveKeyWithValue("connection_string", []byte("amqp://existing-user:existing-password@a name.a namespace.svc:5672/")))
+			Expect(secret.Data).To(HaveKey("host"))
+		})
+	})
+
+	Context("URIs", func() {
+		BeforeEach(func() {
+			builder.DefaultUser = &resource.DefaultUserCredentials{Username: "user", Password: "pass"}
+		})
+
+		It("sets the AMQP and management URIs", func() {
+			obj, err := defaultUserSecretBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			secret = obj.(*corev1.Secret)
+
+			Expect(secret.Data).To(HaveKeyWithValue("amqp_uri", []byte("amqp://user:pass@a name.a namespace.svc:5672/")))
+			Expect(secret.Data).To(HaveKeyWithValue("management_uri", []byte("http://a name.a namespace.svc:15672/")))
+			Expect(secret.Data).NotTo(HaveKey("amqps_uri"))
+			Expect(secret.Data).NotTo(HaveKey("stream_uri"))
+			Expect(secret.Data).NotTo(HaveKey("mqtt_uri"))
+		})
+
+		When("TLS is enabled and non-TLS listeners are disabled", func() {
+			BeforeEach(func() {
+				instance.Spec.TLS.SecretName = "tls-secret"
+				instance.Spec.TLS.DisableNonTLSListeners = true
+				instance.Spec.Rabbitmq.AdditionalPlugins = []rabbitmqv1beta1.Plugin{"rabbitmq_stream", "rabbitmq_mqtt"}
+			})
+
+			It("only sets the TLS URIs", func() {
+				secret = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "a namespace"},
+					Data: map[string][]byte{
+						"username": []byte("user"),
+						"password": []byte("pass"),
+						"host":     []byte("a name.a namespace.svc"),
+						"amqp_uri": []byte("amqp://user:pass@a name.a namespace.svc:5672/"),
+					},
+				}
+				Expect(defaultUserSecretBuilder.Update(secret)).To(Succeed())
+
+				Expect(secret.Data).NotTo(HaveKey("amqp_uri"))
+				Expect(secret.Data).To(HaveKeyWithValue("amqps_uri", []byte("amqps://user:pass@a name.a namespace.svc:5671/")))
+				Expect(secret.Data).To(HaveKeyWithValue("management_uri", []byte("https://a name.a namespace.svc:15671/")))
+				Expect(secret.Data).To(HaveKeyWithValue("stream_uri", []byte("rabbitmq-stream+tls://user:pass@a name.a namespace.svc:5551/")))
+				Expect(secret.Data).To(HaveKeyWithValue("mqtt_uri", []byte("mqtts://user:pass@a name.a namespace.svc:8883")))
+			})
+		})
+	})
+
+	Context("UpdatePassword", func() {
+		It("replaces the password in all keys", func() {
+			secret = &corev1.Secret{
+				Data: map[string][]byte{
+					"username":          []byte("a-user"),
+					"password":          []byte("old-password"),
+					"default_user.conf": []byte("default_user = a-user\ndefault_pass = old-password\n"),
+					"host":              []byte("a name.a namespace.svc"),
+					"port":              []byte("5672"),
+				},
+			}
+			Expect(defaultUserSecretBuilder.UpdatePassword(secret, "new-password")).To(Succeed())
+
+			Expect(secret.Data).To(HaveKeyWithValue("password", []byte("new-password")))
+			Expect(secret.Data).To(HaveKeyWithValue("default_user.conf", []byte("default_user = a-user\ndefault_pass = new-password\n")))
+			Expect(secret.Data).To(HaveKeyWithValue("connection_string", []byte("amqp://a-user:new-password@a name.a namespace.svc:5672/")))
+		})
+	})
+
 	It("sets owner reference", func() {
 		secret = &corev1.Secret{
 			Data: map[string][]byte{},
@@ -17,13 +17,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
-	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	erlangCookieName = "erlang-cookie"
+	// ErlangCookieKey is the key of the Erlang cookie in the Secret mounted by the setup container.
+	ErlangCookieKey = ".erlang.cookie"
 )
 
 type ErlangCookieBuilder struct {
@@ -34,8 +36,22 @@ func (builder *RabbitmqResourceBuilder) ErlangCookie() *ErlangCookieBuilder {
 	return &ErlangCookieBuilder{builder}
 }
 
+// ErlangCookieSecretName returns the name of the Secret mounted as Erlang cookie: either the Secret
+// referenced by spec.secretBackend.existingErlangCookieSecret, or the Secret generated by the operator.
+func ErlangCookieSecretName(instance *rabbitmqv1beta1.RabbitmqCluster) string {
+	if instance.ExistingErlangCookieSecretEnabled() {
+		return instance.Spec.SecretBackend.ExistingErlangCookieSecret.Name
+	}
+	return instance.ChildResourceName(erlangCookieName)
+}
+
+// GenerateErlangCookie returns a random Erlang cookie.
+func GenerateErlangCookie() (string, error) {
+	return randomEncodedString(24)
+}
+
 func (builder *ErlangCookieBuilder) Build() (client.Object, error) {
-	cookie, err := randomEncodedString(24)
+	cookie, err := GenerateErlangCookie()
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +63,7 @@ func (builder *ErlangCookieBuilder) Build() (client.Object, error) {
 		},
 		Type: corev1.SecretTypeOpaque,
 		Data: map[string][]byte{
-			".erlang.cookie": []byte(cookie),
+			ErlangCookieKey: []byte(cookie),
 		},
 	}, nil
 }
@@ -58,8 +74,8 @@ func (builder *ErlangCookieBuilder) UpdateMayRequireStsRecreate() bool {
 
 func (builder *ErlangCookieBuilder) Update(object client.Object) error {
 	secret := object.(*corev1.Secret)
-	secret.Labels = metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels)
-	secret.Annotations = metadata.ReconcileAndFilterAnnotations(secret.GetAnnotations(), builder.Instance.Annotations)
+	secret.Labels = builder.childLabels()
+	secret.Annotations = builder.childAnnotations(secret.GetAnnotations())
 
 	if err := controllerutil.SetControllerReference(builder.Instance, secret, builder.Scheme); err != nil {
 		return fmt.Errorf("failed setting controller reference: %w", err)
@@ -68,6 +84,13 @@ func (builder *ErlangCookieBuilder) Update(object client.Object) error {
 	return nil
 }
 
+// Preserve keeps the Erlang cookie of the existing Secret, since nodes with different cookies cannot communicate.
+func (builder *ErlangCookieBuilder) Preserve(existing, desired client.Object) {
+	if cookie, ok := existing.(*corev1.Secret).Data[ErlangCookieKey]; ok {
+		desired.(*corev1.Secret).Data[ErlangCookieKey] = cookie
+	}
+}
+
 func randomEncodedString(dataLen int) (string, error) {
 	randomBytes := make([]byte, dataLen)
 	if _, err := rand.Read(randomBytes); err != nil {
@@ -178,4 +178,12 @@ var _ = Describe("ErlangCookie", func() {
 			Expect(erlangCookieBuilder.UpdateMayRequireStsRecreate()).To(BeFalse())
 		})
 	})
+
+	It("preserves the cookie of the existing secret", func() {
+		obj, err := erlangCookieBuilder.Build()
+		Expect(err).NotTo(HaveOccurred())
+		existing := &corev1.Secret{Data: map[string][]byte{".erlang.cookie": []byte("existing-cookie")}}
+		erlangCookieBuilder.Preserve(existing, obj)
+		Expect(obj.(*corev1.Secret).Data).To(HaveKeyWithValue(".erlang.cookie", []byte("existing-cookie")))
+	})
 })
@@ -0,0 +1,123 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource
+
+import (
+	"fmt"
+
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	AMQPGatewayRouteSuffix  = "amqp"
+	AMQPSGatewayRouteSuffix = "amqps"
+)
+
+// TCPRoutes and TLSRoutes are handled as unstructured objects so that the operator
+// does not depend on the Gateway API types.
+var (
+	TCPRouteGroupVersionKind = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TCPRoute"}
+	TLSRouteGroupVersionKind = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TLSRoute"}
+)
+
+// NewGatewayRoute returns an empty Gateway API route of the given kind with the given name and namespace.
+func NewGatewayRoute(gvk schema.GroupVersionKind, name, namespace string) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(gvk)
+	route.SetName(name)
+	route.SetNamespace(namespace)
+	return route
+}
+
+type AMQPGatewayRouteBuilder struct {
+	*RabbitmqResourceBuilder
+}
+
+type AMQPSGatewayRouteBuilder struct {
+	*RabbitmqResourceBuilder
+}
+
+func (builder *RabbitmqResourceBuilder) AMQPGatewayRoute() *AMQPGatewayRouteBuilder {
+	return &AMQPGatewayRouteBuilder{builder}
+}
+
+func (builder *RabbitmqResourceBuilder) AMQPSGatewayRoute() *AMQPSGatewayRouteBuilder {
+	return &AMQPSGatewayRouteBuilder{builder}
+}
+
+func (builder *AMQPGatewayRouteBuilder) Build() (client.Object, error) {
+	return NewGatewayRoute(TCPRouteGroupVersionKind, builder.Instance.ChildResourceName(AMQPGatewayRouteSuffix), builder.Instance.Namespace), nil
+}
+
+func (builder *AMQPGatewayRouteBuilder) UpdateMayRequireStsRecreate() bool {
+	return false
+}
+
+func (builder *AMQPGatewayRouteBuilder) Update(object client.Object) error {
+	return builder.updateGatewayRoute(object.(*unstructured.Unstructured), builder.Instance.Spec.Gateway.AMQPSectionName, 5672)
+}
+
+func (builder *AMQPSGatewayRouteBuilder) Build() (client.Object, error) {
+	return NewGatewayRoute(TLSRouteGroupVersionKind, builder.Instance.ChildResourceName(AMQPSGatewayRouteSuffix), builder.Instance.Namespace), nil
+}
+
+func (builder *AMQPSGatewayRouteBuilder) UpdateMayRequireStsRecreate() bool {
+	return false
+}
+
+func (builder *AMQPSGatewayRouteBuilder) Update(object client.Object) error {
+	route := object.(*unstructured.Unstructured)
+	hostnames := builder.Instance.Spec.Gateway.Hostnames
+	if len(hostnames) == 0 {
+		unstructured.RemoveNestedField(route.Object, "spec", "hostnames")
+	} else if err := unstructured.SetNestedStringSlice(route.Object, hostnames, "spec", "hostnames"); err != nil {
+		return err
+	}
+	return builder.updateGatewayRoute(route, builder.Instance.Spec.Gateway.AMQPSSectionName, 5671)
+}
+
+// updateGatewayRoute attaches the route to the listener of the Gateway and routes it to the port of the client Service.
+func (builder *RabbitmqResourceBuilder) updateGatewayRoute(route *unstructured.Unstructured, sectionName string, port int64) error {
+	spec := builder.Instance.Spec.Gateway
+	route.SetLabels(builder.childLabels())
+	route.SetAnnotations(metadata.ReconcileAnnotations(builder.childAnnotations(route.GetAnnotations()), spec.Annotations))
+
+	parentRef := map[string]interface{}{
+		"name":        spec.Name,
+		"sectionName": sectionName,
+	}
+	if spec.Namespace != "" {
+		parentRef["namespace"] = spec.Namespace
+	}
+	if err := unstructured.SetNestedSlice(route.Object, []interface{}{parentRef}, "spec", "parentRefs"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedSlice(route.Object, []interface{}{
+		map[string]interface{}{
+			"backendRefs": []interface{}{
+				map[string]interface{}{
+					"name": builder.Instance.ChildResourceName(ServiceSuffix),
+					"port": port,
+				},
+			},
+		},
+	}, "spec", "rules"); err != nil {
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(builder.Instance, route, builder.Scheme); err != nil {
+		return fmt.Errorf("failed setting controller reference: %w", err)
+	}
+	return nil
+}
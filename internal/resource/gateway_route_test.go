@@ -0,0 +1,138 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	defaultscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("GatewayRoute", func() {
+	var (
+		instance rabbitmqv1beta1.RabbitmqCluster
+		builder  *resource.RabbitmqResourceBuilder
+		scheme   *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+		instance = generateRabbitmqCluster()
+		instance.Spec.Gateway = &rabbitmqv1beta1.RabbitmqClusterGatewaySpec{
+			Name:             "shared-gateway",
+			Namespace:        "gateway-system",
+			AMQPSectionName:  "amqp",
+			AMQPSSectionName: "amqps",
+			Hostnames:        []string{"rabbitmq.example.com"},
+			Annotations:      map[string]string{"example.com/owner": "messaging"},
+		}
+		builder = &resource.RabbitmqResourceBuilder{
+			Instance: &instance,
+			Scheme:   scheme,
+		}
+	})
+
+	Context("AMQPGatewayRoute", func() {
+		It("builds a TCPRoute to the AMQP port of the client Service", func() {
+			obj, err := builder.AMQPGatewayRoute().Build()
+			Expect(err).NotTo(HaveOccurred())
+			route := obj.(*unstructured.Unstructured)
+			Expect(route.GroupVersionKind()).To(Equal(resource.TCPRouteGroupVersionKind))
+			Expect(route.GetName()).To(Equal(instance.ChildResourceName("amqp")))
+			Expect(route.GetNamespace()).To(Equal(instance.Namespace))
+
+			Expect(builder.AMQPGatewayRoute().Update(route)).To(Succeed())
+			Expect(route.Object["spec"]).To(Equal(map[string]interface{}{
+				"parentRefs": []interface{}{
+					map[string]interface{}{
+						"name":        "shared-gateway",
+						"namespace":   "gateway-system",
+						"sectionName": "amqp",
+					},
+				},
+				"rules": []interface{}{
+					map[string]interface{}{
+						"backendRefs": []interface{}{
+							map[string]interface{}{
+								"name": instance.Name,
+								"port": int64(5672),
+							},
+						},
+					},
+				},
+			}))
+			Expect(route.GetAnnotations()).To(HaveKeyWithValue("example.com/owner", "messaging"))
+			Expect(route.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/name", instance.Name))
+			Expect(route.GetOwnerReferences()).To(HaveLen(1))
+		})
+
+		It("omits the namespace of the Gateway when not set", func() {
+			instance.Spec.Gateway.Namespace = ""
+			obj, err := builder.AMQPGatewayRoute().Build()
+			Expect(err).NotTo(HaveOccurred())
+			route := obj.(*unstructured.Unstructured)
+			Expect(builder.AMQPGatewayRoute().Update(route)).To(Succeed())
+
+			parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+			Expect(parentRefs[0]).NotTo(HaveKey("namespace"))
+		})
+	})
+
+	Context("AMQPSGatewayRoute", func() {
+		It("builds a TLSRoute to the AMQPS port of the client Service", func() {
+			obj, err := builder.AMQPSGatewayRoute().Build()
+			Expect(err).NotTo(HaveOccurred())
+			route := obj.(*unstructured.Unstructured)
+			Expect(route.GroupVersionKind()).To(Equal(resource.TLSRouteGroupVersionKind))
+			Expect(route.GetName()).To(Equal(instance.ChildResourceName("amqps")))
+
+			Expect(builder.AMQPSGatewayRoute().Update(route)).To(Succeed())
+			hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+			Expect(hostnames).To(ConsistOf("rabbitmq.example.com"))
+			parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+			Expect(parentRefs[0]).To(HaveKeyWithValue("sectionName", "amqps"))
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			Expect(rules[0]).To(HaveKeyWithValue("backendRefs", ConsistOf(HaveKeyWithValue("port", int64(5671)))))
+		})
+	})
+
+	Context("ResourceBuilders", func() {
+		BeforeEach(func() {
+			builder.GatewayAPIAvailable = true
+		})
+
+		It("appends the TCPRoute builder, and the TLSRoute builder when TLS is enabled", func() {
+			Expect(builder.ResourceBuilders()).To(ContainElement(BeAssignableToTypeOf(&resource.AMQPGatewayRouteBuilder{})))
+			Expect(builder.ResourceBuilders()).NotTo(ContainElement(BeAssignableToTypeOf(&resource.AMQPSGatewayRouteBuilder{})))
+
+			instance.Spec.TLS.SecretName = "tls-secret"
+			Expect(builder.ResourceBuilders()).To(ContainElement(BeAssignableToTypeOf(&resource.AMQPSGatewayRouteBuilder{})))
+		})
+
+		It("does not append the TCPRoute builder when non-TLS listeners are disabled", func() {
+			instance.Spec.TLS.SecretName = "tls-secret"
+			instance.Spec.TLS.DisableNonTLSListeners = true
+			Expect(builder.ResourceBuilders()).NotTo(ContainElement(BeAssignableToTypeOf(&resource.AMQPGatewayRouteBuilder{})))
+		})
+
+		It("does not append the Gateway API route builders when the Gateway API is not available", func() {
+			builder.GatewayAPIAvailable = false
+			instance.Spec.TLS.SecretName = "tls-secret"
+			Expect(builder.ResourceBuilders()).NotTo(ContainElement(BeAssignableToTypeOf(&resource.AMQPGatewayRouteBuilder{})))
+			Expect(builder.ResourceBuilders()).NotTo(ContainElement(BeAssignableToTypeOf(&resource.AMQPSGatewayRouteBuilder{})))
+		})
+	})
+})
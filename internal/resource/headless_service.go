@@ -18,6 +18,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -48,29 +49,32 @@ func (builder *HeadlessServiceBuilder) Build() (client.Object, error) {
 
 func (builder *HeadlessServiceBuilder) Update(object client.Object) error {
 	service := object.(*corev1.Service)
-	service.Labels = metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels)
-	service.Annotations = metadata.ReconcileAndFilterAnnotations(service.GetAnnotations(), builder.Instance.Annotations)
+	service.Labels = builder.childLabels()
+	service.Annotations = builder.childAnnotations(service.GetAnnotations())
 	service.Spec = corev1.ServiceSpec{
 		Type:            corev1.ServiceTypeClusterIP,
 		ClusterIP:       "None",
 		SessionAffinity: corev1.ServiceAffinityNone,
 		Selector:        metadata.LabelSelector(builder.Instance.Name),
-		Ports: []corev1.ServicePort{
+		Ports: append([]corev1.ServicePort{
 			{
-				Protocol:   corev1.ProtocolTCP,
-				Port:       4369,
-				TargetPort: intstr.FromInt32(4369),
-				Name:       "epmd",
+				Protocol:    corev1.ProtocolTCP,
+				Port:        4369,
+				TargetPort:  intstr.FromInt32(4369),
+				Name:        builder.servicePortName("epmd"),
+				AppProtocol: ptr.To("tcp"),
 			},
 			{
-				Protocol:   corev1.ProtocolTCP,
-				Port:       25672,
-				TargetPort: intstr.FromInt32(25672),
-				Name:       "cluster-rpc", // aka distribution port
+				Protocol:    corev1.ProtocolTCP,
+				Port:        25672,
+				TargetPort:  intstr.FromInt32(25672),
+				Name:        builder.servicePortName("cluster-rpc"), // aka distribution port
+				AppProtocol: ptr.To("tcp"),
 			},
-		},
-		PublishNotReadyAddresses: true,
+		}, builder.additionalPorts()...),
+		PublishNotReadyAddresses: ptr.Deref(builder.Instance.Spec.HeadlessService.PublishNotReadyAddresses, true),
 		IPFamilyPolicy:           builder.Instance.Spec.Service.IPFamilyPolicy,
+		IPFamilies:               builder.Instance.Spec.Service.IPFamilies,
 	}
 
 	if err := controllerutil.SetControllerReference(builder.Instance, service, builder.Scheme); err != nil {
@@ -79,3 +83,21 @@ func (builder *HeadlessServiceBuilder) Update(object client.Object) error {
 
 	return nil
 }
+
+// additionalPorts returns the CLI tools ports, if enabled, and the additional ports of the spec.
+func (builder *HeadlessServiceBuilder) additionalPorts() []corev1.ServicePort {
+	spec := builder.Instance.Spec.HeadlessService
+	var ports []corev1.ServicePort
+	if spec.CLIToolsPorts {
+		for port := int32(35672); port <= 35682; port++ {
+			ports = append(ports, corev1.ServicePort{
+				Protocol:    corev1.ProtocolTCP,
+				Port:        port,
+				TargetPort:  intstr.FromInt32(port),
+				Name:        fmt.Sprintf("cli-%d", port),
+				AppProtocol: ptr.To("tcp"),
+			})
+		}
+	}
+	return append(ports, spec.AdditionalPorts...)
+}
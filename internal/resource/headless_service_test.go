@@ -185,16 +185,18 @@ var _ = Describe("HeadlessService", func() {
 				SessionAffinity: corev1.ServiceAffinityNone,
 				Ports: []corev1.ServicePort{
 					{
-						Protocol:   corev1.ProtocolTCP,
-						Port:       4369,
-						TargetPort: intstr.FromInt(4369),
-						Name:       "epmd",
+						Protocol:    corev1.ProtocolTCP,
+						Port:        4369,
+						TargetPort:  intstr.FromInt(4369),
+						Name:        "epmd",
+						AppProtocol: ptr.To("tcp"),
 					},
 					{
-						Protocol:   corev1.ProtocolTCP,
-						Port:       25672,
-						TargetPort: intstr.FromInt(25672),
-						Name:       "cluster-rpc",
+						Protocol:    corev1.ProtocolTCP,
+						Port:        25672,
+						TargetPort:  intstr.FromInt(25672),
+						Name:        "cluster-rpc",
+						AppProtocol: ptr.To("tcp"),
 					},
 				},
 				PublishNotReadyAddresses: true,
@@ -216,16 +218,18 @@ var _ = Describe("HeadlessService", func() {
 				SessionAffinity: corev1.ServiceAffinityNone,
 				Ports: []corev1.ServicePort{
 					{
-						Protocol:   corev1.ProtocolTCP,
-						Port:       4369,
-						TargetPort: intstr.FromInt32(4369),
-						Name:       "epmd",
+						Protocol:    corev1.ProtocolTCP,
+						Port:        4369,
+						TargetPort:  intstr.FromInt32(4369),
+						Name:        "epmd",
+						AppProtocol: ptr.To("tcp"),
 					},
 					{
-						Protocol:   corev1.ProtocolTCP,
-						Port:       25672,
-						TargetPort: intstr.FromInt32(25672),
-						Name:       "cluster-rpc",
+						Protocol:    corev1.ProtocolTCP,
+						Port:        25672,
+						TargetPort:  intstr.FromInt32(25672),
+						Name:        "cluster-rpc",
+						AppProtocol: ptr.To("tcp"),
 					},
 				},
 				PublishNotReadyAddresses: true,
@@ -233,6 +237,56 @@ var _ = Describe("HeadlessService", func() {
 			}
 			Expect(service.Spec).To(Equal(expectedSpec))
 		})
+
+		It("sets the IP families", func() {
+			instance.Spec.Service.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.IPFamilies).To(Equal([]corev1.IPFamily{corev1.IPv6Protocol}))
+		})
+
+		It("does not publish not ready addresses when disabled", func() {
+			instance.Spec.HeadlessService.PublishNotReadyAddresses = ptr.To(false)
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.PublishNotReadyAddresses).To(BeFalse())
+		})
+
+		It("adds the CLI tools ports when enabled", func() {
+			instance.Spec.HeadlessService.CLIToolsPorts = true
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(13))
+			Expect(service.Spec.Ports).To(ContainElements(
+				corev1.ServicePort{
+					Protocol:    corev1.ProtocolTCP,
+					Port:        35672,
+					TargetPort:  intstr.FromInt32(35672),
+					Name:        "cli-35672",
+					AppProtocol: ptr.To("tcp"),
+				},
+				HaveField("Port", int32(35682)),
+			))
+		})
+
+		It("adds the additional ports", func() {
+			additionalPort := corev1.ServicePort{
+				Protocol:   corev1.ProtocolTCP,
+				Port:       25673,
+				TargetPort: intstr.FromInt32(25673),
+				Name:       "dist-1",
+			}
+			instance.Spec.HeadlessService.AdditionalPorts = []corev1.ServicePort{additionalPort}
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(3))
+			Expect(service.Spec.Ports[2]).To(Equal(additionalPort))
+		})
+
+		It("prefixes the port names with their protocol when meshPortNames is set", func() {
+			instance.Spec.Service.MeshPortNames = true
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Ports).To(ConsistOf(
+				HaveField("Name", "tcp-epmd"),
+				HaveField("Name", "tcp-cluster-rpc"),
+			))
+		})
 	})
 
 	It("sets owner reference", func() {
@@ -0,0 +1,110 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource
+
+import (
+	"fmt"
+
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	ManagementIngressSuffix = "management"
+)
+
+type ManagementIngressBuilder struct {
+	*RabbitmqResourceBuilder
+}
+
+func (builder *RabbitmqResourceBuilder) ManagementIngress() *ManagementIngressBuilder {
+	return &ManagementIngressBuilder{builder}
+}
+
+func (builder *ManagementIngressBuilder) Build() (client.Object, error) {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      builder.Instance.ChildResourceName(ManagementIngressSuffix),
+			Namespace: builder.Instance.Namespace,
+		},
+	}, nil
+}
+
+func (builder *ManagementIngressBuilder) UpdateMayRequireStsRecreate() bool {
+	return false
+}
+
+func (builder *ManagementIngressBuilder) Update(object client.Object) error {
+	ingress := object.(*networkingv1.Ingress)
+	spec := builder.Instance.Spec.ManagementIngress
+
+	ingress.Labels = builder.childLabels()
+	ingress.Annotations = metadata.ReconcileAnnotations(builder.childAnnotations(ingress.Annotations), spec.Annotations)
+	ingress.Spec.IngressClassName = spec.IngressClassName
+
+	path := spec.Path
+	if path == "" {
+		path = "/"
+	}
+	ingress.Spec.Rules = []networkingv1.IngressRule{
+		{
+			Host: spec.Host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{
+						{
+							Path:     path,
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend:  builder.backend(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ingress.Spec.TLS = nil
+	if spec.TLSSecretName != "" {
+		tls := networkingv1.IngressTLS{SecretName: spec.TLSSecretName}
+		if spec.Host != "" {
+			tls.Hosts = []string{spec.Host}
+		}
+		ingress.Spec.TLS = []networkingv1.IngressTLS{tls}
+	}
+
+	if err := controllerutil.SetControllerReference(builder.Instance, ingress, builder.Scheme); err != nil {
+		return fmt.Errorf("failed setting controller reference: %w", err)
+	}
+
+	return nil
+}
+
+// backend routes to the management Service if enabled, and to the client Service otherwise.
+// The HTTPS management port is used when non-TLS listeners are disabled.
+func (builder *ManagementIngressBuilder) backend() networkingv1.IngressBackend {
+	serviceName := builder.Instance.ChildResourceName(ServiceSuffix)
+	if builder.Instance.ManagementServiceEnabled() {
+		serviceName = builder.Instance.ChildResourceName(ManagementServiceSuffix)
+	}
+	portName := "management"
+	if builder.Instance.DisableNonTLSListeners() {
+		portName = "management-tls"
+	}
+	return networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: serviceName,
+			Port: networkingv1.ServiceBackendPort{Name: builder.servicePortName(portName)},
+		},
+	}
+}
@@ -0,0 +1,147 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	defaultscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("ManagementIngress", func() {
+	var (
+		instance       rabbitmqv1beta1.RabbitmqCluster
+		builder        *resource.RabbitmqResourceBuilder
+		ingressBuilder *resource.ManagementIngressBuilder
+		ingress        *networkingv1.Ingress
+		scheme         *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+		instance = generateRabbitmqCluster()
+		instance.Spec.ManagementIngress = &rabbitmqv1beta1.RabbitmqClusterManagementIngressSpec{
+			Host:             "rabbitmq.example.com",
+			Path:             "/rabbitmq",
+			IngressClassName: ptr.To("nginx"),
+			TLSSecretName:    "rabbitmq-ingress-tls",
+			Annotations:      map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": "true"},
+		}
+		builder = &resource.RabbitmqResourceBuilder{
+			Instance: &instance,
+			Scheme:   scheme,
+		}
+		ingressBuilder = builder.ManagementIngress()
+	})
+
+	Context("Build", func() {
+		It("generates an ingress object with the correct name and namespace", func() {
+			obj, err := ingressBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			ingress = obj.(*networkingv1.Ingress)
+			Expect(ingress.Name).To(Equal(instance.ChildResourceName("management")))
+			Expect(ingress.Namespace).To(Equal(instance.Namespace))
+		})
+	})
+
+	Context("Update", func() {
+		BeforeEach(func() {
+			ingress = &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      instance.ChildResourceName("management"),
+					Namespace: instance.Namespace,
+				},
+			}
+		})
+
+		It("routes the host and path to the management port of the client Service", func() {
+			Expect(ingressBuilder.Update(ingress)).To(Succeed())
+			Expect(ingress.Spec.IngressClassName).To(Equal(ptr.To("nginx")))
+			Expect(ingress.Spec.Rules).To(ConsistOf(networkingv1.IngressRule{
+				Host: "rabbitmq.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/rabbitmq",
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: instance.Name,
+									Port: networkingv1.ServiceBackendPort{Name: "management"},
+								},
+							},
+						}},
+					},
+				},
+			}))
+		})
+
+		It("routes to the management Service when enabled", func() {
+			instance.Spec.ManagementService = &rabbitmqv1beta1.RabbitmqClusterManagementServiceSpec{}
+			Expect(ingressBuilder.Update(ingress)).To(Succeed())
+			Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name).To(Equal(instance.ChildResourceName("management")))
+		})
+
+		It("routes to the HTTPS management port when non-TLS listeners are disabled", func() {
+			instance.Spec.TLS = rabbitmqv1beta1.TLSSpec{
+				SecretName:             "tls-secret",
+				DisableNonTLSListeners: true,
+			}
+			Expect(ingressBuilder.Update(ingress)).To(Succeed())
+			Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Name).To(Equal("management-tls"))
+		})
+
+		It("routes to the mesh port name when meshPortNames is set", func() {
+			instance.Spec.Service.MeshPortNames = true
+			Expect(ingressBuilder.Update(ingress)).To(Succeed())
+			Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Name).To(Equal("http-management"))
+		})
+
+		It("defaults the path to /", func() {
+			instance.Spec.ManagementIngress.Path = ""
+			Expect(ingressBuilder.Update(ingress)).To(Succeed())
+			Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Path).To(Equal("/"))
+		})
+
+		It("configures TLS for the host", func() {
+			Expect(ingressBuilder.Update(ingress)).To(Succeed())
+			Expect(ingress.Spec.TLS).To(ConsistOf(networkingv1.IngressTLS{
+				Hosts:      []string{"rabbitmq.example.com"},
+				SecretName: "rabbitmq-ingress-tls",
+			}))
+
+			instance.Spec.ManagementIngress.TLSSecretName = ""
+			Expect(ingressBuilder.Update(ingress)).To(Succeed())
+			Expect(ingress.Spec.TLS).To(BeNil())
+		})
+
+		It("sets the annotations and labels", func() {
+			instance.Labels = map[string]string{"team": "messaging"}
+			Expect(ingressBuilder.Update(ingress)).To(Succeed())
+			Expect(ingress.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/ssl-redirect", "true"))
+			Expect(ingress.Labels).To(HaveKeyWithValue("team", "messaging"))
+			Expect(ingress.Labels).To(HaveKeyWithValue("app.kubernetes.io/name", instance.Name))
+		})
+
+		It("sets the owner reference", func() {
+			Expect(ingressBuilder.Update(ingress)).To(Succeed())
+			Expect(ingress.OwnerReferences).To(HaveLen(1))
+			Expect(ingress.OwnerReferences[0].Name).To(Equal(instance.Name))
+		})
+	})
+})
@@ -0,0 +1,105 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource
+
+import (
+	"fmt"
+
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	ManagementServiceSuffix = "management"
+)
+
+type ManagementServiceBuilder struct {
+	*RabbitmqResourceBuilder
+}
+
+func (builder *RabbitmqResourceBuilder) ManagementService() *ManagementServiceBuilder {
+	return &ManagementServiceBuilder{builder}
+}
+
+func (builder *ManagementServiceBuilder) Build() (client.Object, error) {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      builder.Instance.ChildResourceName(ManagementServiceSuffix),
+			Namespace: builder.Instance.Namespace,
+		},
+	}, nil
+}
+
+func (builder *ManagementServiceBuilder) UpdateMayRequireStsRecreate() bool {
+	return false
+}
+
+// Preserve keeps the ports of the existing Service, so that node ports allocated by Kubernetes do not change.
+func (builder *ManagementServiceBuilder) Preserve(existing, desired client.Object) {
+	preserveServicePorts(existing, desired)
+}
+
+func (builder *ManagementServiceBuilder) Update(object client.Object) error {
+	service := object.(*corev1.Service)
+	spec := builder.Instance.Spec.ManagementService
+
+	service.Labels = builder.childLabels()
+	service.Annotations = metadata.ReconcileAnnotations(builder.childAnnotations(service.Annotations), spec.Annotations)
+	service.Spec.Type = spec.Type
+	service.Spec.Selector = metadata.LabelSelector(builder.Instance.Name)
+	service.Spec.IPFamilyPolicy = builder.Instance.Spec.Service.IPFamilyPolicy
+	updateIPFamilies(service, builder.Instance.Spec.Service.IPFamilies)
+
+	// node ports allocated by Kubernetes are kept unless the Service type is ClusterIP
+	nodePorts := map[string]int32{}
+	for _, port := range service.Spec.Ports {
+		nodePorts[port.Name] = port.NodePort
+	}
+	service.Spec.Ports = builder.managementPorts()
+	if spec.Type != corev1.ServiceTypeClusterIP && spec.Type != "" {
+		for i := range service.Spec.Ports {
+			service.Spec.Ports[i].NodePort = nodePorts[service.Spec.Ports[i].Name]
+		}
+	}
+
+	if err := controllerutil.SetControllerReference(builder.Instance, service, builder.Scheme); err != nil {
+		return fmt.Errorf("failed setting controller reference: %w", err)
+	}
+
+	return nil
+}
+
+func (builder *ManagementServiceBuilder) managementPorts() []corev1.ServicePort {
+	var ports []corev1.ServicePort
+	if !builder.Instance.DisableNonTLSListeners() {
+		ports = append(ports, corev1.ServicePort{
+			Protocol:    corev1.ProtocolTCP,
+			Port:        15672,
+			TargetPort:  intstr.FromInt(15672),
+			Name:        builder.servicePortName("management"),
+			AppProtocol: ptr.To("http"),
+		})
+	}
+	if builder.Instance.TLSEnabled() {
+		ports = append(ports, corev1.ServicePort{
+			Protocol:    corev1.ProtocolTCP,
+			Port:        15671,
+			TargetPort:  intstr.FromInt(15671),
+			Name:        builder.servicePortName("management-tls"),
+			AppProtocol: ptr.To("https"),
+		})
+	}
+	return ports
+}
@@ -0,0 +1,115 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	defaultscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("ManagementService", func() {
+	var (
+		instance       rabbitmqv1beta1.RabbitmqCluster
+		builder        *resource.RabbitmqResourceBuilder
+		serviceBuilder *resource.ManagementServiceBuilder
+		service        *corev1.Service
+		scheme         *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+		instance = generateRabbitmqCluster()
+		instance.Spec.ManagementService = &rabbitmqv1beta1.RabbitmqClusterManagementServiceSpec{
+			Type:        corev1.ServiceTypeLoadBalancer,
+			Annotations: map[string]string{"service.example.com/internal": "true"},
+		}
+		builder = &resource.RabbitmqResourceBuilder{
+			Instance: &instance,
+			Scheme:   scheme,
+		}
+		serviceBuilder = builder.ManagementService()
+	})
+
+	Context("Build", func() {
+		It("generates a service object with the correct name and namespace", func() {
+			obj, err := serviceBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			service = obj.(*corev1.Service)
+			Expect(service.Name).To(Equal(instance.ChildResourceName("management")))
+			Expect(service.Namespace).To(Equal(instance.Namespace))
+		})
+	})
+
+	Context("Update", func() {
+		BeforeEach(func() {
+			service = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      instance.ChildResourceName("management"),
+					Namespace: instance.Namespace,
+				},
+			}
+		})
+
+		It("exposes only the management port", func() {
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Ports).To(ConsistOf(corev1.ServicePort{
+				Protocol:    corev1.ProtocolTCP,
+				Port:        15672,
+				TargetPort:  intstr.FromInt(15672),
+				Name:        "management",
+				AppProtocol: ptr.To("http"),
+			}))
+		})
+
+		It("exposes the management TLS port when TLS is enabled", func() {
+			instance.Spec.TLS.SecretName = "tls-secret"
+			instance.Spec.TLS.DisableNonTLSListeners = true
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Ports).To(ConsistOf(HaveField("Name", "management-tls")))
+		})
+
+		It("sets the type, annotations, labels and selector", func() {
+			instance.Annotations = map[string]string{"my-annotation": "i-like-this"}
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+			Expect(service.Annotations).To(Equal(map[string]string{
+				"my-annotation":                "i-like-this",
+				"service.example.com/internal": "true",
+			}))
+			Expect(service.Labels).To(HaveKeyWithValue("app.kubernetes.io/part-of", "rabbitmq"))
+			Expect(service.Spec.Selector).To(Equal(map[string]string{"app.kubernetes.io/name": instance.Name}))
+		})
+
+		It("keeps allocated node ports unless the type is ClusterIP", func() {
+			service.Spec.Ports = []corev1.ServicePort{{Name: "management", Port: 15672, NodePort: 31672}}
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Ports[0].NodePort).To(Equal(int32(31672)))
+
+			instance.Spec.ManagementService.Type = corev1.ServiceTypeClusterIP
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Ports[0].NodePort).To(BeZero())
+		})
+
+		It("sets the owner reference", func() {
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.OwnerReferences).To(ConsistOf(HaveField("Name", instance.Name)))
+		})
+	})
+})
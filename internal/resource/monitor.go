@@ -0,0 +1,164 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource
+
+import (
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const MonitorSuffix = ""
+
+// ServiceMonitors and PodMonitors are handled as unstructured objects so that the operator
+// does not depend on the Prometheus Operator types.
+var (
+	ServiceMonitorGroupVersionKind = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	PodMonitorGroupVersionKind     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+)
+
+// NewMonitor returns an empty ServiceMonitor or PodMonitor with the given name and namespace.
+func NewMonitor(gvk schema.GroupVersionKind, name, namespace string) *unstructured.Unstructured {
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(gvk)
+	monitor.SetName(name)
+	monitor.SetNamespace(namespace)
+	return monitor
+}
+
+type MonitorBuilder struct {
+	*RabbitmqResourceBuilder
+}
+
+func (builder *RabbitmqResourceBuilder) Monitor() *MonitorBuilder {
+	return &MonitorBuilder{builder}
+}
+
+// MonitorGroupVersionKind returns the kind of monitor set in spec.monitoring.kind, ServiceMonitor if it is not set.
+func MonitorGroupVersionKind(instance *rabbitmqv1beta1.RabbitmqCluster) schema.GroupVersionKind {
+	if instance.Spec.Monitoring != nil && instance.Spec.Monitoring.Kind == PodMonitorGroupVersionKind.Kind {
+		return PodMonitorGroupVersionKind
+	}
+	return ServiceMonitorGroupVersionKind
+}
+
+func (builder *MonitorBuilder) Build() (client.Object, error) {
+	return NewMonitor(MonitorGroupVersionKind(builder.Instance), builder.Instance.ChildResourceName(MonitorSuffix), builder.Instance.Namespace), nil
+}
+
+func (builder *MonitorBuilder) UpdateMayRequireStsRecreate() bool {
+	return false
+}
+
+func (builder *MonitorBuilder) Update(object client.Object) error {
+	monitor := object.(*unstructured.Unstructured)
+	spec := builder.Instance.Spec.Monitoring
+
+	labels := builder.childLabels()
+	for label, value := range spec.Labels {
+		labels[label] = value
+	}
+	monitor.SetLabels(labels)
+	monitor.SetAnnotations(builder.childAnnotations(monitor.GetAnnotations()))
+
+	serviceMonitor := MonitorGroupVersionKind(builder.Instance) == ServiceMonitorGroupVersionKind
+	var relabelings []interface{}
+	// only the client Service is scraped, since the Pod Services expose the same metrics port
+	if serviceMonitor {
+		relabelings = append(relabelings, map[string]interface{}{
+			"sourceLabels": []interface{}{"__meta_kubernetes_service_name"},
+			"regex":        builder.Instance.ChildResourceName(ServiceSuffix),
+			"action":       "keep",
+		})
+	}
+	// the client Service only exposes the TLS port of the metrics if TLS is enabled
+	port := "prometheus"
+	endpoint := map[string]interface{}{}
+	if builder.Instance.TLSEnabled() {
+		port = "prometheus-tls"
+		endpoint["scheme"] = "https"
+		endpoint["tlsConfig"] = builder.monitorTLSConfig()
+	}
+	if serviceMonitor {
+		port = builder.servicePortName(port)
+	}
+	endpoint["port"] = port
+	if spec.Interval != "" {
+		endpoint["interval"] = spec.Interval
+	}
+	for _, relabeling := range spec.Relabelings {
+		converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&relabeling)
+		if err != nil {
+			return fmt.Errorf("failed to convert relabeling: %w", err)
+		}
+		relabelings = append(relabelings, converted)
+	}
+	if len(relabelings) > 0 {
+		endpoint["relabelings"] = relabelings
+	}
+
+	endpointsField := "podMetricsEndpoints"
+	if serviceMonitor {
+		endpointsField = "endpoints"
+	}
+	monitorSpec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": stringMap(metadata.LabelSelector(builder.Instance.Name)),
+		},
+		endpointsField: []interface{}{endpoint},
+	}
+	if err := unstructured.SetNestedMap(monitor.Object, monitorSpec, "spec"); err != nil {
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(builder.Instance, monitor, builder.Scheme); err != nil {
+		return fmt.Errorf("failed setting controller reference: %w", err)
+	}
+	return nil
+}
+
+// monitorTLSConfig returns the TLS settings with which Prometheus verifies the certificates of the RabbitMQ nodes.
+func (builder *MonitorBuilder) monitorTLSConfig() map[string]interface{} {
+	caSecretName := builder.Instance.Spec.TLS.CaSecretName
+	if caSecretName == "" {
+		caSecretName = builder.Instance.Spec.TLS.SecretName
+	}
+	tlsConfig := map[string]interface{}{
+		"ca": map[string]interface{}{
+			"secret": map[string]interface{}{
+				"name": caSecretName,
+				"key":  "ca.crt",
+			},
+		},
+	}
+	if tls := builder.Instance.Spec.Monitoring.TLS; tls != nil {
+		if tls.ServerName != "" {
+			tlsConfig["serverName"] = tls.ServerName
+		}
+		if tls.InsecureSkipVerify {
+			tlsConfig["insecureSkipVerify"] = true
+		}
+	}
+	return tlsConfig
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	converted := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		converted[key] = value
+	}
+	return converted
+}
@@ -0,0 +1,135 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	defaultscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Monitor", func() {
+	var (
+		instance rabbitmqv1beta1.RabbitmqCluster
+		builder  *resource.RabbitmqResourceBuilder
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+		instance = generateRabbitmqCluster()
+		instance.Spec.Monitoring = &rabbitmqv1beta1.RabbitmqClusterMonitoringSpec{
+			Enabled:  true,
+			Interval: "30s",
+			Labels:   map[string]string{"release": "prometheus"},
+			Relabelings: []rabbitmqv1beta1.RelabelConfig{{
+				SourceLabels: []string{"__meta_kubernetes_pod_node_name"},
+				TargetLabel:  "node",
+				Replacement:  ptr.To("$1"),
+			}},
+		}
+		builder = &resource.RabbitmqResourceBuilder{
+			Instance: &instance,
+			Scheme:   scheme,
+		}
+	})
+
+	build := func() *unstructured.Unstructured {
+		obj, err := builder.Monitor().Build()
+		Expect(err).NotTo(HaveOccurred())
+		monitor := obj.(*unstructured.Unstructured)
+		Expect(builder.Monitor().Update(monitor)).To(Succeed())
+		return monitor
+	}
+
+	It("builds a ServiceMonitor scraping the metrics port of the client Service", func() {
+		monitor := build()
+		Expect(monitor.GroupVersionKind()).To(Equal(resource.ServiceMonitorGroupVersionKind))
+		Expect(monitor.GetName()).To(Equal(instance.Name))
+		Expect(monitor.GetNamespace()).To(Equal(instance.Namespace))
+		Expect(monitor.GetLabels()).To(HaveKeyWithValue("release", "prometheus"))
+		Expect(monitor.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/name", instance.Name))
+		Expect(monitor.GetOwnerReferences()).To(HaveLen(1))
+		Expect(monitor.Object["spec"]).To(Equal(map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app.kubernetes.io/name": instance.Name},
+			},
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"port":     "prometheus",
+					"interval": "30s",
+					"relabelings": []interface{}{
+						map[string]interface{}{
+							"sourceLabels": []interface{}{"__meta_kubernetes_service_name"},
+							"regex":        instance.Name,
+							"action":       "keep",
+						},
+						map[string]interface{}{
+							"sourceLabels": []interface{}{"__meta_kubernetes_pod_node_name"},
+							"targetLabel":  "node",
+							"replacement":  "$1",
+						},
+					},
+				},
+			},
+		}))
+	})
+
+	It("builds a PodMonitor scraping the metrics port of the Pods", func() {
+		instance.Spec.Monitoring.Kind = "PodMonitor"
+		instance.Spec.Monitoring.Relabelings = nil
+		monitor := build()
+		Expect(monitor.GroupVersionKind()).To(Equal(resource.PodMonitorGroupVersionKind))
+		Expect(monitor.Object["spec"]).To(Equal(map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app.kubernetes.io/name": instance.Name},
+			},
+			"podMetricsEndpoints": []interface{}{
+				map[string]interface{}{
+					"port":     "prometheus",
+					"interval": "30s",
+				},
+			},
+		}))
+	})
+
+	It("scrapes the TLS metrics port if TLS is enabled", func() {
+		instance.Spec.TLS = rabbitmqv1beta1.TLSSpec{SecretName: "tls-secret", CaSecretName: "ca-secret"}
+		instance.Spec.Monitoring.TLS = &rabbitmqv1beta1.RabbitmqClusterMonitoringTLSSpec{ServerName: "rabbit.example.com"}
+		monitor := build()
+		endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+		Expect(endpoints[0]).To(HaveKeyWithValue("port", "prometheus-tls"))
+		Expect(endpoints[0]).To(HaveKeyWithValue("scheme", "https"))
+		Expect(endpoints[0]).To(HaveKeyWithValue("tlsConfig", map[string]interface{}{
+			"ca": map[string]interface{}{
+				"secret": map[string]interface{}{"name": "ca-secret", "key": "ca.crt"},
+			},
+			"serverName": "rabbit.example.com",
+		}))
+	})
+
+	It("is only built when monitoring is enabled and the Prometheus Operator is available", func() {
+		builder.PrometheusOperatorAvailable = true
+		Expect(builder.ResourceBuilders()).To(ContainElement(BeAssignableToTypeOf(&resource.MonitorBuilder{})))
+
+		instance.Spec.Monitoring.Enabled = false
+		Expect(builder.ResourceBuilders()).NotTo(ContainElement(BeAssignableToTypeOf(&resource.MonitorBuilder{})))
+
+		instance.Spec.Monitoring.Enabled = true
+		builder.PrometheusOperatorAvailable = false
+		Expect(builder.ResourceBuilders()).NotTo(ContainElement(BeAssignableToTypeOf(&resource.MonitorBuilder{})))
+	})
+})
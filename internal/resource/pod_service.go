@@ -0,0 +1,99 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource
+
+import (
+	"fmt"
+	"sort"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const podNameLabel = "statefulset.kubernetes.io/pod-name"
+
+type PodServiceBuilder struct {
+	*RabbitmqResourceBuilder
+	Ordinal int32
+}
+
+func (builder *RabbitmqResourceBuilder) PodService(ordinal int32) *PodServiceBuilder {
+	return &PodServiceBuilder{builder, ordinal}
+}
+
+// PodServiceName returns the name of the Service of the RabbitMQ Pod with the given ordinal, which is the name of the Pod.
+func PodServiceName(instance *rabbitmqv1beta1.RabbitmqCluster, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", instance.ChildResourceName(stsSuffix), ordinal)
+}
+
+func (builder *PodServiceBuilder) Build() (client.Object, error) {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PodServiceName(builder.Instance, builder.Ordinal),
+			Namespace: builder.Instance.Namespace,
+		},
+	}, nil
+}
+
+func (builder *PodServiceBuilder) UpdateMayRequireStsRecreate() bool {
+	return false
+}
+
+// Preserve keeps the ports of the existing Service, so that node ports allocated by Kubernetes do not change.
+func (builder *PodServiceBuilder) Preserve(existing, desired client.Object) {
+	preserveServicePorts(existing, desired)
+}
+
+func (builder *PodServiceBuilder) Update(object client.Object) error {
+	service := object.(*corev1.Service)
+	spec := builder.Instance.Spec.PodServices
+
+	service.Labels = builder.childLabels()
+	service.Annotations = metadata.ReconcileAnnotations(builder.childAnnotations(service.Annotations), spec.Annotations)
+	service.Spec.Type = spec.Type
+	if service.Spec.Type == "" {
+		service.Spec.Type = corev1.ServiceTypeLoadBalancer
+	}
+	service.Spec.Selector = metadata.LabelSelector(builder.Instance.Name)
+	service.Spec.Selector[podNameLabel] = service.Name
+	service.Spec.IPFamilyPolicy = builder.Instance.Spec.Service.IPFamilyPolicy
+	updateIPFamilies(service, builder.Instance.Spec.Service.IPFamilies)
+
+	service.Spec.ExternalTrafficPolicy = spec.ExternalTrafficPolicy
+	if service.Spec.ExternalTrafficPolicy == "" {
+		// defaulted by Kubernetes; keeping the default avoids updating the Service on every reconcile
+		service.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+	}
+
+	// node ports allocated by Kubernetes are kept
+	nodePorts := map[string]int32{}
+	for _, port := range service.Spec.Ports {
+		nodePorts[port.Name] = port.NodePort
+	}
+	var ports []corev1.ServicePort
+	for _, port := range builder.Service().generateServicePortsMap() {
+		port.NodePort = nodePorts[port.Name]
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Name < ports[j].Name
+	})
+	service.Spec.Ports = ports
+
+	if err := controllerutil.SetControllerReference(builder.Instance, service, builder.Scheme); err != nil {
+		return fmt.Errorf("failed setting controller reference: %w", err)
+	}
+
+	return nil
+}
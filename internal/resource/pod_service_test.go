@@ -0,0 +1,128 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	defaultscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("PodService", func() {
+	var (
+		instance       rabbitmqv1beta1.RabbitmqCluster
+		builder        *resource.RabbitmqResourceBuilder
+		serviceBuilder *resource.PodServiceBuilder
+		service        *corev1.Service
+		scheme         *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+		instance = generateRabbitmqCluster()
+		instance.Spec.PodServices = &rabbitmqv1beta1.RabbitmqClusterPodServicesSpec{
+			Annotations: map[string]string{"external-dns.alpha.kubernetes.io/hostname": "rabbit.example.com"},
+		}
+		builder = &resource.RabbitmqResourceBuilder{
+			Instance: &instance,
+			Scheme:   scheme,
+		}
+		serviceBuilder = builder.PodService(2)
+	})
+
+	Context("Build", func() {
+		It("generates a service object named like the Pod", func() {
+			obj, err := serviceBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			service = obj.(*corev1.Service)
+			Expect(service.Name).To(Equal(instance.ChildResourceName("server-2")))
+			Expect(service.Namespace).To(Equal(instance.Namespace))
+		})
+	})
+
+	Context("Update", func() {
+		BeforeEach(func() {
+			service = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      instance.ChildResourceName("server-2"),
+					Namespace: instance.Namespace,
+				},
+			}
+		})
+
+		It("selects only its Pod", func() {
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Selector).To(Equal(map[string]string{
+				"app.kubernetes.io/name":             instance.Name,
+				"statefulset.kubernetes.io/pod-name": instance.ChildResourceName("server-2"),
+			}))
+		})
+
+		It("defaults the type to LoadBalancer", func() {
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+
+			instance.Spec.PodServices.Type = corev1.ServiceTypeNodePort
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+		})
+
+		It("exposes the ports of the client Service", func() {
+			instance.Spec.Rabbitmq.AdditionalPlugins = []rabbitmqv1beta1.Plugin{"rabbitmq_stream"}
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Ports).To(ConsistOf(
+				HaveField("Name", "amqp"),
+				HaveField("Name", "management"),
+				HaveField("Name", "prometheus"),
+				HaveField("Name", "stream"),
+			))
+		})
+
+		It("keeps allocated node ports", func() {
+			service.Spec.Ports = []corev1.ServicePort{{Name: "amqp", Port: 5672, NodePort: 30672}}
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.Ports).To(ContainElement(SatisfyAll(
+				HaveField("Name", "amqp"),
+				HaveField("NodePort", int32(30672)),
+			)))
+		})
+
+		It("sets the external traffic policy, which defaults to Cluster", func() {
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.ExternalTrafficPolicy).To(Equal(corev1.ServiceExternalTrafficPolicyCluster))
+
+			instance.Spec.PodServices.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Spec.ExternalTrafficPolicy).To(Equal(corev1.ServiceExternalTrafficPolicyLocal))
+		})
+
+		It("sets the annotations and labels", func() {
+			instance.Annotations = map[string]string{"my-annotation": "i-like-this"}
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.Annotations).To(Equal(map[string]string{
+				"my-annotation": "i-like-this",
+				"external-dns.alpha.kubernetes.io/hostname": "rabbit.example.com",
+			}))
+			Expect(service.Labels).To(HaveKeyWithValue("app.kubernetes.io/part-of", "rabbitmq"))
+		})
+
+		It("sets the owner reference", func() {
+			Expect(serviceBuilder.Update(service)).To(Succeed())
+			Expect(service.OwnerReferences).To(ConsistOf(HaveField("Name", instance.Name)))
+		})
+	})
+})
@@ -0,0 +1,155 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const PrometheusRuleSuffix = ""
+
+// PrometheusRules are handled as unstructured objects so that the operator does not depend on the Prometheus Operator types.
+var PrometheusRuleGroupVersionKind = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+
+// NewPrometheusRule returns an empty PrometheusRule with the given name and namespace.
+func NewPrometheusRule(name, namespace string) *unstructured.Unstructured {
+	rule := &unstructured.Unstructured{}
+	rule.SetGroupVersionKind(PrometheusRuleGroupVersionKind)
+	rule.SetName(name)
+	rule.SetNamespace(namespace)
+	return rule
+}
+
+type PrometheusRuleBuilder struct {
+	*RabbitmqResourceBuilder
+}
+
+func (builder *RabbitmqResourceBuilder) PrometheusRule() *PrometheusRuleBuilder {
+	return &PrometheusRuleBuilder{builder}
+}
+
+func (builder *PrometheusRuleBuilder) Build() (client.Object, error) {
+	return NewPrometheusRule(builder.Instance.ChildResourceName(PrometheusRuleSuffix), builder.Instance.Namespace), nil
+}
+
+func (builder *PrometheusRuleBuilder) UpdateMayRequireStsRecreate() bool {
+	return false
+}
+
+func (builder *PrometheusRuleBuilder) Update(object client.Object) error {
+	rule := object.(*unstructured.Unstructured)
+	spec := builder.Instance.Spec.Monitoring.PrometheusRule
+
+	labels := builder.childLabels()
+	for label, value := range spec.Labels {
+		labels[label] = value
+	}
+	rule.SetLabels(labels)
+	rule.SetAnnotations(builder.childAnnotations(rule.GetAnnotations()))
+
+	if err := unstructured.SetNestedSlice(rule.Object, []interface{}{
+		map[string]interface{}{
+			"name":  "rabbitmq-" + builder.Instance.Namespace + "-" + builder.Instance.Name,
+			"rules": builder.alertingRules(),
+		},
+	}, "spec", "groups"); err != nil {
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(builder.Instance, rule, builder.Scheme); err != nil {
+		return fmt.Errorf("failed setting controller reference: %w", err)
+	}
+	return nil
+}
+
+// alertingRules returns the alerts of the RabbitmqCluster. The expressions only select the series of the RabbitMQ Pods
+// and of the StatefulSet of this RabbitmqCluster, so that the alerts of each RabbitmqCluster are independent.
+func (builder *PrometheusRuleBuilder) alertingRules() []interface{} {
+	namespace := builder.Instance.Namespace
+	statefulSet := builder.Instance.ChildResourceName(stsSuffix)
+	pods := fmt.Sprintf(`namespace="%s",pod=~"%s-[0-9]+"`, namespace, statefulSet)
+	sts := fmt.Sprintf(`namespace="%s",statefulset="%s"`, namespace, statefulSet)
+	nodes := fmt.Sprintf("count(rabbitmq_identity_info{%s})", pods)
+	cluster := namespace + "/" + builder.Instance.Name
+
+	alerts := []struct {
+		name, expr, duration, severity, summary, description string
+	}{
+		{
+			name:        "RabbitMQMemoryAlarm",
+			expr:        fmt.Sprintf("max by (namespace, pod) (rabbitmq_alarms_memory_used_watermark{%s}) > 0", pods),
+			duration:    "1m",
+			severity:    "critical",
+			summary:     "A RabbitMQ node raised a memory alarm",
+			description: "RabbitMQ node {{ $labels.pod }} uses more memory than its high watermark and blocks all publishers.",
+		},
+		{
+			name:        "RabbitMQDiskAlarm",
+			expr:        fmt.Sprintf("max by (namespace, pod) (rabbitmq_alarms_free_disk_space_watermark{%s}) > 0", pods),
+			duration:    "1m",
+			severity:    "critical",
+			summary:     "A RabbitMQ node raised a disk alarm",
+			description: "RabbitMQ node {{ $labels.pod }} has less free disk space than its limit and blocks all publishers.",
+		},
+		{
+			name: "RabbitMQPartitionDetected",
+			// every node has an established distribution link to every other node unless the cluster is partitioned
+			expr:        fmt.Sprintf("count(erlang_vm_dist_node_state{%s} == 3) < %s * (%s - 1)", pods, nodes, nodes),
+			duration:    "5m",
+			severity:    "critical",
+			summary:     "RabbitMQ nodes cannot reach each other",
+			description: fmt.Sprintf("Some nodes of RabbitmqCluster %s have no established connection to each other, which indicates a network partition.", cluster),
+		},
+		{
+			name: "RabbitMQUnroutableMessages",
+			expr: fmt.Sprintf("sum(rate(rabbitmq_global_messages_unroutable_dropped_total{%s}[5m])) + sum(rate(rabbitmq_global_messages_unroutable_returned_total{%s}[5m])) > 0",
+				pods, pods),
+			duration:    "5m",
+			severity:    "warning",
+			summary:     "RabbitMQ drops or returns unroutable messages",
+			description: fmt.Sprintf("Messages published to RabbitmqCluster %s do not match any binding and are dropped or returned to the publishers.", cluster),
+		},
+		{
+			name:        "RabbitMQNoQuorum",
+			expr:        fmt.Sprintf("kube_statefulset_status_replicas_ready{%s} <= kube_statefulset_replicas{%s} / 2", sts, sts),
+			duration:    "5m",
+			severity:    "critical",
+			summary:     "Less than a majority of the RabbitMQ nodes are ready",
+			description: fmt.Sprintf("Quorum queues and streams of RabbitmqCluster %s are unavailable, since only {{ $value }} nodes are ready.", cluster),
+		},
+	}
+
+	rules := make([]interface{}, 0, len(alerts))
+	for _, alert := range alerts {
+		labels := map[string]interface{}{}
+		for label, value := range builder.Instance.Spec.Monitoring.PrometheusRule.AlertLabels {
+			labels[label] = value
+		}
+		labels["severity"] = alert.severity
+		labels["namespace"] = namespace
+		labels["rabbitmq_cluster"] = builder.Instance.Name
+		rules = append(rules, map[string]interface{}{
+			"alert":  alert.name,
+			"expr":   alert.expr,
+			"for":    alert.duration,
+			"labels": labels,
+			"annotations": map[string]interface{}{
+				"summary":     alert.summary,
+				"description": alert.description,
+			},
+		})
+	}
+	return rules
+}
@@ -0,0 +1,114 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	defaultscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("PrometheusRule", func() {
+	var (
+		instance rabbitmqv1beta1.RabbitmqCluster
+		builder  *resource.RabbitmqResourceBuilder
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+		instance = generateRabbitmqCluster()
+		instance.Spec.Monitoring = &rabbitmqv1beta1.RabbitmqClusterMonitoringSpec{
+			Enabled: true,
+			PrometheusRule: &rabbitmqv1beta1.RabbitmqClusterPrometheusRuleSpec{
+				Labels:      map[string]string{"release": "prometheus"},
+				AlertLabels: map[string]string{"team": "messaging"},
+			},
+		}
+		builder = &resource.RabbitmqResourceBuilder{
+			Instance: &instance,
+			Scheme:   scheme,
+		}
+	})
+
+	build := func() *unstructured.Unstructured {
+		obj, err := builder.PrometheusRule().Build()
+		Expect(err).NotTo(HaveOccurred())
+		rule := obj.(*unstructured.Unstructured)
+		Expect(builder.PrometheusRule().Update(rule)).To(Succeed())
+		return rule
+	}
+
+	alerts := func(rule *unstructured.Unstructured) []interface{} {
+		groups, found, err := unstructured.NestedSlice(rule.Object, "spec", "groups")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(groups).To(HaveLen(1))
+		Expect(groups[0]).To(HaveKeyWithValue("name", "rabbitmq-"+instance.Namespace+"-"+instance.Name))
+		return groups[0].(map[string]interface{})["rules"].([]interface{})
+	}
+
+	It("builds a PrometheusRule named like the RabbitmqCluster", func() {
+		rule := build()
+		Expect(rule.GroupVersionKind()).To(Equal(resource.PrometheusRuleGroupVersionKind))
+		Expect(rule.GetName()).To(Equal(instance.Name))
+		Expect(rule.GetNamespace()).To(Equal(instance.Namespace))
+		Expect(rule.GetLabels()).To(HaveKeyWithValue("release", "prometheus"))
+		Expect(rule.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/name", instance.Name))
+		Expect(rule.GetOwnerReferences()).To(HaveLen(1))
+	})
+
+	It("creates the alerts of the RabbitmqCluster", func() {
+		var names []string
+		for _, alert := range alerts(build()) {
+			names = append(names, alert.(map[string]interface{})["alert"].(string))
+		}
+		Expect(names).To(ConsistOf(
+			"RabbitMQMemoryAlarm",
+			"RabbitMQDiskAlarm",
+			"RabbitMQPartitionDetected",
+			"RabbitMQUnroutableMessages",
+			"RabbitMQNoQuorum",
+		))
+	})
+
+	It("only selects the series of the RabbitmqCluster", func() {
+		for _, alert := range alerts(build()) {
+			expr := alert.(map[string]interface{})["expr"].(string)
+			Expect(expr).To(ContainSubstring(`namespace="` + instance.Namespace + `"`))
+			Expect(expr).To(Or(
+				ContainSubstring(`pod=~"`+instance.Name+`-server-[0-9]+"`),
+				ContainSubstring(`statefulset="`+instance.Name+`-server"`),
+			))
+		}
+	})
+
+	It("labels the alerts", func() {
+		for _, alert := range alerts(build()) {
+			labels := alert.(map[string]interface{})["labels"]
+			Expect(labels).To(HaveKeyWithValue("team", "messaging"))
+			Expect(labels).To(HaveKeyWithValue("namespace", instance.Namespace))
+			Expect(labels).To(HaveKeyWithValue("rabbitmq_cluster", instance.Name))
+			Expect(labels).To(HaveKey("severity"))
+		}
+	})
+
+	It("does not let alert labels override the labels set by the operator", func() {
+		instance.Spec.Monitoring.PrometheusRule.AlertLabels = map[string]string{"rabbitmq_cluster": "other"}
+		for _, alert := range alerts(build()) {
+			Expect(alert.(map[string]interface{})["labels"]).To(HaveKeyWithValue("rabbitmq_cluster", instance.Name))
+		}
+	})
+})
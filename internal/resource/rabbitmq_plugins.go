@@ -9,7 +9,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
-	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -35,8 +34,8 @@ func (builder *RabbitmqPluginsConfigMapBuilder) Build() (client.Object, error) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        builder.Instance.ChildResourceName(PluginsConfigName),
 			Namespace:   builder.Instance.Namespace,
-			Labels:      metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels),
-			Annotations: metadata.ReconcileAndFilterAnnotations(nil, builder.Instance.Annotations),
+			Labels:      builder.childLabels(),
+			Annotations: builder.childAnnotations(nil),
 		},
 		Data: map[string]string{
 			"enabled_plugins": desiredPluginsAsString([]rabbitmqv1beta1.Plugin{}),
@@ -54,7 +53,7 @@ func (builder *RabbitmqPluginsConfigMapBuilder) Update(object client.Object) err
 	if configMap.Data == nil {
 		configMap.Data = make(map[string]string)
 	}
-	configMap.Data["enabled_plugins"] = desiredPluginsAsString(builder.Instance.Spec.Rabbitmq.AdditionalPlugins)
+	configMap.Data["enabled_plugins"] = desiredPluginsAsString(builder.Instance.RequestedPlugins())
 
 	if err := controllerutil.SetControllerReference(builder.Instance, configMap, builder.Scheme); err != nil {
 		return fmt.Errorf("failed setting controller reference: %w", err)
@@ -80,7 +79,9 @@ func NewRabbitmqPlugins(plugins []rabbitmqv1beta1.Plugin) RabbitmqPlugins {
 }
 
 func (r *RabbitmqPlugins) DesiredPlugins() []string {
-	allPlugins := append(r.requiredPlugins, r.additionalPlugins...)
+	allPlugins := make([]string, 0, len(r.requiredPlugins)+len(r.additionalPlugins))
+	allPlugins = append(allPlugins, r.requiredPlugins...)
+	allPlugins = append(allPlugins, r.additionalPlugins...)
 
 	check := make(map[string]bool)
 	enabledPlugins := make([]string, 0)
@@ -218,6 +218,36 @@ var _ = Describe("RabbitMQPlugins", func() {
 				})
 			})
 
+			It("enables the OAuth2 backend plugin when OAuth2 is configured", func() {
+				builder.Instance.Spec.Rabbitmq.Auth.OAuth2 = &rabbitmqv1beta1.OAuth2Spec{ResourceServerID: "rabbitmq"}
+
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(configMap.Data).To(HaveKeyWithValue("enabled_plugins", "[rabbitmq_peer_discovery_k8s,rabbitmq_prometheus,rabbitmq_management,rabbitmq_auth_backend_oauth2]."))
+			})
+
+			It("enables the plugins of spec.rabbitmq.mqtt and spec.rabbitmq.stomp", func() {
+				builder.Instance.Spec.Rabbitmq.MQTT = &rabbitmqv1beta1.MQTTSpec{WebSockets: true}
+				builder.Instance.Spec.Rabbitmq.STOMP = &rabbitmqv1beta1.STOMPSpec{}
+
+				Expect(configMapBuilder.Update(configMap)).To(Succeed())
+				Expect(configMap.Data).To(HaveKeyWithValue("enabled_plugins", "[rabbitmq_peer_discovery_k8s,rabbitmq_prometheus,rabbitmq_management,rabbitmq_mqtt,rabbitmq_web_mqtt,rabbitmq_stomp]."))
+			})
+
+			When("enabled_plugins was edited by hand", func() {
+				BeforeEach(func() {
+					configMap.Data = map[string]string{
+						"enabled_plugins": "[rabbitmq_peer_discovery_k8s,rabbitmq_prometheus,rabbitmq_management,rabbitmq_mqtt].",
+					}
+				})
+
+				It("only keeps the required plugins and additionalPlugins", func() {
+					builder.Instance.Spec.Rabbitmq.AdditionalPlugins = []rabbitmqv1beta1.Plugin{"rabbitmq_shovel"}
+
+					Expect(configMapBuilder.Update(configMap)).To(Succeed())
+					Expect(configMap.Data).To(HaveKeyWithValue("enabled_plugins", "[rabbitmq_peer_discovery_k8s,rabbitmq_prometheus,rabbitmq_management,rabbitmq_shovel]."))
+				})
+			})
+
 			// ensures that we are not unnecessarily running `rabbitmq-plugins set` when CR labels are updated
 			It("does not update labels on the config map", func() {
 				configMap.Labels = map[string]string{
@@ -11,13 +11,35 @@ package resource
 
 import (
 	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// FieldManager is the field manager the operator applies child resources with.
+const FieldManager = "rabbitmq-cluster-operator"
+
 type RabbitmqResourceBuilder struct {
 	Instance *rabbitmqv1beta1.RabbitmqCluster
 	Scheme   *runtime.Scheme
+	// LabelMappings copies RabbitmqCluster labels to child resources under a different key,
+	// keyed by the RabbitmqCluster label. For example, {"team": "example.com/team"}.
+	LabelMappings map[string]string
+	// RouteAPIAvailable is true when the OpenShift Route API is served by the Kubernetes cluster.
+	RouteAPIAvailable bool
+	// GatewayAPIAvailable is true when the Gateway API TCPRoute and TLSRoute resources are served by the Kubernetes cluster.
+	GatewayAPIAvailable bool
+	// PrometheusOperatorAvailable is true when the ServiceMonitor, PodMonitor and PrometheusRule resources are served by the Kubernetes cluster.
+	PrometheusOperatorAvailable bool
+	// ConfigFrom holds the rabbitmq.conf settings of the ConfigMap referenced by spec.rabbitmq.configFrom.
+	ConfigFrom string
+	// DefaultUser holds the credentials of the Secret referenced by spec.secretBackend.existingAdminSecret.
+	// Random credentials are generated when nil.
+	DefaultUser *DefaultUserCredentials
+	// InjectedLabels and InjectedAnnotations are set on all child resources by the operator configuration.
+	// Labels and annotations of the RabbitmqCluster take precedence.
+	InjectedLabels      map[string]string
+	InjectedAnnotations map[string]string
 }
 
 type ResourceBuilder interface {
@@ -26,23 +48,93 @@ type ResourceBuilder interface {
 	UpdateMayRequireStsRecreate() bool
 }
 
+// StatePreserver is implemented by builders whose desired state depends on the existing child resource,
+// such as generated credentials or node ports allocated by Kubernetes.
+// Preserve copies that state from the existing object to the object returned by Build, before Update is called.
+type StatePreserver interface {
+	Preserve(existing, desired client.Object)
+}
+
+// ResourceBuilders returns the builders of all child resources, in the order they are applied.
+// The StatefulSet is applied after the resources its Pods depend on, so that Pods do not start before their
+// configuration, credentials and RBAC exist. The client Service is applied last, so that clients are only
+// routed to the RabbitmqCluster once it was deployed.
 func (builder *RabbitmqResourceBuilder) ResourceBuilders() []ResourceBuilder {
 
 	builders := []ResourceBuilder{
 		builder.HeadlessService(),
-		builder.Service(),
-		builder.ErlangCookie(),
-		builder.DefaultUserSecret(),
+	}
+	// do not generate an Erlang cookie when an existing Secret is used
+	if !builder.Instance.ExistingErlangCookieSecretEnabled() {
+		builders = append(builders, builder.ErlangCookie())
+	}
+	// do not create default-user K8s Secret when the credentials are stored in Vault, in an external Secret or in a CSI volume
+	if !builder.Instance.VaultDefaultUserSecretEnabled() && !builder.Instance.ExternalSecretEnabled() && !builder.Instance.CSIDefaultUserEnabled() {
+		builders = append(builders, builder.DefaultUserSecret())
+	}
+	builders = append(builders,
 		builder.RabbitmqPluginsConfigMap(),
 		builder.ServerConfigMap(),
-		builder.ServiceAccount(),
-		builder.Role(),
-		builder.RoleBinding(),
-		builder.StatefulSet(),
+	)
+	// do not create RBAC objects when an existing ServiceAccount is used
+	if builder.Instance.Spec.ServiceAccountName == "" {
+		builders = append(builders,
+			builder.ServiceAccount(),
+			builder.Role(),
+			builder.RoleBinding(),
+		)
+	}
+	builders = append(builders, builder.StatefulSet())
+	if builder.Instance.ManagementServiceEnabled() {
+		builders = append(builders, builder.ManagementService())
+	}
+	if builder.Instance.PodServicesEnabled() {
+		for ordinal := int32(0); ordinal < *builder.Instance.Spec.Replicas; ordinal++ {
+			builders = append(builders, builder.PodService(ordinal))
+		}
+	}
+	if builder.Instance.ManagementIngressEnabled() {
+		builders = append(builders, builder.ManagementIngress())
+	}
+	if builder.RouteAPIAvailable && builder.Instance.RouteEnabled() {
+		builders = append(builders, builder.ManagementRoute())
+		if builder.Instance.AMQPSRouteEnabled() {
+			builders = append(builders, builder.AMQPSRoute())
+		}
 	}
-	if builder.Instance.VaultDefaultUserSecretEnabled() || builder.Instance.ExternalSecretEnabled() {
-		// do not create default-user K8s Secret
-		builders = append(builders[:3], builders[3+1:]...)
+	if builder.GatewayAPIAvailable && builder.Instance.AMQPGatewayRouteEnabled() {
+		builders = append(builders, builder.AMQPGatewayRoute())
 	}
-	return builders
+	if builder.GatewayAPIAvailable && builder.Instance.AMQPSGatewayRouteEnabled() {
+		builders = append(builders, builder.AMQPSGatewayRoute())
+	}
+	if builder.PrometheusOperatorAvailable && builder.Instance.MonitoringEnabled() {
+		builders = append(builders, builder.Monitor())
+	}
+	if builder.PrometheusOperatorAvailable && builder.Instance.PrometheusRuleEnabled() {
+		builders = append(builders, builder.PrometheusRule())
+	}
+	return append(builders, builder.Service())
+}
+
+// childLabels returns the labels set on all child resources: the injected labels, the default labels,
+// the RabbitmqCluster labels and the labels configured by LabelMappings.
+func (builder *RabbitmqResourceBuilder) childLabels() map[string]string {
+	labels := map[string]string{}
+	for label, value := range builder.InjectedLabels {
+		labels[label] = value
+	}
+	for label, value := range metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels) {
+		labels[label] = value
+	}
+	for label, value := range metadata.MapLabels(builder.Instance.Labels, builder.LabelMappings) {
+		labels[label] = value
+	}
+	return labels
+}
+
+// childAnnotations merges the injected annotations and the RabbitmqCluster annotations into the given annotations.
+// Kubernetes annotations of the RabbitmqCluster are not copied.
+func (builder *RabbitmqResourceBuilder) childAnnotations(annotations map[string]string) map[string]string {
+	return metadata.ReconcileAndFilterAnnotations(annotations, builder.InjectedAnnotations, builder.Instance.Annotations)
 }
@@ -17,6 +17,7 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	defaultscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 )
 
 var _ = Describe("RabbitmqResourceBuilder", func() {
@@ -50,7 +51,6 @@ var _ = Describe("RabbitmqResourceBuilder", func() {
 
 			expectedBuildersInOrder := []resource.ResourceBuilder{
 				&resource.HeadlessServiceBuilder{},
-				&resource.ServiceBuilder{},
 				&resource.ErlangCookieBuilder{},
 				&resource.DefaultUserSecretBuilder{},
 				&resource.RabbitmqPluginsConfigMapBuilder{},
@@ -59,6 +59,7 @@ var _ = Describe("RabbitmqResourceBuilder", func() {
 				&resource.RoleBuilder{},
 				&resource.RoleBindingBuilder{},
 				&resource.StatefulSetBuilder{},
+				&resource.ServiceBuilder{},
 			}
 
 			for i, resourceBuilder := range resourceBuilders {
@@ -79,5 +80,163 @@ var _ = Describe("RabbitmqResourceBuilder", func() {
 				Expect(resourceBuilders).NotTo(ContainElement(BeAssignableToTypeOf(&resource.DefaultUserSecretBuilder{})))
 			})
 		})
+
+		When("default user credentials come from a CSI volume", func() {
+			BeforeEach(func() {
+				instance.Spec.SecretBackend.CSI = &rabbitmqv1beta1.CSISpec{
+					SecretProviderClass: "rabbitmq-secrets",
+					DefaultUser:         true,
+				}
+			})
+			It("returns all resource builders except for defaultUser K8s Secret", func() {
+				resourceBuilders := builder.ResourceBuilders()
+				Expect(resourceBuilders).To(HaveLen(9))
+				Expect(resourceBuilders).NotTo(ContainElement(BeAssignableToTypeOf(&resource.DefaultUserSecretBuilder{})))
+			})
+		})
+
+		When("an existing Erlang cookie Secret is used", func() {
+			BeforeEach(func() {
+				instance.Spec.SecretBackend.ExistingErlangCookieSecret.Name = "shared-erlang-cookie"
+			})
+			It("returns all resource builders except for the Erlang cookie Secret", func() {
+				resourceBuilders := builder.ResourceBuilders()
+				Expect(resourceBuilders).To(HaveLen(9))
+				Expect(resourceBuilders).NotTo(ContainElement(BeAssignableToTypeOf(&resource.ErlangCookieBuilder{})))
+			})
+		})
+
+		When("an existing ServiceAccount is used", func() {
+			BeforeEach(func() {
+				instance.Spec.ServiceAccountName = "existing-service-account"
+			})
+			It("returns all resource builders except for the ServiceAccount, Role and RoleBinding", func() {
+				resourceBuilders := builder.ResourceBuilders()
+				Expect(resourceBuilders).To(HaveLen(7))
+				Expect(resourceBuilders).NotTo(ContainElement(BeAssignableToTypeOf(&resource.ServiceAccountBuilder{})))
+				Expect(resourceBuilders).NotTo(ContainElement(BeAssignableToTypeOf(&resource.RoleBuilder{})))
+				Expect(resourceBuilders).NotTo(ContainElement(BeAssignableToTypeOf(&resource.RoleBindingBuilder{})))
+				Expect(resourceBuilders[5]).To(BeAssignableToTypeOf(&resource.StatefulSetBuilder{}))
+			})
+		})
+	})
+
+	Context("ManagementService", func() {
+		It("appends the management Service builder when enabled", func() {
+			instance := generateRabbitmqCluster()
+			instance.Spec.ManagementService = &rabbitmqv1beta1.RabbitmqClusterManagementServiceSpec{}
+			builder := &resource.RabbitmqResourceBuilder{Instance: &instance}
+
+			resourceBuilders := builder.ResourceBuilders()
+			Expect(resourceBuilders).To(HaveLen(11))
+			Expect(resourceBuilders[9]).To(BeAssignableToTypeOf(&resource.ManagementServiceBuilder{}))
+		})
+	})
+
+	Context("PodServices", func() {
+		It("appends one Service builder per replica when enabled", func() {
+			instance := generateRabbitmqCluster()
+			instance.Spec.Replicas = ptr.To(int32(3))
+			instance.Spec.PodServices = &rabbitmqv1beta1.RabbitmqClusterPodServicesSpec{}
+			builder := &resource.RabbitmqResourceBuilder{Instance: &instance}
+
+			resourceBuilders := builder.ResourceBuilders()
+			Expect(resourceBuilders).To(HaveLen(13))
+			for ordinal := 0; ordinal < 3; ordinal++ {
+				Expect(resourceBuilders[9+ordinal]).To(BeAssignableToTypeOf(&resource.PodServiceBuilder{}))
+				Expect(resourceBuilders[9+ordinal].(*resource.PodServiceBuilder).Ordinal).To(Equal(int32(ordinal)))
+			}
+		})
+	})
+
+	Context("ManagementIngress", func() {
+		It("appends the management Ingress builder when enabled", func() {
+			instance := generateRabbitmqCluster()
+			instance.Spec.ManagementIngress = &rabbitmqv1beta1.RabbitmqClusterManagementIngressSpec{}
+			builder := &resource.RabbitmqResourceBuilder{Instance: &instance}
+
+			resourceBuilders := builder.ResourceBuilders()
+			Expect(resourceBuilders).To(HaveLen(11))
+			Expect(resourceBuilders[9]).To(BeAssignableToTypeOf(&resource.ManagementIngressBuilder{}))
+		})
+	})
+
+	Context("Route", func() {
+		var instance rabbitmqv1beta1.RabbitmqCluster
+
+		BeforeEach(func() {
+			instance = generateRabbitmqCluster()
+			instance.Spec.Route = &rabbitmqv1beta1.RabbitmqClusterRouteSpec{AMQPSPassthrough: true}
+			instance.Spec.TLS.SecretName = "tls-secret"
+		})
+
+		It("appends the Route builders when the Route API is available", func() {
+			builder := &resource.RabbitmqResourceBuilder{Instance: &instance, RouteAPIAvailable: true}
+
+			resourceBuilders := builder.ResourceBuilders()
+			Expect(resourceBuilders).To(HaveLen(12))
+			Expect(resourceBuilders[9]).To(BeAssignableToTypeOf(&resource.ManagementRouteBuilder{}))
+			Expect(resourceBuilders[10]).To(BeAssignableToTypeOf(&resource.AMQPSRouteBuilder{}))
+		})
+
+		It("does not append the AMQPS Route builder when TLS is disabled", func() {
+			instance.Spec.TLS.SecretName = ""
+			builder := &resource.RabbitmqResourceBuilder{Instance: &instance, RouteAPIAvailable: true}
+			Expect(builder.ResourceBuilders()).NotTo(ContainElement(BeAssignableToTypeOf(&resource.AMQPSRouteBuilder{})))
+		})
+
+		It("does not append the Route builders when the Route API is not available", func() {
+			builder := &resource.RabbitmqResourceBuilder{Instance: &instance}
+			Expect(builder.ResourceBuilders()).To(HaveLen(10))
+		})
+	})
+
+	Context("LabelMappings", func() {
+		It("sets the mapped labels on all child resources", func() {
+			scheme := runtime.NewScheme()
+			Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+			Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+			instance := generateRabbitmqCluster()
+			instance.Labels = map[string]string{"team": "messaging"}
+			builder := &resource.RabbitmqResourceBuilder{
+				Instance:      &instance,
+				Scheme:        scheme,
+				LabelMappings: map[string]string{"team": "example.com/team"},
+			}
+
+			for _, resourceBuilder := range builder.ResourceBuilders() {
+				obj, err := resourceBuilder.Build()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resourceBuilder.Update(obj)).To(Succeed())
+				Expect(obj.GetLabels()).To(HaveKeyWithValue("example.com/team", "messaging"), "%T", obj)
+			}
+		})
+	})
+
+	Context("Injected labels and annotations", func() {
+		It("sets them on all child resources unless the RabbitmqCluster sets them", func() {
+			scheme := runtime.NewScheme()
+			Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+			Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+			instance := generateRabbitmqCluster()
+			instance.Labels = map[string]string{"example.com/team": "messaging"}
+			instance.Annotations = map[string]string{"example.com/owner": "messaging"}
+			builder := &resource.RabbitmqResourceBuilder{
+				Instance:            &instance,
+				Scheme:              scheme,
+				InjectedLabels:      map[string]string{"example.com/team": "platform", "example.com/env": "prod"},
+				InjectedAnnotations: map[string]string{"example.com/owner": "platform", "example.com/contact": "ops"},
+			}
+
+			for _, resourceBuilder := range builder.ResourceBuilders() {
+				obj, err := resourceBuilder.Build()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resourceBuilder.Update(obj)).To(Succeed())
+				Expect(obj.GetLabels()).To(HaveKeyWithValue("example.com/env", "prod"), "%T", obj)
+				Expect(obj.GetLabels()).To(HaveKeyWithValue("example.com/team", "messaging"), "%T", obj)
+				Expect(obj.GetAnnotations()).To(HaveKeyWithValue("example.com/contact", "ops"), "%T", obj)
+				Expect(obj.GetAnnotations()).To(HaveKeyWithValue("example.com/owner", "messaging"), "%T", obj)
+			}
+		})
 	})
 })
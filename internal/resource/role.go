@@ -14,7 +14,6 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -47,8 +46,8 @@ func (builder *RoleBuilder) UpdateMayRequireStsRecreate() bool {
 
 func (builder *RoleBuilder) Update(object client.Object) error {
 	role := object.(*rbacv1.Role)
-	role.Labels = metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels)
-	role.Annotations = metadata.ReconcileAndFilterAnnotations(role.GetAnnotations(), builder.Instance.Annotations)
+	role.Labels = builder.childLabels()
+	role.Annotations = builder.childAnnotations(role.GetAnnotations())
 	role.Rules = []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{""},
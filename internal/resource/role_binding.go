@@ -17,7 +17,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
-	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
 )
 
 const (
@@ -38,8 +37,8 @@ func (builder *RoleBindingBuilder) UpdateMayRequireStsRecreate() bool {
 
 func (builder *RoleBindingBuilder) Update(object client.Object) error {
 	roleBinding := object.(*rbacv1.RoleBinding)
-	roleBinding.Labels = metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels)
-	roleBinding.Annotations = metadata.ReconcileAndFilterAnnotations(roleBinding.GetAnnotations(), builder.Instance.Annotations)
+	roleBinding.Labels = builder.childLabels()
+	roleBinding.Annotations = builder.childAnnotations(roleBinding.GetAnnotations())
 	roleBinding.RoleRef = rbacv1.RoleRef{
 		APIGroup: "rbac.authorization.k8s.io",
 		Kind:     "Role",
@@ -0,0 +1,130 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource
+
+import (
+	"fmt"
+
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	ManagementRouteSuffix = "management"
+	AMQPSRouteSuffix      = "amqps"
+)
+
+// RouteGroupVersionKind identifies OpenShift Routes. Routes are handled as unstructured objects
+// so that the operator does not depend on the OpenShift API types.
+var RouteGroupVersionKind = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+// NewRoute returns an empty OpenShift Route with the given name and namespace.
+func NewRoute(name, namespace string) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(RouteGroupVersionKind)
+	route.SetName(name)
+	route.SetNamespace(namespace)
+	return route
+}
+
+type ManagementRouteBuilder struct {
+	*RabbitmqResourceBuilder
+}
+
+type AMQPSRouteBuilder struct {
+	*RabbitmqResourceBuilder
+}
+
+func (builder *RabbitmqResourceBuilder) ManagementRoute() *ManagementRouteBuilder {
+	return &ManagementRouteBuilder{builder}
+}
+
+func (builder *RabbitmqResourceBuilder) AMQPSRoute() *AMQPSRouteBuilder {
+	return &AMQPSRouteBuilder{builder}
+}
+
+func (builder *ManagementRouteBuilder) Build() (client.Object, error) {
+	return NewRoute(builder.Instance.ChildResourceName(ManagementRouteSuffix), builder.Instance.Namespace), nil
+}
+
+func (builder *ManagementRouteBuilder) UpdateMayRequireStsRecreate() bool {
+	return false
+}
+
+func (builder *ManagementRouteBuilder) Update(object client.Object) error {
+	spec := builder.Instance.Spec.Route
+	serviceName := builder.Instance.ChildResourceName(ServiceSuffix)
+	if builder.Instance.ManagementServiceEnabled() {
+		serviceName = builder.Instance.ChildResourceName(ManagementServiceSuffix)
+	}
+	targetPort := "management"
+	if spec.TLSTermination == "reencrypt" {
+		targetPort = "management-tls"
+	}
+	var tls map[string]interface{}
+	if spec.TLSTermination != "" {
+		tls = map[string]interface{}{
+			"termination":                   spec.TLSTermination,
+			"insecureEdgeTerminationPolicy": "Redirect",
+		}
+	}
+	return builder.updateRoute(object.(*unstructured.Unstructured), spec.Host, serviceName, builder.servicePortName(targetPort), tls)
+}
+
+func (builder *AMQPSRouteBuilder) Build() (client.Object, error) {
+	return NewRoute(builder.Instance.ChildResourceName(AMQPSRouteSuffix), builder.Instance.Namespace), nil
+}
+
+func (builder *AMQPSRouteBuilder) UpdateMayRequireStsRecreate() bool {
+	return false
+}
+
+func (builder *AMQPSRouteBuilder) Update(object client.Object) error {
+	tls := map[string]interface{}{
+		"termination": "passthrough",
+	}
+	return builder.updateRoute(object.(*unstructured.Unstructured), builder.Instance.Spec.Route.AMQPSHost, builder.Instance.ChildResourceName(ServiceSuffix), builder.servicePortName("amqps"), tls)
+}
+
+// updateRoute sets the Route fields managed by the operator. A host generated by OpenShift is kept
+// when no host is configured.
+func (builder *RabbitmqResourceBuilder) updateRoute(route *unstructured.Unstructured, host, serviceName, targetPort string, tls map[string]interface{}) error {
+	route.SetLabels(builder.childLabels())
+	route.SetAnnotations(metadata.ReconcileAnnotations(builder.childAnnotations(route.GetAnnotations()), builder.Instance.Spec.Route.Annotations))
+
+	if host != "" {
+		if err := unstructured.SetNestedField(route.Object, host, "spec", "host"); err != nil {
+			return err
+		}
+	}
+	if err := unstructured.SetNestedMap(route.Object, map[string]interface{}{
+		"kind":   "Service",
+		"name":   serviceName,
+		"weight": int64(100),
+	}, "spec", "to"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(route.Object, targetPort, "spec", "port", "targetPort"); err != nil {
+		return err
+	}
+	if tls == nil {
+		unstructured.RemoveNestedField(route.Object, "spec", "tls")
+	} else if err := unstructured.SetNestedMap(route.Object, tls, "spec", "tls"); err != nil {
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(builder.Instance, route, builder.Scheme); err != nil {
+		return fmt.Errorf("failed setting controller reference: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// RabbitMQ Cluster Operator
+//
+// Copyright 2020 VMware, Inc. All Rights Reserved.
+//
+// This product is licensed to you under the Mozilla Public license, Version 2.0 (the "License").  You may not use this product except in compliance with the Mozilla Public License.
+//
+// This product may include a number of subcomponents with separate copyright notices and license terms. Your use of these subcomponents is subject to the terms and conditions of the subcomponent's license, as noted in the LICENSE file.
+//
+
+package resource_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	defaultscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("Route", func() {
+	var (
+		instance rabbitmqv1beta1.RabbitmqCluster
+		builder  *resource.RabbitmqResourceBuilder
+		scheme   *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+		instance = generateRabbitmqCluster()
+		instance.Spec.Route = &rabbitmqv1beta1.RabbitmqClusterRouteSpec{
+			Host:        "rabbitmq.apps.example.com",
+			Annotations: map[string]string{"haproxy.router.openshift.io/timeout": "1m"},
+		}
+		builder = &resource.RabbitmqResourceBuilder{
+			Instance: &instance,
+			Scheme:   scheme,
+		}
+	})
+
+	Context("ManagementRoute", func() {
+		var route *unstructured.Unstructured
+
+		BeforeEach(func() {
+			obj, err := builder.ManagementRoute().Build()
+			Expect(err).NotTo(HaveOccurred())
+			route = obj.(*unstructured.Unstructured)
+		})
+
+		It("builds a Route with the correct name and namespace", func() {
+			Expect(route.GetObjectKind().GroupVersionKind()).To(Equal(resource.RouteGroupVersionKind))
+			Expect(route.GetName()).To(Equal(instance.ChildResourceName("management")))
+			Expect(route.GetNamespace()).To(Equal(instance.Namespace))
+		})
+
+		It("routes the host to the management port of the client Service", func() {
+			Expect(builder.ManagementRoute().Update(route)).To(Succeed())
+			Expect(route.Object["spec"]).To(Equal(map[string]interface{}{
+				"host": "rabbitmq.apps.example.com",
+				"to": map[string]interface{}{
+					"kind":   "Service",
+					"name":   instance.Name,
+					"weight": int64(100),
+				},
+				"port": map[string]interface{}{
+					"targetPort": "management",
+				},
+			}))
+		})
+
+		It("routes to the management Service when enabled", func() {
+			instance.Spec.ManagementService = &rabbitmqv1beta1.RabbitmqClusterManagementServiceSpec{}
+			Expect(builder.ManagementRoute().Update(route)).To(Succeed())
+			name, _, _ := unstructured.NestedString(route.Object, "spec", "to", "name")
+			Expect(name).To(Equal(instance.ChildResourceName("management")))
+		})
+
+		It("keeps a host generated by OpenShift when no host is configured", func() {
+			instance.Spec.Route.Host = ""
+			Expect(unstructured.SetNestedField(route.Object, "generated.apps.example.com", "spec", "host")).To(Succeed())
+			Expect(builder.ManagementRoute().Update(route)).To(Succeed())
+			host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+			Expect(host).To(Equal("generated.apps.example.com"))
+		})
+
+		It("configures edge TLS termination", func() {
+			instance.Spec.Route.TLSTermination = "edge"
+			Expect(builder.ManagementRoute().Update(route)).To(Succeed())
+			tls, _, _ := unstructured.NestedMap(route.Object, "spec", "tls")
+			Expect(tls).To(Equal(map[string]interface{}{
+				"termination":                   "edge",
+				"insecureEdgeTerminationPolicy": "Redirect",
+			}))
+
+			instance.Spec.Route.TLSTermination = ""
+			Expect(builder.ManagementRoute().Update(route)).To(Succeed())
+			_, found, _ := unstructured.NestedMap(route.Object, "spec", "tls")
+			Expect(found).To(BeFalse())
+		})
+
+		It("routes to the HTTPS management port for reencrypt TLS termination", func() {
+			instance.Spec.Route.TLSTermination = "reencrypt"
+			Expect(builder.ManagementRoute().Update(route)).To(Succeed())
+			port, _, _ := unstructured.NestedString(route.Object, "spec", "port", "targetPort")
+			Expect(port).To(Equal("management-tls"))
+		})
+
+		It("sets the annotations, labels and owner reference", func() {
+			Expect(builder.ManagementRoute().Update(route)).To(Succeed())
+			Expect(route.GetAnnotations()).To(HaveKeyWithValue("haproxy.router.openshift.io/timeout", "1m"))
+			Expect(route.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/name", instance.Name))
+			Expect(route.GetOwnerReferences()).To(HaveLen(1))
+			Expect(route.GetOwnerReferences()[0].Name).To(Equal(instance.Name))
+		})
+	})
+
+	Context("AMQPSRoute", func() {
+		It("passes TLS connections through to the AMQPS port", func() {
+			instance.Spec.Route.AMQPSHost = "amqps.apps.example.com"
+			obj, err := builder.AMQPSRoute().Build()
+			Expect(err).NotTo(HaveOccurred())
+			route := obj.(*unstructured.Unstructured)
+			Expect(route.GetName()).To(Equal(instance.ChildResourceName("amqps")))
+
+			Expect(builder.AMQPSRoute().Update(route)).To(Succeed())
+			Expect(route.Object["spec"]).To(Equal(map[string]interface{}{
+				"host": "amqps.apps.example.com",
+				"to": map[string]interface{}{
+					"kind":   "Service",
+					"name":   instance.Name,
+					"weight": int64(100),
+				},
+				"port": map[string]interface{}{
+					"targetPort": "amqps",
+				},
+				"tls": map[string]interface{}{
+					"termination": "passthrough",
+				},
+			}))
+		})
+	})
+})
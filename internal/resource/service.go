@@ -12,6 +12,8 @@ package resource
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"k8s.io/utils/ptr"
 
@@ -31,6 +33,51 @@ const (
 	ServiceSuffix = ""
 )
 
+// meshPortNamePrefixes maps Service port names to the protocol prefixes service meshes use to detect the protocol of a port.
+// See https://istio.io/latest/docs/ops/configuration/traffic-management/protocol-selection/
+var meshPortNamePrefixes = map[string]string{
+	"amqp":           "tcp",
+	"amqps":          "tls",
+	"management":     "http",
+	"management-tls": "https",
+	"mqtt":           "tcp",
+	"mqtts":          "tls",
+	"web-mqtt":       "http",
+	"web-mqtt-tls":   "https",
+	"stomp":          "tcp",
+	"stomps":         "tls",
+	"web-stomp":      "http",
+	"web-stomp-tls":  "https",
+	"stream":         "tcp",
+	"streams":        "tls",
+	"prometheus":     "http",
+	"prometheus-tls": "https",
+	"epmd":           "tcp",
+	"cluster-rpc":    "tcp",
+}
+
+// servicePortName returns the name of a Service port, prefixed with its protocol if spec.service.meshPortNames is set.
+func (builder *RabbitmqResourceBuilder) servicePortName(name string) string {
+	if prefix, ok := meshPortNamePrefixes[name]; ok && builder.Instance.Spec.Service.MeshPortNames {
+		return prefix + "-" + name
+	}
+	return name
+}
+
+// TLSListeners returns the names of the ports of the client Service which serve TLS, sorted by name.
+func TLSListeners(instance *rabbitmqv1beta1.RabbitmqCluster) []string {
+	builder := &RabbitmqResourceBuilder{Instance: instance.DeepCopy()}
+	builder.Instance.Spec.Service.MeshPortNames = false
+	var listeners []string
+	for name := range builder.Service().generateServicePortsMap() {
+		if prefix := meshPortNamePrefixes[name]; prefix == "tls" || prefix == "https" {
+			listeners = append(listeners, name)
+		}
+	}
+	sort.Strings(listeners)
+	return listeners
+}
+
 type ServiceBuilder struct {
 	*RabbitmqResourceBuilder
 }
@@ -55,10 +102,27 @@ func (builder *ServiceBuilder) UpdateMayRequireStsRecreate() bool {
 func (builder *ServiceBuilder) Update(object client.Object) error {
 	service := object.(*corev1.Service)
 	builder.setAnnotations(service)
-	service.Labels = metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels)
+	service.Labels = builder.childLabels()
 	service.Spec.Type = builder.Instance.Spec.Service.Type
 	service.Spec.Selector = metadata.LabelSelector(builder.Instance.Name)
 	service.Spec.IPFamilyPolicy = builder.Instance.Spec.Service.IPFamilyPolicy
+	updateIPFamilies(service, builder.Instance.Spec.Service.IPFamilies)
+
+	if builder.Instance.Spec.Service.Type == corev1.ServiceTypeLoadBalancer {
+		service.Spec.LoadBalancerIP = builder.Instance.Spec.Service.LoadBalancerIP
+		service.Spec.LoadBalancerSourceRanges = builder.Instance.Spec.Service.LoadBalancerSourceRanges
+	} else {
+		service.Spec.LoadBalancerIP = ""
+		service.Spec.LoadBalancerSourceRanges = nil
+	}
+
+	builder.updateExternalTrafficPolicy(service)
+	builder.updateSessionAffinity(service)
+
+	service.Spec.InternalTrafficPolicy = builder.Instance.Spec.Service.InternalTrafficPolicy
+	if service.Spec.InternalTrafficPolicy == nil {
+		service.Spec.InternalTrafficPolicy = ptr.To(corev1.ServiceInternalTrafficPolicyCluster)
+	}
 
 	service.Spec.Ports = builder.updatePorts(service.Spec.Ports)
 
@@ -66,6 +130,12 @@ func (builder *ServiceBuilder) Update(object client.Object) error {
 		for i := range service.Spec.Ports {
 			service.Spec.Ports[i].NodePort = int32(0)
 		}
+	} else {
+		for i := range service.Spec.Ports {
+			if nodePort, ok := builder.nodePort(service.Spec.Ports[i].Name); ok {
+				service.Spec.Ports[i].NodePort = nodePort
+			}
+		}
 	}
 
 	if builder.Instance.Spec.Override.Service != nil {
@@ -266,7 +336,29 @@ func (builder *ServiceBuilder) generateServicePortsMap() map[string]corev1.Servi
 			}
 		}
 	}
-	return servicePortsMap
+	if !builder.Instance.Spec.Service.MeshPortNames {
+		return servicePortsMap
+	}
+	meshServicePortsMap := make(map[string]corev1.ServicePort, len(servicePortsMap))
+	for _, servicePort := range servicePortsMap {
+		servicePort.Name = builder.servicePortName(servicePort.Name)
+		meshServicePortsMap[servicePort.Name] = servicePort
+	}
+	return meshServicePortsMap
+}
+
+// nodePort returns the node port configured for the Service port. Node ports keyed by the
+// port name without the protocol prefix are accepted when spec.service.meshPortNames is set.
+func (builder *ServiceBuilder) nodePort(portName string) (int32, bool) {
+	nodePorts := builder.Instance.Spec.Service.NodePorts
+	if nodePort, ok := nodePorts[portName]; ok {
+		return nodePort, true
+	}
+	if prefix, name, found := strings.Cut(portName, "-"); found && builder.Instance.Spec.Service.MeshPortNames && meshPortNamePrefixes[name] == prefix {
+		nodePort, ok := nodePorts[name]
+		return nodePort, ok
+	}
+	return 0, false
 }
 
 func (builder *ServiceBuilder) updatePorts(servicePorts []corev1.ServicePort) []corev1.ServicePort {
@@ -282,17 +374,78 @@ func (builder *ServiceBuilder) updatePorts(servicePorts []corev1.ServicePort) []
 		}
 	}
 
+	// new ports are appended in a stable order
+	var newServicePorts []corev1.ServicePort
 	for _, value := range servicePortsMap {
-		updatedServicePorts = append(updatedServicePorts, value)
+		newServicePorts = append(newServicePorts, value)
+	}
+	sort.Slice(newServicePorts, func(i, j int) bool {
+		return newServicePorts[i].Name < newServicePorts[j].Name
+	})
+
+	return append(updatedServicePorts, newServicePorts...)
+}
+
+// Preserve keeps the ports of the existing Service, so that node ports allocated by Kubernetes do not change.
+func (builder *ServiceBuilder) Preserve(existing, desired client.Object) {
+	preserveServicePorts(existing, desired)
+}
+
+func preserveServicePorts(existing, desired client.Object) {
+	desired.(*corev1.Service).Spec.Ports = existing.(*corev1.Service).DeepCopy().Spec.Ports
+}
+
+// updateIPFamilies sets the configured IP families. IP families assigned by Kubernetes are kept
+// when none are configured.
+func updateIPFamilies(service *corev1.Service, ipFamilies []corev1.IPFamily) {
+	if len(ipFamilies) > 0 {
+		service.Spec.IPFamilies = ipFamilies
+	}
+}
+
+func (builder *ServiceBuilder) updateSessionAffinity(service *corev1.Service) {
+	serviceSpec := builder.Instance.Spec.Service
+	if serviceSpec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		service.Spec.SessionAffinity = corev1.ServiceAffinityNone
+		service.Spec.SessionAffinityConfig = nil
+		return
+	}
+
+	service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	// the timeout defaulted by Kubernetes is kept if none is configured
+	if serviceSpec.SessionAffinityTimeoutSeconds != nil {
+		service.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: serviceSpec.SessionAffinityTimeoutSeconds},
+		}
 	}
+}
 
-	return updatedServicePorts
+func (builder *ServiceBuilder) updateExternalTrafficPolicy(service *corev1.Service) {
+	serviceSpec := builder.Instance.Spec.Service
+	if serviceSpec.Type != corev1.ServiceTypeNodePort && serviceSpec.Type != corev1.ServiceTypeLoadBalancer {
+		service.Spec.ExternalTrafficPolicy = ""
+		service.Spec.HealthCheckNodePort = 0
+		return
+	}
+
+	service.Spec.ExternalTrafficPolicy = serviceSpec.ExternalTrafficPolicy
+	if service.Spec.ExternalTrafficPolicy == "" {
+		// defaulted by Kubernetes; keeping the default avoids updating the Service on every reconcile
+		service.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+	}
+
+	// the health check node port of an existing Service is kept unless it is set in the spec
+	if serviceSpec.Type != corev1.ServiceTypeLoadBalancer || service.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyLocal {
+		service.Spec.HealthCheckNodePort = 0
+	} else if serviceSpec.HealthCheckNodePort != nil {
+		service.Spec.HealthCheckNodePort = *serviceSpec.HealthCheckNodePort
+	}
 }
 
 func (builder *ServiceBuilder) setAnnotations(service *corev1.Service) {
 	if builder.Instance.Spec.Service.Annotations != nil {
-		service.Annotations = metadata.ReconcileAnnotations(metadata.ReconcileAndFilterAnnotations(service.Annotations, builder.Instance.Annotations), builder.Instance.Spec.Service.Annotations)
+		service.Annotations = metadata.ReconcileAnnotations(builder.childAnnotations(service.Annotations), builder.Instance.Spec.Service.Annotations)
 	} else {
-		service.Annotations = metadata.ReconcileAndFilterAnnotations(service.Annotations, builder.Instance.Annotations)
+		service.Annotations = builder.childAnnotations(service.Annotations)
 	}
 }
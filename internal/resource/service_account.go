@@ -14,7 +14,6 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -47,8 +46,8 @@ func (builder *ServiceAccountBuilder) UpdateMayRequireStsRecreate() bool {
 
 func (builder *ServiceAccountBuilder) Update(object client.Object) error {
 	serviceAccount := object.(*corev1.ServiceAccount)
-	serviceAccount.Labels = metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels)
-	serviceAccount.Annotations = metadata.ReconcileAndFilterAnnotations(serviceAccount.GetAnnotations(), builder.Instance.Annotations)
+	serviceAccount.Labels = builder.childLabels()
+	serviceAccount.Annotations = builder.childAnnotations(serviceAccount.GetAnnotations())
 
 	if err := controllerutil.SetControllerReference(builder.Instance, serviceAccount, builder.Scheme); err != nil {
 		return fmt.Errorf("failed setting controller reference: %w", err)
@@ -58,6 +58,23 @@ var _ = Context("Services", func() {
 		})
 	})
 
+	Describe("TLSListeners", func() {
+		BeforeEach(func() {
+			instance = generateRabbitmqCluster()
+		})
+
+		It("returns no listeners when TLS is disabled", func() {
+			Expect(resource.TLSListeners(&instance)).To(BeEmpty())
+		})
+
+		It("returns the TLS ports of the enabled plugins, ignoring mesh port names", func() {
+			instance.Spec.TLS = rabbitmqv1beta1.TLSSpec{SecretName: "tls-secret", CaSecretName: "ca-secret"}
+			instance.Spec.Rabbitmq.AdditionalPlugins = []rabbitmqv1beta1.Plugin{"rabbitmq_mqtt", "rabbitmq_web_stomp"}
+			instance.Spec.Service.MeshPortNames = true
+			Expect(resource.TLSListeners(&instance)).To(Equal([]string{"amqps", "management-tls", "mqtts", "prometheus-tls", "web-stomp-tls"}))
+		})
+	})
+
 	Describe("Update", func() {
 		BeforeEach(func() {
 			scheme = runtime.NewScheme()
@@ -310,6 +327,21 @@ var _ = Context("Services", func() {
 				})
 			})
 
+			When("MQTT and STOMP are configured with spec.rabbitmq.mqtt and spec.rabbitmq.stomp", func() {
+				It("exposes the plugin ports", func() {
+					instance.Spec.Rabbitmq.MQTT = &rabbitmqv1beta1.MQTTSpec{WebSockets: true}
+					instance.Spec.Rabbitmq.STOMP = &rabbitmqv1beta1.STOMPSpec{}
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+
+					var portNames []string
+					for _, port := range svc.Spec.Ports {
+						portNames = append(portNames, port.Name)
+					}
+					Expect(portNames).To(ContainElements("mqtt", "web-mqtt", "stomp"))
+					Expect(portNames).NotTo(ContainElement("web-stomp"))
+				})
+			})
+
 			When("STOMP and Web-STOMP are enabled", func() {
 				It("exposes ports for both protocols", func() {
 					instance.Spec.Rabbitmq.AdditionalPlugins = []rabbitmqv1beta1.Plugin{"rabbitmq_stomp", "rabbitmq_web_stomp"}
@@ -430,6 +462,93 @@ var _ = Context("Services", func() {
 					Expect(service.ObjectMeta.Annotations).To(Equal(expectedAnnotations))
 				})
 			})
+
+			When("annotations for external-dns and cloud load balancers are specified", func() {
+				var (
+					serviceBuilder *resource.ServiceBuilder
+					svc            *corev1.Service
+					annotations    map[string]string
+				)
+
+				BeforeEach(func() {
+					annotations = map[string]string{
+						"external-dns.alpha.kubernetes.io/hostname":               "rabbitmq.example.com",
+						"external-dns.alpha.kubernetes.io/ttl":                    "60",
+						"service.beta.kubernetes.io/aws-load-balancer-type":       "nlb",
+						"service.beta.kubernetes.io/azure-load-balancer-internal": "true",
+						"cloud.google.com/load-balancer-type":                     "Internal",
+					}
+					instance = generateRabbitmqCluster()
+					instance.Spec.Service.Type = corev1.ServiceTypeLoadBalancer
+					instance.Spec.Service.Annotations = annotations
+					serviceBuilder = builder.Service()
+					svc = &corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      instance.Name,
+							Namespace: instance.Namespace,
+						},
+					}
+				})
+
+				It("passes them through to the Service", func() {
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					for key, value := range annotations {
+						Expect(svc.Annotations).To(HaveKeyWithValue(key, value))
+					}
+				})
+
+				It("keeps them on subsequent updates", func() {
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					instance.Annotations = map[string]string{"my-annotation": "i-like-this"}
+					instance.Spec.Service.Type = corev1.ServiceTypeNodePort
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					for key, value := range annotations {
+						Expect(svc.Annotations).To(HaveKeyWithValue(key, value))
+					}
+					Expect(svc.Annotations).To(HaveKeyWithValue("my-annotation", "i-like-this"))
+				})
+
+				It("updates their values when changed in the spec", func() {
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					instance.Spec.Service.Annotations = map[string]string{
+						"external-dns.alpha.kubernetes.io/hostname": "amqp.example.com",
+					}
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					Expect(svc.Annotations).To(HaveKeyWithValue("external-dns.alpha.kubernetes.io/hostname", "amqp.example.com"))
+				})
+
+				It("keeps annotations set on the Service by other controllers", func() {
+					svc.Annotations = map[string]string{
+						"service.kubernetes.io/load-balancer-cleanup": "true",
+					}
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					Expect(svc.Annotations).To(HaveKeyWithValue("service.kubernetes.io/load-balancer-cleanup", "true"))
+				})
+
+				It("keeps them when a Service override is provided", func() {
+					instance.Spec.Override.Service = &rabbitmqv1beta1.Service{
+						Spec: &corev1.ServiceSpec{
+							Ports: []corev1.ServicePort{{Name: "additional-port", Port: 12345, Protocol: corev1.ProtocolTCP}},
+						},
+					}
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					for key, value := range annotations {
+						Expect(svc.Annotations).To(HaveKeyWithValue(key, value))
+					}
+				})
+
+				It("lets annotations of a Service override take precedence", func() {
+					instance.Spec.Override.Service = &rabbitmqv1beta1.Service{
+						EmbeddedLabelsAnnotations: &rabbitmqv1beta1.EmbeddedLabelsAnnotations{
+							Annotations: map[string]string{"external-dns.alpha.kubernetes.io/hostname": "override.example.com"},
+						},
+					}
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					Expect(svc.Annotations).To(HaveKeyWithValue("external-dns.alpha.kubernetes.io/hostname", "override.example.com"))
+					Expect(svc.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-type", "nlb"))
+				})
+			})
 		})
 
 		Context("Labels", func() {
@@ -548,6 +667,18 @@ var _ = Context("Services", func() {
 				Expect(svc.Spec.Ports).To(ConsistOf(amqpPort, managementPort, prometheusPort))
 			})
 
+			It("keeps the order of existing ports and appends new ports sorted by name", func() {
+				svc.Spec.Ports = []corev1.ServicePort{{Name: "prometheus"}}
+				instance.Spec.Rabbitmq.AdditionalPlugins = []rabbitmqv1beta1.Plugin{"rabbitmq_stream", "rabbitmq_mqtt"}
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+
+				var names []string
+				for _, port := range svc.Spec.Ports {
+					names = append(names, port.Name)
+				}
+				Expect(names).To(Equal([]string{"prometheus", "amqp", "management", "mqtt", "stream"}))
+			})
+
 			DescribeTable("plugins exposing ports",
 				func(plugin, servicePortName string, port int, appProtocol *string) {
 					instance.Spec.Rabbitmq.AdditionalPlugins = []rabbitmqv1beta1.Plugin{rabbitmqv1beta1.Plugin(plugin)}
@@ -628,6 +759,51 @@ var _ = Context("Services", func() {
 				Expect(svc.Spec.Ports).To(ContainElement(expectedManagementServicePort))
 			})
 
+			When("node ports are set in the spec", func() {
+				BeforeEach(func() {
+					serviceBuilder.Instance.Spec.Service.Type = "NodePort"
+					serviceBuilder.Instance.Spec.Service.NodePorts = map[string]int32{
+						"amqp":       30672,
+						"management": 31672,
+					}
+				})
+
+				It("sets the node ports on the matching service ports", func() {
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+
+					nodePorts := map[string]int32{}
+					for _, port := range svc.Spec.Ports {
+						nodePorts[port.Name] = port.NodePort
+					}
+					Expect(nodePorts).To(HaveKeyWithValue("amqp", int32(30672)))
+					Expect(nodePorts).To(HaveKeyWithValue("management", int32(31672)))
+					Expect(nodePorts).To(HaveKeyWithValue("prometheus", int32(0)))
+				})
+
+				It("overrides node ports allocated by Kubernetes", func() {
+					svc.Spec.Type = corev1.ServiceTypeNodePort
+					svc.Spec.Ports = []corev1.ServicePort{
+						{
+							Protocol:   corev1.ProtocolTCP,
+							Port:       5672,
+							TargetPort: intstr.FromInt(5672),
+							Name:       "amqp",
+							NodePort:   12345,
+						},
+					}
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					Expect(svc.Spec.Ports).To(ContainElement(HaveField("NodePort", int32(30672))))
+				})
+
+				It("ignores the node ports when the service type is ClusterIP", func() {
+					serviceBuilder.Instance.Spec.Service.Type = "ClusterIP"
+					Expect(serviceBuilder.Update(svc)).To(Succeed())
+					for _, port := range svc.Spec.Ports {
+						Expect(port.NodePort).To(BeZero())
+					}
+				})
+			})
+
 			When("service type is updated from NodePort to ClusterIP", func() {
 				It("unsets nodePort field", func() {
 					svc.Spec.Type = corev1.ServiceTypeNodePort
@@ -716,6 +892,213 @@ var _ = Context("Services", func() {
 				Expect(serviceBuilder.Update(svc)).To(Succeed())
 				Expect(svc.Spec.IPFamilyPolicy).To(BeEquivalentTo(ptr.To("PreferDualStack")))
 			})
+
+			It("sets the IP families", func() {
+				instance.Spec.Service.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.IPFamilies).To(Equal([]corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}))
+			})
+
+			It("keeps the IP families assigned by Kubernetes when not configured", func() {
+				svc.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.IPFamilies).To(Equal([]corev1.IPFamily{corev1.IPv4Protocol}))
+			})
+		})
+
+		Context("LoadBalancer configuration", func() {
+			var (
+				svc            *corev1.Service
+				serviceBuilder *resource.ServiceBuilder
+			)
+
+			BeforeEach(func() {
+				serviceBuilder = builder.Service()
+				instance = generateRabbitmqCluster()
+				instance.Spec.Service.LoadBalancerIP = "10.0.0.10"
+				instance.Spec.Service.LoadBalancerSourceRanges = []string{"192.168.0.0/16", "10.1.0.0/24"}
+
+				svc = &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo",
+						Namespace: "foo-namespace",
+					},
+				}
+			})
+
+			It("sets the load balancer IP and source ranges", func() {
+				instance.Spec.Service.Type = corev1.ServiceTypeLoadBalancer
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.LoadBalancerIP).To(Equal("10.0.0.10"))
+				Expect(svc.Spec.LoadBalancerSourceRanges).To(ConsistOf("192.168.0.0/16", "10.1.0.0/24"))
+			})
+
+			It("unsets the load balancer IP and source ranges when the service type is not LoadBalancer", func() {
+				svc.Spec.LoadBalancerIP = "10.0.0.10"
+				svc.Spec.LoadBalancerSourceRanges = []string{"192.168.0.0/16"}
+				instance.Spec.Service.Type = corev1.ServiceTypeNodePort
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.LoadBalancerIP).To(BeEmpty())
+				Expect(svc.Spec.LoadBalancerSourceRanges).To(BeEmpty())
+			})
+		})
+
+		Context("external traffic policy", func() {
+			var (
+				svc            *corev1.Service
+				serviceBuilder *resource.ServiceBuilder
+			)
+
+			BeforeEach(func() {
+				serviceBuilder = builder.Service()
+				instance = generateRabbitmqCluster()
+				instance.Spec.Service.Type = corev1.ServiceTypeLoadBalancer
+
+				svc = &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo",
+						Namespace: "foo-namespace",
+					},
+				}
+			})
+
+			It("sets the external traffic policy and health check node port", func() {
+				instance.Spec.Service.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+				instance.Spec.Service.HealthCheckNodePort = ptr.To(int32(32000))
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.ExternalTrafficPolicy).To(Equal(corev1.ServiceExternalTrafficPolicyLocal))
+				Expect(svc.Spec.HealthCheckNodePort).To(Equal(int32(32000)))
+			})
+
+			It("keeps the health check node port allocated by Kubernetes", func() {
+				instance.Spec.Service.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+				svc.Spec.HealthCheckNodePort = 31000
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.HealthCheckNodePort).To(Equal(int32(31000)))
+			})
+
+			It("defaults the external traffic policy to Cluster and unsets the health check node port", func() {
+				svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+				svc.Spec.HealthCheckNodePort = 31000
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.ExternalTrafficPolicy).To(Equal(corev1.ServiceExternalTrafficPolicyCluster))
+				Expect(svc.Spec.HealthCheckNodePort).To(BeZero())
+			})
+
+			It("unsets the external traffic policy when the service type is ClusterIP", func() {
+				instance.Spec.Service.Type = corev1.ServiceTypeClusterIP
+				instance.Spec.Service.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.ExternalTrafficPolicy).To(BeEmpty())
+			})
+		})
+
+		Context("session affinity and internal traffic policy", func() {
+			var (
+				svc            *corev1.Service
+				serviceBuilder *resource.ServiceBuilder
+			)
+
+			BeforeEach(func() {
+				serviceBuilder = builder.Service()
+				instance = generateRabbitmqCluster()
+
+				svc = &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo",
+						Namespace: "foo-namespace",
+					},
+				}
+			})
+
+			It("sets the ClientIP session affinity and its timeout", func() {
+				instance.Spec.Service.SessionAffinity = corev1.ServiceAffinityClientIP
+				instance.Spec.Service.SessionAffinityTimeoutSeconds = ptr.To(int32(3600))
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityClientIP))
+				Expect(svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds).To(Equal(ptr.To(int32(3600))))
+			})
+
+			It("keeps the session affinity timeout defaulted by Kubernetes", func() {
+				instance.Spec.Service.SessionAffinity = corev1.ServiceAffinityClientIP
+				svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ptr.To(int32(10800))}}
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds).To(Equal(ptr.To(int32(10800))))
+			})
+
+			It("unsets the session affinity when not configured", func() {
+				svc.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+				svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ptr.To(int32(10800))}}
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityNone))
+				Expect(svc.Spec.SessionAffinityConfig).To(BeNil())
+			})
+
+			It("sets the internal traffic policy, which defaults to Cluster", func() {
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.InternalTrafficPolicy).To(Equal(ptr.To(corev1.ServiceInternalTrafficPolicyCluster)))
+
+				instance.Spec.Service.InternalTrafficPolicy = ptr.To(corev1.ServiceInternalTrafficPolicyLocal)
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.InternalTrafficPolicy).To(Equal(ptr.To(corev1.ServiceInternalTrafficPolicyLocal)))
+			})
+		})
+
+		Context("mesh port names", func() {
+			var (
+				svc            *corev1.Service
+				serviceBuilder *resource.ServiceBuilder
+			)
+
+			BeforeEach(func() {
+				serviceBuilder = builder.Service()
+				instance = generateRabbitmqCluster()
+				instance.Spec.Service.MeshPortNames = true
+
+				svc = &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "foo",
+						Namespace: "foo-namespace",
+					},
+				}
+			})
+
+			It("prefixes the port names with their protocol and keeps the app protocols", func() {
+				instance.Spec.TLS.SecretName = "tls-secret"
+				instance.Spec.Rabbitmq.AdditionalPlugins = []rabbitmqv1beta1.Plugin{"rabbitmq_mqtt"}
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.Ports).To(ConsistOf(
+					SatisfyAll(HaveField("Name", "tcp-amqp"), HaveField("AppProtocol", ptr.To("amqp"))),
+					SatisfyAll(HaveField("Name", "http-management"), HaveField("AppProtocol", ptr.To("http"))),
+					SatisfyAll(HaveField("Name", "tcp-mqtt"), HaveField("AppProtocol", ptr.To("mqtt"))),
+					SatisfyAll(HaveField("Name", "tls-amqps"), HaveField("AppProtocol", ptr.To("amqps"))),
+					SatisfyAll(HaveField("Name", "https-management-tls"), HaveField("AppProtocol", ptr.To("https"))),
+					SatisfyAll(HaveField("Name", "tls-mqtts"), HaveField("AppProtocol", ptr.To("mqtts"))),
+					SatisfyAll(HaveField("Name", "https-prometheus-tls"), HaveField("AppProtocol", ptr.To("prometheus.io/metric-tls"))),
+				))
+			})
+
+			It("replaces the ports of an existing Service", func() {
+				svc.Spec.Ports = []corev1.ServicePort{
+					{Protocol: corev1.ProtocolTCP, Port: 5672, TargetPort: intstr.FromInt(5672), Name: "amqp"},
+				}
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.Ports).NotTo(ContainElement(HaveField("Name", "amqp")))
+				Expect(svc.Spec.Ports).To(ContainElement(HaveField("Name", "tcp-amqp")))
+			})
+
+			It("sets node ports keyed by the port names with or without protocol prefix", func() {
+				instance.Spec.Service.Type = corev1.ServiceTypeNodePort
+				instance.Spec.Service.NodePorts = map[string]int32{
+					"amqp":            30672,
+					"http-management": 31672,
+				}
+				Expect(serviceBuilder.Update(svc)).To(Succeed())
+				Expect(svc.Spec.Ports).To(ContainElements(
+					SatisfyAll(HaveField("Name", "tcp-amqp"), HaveField("NodePort", int32(30672))),
+					SatisfyAll(HaveField("Name", "http-management"), HaveField("NodePort", int32(31672))),
+				))
+			})
 		})
 
 		When("Override is provided", func() {
@@ -851,6 +1234,38 @@ var _ = Context("Services", func() {
 		})
 	})
 
+	Context("Preserve", func() {
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+			Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+			instance = generateRabbitmqCluster()
+			instance.Spec.Service.Type = corev1.ServiceTypeNodePort
+			builder = resource.RabbitmqResourceBuilder{
+				Instance: &instance,
+				Scheme:   scheme,
+			}
+		})
+
+		It("keeps the node ports allocated by Kubernetes", func() {
+			serviceBuilder := builder.Service()
+			existing := &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Name: "amqp", Port: 5672, NodePort: 30672}},
+				},
+			}
+			obj, err := serviceBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			serviceBuilder.Preserve(existing, obj)
+			Expect(serviceBuilder.Update(obj)).To(Succeed())
+
+			Expect(obj.(*corev1.Service).Spec.Ports).To(ContainElement(SatisfyAll(
+				HaveField("Name", "amqp"),
+				HaveField("NodePort", int32(30672)),
+			)))
+		})
+	})
+
 	Context("UpdateMayRequireStsRecreate", func() {
 		BeforeEach(func() {
 			BeforeEach(func() {
@@ -41,6 +41,12 @@ const (
 	DeletionMarker      string = "skipPreStopChecks"
 )
 
+// Secrets Store CSI Driver volume configured in spec.secretBackend.csi.
+const (
+	secretsStoreVolumeName = "secrets-store"
+	defaultCSIDriver       = "secrets-store.csi.k8s.io"
+)
+
 type StatefulSetBuilder struct {
 	*RabbitmqResourceBuilder
 }
@@ -50,6 +56,11 @@ func (builder *RabbitmqResourceBuilder) StatefulSet() *StatefulSetBuilder {
 }
 
 func (builder *StatefulSetBuilder) Build() (client.Object, error) {
+	// defaults are set by the defaulting webhook or the reconciler, not by the builders
+	if err := builder.Instance.CheckDefaults(); err != nil {
+		return nil, err
+	}
+
 	// PVC, ServiceName & Selector: can't be updated without deleting the statefulset
 	pvc, err := persistentVolumeClaim(builder.Instance, builder.Scheme)
 	if err != nil {
@@ -94,6 +105,18 @@ func (builder *StatefulSetBuilder) UpdateMayRequireStsRecreate() bool {
 	return true
 }
 
+// Preserve keeps the operator annotations of the existing Pod template, such as the time of the last restart.
+// It also keeps the selector, which cannot change, so that StatefulSets which were not created by the operator can be adopted.
+func (builder *StatefulSetBuilder) Preserve(existing, desired client.Object) {
+	sts := desired.(*appsv1.StatefulSet)
+	existingSts := existing.(*appsv1.StatefulSet)
+	sts.Spec.Template.Annotations = metadata.ReconcileAnnotations(sts.Spec.Template.Annotations,
+		metadata.OperatorAnnotations(existingSts.Spec.Template.Annotations))
+	if existingSts.Spec.Selector != nil {
+		sts.Spec.Selector = existingSts.Spec.Selector.DeepCopy()
+	}
+}
+
 func (builder *StatefulSetBuilder) Update(object client.Object) error {
 	sts := object.(*appsv1.StatefulSet)
 
@@ -107,12 +130,16 @@ func (builder *StatefulSetBuilder) Update(object client.Object) error {
 		},
 		Type: appsv1.RollingUpdateStatefulSetStrategyType,
 	}
+	if builder.Instance.HealthGatedUpdates() {
+		// the operator deletes outdated Pods one at a time
+		sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	}
 
 	//Annotations
-	sts.Annotations = metadata.ReconcileAndFilterAnnotations(sts.Annotations, builder.Instance.Annotations)
+	sts.Annotations = builder.childAnnotations(sts.Annotations)
 
 	//Labels
-	sts.Labels = metadata.GetLabels(builder.Instance.Name, builder.Instance.Labels)
+	sts.Labels = builder.childLabels()
 
 	// PVC storage capacity
 	updatePersistenceStorageCapacity(&sts.Spec.VolumeClaimTemplates, builder.Instance.Spec.Persistence.Storage)
@@ -348,6 +375,31 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 		readinessProbePort = "amqps"
 	}
 
+	serverConfItems := []corev1.KeyToPath{
+		{
+			Key:  OperatorDefaultsConfKey,
+			Path: OperatorDefaultsConfKey,
+		},
+		{
+			Key:  TLSConfKey,
+			Path: TLSConfKey,
+		},
+		{
+			Key:  PluginsConfKey,
+			Path: PluginsConfKey,
+		},
+		{
+			Key:  UserDefinedConfigurationConfKey,
+			Path: UserDefinedConfigurationConfKey,
+		},
+	}
+	if builder.Instance.Spec.Rabbitmq.ConfigFrom != nil {
+		serverConfItems = append(serverConfItems, corev1.KeyToPath{
+			Key:  ConfigFromConfKey,
+			Path: ConfigFromConfKey,
+		})
+	}
+
 	volumes := []corev1.Volume{
 		{
 			Name: "plugins-conf",
@@ -370,16 +422,7 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 								LocalObjectReference: corev1.LocalObjectReference{
 									Name: builder.Instance.ChildResourceName(ServerConfigMapName),
 								},
-								Items: []corev1.KeyToPath{
-									{
-										Key:  "operatorDefaults.conf",
-										Path: "operatorDefaults.conf",
-									},
-									{
-										Key:  "userDefinedConfiguration.conf",
-										Path: "userDefinedConfiguration.conf",
-									},
-								},
+								Items: serverConfItems,
 							},
 						},
 					},
@@ -396,7 +439,7 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 			Name: "erlang-cookie-secret",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: builder.Instance.ChildResourceName(erlangCookieName),
+					SecretName: ErlangCookieSecretName(builder.Instance),
 				},
 			},
 		},
@@ -423,12 +466,35 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 		},
 	}
 
-	if !builder.Instance.VaultDefaultUserSecretEnabled() && !builder.Instance.ExternalSecretEnabled() {
+	if !builder.Instance.VaultDefaultUserSecretEnabled() && !builder.Instance.ExternalSecretEnabled() && !builder.Instance.CSIDefaultUserEnabled() {
 		appendDefaultUserSecretVolumeProjection(volumes, builder.Instance, "")
 	} else if builder.Instance.ExternalSecretEnabled() {
 		appendDefaultUserSecretVolumeProjection(volumes, builder.Instance, builder.Instance.Spec.SecretBackend.ExternalSecret.Name)
 	}
 
+	appendConfigFragmentVolumeProjections(volumes, builder.Instance)
+
+	if definitions := builder.Instance.Spec.Rabbitmq.Definitions; definitions != nil {
+		volumes = append(volumes, definitionsVolume(definitions))
+	}
+
+	if builder.Instance.OAuth2Enabled() && builder.Instance.Spec.Rabbitmq.Auth.OAuth2.SigningKey != nil {
+		signingKey := builder.Instance.Spec.Rabbitmq.Auth.OAuth2.SigningKey
+		key := signingKey.Key
+		if key == "" {
+			key = "signing-key.pem"
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: "oauth2-signing-key",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: signingKey.SecretRef.Name,
+					Items:      []corev1.KeyToPath{{Key: key, Path: "signing-key.pem"}},
+				},
+			},
+		})
+	}
+
 	if builder.rabbitmqConfigurationIsSet() {
 		volumes = append(volumes, corev1.Volume{
 			Name: "server-conf",
@@ -465,12 +531,22 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 		{
 			Name:      "rabbitmq-confd",
 			MountPath: "/etc/rabbitmq/conf.d/10-operatorDefaults.conf",
-			SubPath:   "operatorDefaults.conf",
+			SubPath:   OperatorDefaultsConfKey,
+		},
+		{
+			Name:      "rabbitmq-confd",
+			MountPath: "/etc/rabbitmq/conf.d/20-tls.conf",
+			SubPath:   TLSConfKey,
+		},
+		{
+			Name:      "rabbitmq-confd",
+			MountPath: "/etc/rabbitmq/conf.d/30-plugins.conf",
+			SubPath:   PluginsConfKey,
 		},
 		{
 			Name:      "rabbitmq-confd",
 			MountPath: "/etc/rabbitmq/conf.d/90-userDefinedConfiguration.conf",
-			SubPath:   "userDefinedConfiguration.conf",
+			SubPath:   UserDefinedConfigurationConfKey,
 		},
 		{
 			Name:      "pod-info",
@@ -478,12 +554,72 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 		},
 	}
 
-	if !builder.Instance.VaultDefaultUserSecretEnabled() {
+	if builder.Instance.CSIDefaultUserEnabled() {
+		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts, corev1.VolumeMount{
+			Name: secretsStoreVolumeName, MountPath: "/etc/rabbitmq/conf.d/11-default_user.conf", SubPath: "default_user.conf", ReadOnly: true,
+		})
+	} else if !builder.Instance.VaultDefaultUserSecretEnabled() {
 		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts, corev1.VolumeMount{
 			Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/11-default_user.conf", SubPath: "default_user.conf",
 		})
 	}
 
+	for _, path := range configFragmentPaths(builder.Instance) {
+		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts, corev1.VolumeMount{
+			Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/" + path, SubPath: path,
+		})
+	}
+
+	if builder.Instance.Spec.Rabbitmq.ConfigFrom != nil {
+		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts, corev1.VolumeMount{
+			Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/80-configFrom.conf", SubPath: ConfigFromConfKey,
+		})
+	}
+
+	rabbitmqContainerEnv := append(envVarsK8sObjects(builder.Instance),
+		corev1.EnvVar{
+			Name:  "RABBITMQ_ENABLED_PLUGINS_FILE",
+			Value: "/operator/enabled_plugins",
+		},
+		corev1.EnvVar{
+			Name:  "RABBITMQ_USE_LONGNAME",
+			Value: "true",
+		},
+		corev1.EnvVar{
+			Name:  "RABBITMQ_NODENAME",
+			Value: "rabbit@$(MY_POD_NAME).$(K8S_SERVICE_NAME).$(MY_POD_NAMESPACE)",
+		},
+		corev1.EnvVar{
+			Name:  "K8S_HOSTNAME_SUFFIX",
+			Value: ".$(K8S_SERVICE_NAME).$(MY_POD_NAMESPACE)",
+		},
+		// the RabbitMQ image sets RABBITMQ_LOGS=-, which overrides the log settings of spec.rabbitmq.logging
+		corev1.EnvVar{
+			Name:  "RABBITMQ_LOGS",
+			Value: "",
+		},
+	)
+	if builder.Instance.PeerDiscoveryByIP() {
+		rabbitmqContainerEnv = envVarsNodeNameByIP(rabbitmqContainerEnv)
+	}
+	rabbitmqContainerEnv = append(rabbitmqContainerEnv, envVarsDistribution(builder.Instance)...)
+
+	if builder.Instance.OAuth2Enabled() && builder.Instance.Spec.Rabbitmq.Auth.OAuth2.SigningKey != nil {
+		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts, corev1.VolumeMount{
+			Name:      "oauth2-signing-key",
+			MountPath: oauth2Dir,
+			ReadOnly:  true,
+		})
+	}
+
+	if builder.Instance.Spec.Rabbitmq.Definitions != nil {
+		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts, corev1.VolumeMount{
+			Name:      "definitions",
+			MountPath: definitionsDir,
+			ReadOnly:  true,
+		})
+	}
+
 	if builder.Instance.Spec.Rabbitmq.EnvConfig != "" {
 		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts, corev1.VolumeMount{
 			Name: "server-conf", MountPath: "/etc/rabbitmq/rabbitmq-env.conf", SubPath: "rabbitmq-env.conf",
@@ -502,6 +638,44 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 		})
 	}
 
+	if builder.Instance.InterNodeTLSEnabled() {
+		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts,
+			corev1.VolumeMount{
+				Name: "server-conf", MountPath: interNodeTLSConfigPath, SubPath: InterNodeTLSConfigKey,
+			},
+			corev1.VolumeMount{
+				Name:      "rabbitmq-distribution-tls",
+				MountPath: distributionTLSDir,
+				ReadOnly:  true,
+			},
+		)
+		volumes = append(volumes, corev1.Volume{
+			Name: "rabbitmq-distribution-tls",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: builder.Instance.Spec.TLS.Distribution.SecretName,
+					Items: []corev1.KeyToPath{
+						{Key: "ca.crt", Path: "ca.crt"},
+						{Key: "tls.crt", Path: "tls.crt"},
+						{Key: "tls.key", Path: "tls.key"},
+					},
+					DefaultMode: ptr.To(int32(400)),
+				},
+			},
+		})
+	}
+
+	if builder.Instance.CSIEnabled() {
+		volumes = append(volumes, secretsStoreVolume(builder.Instance.Spec.SecretBackend.CSI))
+	}
+	if builder.Instance.CSITLSEnabled() {
+		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts, corev1.VolumeMount{
+			Name:      secretsStoreVolumeName,
+			MountPath: "/etc/rabbitmq-tls/",
+			ReadOnly:  true,
+		})
+	}
+
 	tlsSpec := builder.Instance.Spec.TLS
 	if builder.Instance.SecretTLSEnabled() {
 		rabbitmqContainerVolumeMounts = append(rabbitmqContainerVolumeMounts, corev1.VolumeMount{
@@ -551,11 +725,20 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 		volumes = append(volumes, tlsProjectedVolume)
 	}
 
+	// injected and mapped labels are set on pods for tools which attribute resource usage by pod labels
+	podLabels := metadata.Label(builder.Instance.Name)
+	for label, value := range builder.InjectedLabels {
+		podLabels[label] = value
+	}
+	for label, value := range metadata.MapLabels(builder.Instance.Labels, builder.LabelMappings) {
+		podLabels[label] = value
+	}
+
 	rabbitmqUID := int64(999)
 	podTemplateSpec := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: metadata.ReconcileAnnotations(previousPodAnnotations, defaultPodAnnotations),
-			Labels:      metadata.Label(builder.Instance.Name),
+			Labels:      podLabels,
 		},
 		Spec: corev1.PodSpec{
 			TopologySpreadConstraints: builder.defaultTopologySpreadConstraints(),
@@ -565,7 +748,7 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 			},
 			ImagePullSecrets:              builder.Instance.Spec.ImagePullSecrets,
 			TerminationGracePeriodSeconds: builder.Instance.Spec.TerminationGracePeriodSeconds,
-			ServiceAccountName:            builder.Instance.ChildResourceName(serviceAccountName),
+			ServiceAccountName:            builder.serviceAccountName(),
 			AutomountServiceAccountToken:  ptr.To(true),
 			Affinity:                      builder.Instance.Spec.Affinity,
 			Tolerations:                   builder.Instance.Spec.Tolerations,
@@ -573,29 +756,13 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 			Volumes:                       volumes,
 			Containers: []corev1.Container{
 				{
-					Name:      "rabbitmq",
-					Resources: *builder.Instance.Spec.Resources,
-					Image:     builder.Instance.Spec.Image,
-					Env: append(envVarsK8sObjects(builder.Instance),
-						corev1.EnvVar{
-							Name:  "RABBITMQ_ENABLED_PLUGINS_FILE",
-							Value: "/operator/enabled_plugins",
-						},
-						corev1.EnvVar{
-							Name:  "RABBITMQ_USE_LONGNAME",
-							Value: "true",
-						},
-						corev1.EnvVar{
-							Name:  "RABBITMQ_NODENAME",
-							Value: "rabbit@$(MY_POD_NAME).$(K8S_SERVICE_NAME).$(MY_POD_NAMESPACE)",
-						},
-						corev1.EnvVar{
-							Name:  "K8S_HOSTNAME_SUFFIX",
-							Value: ".$(K8S_SERVICE_NAME).$(MY_POD_NAMESPACE)",
-						},
-					),
-					Ports:        builder.updateContainerPorts(),
-					VolumeMounts: rabbitmqContainerVolumeMounts,
+					Name:            "rabbitmq",
+					Resources:       *builder.Instance.Spec.Resources,
+					Image:           builder.Instance.Spec.Image,
+					ImagePullPolicy: builder.Instance.RabbitmqImagePullPolicy(),
+					Env:             rabbitmqContainerEnv,
+					Ports:           builder.updateContainerPorts(),
+					VolumeMounts:    rabbitmqContainerVolumeMounts,
 					// Why using a tcp readiness probe instead of running `rabbitmq-diagnostics check_port_connectivity`?
 					// Using rabbitmq-diagnostics command as the probe could cause context deadline exceeded errors
 					// Pods could be stuck at terminating at deletion as a result of that
@@ -646,7 +813,8 @@ func (builder *StatefulSetBuilder) podTemplateSpec(previousPodAnnotations map[st
 func (builder *StatefulSetBuilder) rabbitmqConfigurationIsSet() bool {
 	return builder.Instance.Spec.Rabbitmq.AdvancedConfig != "" ||
 		builder.Instance.Spec.Rabbitmq.EnvConfig != "" ||
-		builder.Instance.Spec.Rabbitmq.ErlangInetConfig != ""
+		builder.Instance.Spec.Rabbitmq.ErlangInetConfig != "" ||
+		builder.Instance.InterNodeTLSEnabled()
 }
 
 func defaultUserCredentialUpdater(instance *rabbitmqv1beta1.RabbitmqCluster) corev1.Container {
@@ -726,6 +894,56 @@ func envVarsK8sObjects(instance *rabbitmqv1beta1.RabbitmqCluster) []corev1.EnvVa
 	}
 }
 
+// envVarsNodeNameByIP names the RabbitMQ node after the IP of its Pod, which is how peers
+// are addressed when the peer discovery address type is ip.
+func envVarsNodeNameByIP(envVars []corev1.EnvVar) []corev1.EnvVar {
+	var result []corev1.EnvVar
+	for _, envVar := range envVars {
+		if envVar.Name == "RABBITMQ_NODENAME" {
+			result = append(result, corev1.EnvVar{
+				Name: "MY_POD_IP",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath:  "status.podIP",
+						APIVersion: "v1",
+					},
+				},
+			})
+			envVar.Value = "rabbit@$(MY_POD_IP)"
+		}
+		result = append(result, envVar)
+	}
+	return result
+}
+
+// envVarsDistribution configures Erlang distribution and the CLI tools, which use IPv4 without TLS by default,
+// to use IPv6 when it is the primary IP family, and TLS when inter-node TLS is enabled.
+func envVarsDistribution(instance *rabbitmqv1beta1.RabbitmqCluster) []corev1.EnvVar {
+	protoDist := "inet"
+	if instance.IPv6Primary() {
+		protoDist = "inet6"
+	}
+	var erlArgs string
+	switch {
+	case instance.InterNodeTLSEnabled():
+		erlArgs = fmt.Sprintf("-proto_dist %s_tls -ssl_dist_optfile %s", protoDist, interNodeTLSConfigPath)
+	case instance.IPv6Primary():
+		erlArgs = "-proto_dist inet6_tcp"
+	default:
+		return nil
+	}
+	return []corev1.EnvVar{
+		{
+			Name:  "RABBITMQ_SERVER_ADDITIONAL_ERL_ARGS",
+			Value: erlArgs,
+		},
+		{
+			Name:  "RABBITMQ_CTL_ERL_ARGS",
+			Value: erlArgs,
+		},
+	}
+}
+
 func setupContainer(instance *rabbitmqv1beta1.RabbitmqCluster) corev1.Container {
 	//Init Container resources
 	cpuRequest := k8sresource.MustParse(initContainerCPU)
@@ -741,9 +959,10 @@ func setupContainer(instance *rabbitmqv1beta1.RabbitmqCluster) corev1.Container
 			"sleep " + strconv.Itoa(int(ptr.Deref(instance.Spec.DelayStartSeconds, 30))),
 	}
 	setupContainer := corev1.Container{
-		Name:    "setup-container",
-		Image:   instance.Spec.Image,
-		Command: command,
+		Name:            "setup-container",
+		Image:           instance.Spec.Image,
+		ImagePullPolicy: instance.RabbitmqImagePullPolicy(),
+		Command:         command,
 		Resources: corev1.ResourceRequirements{
 			Limits: corev1.ResourceList{
 				"cpu":    cpuRequest,
@@ -782,9 +1001,13 @@ func setupContainer(instance *rabbitmqv1beta1.RabbitmqCluster) corev1.Container
 		// Vault annotation automatically mounts the volume
 		setupContainer.Command[2] = fmt.Sprintf(setupContainer.Command[2], "/etc/rabbitmq/conf.d/11-default_user.conf")
 	} else {
+		defaultUserVolume := "rabbitmq-confd"
+		if instance.CSIDefaultUserEnabled() {
+			defaultUserVolume = secretsStoreVolumeName
+		}
 		setupContainer.Command[2] = fmt.Sprintf(setupContainer.Command[2], "/tmp/default_user.conf")
 		setupContainer.VolumeMounts = append(setupContainer.VolumeMounts, corev1.VolumeMount{
-			Name:      "rabbitmq-confd",
+			Name:      defaultUserVolume,
 			MountPath: "/tmp/default_user.conf",
 			SubPath:   "default_user.conf",
 		})
@@ -792,6 +1015,24 @@ func setupContainer(instance *rabbitmqv1beta1.RabbitmqCluster) corev1.Container
 	return setupContainer
 }
 
+// secretsStoreVolume returns the volume of the Secrets Store CSI Driver configured in spec.secretBackend.csi.
+func secretsStoreVolume(csi *rabbitmqv1beta1.CSISpec) corev1.Volume {
+	driver := csi.Driver
+	if driver == "" {
+		driver = defaultCSIDriver
+	}
+	return corev1.Volume{
+		Name: secretsStoreVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			CSI: &corev1.CSIVolumeSource{
+				Driver:           driver,
+				ReadOnly:         ptr.To(true),
+				VolumeAttributes: map[string]string{"secretProviderClass": csi.SecretProviderClass},
+			},
+		},
+	}
+}
+
 func appendDefaultUserSecretVolumeProjection(volumes []corev1.Volume, instance *rabbitmqv1beta1.RabbitmqCluster, secretName string) {
 
 	if secretName == "" {
@@ -819,6 +1060,83 @@ func appendDefaultUserSecretVolumeProjection(volumes []corev1.Volume, instance *
 	}
 }
 
+// appendConfigFragmentVolumeProjections projects the keys of spec.additionalConfigMaps and spec.additionalSecrets
+// into the rabbitmq-confd volume, in the paths returned by configFragmentPaths.
+func appendConfigFragmentVolumeProjections(volumes []corev1.Volume, instance *rabbitmqv1beta1.RabbitmqCluster) {
+	var projections []corev1.VolumeProjection
+	for _, configMap := range instance.Spec.AdditionalConfigMaps {
+		projections = append(projections, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+				Items:                configFragmentItems(configMapFragmentPrefix, configMap),
+			},
+		})
+	}
+	for _, secret := range instance.Spec.AdditionalSecrets {
+		projections = append(projections, corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+				Items:                configFragmentItems(secretFragmentPrefix, secret),
+			},
+		})
+	}
+
+	for _, value := range volumes {
+		if value.Name == "rabbitmq-confd" {
+			value.VolumeSource.Projected.Sources = append(value.VolumeSource.Projected.Sources, projections...)
+		}
+	}
+}
+
+// definitionsVolume provides the definitions file of spec.rabbitmq.definitions.
+func definitionsVolume(source *rabbitmqv1beta1.DefinitionsSource) corev1.Volume {
+	items := []corev1.KeyToPath{{Key: source.DefinitionsKey(), Path: "definitions.json"}}
+	volume := corev1.Volume{Name: "definitions"}
+	if source.SecretRef != nil {
+		volume.VolumeSource.Secret = &corev1.SecretVolumeSource{
+			SecretName: source.SecretRef.Name,
+			Items:      items,
+		}
+	} else if source.ConfigMapRef != nil {
+		volume.VolumeSource.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: *source.ConfigMapRef,
+			Items:                items,
+		}
+	}
+	return volume
+}
+
+const (
+	configMapFragmentPrefix = "50"
+	secretFragmentPrefix    = "60"
+)
+
+func configFragmentItems(prefix string, source rabbitmqv1beta1.ConfigFragmentSource) []corev1.KeyToPath {
+	items := make([]corev1.KeyToPath, 0, len(source.Keys))
+	for _, key := range source.Keys {
+		items = append(items, corev1.KeyToPath{
+			Key:  key,
+			Path: fmt.Sprintf("%s-%s-%s", prefix, source.Name, key),
+		})
+	}
+	return items
+}
+
+func configFragmentPaths(instance *rabbitmqv1beta1.RabbitmqCluster) []string {
+	var paths []string
+	for _, configMap := range instance.Spec.AdditionalConfigMaps {
+		for _, item := range configFragmentItems(configMapFragmentPrefix, configMap) {
+			paths = append(paths, item.Path)
+		}
+	}
+	for _, secret := range instance.Spec.AdditionalSecrets {
+		for _, item := range configFragmentItems(secretFragmentPrefix, secret) {
+			paths = append(paths, item.Path)
+		}
+	}
+	return paths
+}
+
 func appendVaultAnnotations(currentAnnotations map[string]string, instance *rabbitmqv1beta1.RabbitmqCluster) map[string]string {
 	vault := instance.Spec.SecretBackend.Vault
 
@@ -872,7 +1190,7 @@ default_pass = {{ .Data.data.password }}
 func podHostNames(instance *rabbitmqv1beta1.RabbitmqCluster) string {
 	altNames := ""
 	var i int32
-	for i = 0; i < ptr.Deref(instance.Spec.Replicas, 1); i++ {
+	for i = 0; i < *instance.Spec.Replicas; i++ {
 		altNames += fmt.Sprintf(",%s", fmt.Sprintf("%s-%d.%s.%s", instance.ChildResourceName(stsSuffix), i, instance.ChildResourceName(headlessServiceSuffix), instance.Namespace))
 	}
 	return strings.TrimPrefix(altNames, ",")
@@ -1149,3 +1467,12 @@ func containerRabbitmq(containers []corev1.Container) corev1.Container {
 	}
 	return corev1.Container{}
 }
+
+// serviceAccountName returns the existing ServiceAccount set in the spec,
+// or the ServiceAccount created by the operator.
+func (builder *StatefulSetBuilder) serviceAccountName() string {
+	if builder.Instance.Spec.ServiceAccountName != "" {
+		return builder.Instance.Spec.ServiceAccountName
+	}
+	return builder.Instance.ChildResourceName(serviceAccountName)
+}
@@ -10,6 +10,10 @@
 package resource_test
 
 import (
+	"slices"
+	"sort"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
@@ -73,6 +77,12 @@ var _ = Describe("StatefulSet", func() {
 			Expect(labels["app.kubernetes.io/name"]).To(Equal(instance.Name))
 		})
 
+		It("returns an error if the RabbitmqCluster is not defaulted", func() {
+			builder.Instance.Spec.Replicas = nil
+			_, err := stsBuilder.Build()
+			Expect(err).To(MatchError(rabbitmqv1beta1.ErrNotDefaulted))
+		})
+
 		It("sets pod management policy to 'Parallel' ", func() {
 			obj, err := stsBuilder.Build()
 			Expect(err).NotTo(HaveOccurred())
@@ -283,6 +293,14 @@ var _ = Describe("StatefulSet", func() {
 			Expect(statefulSet.Spec.UpdateStrategy).To(Equal(updateStrategy))
 		})
 
+		It("uses the OnDelete strategy for health gated updates", func() {
+			instance.Spec.UpdateStrategy = rabbitmqv1beta1.HealthGatedStrategy
+			stsBuilder := builder.StatefulSet()
+			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+
+			Expect(statefulSet.Spec.UpdateStrategy).To(Equal(appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}))
+		})
+
 		It("updates toleration", func() {
 			newToleration := corev1.Toleration{
 				Key:      "update",
@@ -363,6 +381,26 @@ var _ = Describe("StatefulSet", func() {
 				))
 			})
 
+			It("adds mapped labels to the statefulset and pods", func() {
+				builder.LabelMappings = map[string]string{
+					"rabbitmq":    "example.com/rabbitmq",
+					"cost-center": "example.com/cost-center",
+				}
+				stsBuilder := builder.StatefulSet()
+				Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+
+				Expect(statefulSet.Labels).To(SatisfyAll(
+					HaveKeyWithValue("rabbitmq", "is-great"),
+					HaveKeyWithValue("example.com/rabbitmq", "is-great"),
+					Not(HaveKey("example.com/cost-center")),
+				))
+				Expect(statefulSet.Spec.Template.ObjectMeta.Labels).To(SatisfyAll(
+					HaveLen(4),
+					HaveKeyWithValue("example.com/rabbitmq", "is-great"),
+					Not(HaveKey("rabbitmq")),
+				))
+			})
+
 			It("adds the correct labels on the statefulset", func() {
 				stsBuilder.Instance.Labels = map[string]string{
 					"app.kubernetes.io/foo": "bar",
@@ -868,6 +906,10 @@ var _ = Describe("StatefulSet", func() {
 					Name:  "K8S_HOSTNAME_SUFFIX",
 					Value: ".$(K8S_SERVICE_NAME).$(MY_POD_NAMESPACE)",
 				},
+				{
+					Name:  "RABBITMQ_LOGS",
+					Value: "",
+				},
 			}
 
 			container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
@@ -893,6 +935,72 @@ var _ = Describe("StatefulSet", func() {
 
 		})
 
+		When("SecretBackend.ExistingErlangCookieSecret is set", func() {
+			BeforeEach(func() {
+				instance.Spec.SecretBackend.ExistingErlangCookieSecret.Name = "shared-erlang-cookie"
+			})
+
+			It("mounts the existing Secret as Erlang cookie", func() {
+				Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+				cookieVolume := extractVolume(statefulSet.Spec.Template.Spec.Volumes, "erlang-cookie-secret")
+				Expect(cookieVolume.Secret.SecretName).To(Equal("shared-erlang-cookie"))
+			})
+		})
+
+		Context("CSI", func() {
+			BeforeEach(func() {
+				instance.Spec.SecretBackend.CSI = &rabbitmqv1beta1.CSISpec{
+					SecretProviderClass: "rabbitmq-secrets",
+				}
+			})
+			JustBeforeEach(func() {
+				Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+			})
+
+			It("adds the Secrets Store CSI Driver volume", func() {
+				volume := extractVolume(statefulSet.Spec.Template.Spec.Volumes, "secrets-store")
+				Expect(volume.CSI).To(Equal(&corev1.CSIVolumeSource{
+					Driver:           "secrets-store.csi.k8s.io",
+					ReadOnly:         ptr.To(true),
+					VolumeAttributes: map[string]string{"secretProviderClass": "rabbitmq-secrets"},
+				}))
+			})
+
+			When("secretBackend.csi.defaultUser is set", func() {
+				BeforeEach(func() {
+					instance.Spec.SecretBackend.CSI.DefaultUser = true
+				})
+
+				It("mounts default_user.conf from the CSI volume instead of the default user Secret", func() {
+					rabbitmqConfdVolume := extractVolume(statefulSet.Spec.Template.Spec.Volumes, "rabbitmq-confd")
+					Expect(extractProjectedSecret(rabbitmqConfdVolume, "foo-default-user").Secret).To(BeNil())
+
+					container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+					Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+						Name: "secrets-store", MountPath: "/etc/rabbitmq/conf.d/11-default_user.conf", SubPath: "default_user.conf", ReadOnly: true,
+					}))
+					setupContainer := extractContainer(statefulSet.Spec.Template.Spec.InitContainers, "setup-container")
+					Expect(setupContainer.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+						Name: "secrets-store", MountPath: "/tmp/default_user.conf", SubPath: "default_user.conf",
+					}))
+				})
+			})
+
+			When("secretBackend.csi.tls is set", func() {
+				BeforeEach(func() {
+					instance.Spec.SecretBackend.CSI.TLS = true
+				})
+
+				It("mounts the CSI volume as TLS directory and exposes the TLS ports", func() {
+					container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+					Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+						Name: "secrets-store", MountPath: "/etc/rabbitmq-tls/", ReadOnly: true,
+					}))
+					Expect(container.Ports).To(ContainElement(HaveField("Name", "amqps")))
+				})
+			})
+		})
+
 		Context("Vault", func() {
 			BeforeEach(func() {
 				instance.Spec.SecretBackend.Vault = &rabbitmqv1beta1.VaultSpec{
@@ -1158,6 +1266,8 @@ default_pass = {{ .Data.data.password }}
 						{Name: "pod-info", MountPath: "/etc/pod-info/"},
 						{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/10-operatorDefaults.conf", SubPath: "operatorDefaults.conf"},
 						{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/11-default_user.conf", SubPath: "default_user.conf"},
+						{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/20-tls.conf", SubPath: "tls.conf"},
+						{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/30-plugins.conf", SubPath: "plugins.conf"},
 						{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/90-userDefinedConfiguration.conf", SubPath: "userDefinedConfiguration.conf"},
 						{Name: "rabbitmq-plugins", MountPath: "/operator"},
 					}
@@ -1199,6 +1309,146 @@ default_pass = {{ .Data.data.password }}
 			)
 		})
 
+		Context("Additional ConfigMaps and Secrets", func() {
+			BeforeEach(func() {
+				instance.Spec.AdditionalConfigMaps = []rabbitmqv1beta1.ConfigFragmentSource{
+					{Name: "shared-config", Keys: []string{"limits.conf", "logging.conf"}},
+				}
+				instance.Spec.AdditionalSecrets = []rabbitmqv1beta1.ConfigFragmentSource{
+					{Name: "ldap-config", Keys: []string{"ldap.conf"}},
+				}
+				stsBuilder := builder.StatefulSet()
+				Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+			})
+
+			It("projects the keys into the rabbitmq-confd volume", func() {
+				rabbitmqConfdVolume := extractVolume(statefulSet.Spec.Template.Spec.Volumes, "rabbitmq-confd")
+				Expect(rabbitmqConfdVolume.Projected.Sources).To(ContainElements(
+					corev1.VolumeProjection{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "shared-config"},
+							Items: []corev1.KeyToPath{
+								{Key: "limits.conf", Path: "50-shared-config-limits.conf"},
+								{Key: "logging.conf", Path: "50-shared-config-logging.conf"},
+							},
+						},
+					},
+					corev1.VolumeProjection{
+						Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "ldap-config"},
+							Items: []corev1.KeyToPath{
+								{Key: "ldap.conf", Path: "60-ldap-config-ldap.conf"},
+							},
+						},
+					},
+				))
+			})
+
+			It("mounts the keys into conf.d", func() {
+				container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+				Expect(container.VolumeMounts).To(ContainElements(
+					corev1.VolumeMount{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/50-shared-config-limits.conf", SubPath: "50-shared-config-limits.conf"},
+					corev1.VolumeMount{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/50-shared-config-logging.conf", SubPath: "50-shared-config-logging.conf"},
+					corev1.VolumeMount{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/60-ldap-config-ldap.conf", SubPath: "60-ldap-config-ldap.conf"},
+				))
+			})
+
+			It("mounts the settings of spec.rabbitmq.configFrom before the user defined configuration", func() {
+				instance.Spec.Rabbitmq.ConfigFrom = &rabbitmqv1beta1.ConfigFromSource{
+					ConfigMapRef: corev1.LocalObjectReference{Name: "user-conf"},
+				}
+				Expect(builder.StatefulSet().Update(statefulSet)).To(Succeed())
+
+				rabbitmqConfdVolume := extractVolume(statefulSet.Spec.Template.Spec.Volumes, "rabbitmq-confd")
+				Expect(rabbitmqConfdVolume.Projected.Sources[0].ConfigMap.Items).To(ContainElement(
+					corev1.KeyToPath{Key: "configFrom.conf", Path: "configFrom.conf"},
+				))
+				container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+				Expect(container.VolumeMounts).To(ContainElement(
+					corev1.VolumeMount{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/80-configFrom.conf", SubPath: "configFrom.conf"},
+				))
+			})
+
+			It("loads the user defined configuration after all other conf.d files", func() {
+				container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+				var confdFiles []string
+				for _, mount := range container.VolumeMounts {
+					if strings.HasPrefix(mount.MountPath, "/etc/rabbitmq/conf.d/") {
+						confdFiles = append(confdFiles, mount.MountPath)
+					}
+				}
+				sort.Strings(confdFiles)
+				Expect(confdFiles).To(HaveLen(8))
+				Expect(confdFiles[len(confdFiles)-1]).To(Equal("/etc/rabbitmq/conf.d/90-userDefinedConfiguration.conf"))
+			})
+		})
+
+		It("mounts the OAuth2 signing key", func() {
+			instance.Spec.Rabbitmq.Auth.OAuth2 = &rabbitmqv1beta1.OAuth2Spec{
+				ResourceServerID: "rabbitmq",
+				SigningKey: &rabbitmqv1beta1.OAuth2SigningKey{
+					SecretRef: corev1.LocalObjectReference{Name: "uaa-signing-key"},
+					Key:       "key.pem",
+				},
+			}
+			Expect(builder.StatefulSet().Update(statefulSet)).To(Succeed())
+
+			Expect(statefulSet.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "oauth2-signing-key",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "uaa-signing-key",
+						Items:      []corev1.KeyToPath{{Key: "key.pem", Path: "signing-key.pem"}},
+					},
+				},
+			}))
+			container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name: "oauth2-signing-key", MountPath: "/etc/rabbitmq-oauth2/", ReadOnly: true,
+			}))
+		})
+
+		Context("Definitions", func() {
+			It("mounts the definitions of a ConfigMap", func() {
+				instance.Spec.Rabbitmq.Definitions = &rabbitmqv1beta1.DefinitionsSource{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "my-definitions"},
+					Key:          "export.json",
+				}
+				Expect(builder.StatefulSet().Update(statefulSet)).To(Succeed())
+
+				Expect(statefulSet.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "definitions",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "my-definitions"},
+							Items:                []corev1.KeyToPath{{Key: "export.json", Path: "definitions.json"}},
+						},
+					},
+				}))
+				container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+				Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+					Name: "definitions", MountPath: "/etc/rabbitmq-definitions/", ReadOnly: true,
+				}))
+			})
+
+			It("mounts the definitions of a Secret", func() {
+				instance.Spec.Rabbitmq.Definitions = &rabbitmqv1beta1.DefinitionsSource{
+					SecretRef: &corev1.LocalObjectReference{Name: "my-definitions"},
+				}
+				Expect(builder.StatefulSet().Update(statefulSet)).To(Succeed())
+
+				Expect(statefulSet.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "definitions",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "my-definitions",
+							Items:      []corev1.KeyToPath{{Key: "definitions.json", Path: "definitions.json"}},
+						},
+					},
+				}))
+			})
+		})
+
 		Context("Volumes", func() {
 			DescribeTable("Volumes based on user configuration", func(rabbitmqEnv, advancedConfig, erlInetRc string) {
 				stsBuilder := builder.StatefulSet()
@@ -1234,6 +1484,14 @@ default_pass = {{ .Data.data.password }}
 													Key:  "operatorDefaults.conf",
 													Path: "operatorDefaults.conf",
 												},
+												{
+													Key:  "tls.conf",
+													Path: "tls.conf",
+												},
+												{
+													Key:  "plugins.conf",
+													Path: "plugins.conf",
+												},
 												{
 													Key:  "userDefinedConfiguration.conf",
 													Path: "userDefinedConfiguration.conf",
@@ -1343,6 +1601,14 @@ default_pass = {{ .Data.data.password }}
 			Expect(statefulSet.Spec.Template.Spec.ServiceAccountName).To(Equal(instance.ChildResourceName("server")))
 		})
 
+		It("uses an existing service account when configured", func() {
+			instance.Spec.ServiceAccountName = "existing-service-account"
+			stsBuilder := builder.StatefulSet()
+			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+
+			Expect(statefulSet.Spec.Template.Spec.ServiceAccountName).To(Equal("existing-service-account"))
+		})
+
 		It("mounts the service account in its pods", func() {
 			stsBuilder := builder.StatefulSet()
 			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
@@ -1374,6 +1640,92 @@ default_pass = {{ .Data.data.password }}
 			Expect(TCPProbe.Port.StrVal).To(Equal("amqp"))
 		})
 
+		It("configures Erlang distribution for IPv6 when it is the primary IP family", func() {
+			stsBuilder := builder.StatefulSet()
+			instance.Spec.Service.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+			container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+			Expect(container.Env).NotTo(ContainElement(HaveField("Name", "RABBITMQ_SERVER_ADDITIONAL_ERL_ARGS")))
+
+			instance.Spec.Service.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+			container = extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+			Expect(container.Env).To(ContainElements(
+				corev1.EnvVar{Name: "RABBITMQ_SERVER_ADDITIONAL_ERL_ARGS", Value: "-proto_dist inet6_tcp"},
+				corev1.EnvVar{Name: "RABBITMQ_CTL_ERL_ARGS", Value: "-proto_dist inet6_tcp"},
+			))
+		})
+
+		It("configures Erlang distribution for TLS when inter-node TLS is enabled", func() {
+			stsBuilder := builder.StatefulSet()
+			instance.Spec.TLS.Distribution = &rabbitmqv1beta1.DistributionTLSSpec{SecretName: "distribution-tls"}
+			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+			container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+			Expect(container.Env).To(ContainElements(
+				corev1.EnvVar{Name: "RABBITMQ_SERVER_ADDITIONAL_ERL_ARGS", Value: "-proto_dist inet_tls -ssl_dist_optfile /etc/rabbitmq/inter_node_tls.config"},
+				corev1.EnvVar{Name: "RABBITMQ_CTL_ERL_ARGS", Value: "-proto_dist inet_tls -ssl_dist_optfile /etc/rabbitmq/inter_node_tls.config"},
+			))
+			Expect(container.VolumeMounts).To(ContainElements(
+				corev1.VolumeMount{Name: "server-conf", MountPath: "/etc/rabbitmq/inter_node_tls.config", SubPath: "inter_node_tls.config"},
+				corev1.VolumeMount{Name: "rabbitmq-distribution-tls", MountPath: "/etc/rabbitmq-distribution-tls/", ReadOnly: true},
+			))
+			Expect(statefulSet.Spec.Template.Spec.Volumes).To(ContainElements(
+				HaveField("Name", "server-conf"),
+				corev1.Volume{
+					Name: "rabbitmq-distribution-tls",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: "distribution-tls",
+							Items: []corev1.KeyToPath{
+								{Key: "ca.crt", Path: "ca.crt"},
+								{Key: "tls.crt", Path: "tls.crt"},
+								{Key: "tls.key", Path: "tls.key"},
+							},
+							DefaultMode: ptr.To(int32(400)),
+						},
+					},
+				},
+			))
+
+			instance.Spec.Service.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+			container = extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+			Expect(container.Env).To(ContainElement(
+				corev1.EnvVar{Name: "RABBITMQ_SERVER_ADDITIONAL_ERL_ARGS", Value: "-proto_dist inet6_tls -ssl_dist_optfile /etc/rabbitmq/inter_node_tls.config"},
+			))
+		})
+
+		It("names the RabbitMQ node after the Pod IP when peers are addressed by IP", func() {
+			stsBuilder := builder.StatefulSet()
+			instance.Spec.Rabbitmq.PeerDiscovery.AddressType = "ip"
+			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+			container := extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq")
+
+			var envNames []string
+			for _, envVar := range container.Env {
+				envNames = append(envNames, envVar.Name)
+			}
+			// MY_POD_IP must be defined before it is referenced
+			Expect(envNames).To(ContainElements("MY_POD_IP", "RABBITMQ_NODENAME"))
+			Expect(slices.Index(envNames, "MY_POD_IP")).To(BeNumerically("<", slices.Index(envNames, "RABBITMQ_NODENAME")))
+			Expect(container.Env).To(ContainElements(
+				corev1.EnvVar{Name: "MY_POD_IP", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP", APIVersion: "v1"}}},
+				corev1.EnvVar{Name: "RABBITMQ_NODENAME", Value: "rabbit@$(MY_POD_IP)"},
+			))
+		})
+
+		It("sets the image pull policy on the rabbitmq and init containers", func() {
+			stsBuilder := builder.StatefulSet()
+			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+			Expect(extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq").ImagePullPolicy).To(Equal(corev1.PullAlways))
+			Expect(extractContainer(statefulSet.Spec.Template.Spec.InitContainers, "setup-container").ImagePullPolicy).To(Equal(corev1.PullAlways))
+
+			instance.Spec.ImagePullPolicy = corev1.PullNever
+			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
+			Expect(extractContainer(statefulSet.Spec.Template.Spec.Containers, "rabbitmq").ImagePullPolicy).To(Equal(corev1.PullNever))
+			Expect(extractContainer(statefulSet.Spec.Template.Spec.InitContainers, "setup-container").ImagePullPolicy).To(Equal(corev1.PullNever))
+		})
+
 		It("templates the correct InitContainer", func() {
 			stsBuilder := builder.StatefulSet()
 			Expect(stsBuilder.Update(statefulSet)).To(Succeed())
@@ -2053,6 +2405,10 @@ default_pass = {{ .Data.data.password }}
 							{
 								Name:  "K8S_HOSTNAME_SUFFIX",
 								Value: ".$(K8S_SERVICE_NAME).$(MY_POD_NAMESPACE)",
+							},
+							{
+								Name:  "RABBITMQ_LOGS",
+								Value: "",
 							}}))
 					Expect(extractContainer(statefulSet.Spec.Template.Spec.Containers, "new-container-0")).To(Equal(
 						corev1.Container{Name: "new-container-0", Image: "my-image-0"}))
@@ -2078,6 +2434,16 @@ default_pass = {{ .Data.data.password }}
 								MountPath: "/etc/rabbitmq/conf.d/11-default_user.conf",
 								SubPath:   "default_user.conf",
 							},
+							{
+								Name:      "rabbitmq-confd",
+								MountPath: "/etc/rabbitmq/conf.d/20-tls.conf",
+								SubPath:   "tls.conf",
+							},
+							{
+								Name:      "rabbitmq-confd",
+								MountPath: "/etc/rabbitmq/conf.d/30-plugins.conf",
+								SubPath:   "plugins.conf",
+							},
 							{
 								Name:      "rabbitmq-confd",
 								MountPath: "/etc/rabbitmq/conf.d/90-userDefinedConfiguration.conf",
@@ -2241,6 +2607,8 @@ default_pass = {{ .Data.data.password }}
 							{Name: "pod-info", MountPath: "/etc/pod-info/"},
 							{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/10-operatorDefaults.conf", SubPath: "operatorDefaults.conf"},
 							{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/11-default_user.conf", SubPath: "default_user.conf"},
+							{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/20-tls.conf", SubPath: "tls.conf"},
+							{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/30-plugins.conf", SubPath: "plugins.conf"},
 							{Name: "rabbitmq-confd", MountPath: "/etc/rabbitmq/conf.d/90-userDefinedConfiguration.conf", SubPath: "userDefinedConfiguration.conf"},
 							{Name: "rabbitmq-plugins", MountPath: "/operator"},
 							{Name: "test", MountPath: "test"},
@@ -2363,6 +2731,10 @@ default_pass = {{ .Data.data.password }}
 								Name:  "K8S_HOSTNAME_SUFFIX",
 								Value: ".$(K8S_SERVICE_NAME).$(MY_POD_NAMESPACE)",
 							},
+							{
+								Name:  "RABBITMQ_LOGS",
+								Value: "",
+							},
 							{
 								Name:  "RABBITMQ_STREAM_ADVERTISED_HOST",
 								Value: "$(MY_POD_NAME).$(K8S_SERVICE_NAME).$(MY_POD_NAMESPACE)",
@@ -2404,6 +2776,48 @@ default_pass = {{ .Data.data.password }}
 		})
 	})
 
+	Describe("Preserve", func() {
+		BeforeEach(func() {
+			instance = generateRabbitmqCluster()
+
+			scheme = runtime.NewScheme()
+			Expect(rabbitmqv1beta1.AddToScheme(scheme)).To(Succeed())
+			Expect(defaultscheme.AddToScheme(scheme)).To(Succeed())
+			builder = &resource.RabbitmqResourceBuilder{
+				Instance: &instance,
+				Scheme:   scheme,
+			}
+			stsBuilder = builder.StatefulSet()
+		})
+
+		It("keeps the operator annotations of the existing Pod template", func() {
+			existing := &appsv1.StatefulSet{}
+			existing.Spec.Template.Annotations = map[string]string{
+				"rabbitmq.com/lastRestartAt":        "2024-01-01T00:00:00Z",
+				"kubectl.kubernetes.io/restartedAt": "2024-01-01T00:00:00Z",
+			}
+			obj, err := stsBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			stsBuilder.Preserve(existing, obj)
+			Expect(stsBuilder.Update(obj)).To(Succeed())
+
+			annotations := obj.(*appsv1.StatefulSet).Spec.Template.Annotations
+			Expect(annotations).To(HaveKeyWithValue("rabbitmq.com/lastRestartAt", "2024-01-01T00:00:00Z"))
+			Expect(annotations).NotTo(HaveKey("kubectl.kubernetes.io/restartedAt"))
+		})
+
+		It("keeps the selector of the existing StatefulSet", func() {
+			existing := &appsv1.StatefulSet{}
+			existing.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/instance": "helm-release"}}
+			obj, err := stsBuilder.Build()
+			Expect(err).NotTo(HaveOccurred())
+			stsBuilder.Preserve(existing, obj)
+			Expect(stsBuilder.Update(obj)).To(Succeed())
+
+			Expect(obj.(*appsv1.StatefulSet).Spec.Selector.MatchLabels).To(Equal(map[string]string{"app.kubernetes.io/instance": "helm-release"}))
+		})
+	})
+
 	Context("UpdateMayRequireStsRecreate", func() {
 		It("returns true", func() {
 			Expect(stsBuilder.UpdateMayRequireStsRecreate()).To(BeTrue())
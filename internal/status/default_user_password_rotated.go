@@ -0,0 +1,19 @@
+package status
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultUserPasswordRotatedCondition reports the progress of the last rotation of the default user's password.
+func DefaultUserPasswordRotatedCondition(status corev1.ConditionStatus, reason, message string) RabbitmqClusterCondition {
+	return RabbitmqClusterCondition{
+		Type:               DefaultUserPasswordRotated,
+		Status:             status,
+		LastTransitionTime: metav1.Time{Time: time.Now()},
+		Reason:             reason,
+		Message:            message,
+	}
+}
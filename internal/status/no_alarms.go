@@ -0,0 +1,19 @@
+package status
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NoAlarmsCondition reports the memory, disk and file descriptor alarms raised by the RabbitMQ nodes.
+func NoAlarmsCondition(status corev1.ConditionStatus, reason, message string) RabbitmqClusterCondition {
+	return RabbitmqClusterCondition{
+		Type:               NoAlarms,
+		Status:             status,
+		LastTransitionTime: metav1.Time{Time: time.Now()},
+		Reason:             reason,
+		Message:            message,
+	}
+}
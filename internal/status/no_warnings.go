@@ -10,6 +10,7 @@
 package status
 
 import (
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -19,7 +20,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-func NoWarningsCondition(resources []runtime.Object, oldCondition *RabbitmqClusterCondition) RabbitmqClusterCondition {
+// NoWarningsCondition is false when the memory request and limit of the RabbitMQ container differ, or when
+// configurationWarnings lists deprecated or risky settings of the RabbitmqCluster.
+func NoWarningsCondition(resources []runtime.Object, configurationWarnings []string, oldCondition *RabbitmqClusterCondition) RabbitmqClusterCondition {
 	condition := newRabbitmqClusterCondition(NoWarnings)
 	if oldCondition != nil {
 		condition.LastTransitionTime = oldCondition.LastTransitionTime
@@ -42,6 +45,13 @@ func NoWarningsCondition(resources []runtime.Object, oldCondition *RabbitmqClust
 				goto assignLastTransitionTime
 			}
 
+			if len(configurationWarnings) > 0 {
+				condition.Status = corev1.ConditionFalse
+				condition.Reason = "ConfigurationWarnings"
+				condition.Message = strings.Join(configurationWarnings, "; ")
+				goto assignLastTransitionTime
+			}
+
 			condition.Status = corev1.ConditionTrue
 			condition.Reason = "NoWarnings"
 		}
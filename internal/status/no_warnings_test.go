@@ -38,7 +38,7 @@ var _ = Describe("NoWarnings", func() {
 				},
 			},
 		}
-		condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{sts}, nil)
+		condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{sts}, nil, nil)
 		By("having the correct type", func() {
 			var conditionType rabbitmqstatus.RabbitmqClusterConditionType = "NoWarnings"
 			Expect(condition.Type).To(Equal(conditionType))
@@ -52,7 +52,7 @@ var _ = Describe("NoWarnings", func() {
 	})
 
 	It("is false if the memory request does not match the memory limit", func() {
-		condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, nil)
+		condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, nil, nil)
 		By("having the correct type", func() {
 			var conditionType rabbitmqstatus.RabbitmqClusterConditionType = "NoWarnings"
 			Expect(condition.Type).To(Equal(conditionType))
@@ -67,7 +67,7 @@ var _ = Describe("NoWarnings", func() {
 
 	It("is unknown when the StatefulSet does not exist", func() {
 		var sts *appsv1.StatefulSet = nil
-		condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{sts}, nil)
+		condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{sts}, nil, nil)
 
 		By("having status unknown and reason", func() {
 			Expect(condition.Status).To(Equal(corev1.ConditionUnknown))
@@ -98,7 +98,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("remains true", func() {
 				It("does not update transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{noMemoryWarningStatefulSet()}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{noMemoryWarningStatefulSet()}, nil, existingCondition)
 
 					Expect(existingCondition).NotTo(BeNil())
 					existingConditionTime := existingCondition.LastTransitionTime.DeepCopy()
@@ -108,7 +108,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("transitions to false", func() {
 				It("updates transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, nil, existingCondition)
 
 					Expect(existingCondition).NotTo(BeNil())
 					existingConditionTime := existingCondition.LastTransitionTime.DeepCopy()
@@ -119,7 +119,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("transitions to unknown", func() {
 				It("updates transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{nil}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{nil}, nil, existingCondition)
 
 					Expect(existingCondition).NotTo(BeNil())
 					existingConditionTime := existingCondition.LastTransitionTime.DeepCopy()
@@ -141,7 +141,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("transitions to true", func() {
 				It("updates transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{noMemoryWarningStatefulSet()}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{noMemoryWarningStatefulSet()}, nil, existingCondition)
 
 					Expect(existingCondition).NotTo(BeNil())
 					existingConditionTime := existingCondition.LastTransitionTime.DeepCopy()
@@ -152,7 +152,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("remains false", func() {
 				It("does not update transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, nil, existingCondition)
 
 					Expect(existingCondition).NotTo(BeNil())
 					existingConditionTime := existingCondition.LastTransitionTime.DeepCopy()
@@ -162,7 +162,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("transitions to unknown", func() {
 				It("updates transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{nil}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{nil}, nil, existingCondition)
 
 					Expect(existingCondition).NotTo(BeNil())
 					existingConditionTime := existingCondition.LastTransitionTime.DeepCopy()
@@ -184,7 +184,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("transitions to true", func() {
 				It("updates transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, nil, existingCondition)
 
 					Expect(existingCondition).NotTo(BeNil())
 					existingConditionTime := existingCondition.LastTransitionTime.DeepCopy()
@@ -197,7 +197,7 @@ var _ = Describe("NoWarnings", func() {
 
 				It("updates transition time", func() {
 
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, nil, existingCondition)
 
 					Expect(existingCondition).NotTo(BeNil())
 					existingConditionTime := existingCondition.LastTransitionTime.DeepCopy()
@@ -208,7 +208,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("remains unknown", func() {
 				It("does not update transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{nil}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{nil}, nil, existingCondition)
 
 					Expect(existingCondition).NotTo(BeNil())
 					existingConditionTime := existingCondition.LastTransitionTime.DeepCopy()
@@ -228,7 +228,7 @@ var _ = Describe("NoWarnings", func() {
 			When("transitions to true", func() {
 
 				It("updates transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, nil, existingCondition)
 
 					Expect(condition.LastTransitionTime).ToNot(Equal(emptyTime))
 				})
@@ -236,7 +236,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("transitions to false", func() {
 				It("updates transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, nil, existingCondition)
 
 					Expect(condition.LastTransitionTime).ToNot(Equal(emptyTime))
 				})
@@ -244,7 +244,7 @@ var _ = Describe("NoWarnings", func() {
 
 			When("transitions to unknown", func() {
 				It("updates transition time", func() {
-					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{nil}, existingCondition)
+					condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{nil}, nil, existingCondition)
 
 					Expect(condition.LastTransitionTime).ToNot(Equal(emptyTime))
 				})
@@ -294,3 +294,17 @@ func noMemoryWarningStatefulSet() *appsv1.StatefulSet {
 
 	return sts
 }
+
+var _ = Describe("NoWarnings with configuration warnings", func() {
+	It("is false and lists the configuration warnings", func() {
+		condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{noMemoryWarningStatefulSet()}, []string{"first warning", "second warning"}, nil)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("ConfigurationWarnings"))
+		Expect(condition.Message).To(Equal("first warning; second warning"))
+	})
+
+	It("reports a memory warning first", func() {
+		condition := rabbitmqstatus.NoWarningsCondition([]runtime.Object{memoryWarningStatefulSet()}, []string{"first warning"}, nil)
+		Expect(condition.Reason).To(Equal("MemoryRequestAndLimitDifferent"))
+	})
+})
@@ -1,12 +1,19 @@
 package status
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ErrNotControlled is returned when a child resource exists but is not controlled by the RabbitmqCluster,
+// for example because it was created by a previous Helm deployment, and adoption is not enabled.
+var ErrNotControlled = errors.New("child resource exists and is not controlled by the RabbitmqCluster")
+
 func ReconcileSuccessCondition(status corev1.ConditionStatus, reason, message string) RabbitmqClusterCondition {
 	return RabbitmqClusterCondition{
 		Type:               ReconcileSuccess,
@@ -16,3 +23,24 @@ func ReconcileSuccessCondition(status corev1.ConditionStatus, reason, message st
 		Message:            message,
 	}
 }
+
+// ApplyFailureReason returns the ReconcileSuccess reason for an error returned by the Kubernetes API
+// when creating or updating a child resource.
+func ApplyFailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrNotControlled):
+		return "ChildResourceNotControlled"
+	case k8serrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota"):
+		return "ResourceQuotaExceeded"
+	case k8serrors.IsForbidden(err):
+		return "Forbidden"
+	case k8serrors.IsInvalid(err):
+		return "InvalidChildResource"
+	case k8serrors.IsConflict(err), k8serrors.IsAlreadyExists(err):
+		return "ChildResourceConflict"
+	case k8serrors.IsTimeout(err), k8serrors.IsServerTimeout(err), k8serrors.IsTooManyRequests(err), k8serrors.IsServiceUnavailable(err):
+		return "APIServerUnavailable"
+	default:
+		return "FailedApplyChildResource"
+	}
+}
@@ -1,10 +1,16 @@
 package status_test
 
 import (
+	"errors"
+	"fmt"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	. "github.com/rabbitmq/cluster-operator/v2/internal/status"
 )
@@ -21,3 +27,20 @@ var _ = Describe("ReconcileSuccess", func() {
 		Expect(reconcilableCondition.LastTransitionTime).NotTo(Equal(emptyTime))
 	})
 })
+
+var _ = Describe("ApplyFailureReason", func() {
+	gr := schema.GroupResource{Group: "apps", Resource: "statefulsets"}
+
+	DescribeTable("maps API errors to machine-readable reasons",
+		func(err error, reason string) {
+			Expect(ApplyFailureReason(err)).To(Equal(reason))
+		},
+		Entry("exceeded quota", k8serrors.NewForbidden(gr, "rabbit-server", errors.New(`exceeded quota: compute, requested: requests.memory=2Gi, used: requests.memory=1Gi, limited: requests.memory=2Gi`)), "ResourceQuotaExceeded"),
+		Entry("forbidden", k8serrors.NewForbidden(gr, "rabbit-server", errors.New("not allowed")), "Forbidden"),
+		Entry("invalid", k8serrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "StatefulSet"}, "rabbit-server", field.ErrorList{field.Invalid(field.NewPath("spec", "volumeClaimTemplates"), "", "invalid storage class")}), "InvalidChildResource"),
+		Entry("conflict", k8serrors.NewConflict(gr, "rabbit-server", errors.New("modified")), "ChildResourceConflict"),
+		Entry("timeout", k8serrors.NewTimeoutError("timed out", 1), "APIServerUnavailable"),
+		Entry("not controlled", fmt.Errorf("%w: set annotation to adopt it", ErrNotControlled), "ChildResourceNotControlled"),
+		Entry("other", errors.New("connection refused"), "FailedApplyChildResource"),
+	)
+})
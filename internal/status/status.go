@@ -19,10 +19,15 @@ import (
 )
 
 const (
-	AllReplicasReady RabbitmqClusterConditionType = "AllReplicasReady"
-	ClusterAvailable RabbitmqClusterConditionType = "ClusterAvailable"
-	NoWarnings       RabbitmqClusterConditionType = "NoWarnings"
-	ReconcileSuccess RabbitmqClusterConditionType = "ReconcileSuccess"
+	AllReplicasReady           RabbitmqClusterConditionType = "AllReplicasReady"
+	ClusterAvailable           RabbitmqClusterConditionType = "ClusterAvailable"
+	NoWarnings                 RabbitmqClusterConditionType = "NoWarnings"
+	ReconcileSuccess           RabbitmqClusterConditionType = "ReconcileSuccess"
+	TLSCertificateLoaded       RabbitmqClusterConditionType = "TLSCertificateLoaded"
+	DefaultUserPasswordRotated RabbitmqClusterConditionType = "DefaultUserPasswordRotated"
+	NoAlarms                   RabbitmqClusterConditionType = "NoAlarms"
+	NoPartitions               RabbitmqClusterConditionType = "NoPartitions"
+	Paused                     RabbitmqClusterConditionType = "Paused"
 )
 
 type RabbitmqClusterConditionType string
@@ -0,0 +1,22 @@
+package status_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/rabbitmq/cluster-operator/v2/internal/status"
+)
+
+var _ = Describe("TLSCertificateLoaded", func() {
+
+	It("has the required fields", func() {
+		condition := TLSCertificateLoadedCondition(corev1.ConditionTrue, "CertificateLoaded", "SomeMessage")
+		Expect(condition.Type).To(Equal(RabbitmqClusterConditionType("TLSCertificateLoaded")))
+		Expect(condition.Status).To(Equal(corev1.ConditionStatus("True")))
+		Expect(condition.Reason).To(Equal("CertificateLoaded"))
+		Expect(condition.Message).To(Equal("SomeMessage"))
+		Expect(condition.LastTransitionTime).NotTo(Equal(metav1.Time{}))
+	})
+})
@@ -0,0 +1,111 @@
+// Package tracing traces the reconciliations of the operator with OpenTelemetry.
+//
+// Spans are only exported if an OTLP endpoint is configured with the environment variables of the OpenTelemetry SDK,
+// e.g. OTEL_EXPORTER_OTLP_ENDPOINT. Otherwise the global TracerProvider of OpenTelemetry, which drops all spans,
+// is used.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/rabbitmq/cluster-operator"
+	serviceName         = "rabbitmq-cluster-operator"
+)
+
+// Enabled returns true if an OTLP endpoint for traces is configured, and the OpenTelemetry SDK is not disabled.
+func Enabled() bool {
+	if disabled, err := strconv.ParseBool(os.Getenv("OTEL_SDK_DISABLED")); err == nil && disabled {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup registers a TracerProvider which exports spans over OTLP/HTTP. The exporter, the sampler and the resource
+// are configured with the environment variables of the OpenTelemetry SDK. The service name defaults to
+// rabbitmq-cluster-operator. The returned function flushes the remaining spans.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName(serviceName)),
+		// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenTelemetry resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of the operator. It is a child of the span in ctx, if any.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends the span, and marks it as failed if err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Transport returns a RoundTripper which traces the requests sent by next. Requests are only traced if their context
+// holds a span, so that the requests of informers and other background requests do not create traces of their own.
+// The spans are named after the API and the HTTP method, e.g. "kubernetes-api PATCH".
+func Transport(api string, next http.RoundTripper) http.RoundTripper {
+	return &transport{api: api, next: next}
+}
+
+type transport struct {
+	api  string
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !trace.SpanFromContext(req.Context()).SpanContext().IsValid() {
+		return t.next.RoundTrip(req)
+	}
+	_, span := otel.Tracer(instrumentationName).Start(req.Context(), t.api+" "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+			semconv.URLPath(req.URL.Path),
+		))
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		End(span, err)
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		// HTTP clients treat all 4xx and 5xx responses as errors in the OpenTelemetry semantic conventions
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	span.End()
+	return resp, nil
+}
@@ -0,0 +1,111 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe("Tracing", func() {
+	var (
+		recorder *tracetest.SpanRecorder
+		server   *httptest.Server
+		client   *http.Client
+	)
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		DeferCleanup(func() {
+			otel.SetTracerProvider(previous)
+		})
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/health/checks/alarms" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		DeferCleanup(server.Close)
+		client = &http.Client{Transport: tracing.Transport("management-api", http.DefaultTransport)}
+	})
+
+	get := func(ctx context.Context, path string) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+	}
+
+	attributes := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		values := map[attribute.Key]attribute.Value{}
+		for _, kv := range span.Attributes() {
+			values[kv.Key] = kv.Value
+		}
+		return values
+	}
+
+	It("traces requests as children of the span in their context", func() {
+		ctx, span := tracing.Start(context.Background(), "Reconcile")
+		get(ctx, "/api/overview")
+		tracing.End(span, nil)
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		request := spans[0]
+		Expect(request.Name()).To(Equal("management-api GET"))
+		Expect(request.Parent().SpanID()).To(Equal(spans[1].SpanContext().SpanID()))
+		Expect(attributes(request)).To(HaveKeyWithValue(attribute.Key("url.path"), attribute.StringValue("/api/overview")))
+		Expect(attributes(request)).To(HaveKeyWithValue(attribute.Key("http.response.status_code"), attribute.IntValue(http.StatusOK)))
+		Expect(request.Status().Code).To(Equal(codes.Unset))
+	})
+
+	It("marks requests with an error status as failed", func() {
+		ctx, span := tracing.Start(context.Background(), "Reconcile")
+		get(ctx, "/api/health/checks/alarms")
+		tracing.End(span, nil)
+
+		request := recorder.Ended()[0]
+		Expect(attributes(request)).To(HaveKeyWithValue(attribute.Key("http.response.status_code"), attribute.IntValue(http.StatusServiceUnavailable)))
+		Expect(request.Status().Code).To(Equal(codes.Error))
+	})
+
+	It("does not trace requests without a span in their context", func() {
+		get(context.Background(), "/api/overview")
+		Expect(recorder.Ended()).To(BeEmpty())
+	})
+
+	It("records the error of a span", func() {
+		_, span := tracing.Start(context.Background(), "apply StatefulSet")
+		tracing.End(span, errors.New("conflict"))
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Status().Code).To(Equal(codes.Error))
+		Expect(spans[0].Status().Description).To(Equal("conflict"))
+		Expect(spans[0].Events()).To(HaveLen(1))
+	})
+
+	It("is only enabled when an OTLP endpoint is configured", func() {
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+		Expect(tracing.Enabled()).To(BeFalse())
+
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://collector:4318/v1/traces")
+		Expect(tracing.Enabled()).To(BeTrue())
+
+		GinkgoT().Setenv("OTEL_SDK_DISABLED", "true")
+		Expect(tracing.Enabled()).To(BeFalse())
+	})
+})
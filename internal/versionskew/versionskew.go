@@ -0,0 +1,81 @@
+// Package versionskew tells whether RabbitMQ supports changing the version of a running cluster.
+package versionskew
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// ErrUnsupportedVersionChange is returned for version changes which RabbitMQ does not support.
+var ErrUnsupportedVersionChange = errors.New("unsupported version change")
+
+var versionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)(\.\d+)?`)
+
+// ImageVersion returns the RabbitMQ version of an image from its tag, e.g. 4.1.0 for rabbitmq:4.1.0-management.
+// It returns false if the tag does not start with a version, like latest or digests.
+func ImageVersion(image string) (string, bool) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	i := strings.LastIndex(image, ":")
+	// a colon before the last slash separates the port of the registry
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return "", false
+	}
+	return Parse(image[i+1:])
+}
+
+// Parse returns the major, minor and patch version at the start of s in canonical semantic version form without
+// the "v" prefix, e.g. 4.1.0 for 4.1.0-rc.1 or 4.1. It returns false if s does not start with a version.
+func Parse(s string) (string, bool) {
+	match := versionPattern.FindStringSubmatch(s)
+	if match == nil {
+		return "", false
+	}
+	patch := match[3]
+	if patch == "" {
+		patch = ".0"
+	}
+	version := "v" + match[1] + "." + match[2] + patch
+	if !semver.IsValid(version) {
+		return "", false
+	}
+	return strings.TrimPrefix(semver.Canonical(version), "v"), true
+}
+
+// Check returns an error wrapping ErrUnsupportedVersionChange if RabbitMQ does not support changing the version of
+// a running cluster from one version to the other. Downgrades are not supported, and upgrades must not skip a minor
+// version. Upgrades to a new major version must target its first minor version, e.g. 3.13 to 4.0.
+func Check(from, to string) error {
+	from, to = "v"+from, "v"+to
+	if semver.Compare(to, from) < 0 {
+		return fmt.Errorf("%w: downgrade from %s to %s", ErrUnsupportedVersionChange, from[1:], to[1:])
+	}
+
+	fromMajor, fromMinor := majorMinor(from)
+	toMajor, toMinor := majorMinor(to)
+	switch {
+	case toMajor == fromMajor && toMinor-fromMinor > 1,
+		toMajor == fromMajor+1 && toMinor > 0,
+		toMajor > fromMajor+1:
+		return fmt.Errorf("%w: upgrade from %s to %s skips a minor version; upgrade one minor version at a time",
+			ErrUnsupportedVersionChange, from[1:], to[1:])
+	}
+	return nil
+}
+
+// SameMinor returns true if the versions only differ in their patch version, e.g. 4.0.5 and 4.0.7.
+func SameMinor(a, b string) bool {
+	return semver.MajorMinor("v"+a) == semver.MajorMinor("v"+b)
+}
+
+func majorMinor(version string) (int, int) {
+	var major, minor int
+	// versions are canonical, so parsing cannot fail
+	_, _ = fmt.Sscanf(semver.MajorMinor(version), "v%d.%d", &major, &minor)
+	return major, minor
+}
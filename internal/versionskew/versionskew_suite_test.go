@@ -0,0 +1,13 @@
+package versionskew_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestVersionskew(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Versionskew Suite")
+}
@@ -0,0 +1,69 @@
+package versionskew_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/versionskew"
+)
+
+var _ = Describe("ImageVersion", func() {
+	DescribeTable("reads the version from the image tag",
+		func(image, expected string) {
+			version, ok := versionskew.ImageVersion(image)
+			Expect(ok).To(BeTrue())
+			Expect(version).To(Equal(expected))
+		},
+		Entry("plain tag", "rabbitmq:4.1.0", "4.1.0"),
+		Entry("tag with suffix", "rabbitmq:3.13.7-management", "3.13.7"),
+		Entry("minor version tag", "rabbitmq:4.1-management-alpine", "4.1.0"),
+		Entry("registry with port", "registry.example.com:5000/library/rabbitmq:4.0.5", "4.0.5"),
+		Entry("tag and digest", "rabbitmq:4.0.5@sha256:0123456789abcdef", "4.0.5"),
+	)
+
+	DescribeTable("ignores images without a version",
+		func(image string) {
+			_, ok := versionskew.ImageVersion(image)
+			Expect(ok).To(BeFalse())
+		},
+		Entry("no tag", "rabbitmq"),
+		Entry("latest", "rabbitmq:latest"),
+		Entry("digest only", "rabbitmq@sha256:0123456789abcdef"),
+		Entry("registry with port and no tag", "registry.example.com:5000/rabbitmq"),
+	)
+})
+
+var _ = Describe("Check", func() {
+	DescribeTable("supported version changes",
+		func(from, to string) {
+			Expect(versionskew.Check(from, to)).To(Succeed())
+		},
+		Entry("same version", "4.0.5", "4.0.5"),
+		Entry("patch upgrade", "4.0.5", "4.0.7"),
+		Entry("minor upgrade", "3.12.14", "3.13.7"),
+		Entry("major upgrade to the first minor", "3.13.7", "4.0.5"),
+	)
+
+	DescribeTable("unsupported version changes",
+		func(from, to, message string) {
+			err := versionskew.Check(from, to)
+			Expect(err).To(MatchError(versionskew.ErrUnsupportedVersionChange))
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("patch downgrade", "4.0.7", "4.0.5", "downgrade from 4.0.7 to 4.0.5"),
+		Entry("minor downgrade", "4.1.0", "4.0.5", "downgrade from 4.1.0 to 4.0.5"),
+		Entry("skipped minor version", "3.11.28", "3.13.7", "upgrade from 3.11.28 to 3.13.7 skips a minor version"),
+		Entry("major upgrade past the first minor", "3.13.7", "4.1.0", "skips a minor version"),
+		Entry("skipped major version", "3.13.7", "5.0.0", "skips a minor version"),
+	)
+})
+
+var _ = Describe("SameMinor", func() {
+	DescribeTable("compares the major and minor versions",
+		func(a, b string, same bool) {
+			Expect(versionskew.SameMinor(a, b)).To(Equal(same))
+		},
+		Entry("patch versions", "4.0.7", "4.0.5", true),
+		Entry("minor versions", "4.1.0", "4.0.5", false),
+		Entry("major versions", "4.0.0", "3.0.0", false),
+	)
+})
@@ -0,0 +1,415 @@
+// Package webhookcert generates and rotates the serving certificate of the webhooks of the operator, so that
+// the webhooks work in installations without cert-manager.
+//
+// The certificate and its self-signed CA are stored in a Secret in the operator namespace, which all replicas of the
+// operator share. Each replica writes the certificate into the directory read by its webhook server, and the CA into
+// the caBundle of the webhook configurations and of the conversion webhook of the CRD.
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;patch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;patch
+
+const (
+	caValidity = 10 * 365 * 24 * time.Hour
+	// certificates are renewed when they expire within this duration
+	renewBefore = 30 * 24 * time.Hour
+
+	caCertKey = "ca.crt"
+	caKeyKey  = "ca.key"
+)
+
+// Options configure the names of the resources which the Rotator manages.
+type Options struct {
+	// Namespace of the operator, which contains the Secret and the webhook Service.
+	Namespace string
+	// SecretName is the name of the Secret storing the certificate and the CA.
+	SecretName string
+	// ServiceName is the name of the webhook Service. The certificate is valid for its DNS names.
+	ServiceName string
+	// CertDir is the directory read by the webhook server, where tls.crt and tls.key are written.
+	CertDir string
+	// MutatingWebhookConfiguration and ValidatingWebhookConfiguration are the names of the webhook configurations
+	// whose caBundle is set. Configurations which do not exist are skipped.
+	MutatingWebhookConfiguration   string
+	ValidatingWebhookConfiguration string
+	// CustomResourceDefinitions are the names of the CRDs whose conversion webhook caBundle is set.
+	CustomResourceDefinitions []string
+	// Validity of the serving certificate. Defaults to one year.
+	Validity time.Duration
+	// RefreshInterval is the interval at which Start checks whether the certificate must be renewed. Defaults to one hour.
+	RefreshInterval time.Duration
+}
+
+// Rotator keeps the webhook serving certificate valid. Its client must not read Secrets from a cache filtered by
+// labels, and its scheme must include apiextensions.k8s.io/v1.
+type Rotator struct {
+	Client client.Client
+	Options
+}
+
+// Start implements manager.Runnable. It syncs the certificate every RefreshInterval until the context is done.
+// Sync should be called once before the manager starts, since the webhook server fails to start without a certificate.
+func (r *Rotator) Start(ctx context.Context) error {
+	interval := r.RefreshInterval
+	if interval == 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Sync(ctx); err != nil {
+				ctrl.LoggerFrom(ctx).Error(err, "failed to rotate the webhook serving certificate")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. All replicas serve webhooks, so all of them must
+// write the certificate to their CertDir.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Sync renews the certificate in the Secret if it is missing or expires soon, then sets the caBundle of the webhook
+// configurations and writes the certificate to CertDir. The caBundle is set before the certificate is served, so that
+// the API server trusts the certificate of every replica during a rotation of the CA.
+func (r *Rotator) Sync(ctx context.Context) error {
+	secret, err := r.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+	caBundle := secret.Data[caCertKey]
+	if err := r.injectCABundle(ctx, caBundle); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(r.CertDir, corev1.TLSCertKey), secret.Data[corev1.TLSCertKey]); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(r.CertDir, corev1.TLSPrivateKeyKey), secret.Data[corev1.TLSPrivateKeyKey])
+}
+
+// ensureSecret returns the Secret after creating or renewing its certificate if needed. Replicas of the operator
+// which start at the same time conflict when they write the Secret; the loser uses the certificate of the winner.
+func (r *Rotator) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		err := r.Client.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: r.SecretName}, secret)
+		if apierrors.IsNotFound(err) {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: r.Namespace, Name: r.SecretName},
+				Type:       corev1.SecretTypeTLS,
+			}
+		} else if err != nil {
+			return err
+		}
+
+		data, renewed, err := r.renew(secret.Data, time.Now())
+		if err != nil || !renewed {
+			return err
+		}
+		secret.Data = data
+		if secret.ResourceVersion == "" {
+			err = r.Client.Create(ctx, secret)
+		} else {
+			err = r.Client.Update(ctx, secret)
+		}
+		if err == nil {
+			ctrl.LoggerFrom(ctx).Info("renewed the webhook serving certificate", "secret", r.SecretName)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew the webhook serving certificate in Secret %s: %w", r.SecretName, err)
+	}
+	return secret, nil
+}
+
+// renew returns the Secret data with a new certificate if the current one is missing, invalid or expires soon.
+// The CA is kept unless it expires before the new certificate would. A new CA is bundled with the previous one in
+// ca.crt, so that certificates signed by the previous CA stay trusted until all replicas serve the new certificate.
+func (r *Rotator) renew(data map[string][]byte, now time.Time) (map[string][]byte, bool, error) {
+	validity := r.Validity
+	if validity == 0 {
+		validity = 365 * 24 * time.Hour
+	}
+	if cert, err := parseCertificate(data[corev1.TLSCertKey]); err == nil && now.Add(renewBefore).Before(cert.NotAfter) {
+		if checkKeyPair(data) == nil {
+			return data, false, nil
+		}
+	}
+
+	caCert, caKey, err := parseCA(data)
+	caPEM := data[caCertKey]
+	if err != nil || !now.Add(validity+renewBefore).Before(caCert.NotAfter) {
+		previous := caPEM
+		if caCert, caKey, caPEM, err = generateCA(now); err != nil {
+			return nil, false, err
+		}
+		if len(previous) > 0 {
+			caPEM = append(append([]byte{}, caPEM...), previous...)
+		}
+	}
+	caKeyPEM, err := encodeKey(caKey)
+	if err != nil {
+		return nil, false, err
+	}
+	certPEM, keyPEM, err := r.generateCertificate(caCert, caKey, now, validity)
+	if err != nil {
+		return nil, false, err
+	}
+	return map[string][]byte{
+		caCertKey:               caBundleOf(caPEM, now),
+		caKeyKey:                caKeyPEM,
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+	}, true, nil
+}
+
+// injectCABundle sets the caBundle of the webhooks and of the conversion webhooks if it differs.
+func (r *Rotator) injectCABundle(ctx context.Context, caBundle []byte) error {
+	if name := r.MutatingWebhookConfiguration; name != "" {
+		config := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := r.patch(ctx, name, config, func() bool {
+			changed := false
+			for i := range config.Webhooks {
+				if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+					config.Webhooks[i].ClientConfig.CABundle = caBundle
+					changed = true
+				}
+			}
+			return changed
+		}); err != nil {
+			return err
+		}
+	}
+	if name := r.ValidatingWebhookConfiguration; name != "" {
+		config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := r.patch(ctx, name, config, func() bool {
+			changed := false
+			for i := range config.Webhooks {
+				if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+					config.Webhooks[i].ClientConfig.CABundle = caBundle
+					changed = true
+				}
+			}
+			return changed
+		}); err != nil {
+			return err
+		}
+	}
+	for _, name := range r.CustomResourceDefinitions {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := r.patch(ctx, name, crd, func() bool {
+			conversion := crd.Spec.Conversion
+			if conversion == nil || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil ||
+				bytes.Equal(conversion.Webhook.ClientConfig.CABundle, caBundle) {
+				return false
+			}
+			conversion.Webhook.ClientConfig.CABundle = caBundle
+			return true
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patch gets the cluster scoped object with the given name and patches it if mutate changes it.
+// Objects which do not exist are skipped.
+func (r *Rotator) patch(ctx context.Context, name string, obj client.Object, mutate func() bool) error {
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: name}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			ctrl.LoggerFrom(ctx).V(1).Info("not setting the caBundle of missing object", "name", name)
+			return nil
+		}
+		return err
+	}
+	base := obj.DeepCopyObject().(client.Object)
+	if !mutate() {
+		return nil
+	}
+	if err := r.Client.Patch(ctx, obj, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("failed to set the caBundle of %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *Rotator) generateCertificate(caCert *x509.Certificate, caKey *ecdsa.PrivateKey, now time.Time, validity time.Duration) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	host := fmt.Sprintf("%s.%s.svc", r.ServiceName, r.Namespace)
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames: []string{
+			r.ServiceName,
+			r.ServiceName + "." + r.Namespace,
+			host,
+			host + ".cluster.local",
+		},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+func generateCA(now time.Time) (*x509.Certificate, *ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "rabbitmq-cluster-operator-webhook-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+// parseCA returns the first certificate of ca.crt, which is the current CA, and its key.
+func parseCA(data map[string][]byte) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	cert, err := parseCertificate(data[caCertKey])
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(data[caKeyKey])
+	if block == nil {
+		return nil, nil, errors.New("no PEM encoded CA key")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// checkKeyPair returns an error if tls.key is not the key of tls.crt.
+func checkKeyPair(data map[string][]byte) error {
+	cert, err := parseCertificate(data[corev1.TLSCertKey])
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(data[corev1.TLSPrivateKeyKey])
+	if block == nil {
+		return errors.New("no PEM encoded key")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return errors.New("key does not match certificate")
+	}
+	return nil
+}
+
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// caBundleOf returns the certificates of the bundle which have not expired yet.
+func caBundleOf(bundle []byte, now time.Time) []byte {
+	var valid []byte
+	for block, rest := pem.Decode(bundle); block != nil; block, rest = pem.Decode(rest) {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil && now.Before(cert.NotAfter) {
+			valid = append(valid, pem.EncodeToMemory(block)...)
+		}
+	}
+	return valid
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// writeFile replaces the file atomically if its content differs, so that the webhook server never reads a partially
+// written file.
+func writeFile(path string, data []byte) error {
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,13 @@
+package webhookcert_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebhookcert(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Webhookcert Suite")
+}
@@ -0,0 +1,130 @@
+package webhookcert_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rabbitmq/cluster-operator/v2/internal/webhookcert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Rotator", func() {
+	var (
+		ctx        = context.Background()
+		fakeClient client.Client
+		rotator    *webhookcert.Rotator
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&admissionregistrationv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "mutating-webhook-configuration"},
+				Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mrabbitmqcluster.rabbitmq.com"}},
+			},
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "rabbitmqclusters.rabbitmq.com"},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Conversion: &apiextensionsv1.CustomResourceConversion{
+						Strategy: apiextensionsv1.WebhookConverter,
+						Webhook:  &apiextensionsv1.WebhookConversion{ClientConfig: &apiextensionsv1.WebhookClientConfig{}},
+					},
+				},
+			},
+		).Build()
+		rotator = &webhookcert.Rotator{
+			Client: fakeClient,
+			Options: webhookcert.Options{
+				Namespace:                      "rabbitmq-system",
+				SecretName:                     "webhook-server-cert",
+				ServiceName:                    "webhook-service",
+				CertDir:                        GinkgoT().TempDir(),
+				MutatingWebhookConfiguration:   "mutating-webhook-configuration",
+				ValidatingWebhookConfiguration: "validating-webhook-configuration",
+				CustomResourceDefinitions:      []string{"rabbitmqclusters.rabbitmq.com"},
+			},
+		}
+	})
+
+	secret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: "rabbitmq-system", Name: "webhook-server-cert"}, secret)).To(Succeed())
+		return secret
+	}
+
+	servedCertificate := func() *x509.Certificate {
+		keyPair, err := tls.LoadX509KeyPair(filepath.Join(rotator.CertDir, "tls.crt"), filepath.Join(rotator.CertDir, "tls.key"))
+		Expect(err).NotTo(HaveOccurred())
+		cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+		Expect(err).NotTo(HaveOccurred())
+		return cert
+	}
+
+	It("generates a certificate trusted by the caBundle of the webhooks", func() {
+		Expect(rotator.Sync(ctx)).To(Succeed())
+
+		Expect(secret().Type).To(Equal(corev1.SecretTypeTLS))
+		caBundle := secret().Data["ca.crt"]
+		webhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "mutating-webhook-configuration"}, webhookConfig)).To(Succeed())
+		Expect(webhookConfig.Webhooks[0].ClientConfig.CABundle).To(Equal(caBundle))
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "rabbitmqclusters.rabbitmq.com"}, crd)).To(Succeed())
+		Expect(crd.Spec.Conversion.Webhook.ClientConfig.CABundle).To(Equal(caBundle))
+
+		roots := x509.NewCertPool()
+		Expect(roots.AppendCertsFromPEM(caBundle)).To(BeTrue())
+		_, err := servedCertificate().Verify(x509.VerifyOptions{DNSName: "webhook-service.rabbitmq-system.svc", Roots: roots})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("keeps a certificate which does not expire soon", func() {
+		Expect(rotator.Sync(ctx)).To(Succeed())
+		first := secret().Data
+
+		Expect(rotator.Sync(ctx)).To(Succeed())
+		Expect(secret().Data).To(Equal(first))
+	})
+
+	It("renews a certificate which expires soon with the same CA", func() {
+		rotator.Validity = 10 * 24 * time.Hour
+		Expect(rotator.Sync(ctx)).To(Succeed())
+		first := secret().Data
+		firstServed := servedCertificate()
+		Expect(firstServed.NotAfter).To(BeTemporally("<", time.Now().Add(11*24*time.Hour)))
+
+		rotator.Validity = 0
+		Expect(rotator.Sync(ctx)).To(Succeed())
+		Expect(secret().Data["ca.crt"]).To(Equal(first["ca.crt"]))
+		Expect(secret().Data["tls.crt"]).NotTo(Equal(first["tls.crt"]))
+		Expect(servedCertificate().NotAfter).To(BeTemporally(">", time.Now().Add(360*24*time.Hour)))
+	})
+
+	It("renews a certificate whose key was lost", func() {
+		Expect(rotator.Sync(ctx)).To(Succeed())
+		broken := secret()
+		broken.Data["tls.key"] = nil
+		Expect(fakeClient.Update(ctx, broken)).To(Succeed())
+
+		Expect(rotator.Sync(ctx)).To(Succeed())
+		block, _ := pem.Decode(secret().Data["tls.key"])
+		Expect(block).NotTo(BeNil())
+		Expect(os.ReadFile(filepath.Join(rotator.CertDir, "tls.key"))).To(Equal(secret().Data["tls.key"]))
+	})
+})
@@ -9,34 +9,54 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/rabbitmq/cluster-operator/v2/pkg/profiling"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
+	rabbitmqv1 "github.com/rabbitmq/cluster-operator/v2/api/v1"
 	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
 	"github.com/rabbitmq/cluster-operator/v2/controllers"
+	"github.com/rabbitmq/cluster-operator/v2/internal/dashboards"
+	"github.com/rabbitmq/cluster-operator/v2/internal/metadata"
+	"github.com/rabbitmq/cluster-operator/v2/internal/operatorconfig"
+	"github.com/rabbitmq/cluster-operator/v2/internal/quota"
+	"github.com/rabbitmq/cluster-operator/v2/internal/rabbitmqclient"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	"github.com/rabbitmq/cluster-operator/v2/internal/tracing"
+	"github.com/rabbitmq/cluster-operator/v2/internal/webhookcert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	defaultscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	// +kubebuilder:scaffold:imports
@@ -51,7 +71,9 @@ var (
 
 func init() {
 	_ = rabbitmqv1beta1.AddToScheme(scheme)
+	_ = rabbitmqv1.AddToScheme(scheme)
 	_ = defaultscheme.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -60,11 +82,31 @@ func main() {
 		metricsAddr             string
 		defaultRabbitmqImage    = "rabbitmq:4.0.3-management"
 		controlRabbitmqImage    = false
+		enableWebhooks          = false
+		manageWebhookCerts      = false
+		productionNamespaces    = labels.Everything()
+		allowedNamespaces       []string
+		deniedNamespaces        []string
 		defaultUserUpdaterImage = "rabbitmqoperator/default-user-credential-updater:1.0.2"
 		defaultImagePullSecrets = ""
+		quotaPolicyConfigMap    = ""
+		labelMappings           map[string]string
+		healthPollInterval      = 60 * time.Second
+		retryBaseDelay          time.Duration
+		retryMaxDelay           time.Duration
+		syncPeriod              time.Duration
+		resyncInterval          time.Duration
+		grafanaDashboards       = false
+		remoteClusters          = false
+		grafanaDashboardsLabels map[string]string
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":9782", "The address the metric endpoint binds to.")
+	flag.DurationVar(&syncPeriod, "sync-period", 0,
+		"The interval at which the cache is resynced, enqueuing all RabbitmqClusters. Defaults to 10 hours if it is 0.")
+	flag.DurationVar(&resyncInterval, "resync-interval", 0,
+		"The interval at which each RabbitmqCluster is reconciled again after a successful reconcile, e.g. 30m. "+
+			"Disabled if it is 0. The annotation rabbitmq.com/resyncInterval overrides it for a RabbitmqCluster.")
 
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
@@ -82,8 +124,34 @@ func main() {
 		os.Exit(1)
 	}
 
-	// If the environment variable is not set Getenv returns an empty string which ctrl.Options.Namespace takes to mean all namespaces should be watched
-	operatorScopeNamespace := os.Getenv("OPERATOR_SCOPE_NAMESPACE")
+	// WATCH_NAMESPACES restricts the operator to RabbitmqClusters in a comma-separated list of namespaces,
+	// e.g. "team-a,team-b". OPERATOR_SCOPE_NAMESPACE is its previous name. All namespaces are watched if neither is set.
+	watchNamespaces := parseList(os.Getenv("OPERATOR_SCOPE_NAMESPACE"))
+	if configuredWatchNamespaces, ok := os.LookupEnv("WATCH_NAMESPACES"); ok {
+		watchNamespaces = parseList(configuredWatchNamespaces)
+	}
+
+	// LEADER_ELECTION_ID must differ between operator deployments in the same namespace which watch different namespaces,
+	// otherwise only one of them is active at a time.
+	leaderElectionID := "rabbitmq-cluster-operator-leader-election"
+	if configuredLeaderElectionID, ok := os.LookupEnv("LEADER_ELECTION_ID"); ok && configuredLeaderElectionID != "" {
+		leaderElectionID = configuredLeaderElectionID
+	}
+
+	// OPERATOR_CONFIG_FILE is the path of the operator configuration file, usually mounted from a ConfigMap.
+	// Environment variables take precedence over the defaults it sets.
+	operatorConfig := &operatorconfig.Config{}
+	if configFile, ok := os.LookupEnv("OPERATOR_CONFIG_FILE"); ok && configFile != "" {
+		var err error
+		if operatorConfig, err = operatorconfig.Load(configFile); err != nil {
+			log.Error(err, "unable to start manager")
+			os.Exit(1)
+		}
+		if operatorConfig.DefaultRabbitmqImage != "" {
+			defaultRabbitmqImage = operatorConfig.DefaultRabbitmqImage
+		}
+		defaultImagePullSecrets = strings.Join(operatorConfig.DefaultImagePullSecrets, ",")
+	}
 
 	if configuredDefaultRabbitmqImage, ok := os.LookupEnv("DEFAULT_RABBITMQ_IMAGE"); ok {
 		defaultRabbitmqImage = configuredDefaultRabbitmqImage
@@ -104,38 +172,153 @@ func main() {
 		}
 	}
 
+	// If the environment variable ENABLE_WEBHOOKS is set to `true`, the operator serves its webhooks on port 9443,
+	// including the conversion webhook of RabbitmqClusters of API version v1 and the defaulting webhook.
+	// The serving certificate and key are read from tls.crt and tls.key in /tmp/k8s-webhook-server/serving-certs,
+	// unless MANAGE_WEBHOOK_CERTS is set.
+	if configuredEnableWebhooks, ok := os.LookupEnv("ENABLE_WEBHOOKS"); ok {
+		var err error
+		if enableWebhooks, err = strconv.ParseBool(configuredEnableWebhooks); err != nil {
+			log.Error(err, "unable to start manager")
+			os.Exit(1)
+		}
+	}
+
+	// If the environment variable MANAGE_WEBHOOK_CERTS is set to `true`, the operator generates and rotates the webhook
+	// serving certificate itself instead of reading one provisioned by cert-manager. The certificate is stored in the
+	// Secret webhook-server-cert, and its CA is set as caBundle of the webhook configurations and of the CRD.
+	if configuredManageWebhookCerts, ok := os.LookupEnv("MANAGE_WEBHOOK_CERTS"); ok {
+		var err error
+		if manageWebhookCerts, err = strconv.ParseBool(configuredManageWebhookCerts); err != nil {
+			log.Error(err, "unable to start manager")
+			os.Exit(1)
+		}
+	}
+
+	// PRODUCTION_NAMESPACE_SELECTOR is a label selector of production namespaces, e.g. "environment=production".
+	// The validating webhook warns about RabbitmqClusters without TLS in these namespaces.
+	if configuredProductionNamespaces, ok := os.LookupEnv("PRODUCTION_NAMESPACE_SELECTOR"); ok {
+		var err error
+		if productionNamespaces, err = labels.Parse(configuredProductionNamespaces); err != nil {
+			log.Error(err, "unable to start manager")
+			os.Exit(1)
+		}
+	}
+
+	// ALLOWED_NAMESPACES and DENIED_NAMESPACES are comma-separated lists of namespace patterns, e.g. "team-*".
+	// The validating webhook rejects new RabbitmqClusters in namespaces which match DENIED_NAMESPACES, or which do not
+	// match ALLOWED_NAMESPACES if it is set.
+	allowedNamespaces = parseList(os.Getenv("ALLOWED_NAMESPACES"))
+	deniedNamespaces = parseList(os.Getenv("DENIED_NAMESPACES"))
+	for _, pattern := range append(append([]string{}, allowedNamespaces...), deniedNamespaces...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			log.Error(err, "unable to start manager", "pattern", pattern)
+			os.Exit(1)
+		}
+	}
+
+	// ROLLOUT_ANALYSIS_PROMETHEUS_URLS is a comma-separated list of the Prometheus URLs which the rollout analyses of
+	// RabbitmqClusters may query, e.g. "http://prometheus.monitoring.svc:9090". Rollout analyses are not run if it is unset.
+	rolloutAnalysisPrometheusURLs := parseList(os.Getenv("ROLLOUT_ANALYSIS_PROMETHEUS_URLS"))
+
+	// If the environment variable REMOTE_CLUSTERS is set to `true`, RabbitmqClusters may set spec.remoteCluster to be
+	// deployed into other Kubernetes clusters with the kubeconfig of a Secret in their namespace.
+	if configuredRemoteClusters, ok := os.LookupEnv("REMOTE_CLUSTERS"); ok {
+		var err error
+		if remoteClusters, err = strconv.ParseBool(configuredRemoteClusters); err != nil {
+			log.Error(err, "unable to start manager")
+			os.Exit(1)
+		}
+	}
+
+	// If the environment variable GRAFANA_DASHBOARDS is set to `true`, the operator creates ConfigMaps with the RabbitMQ
+	// Grafana dashboards in GRAFANA_DASHBOARDS_NAMESPACE, which defaults to the operator namespace. The ConfigMaps are
+	// labelled with GRAFANA_DASHBOARDS_LABELS, e.g. "grafana_dashboard=1", which defaults to "grafana_dashboard=true".
+	if configuredGrafanaDashboards, ok := os.LookupEnv("GRAFANA_DASHBOARDS"); ok {
+		var err error
+		if grafanaDashboards, err = strconv.ParseBool(configuredGrafanaDashboards); err != nil {
+			log.Error(err, "unable to start manager")
+			os.Exit(1)
+		}
+	}
+	grafanaDashboardsNamespace := operatorNamespace
+	if configuredGrafanaDashboardsNamespace, ok := os.LookupEnv("GRAFANA_DASHBOARDS_NAMESPACE"); ok && configuredGrafanaDashboardsNamespace != "" {
+		grafanaDashboardsNamespace = configuredGrafanaDashboardsNamespace
+	}
+	if configuredGrafanaDashboardsLabels, ok := os.LookupEnv("GRAFANA_DASHBOARDS_LABELS"); ok {
+		var err error
+		if grafanaDashboardsLabels, err = labels.ConvertSelectorToLabelsMap(configuredGrafanaDashboardsLabels); err != nil {
+			log.Error(err, "unable to start manager")
+			os.Exit(1)
+		}
+	}
+
 	if configuredDefaultImagePullSecrets, ok := os.LookupEnv("DEFAULT_IMAGE_PULL_SECRETS"); ok {
 		defaultImagePullSecrets = configuredDefaultImagePullSecrets
 	}
 
+	// If the environment variable QUOTA_POLICY_CONFIGMAP_NAME is set, the validating webhook enforces the per-namespace
+	// limits defined in the ConfigMap with that name in the operator namespace.
+	if configuredQuotaPolicyConfigMap, ok := os.LookupEnv("QUOTA_POLICY_CONFIGMAP_NAME"); ok {
+		quotaPolicyConfigMap = configuredQuotaPolicyConfigMap
+	}
+
+	// LABEL_MAPPINGS copies RabbitmqCluster labels to all child resources and pods under a different key,
+	// e.g. "team=example.com/team,cost-center=example.com/cost-center", for cost allocation tools.
+	if configuredLabelMappings, ok := os.LookupEnv("LABEL_MAPPINGS"); ok {
+		var err error
+		if labelMappings, err = metadata.ParseLabelMappings(configuredLabelMappings); err != nil {
+			log.Error(err, "unable to start manager")
+			os.Exit(1)
+		}
+	}
+
+	// CHILD_RESOURCE_NAME_TEMPLATE names child resources with a text/template instead of <name>-<suffix>, e.g.
+	// "{{ trunc 30 .Name }}-{{ .Hash }}-{{ .Suffix }}" to keep the names of RabbitmqClusters with long names short.
+	// It must not change while RabbitmqClusters exist, since it renames their child resources.
+	if childResourceNameTemplate, ok := os.LookupEnv("CHILD_RESOURCE_NAME_TEMPLATE"); ok {
+		if err := rabbitmqv1beta1.SetChildResourceNameTemplate(childResourceNameTemplate); err != nil {
+			log.Error(err, "unable to start manager")
+			os.Exit(1)
+		}
+	}
+
+	// HEALTH_POLL_INTERVAL is the interval in seconds at which the alarms and partitions of RabbitmqClusters are read
+	// into their NoAlarms and NoPartitions conditions. Setting it to 0 disables polling.
+	if _, ok := os.LookupEnv("HEALTH_POLL_INTERVAL"); ok {
+		healthPollInterval = getEnvInDuration("HEALTH_POLL_INTERVAL")
+	}
+
+	// RETRY_BASE_DELAY and RETRY_MAX_DELAY are the delays in seconds of the first and of the longest retry of a
+	// RabbitmqCluster which fails to reconcile. The delay doubles with each retry and is jittered.
+	if _, ok := os.LookupEnv("RETRY_BASE_DELAY"); ok {
+		retryBaseDelay = getEnvInDuration("RETRY_BASE_DELAY")
+	}
+	if _, ok := os.LookupEnv("RETRY_MAX_DELAY"); ok {
+		retryMaxDelay = getEnvInDuration("RETRY_MAX_DELAY")
+	}
+
+	webhookCertDir := filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
 	options := ctrl.Options{
 		Scheme: scheme,
 		Metrics: server.Options{
 			BindAddress: metricsAddr,
 		},
+		WebhookServer:           webhook.NewServer(webhook.Options{CertDir: webhookCertDir}),
 		LeaderElection:          true,
 		LeaderElectionNamespace: operatorNamespace,
-		LeaderElectionID:        "rabbitmq-cluster-operator-leader-election",
-		// Namespace is deprecated. Advice is to use Cache.Namespaces instead
-	}
-
-	if operatorScopeNamespace != "" {
-		if strings.Contains(operatorScopeNamespace, ",") {
-			namespaces := strings.Split(operatorScopeNamespace, ",")
-			// https://github.com/kubernetes-sigs/controller-runtime/blob/main/designs/cache_options.md#only-cache-namespaced-objects-in-the-foo-and-bar-namespace
-			// Sometimes I wish that controller-runtime graduated to 1.x
-			// This changed in 0.15, and again in 0.16 🤦
-			options.Cache.DefaultNamespaces = make(map[string]cache.Config)
-			for _, namespace := range namespaces {
-				options.Cache.DefaultNamespaces[namespace] = cache.Config{}
-			}
-			log.Info("limiting watch to specific namespaces for RabbitMQ resources", "namespaces", namespaces)
-		} else {
-			options.Cache = cache.Options{
-				DefaultNamespaces: map[string]cache.Config{operatorScopeNamespace: {}},
-			}
-			log.Info("limiting watch to one namespace", "namespace", operatorScopeNamespace)
+		LeaderElectionID:        leaderElectionID,
+	}
+
+	if len(watchNamespaces) > 0 {
+		// https://github.com/kubernetes-sigs/controller-runtime/blob/main/designs/cache_options.md#only-cache-namespaced-objects-in-the-foo-and-bar-namespace
+		// Sometimes I wish that controller-runtime graduated to 1.x
+		// This changed in 0.15, and again in 0.16 🤦
+		options.Cache.DefaultNamespaces = make(map[string]cache.Config)
+		for _, namespace := range watchNamespaces {
+			options.Cache.DefaultNamespaces[namespace] = cache.Config{}
 		}
+		log.Info("limiting watch to specific namespaces for RabbitMQ resources", "namespaces", watchNamespaces)
 	}
 
 	rmqLabel, err := labels.NewRequirement("app.kubernetes.io/part-of", selection.Equals, []string{"rabbitmq"})
@@ -155,6 +338,74 @@ func main() {
 		&corev1.Endpoints{}:                {Label: rmqSelector},
 		&rbacv1.Role{}:                     {Label: rmqSelector},
 		&rbacv1.RoleBinding{}:              {Label: rmqSelector},
+		&networkingv1.Ingress{}:            {Label: rmqSelector},
+	}
+
+	clusterConfig := config.GetConfigOrDie()
+
+	// If an OTLP endpoint is configured with the environment variables of the OpenTelemetry SDK, e.g.
+	// OTEL_EXPORTER_OTLP_ENDPOINT, reconciliations are traced. The spans of a reconciliation include the requests to
+	// the Kubernetes API and to the management API of RabbitMQ.
+	shutdownTracing := func(context.Context) error { return nil }
+	if tracing.Enabled() {
+		if shutdownTracing, err = tracing.Setup(context.Background()); err != nil {
+			log.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		clusterConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return tracing.Transport("kubernetes-api", rt)
+		})
+		log.Info("tracing reconciliations with OpenTelemetry")
+	}
+
+	routeAPIAvailable, err := apiAvailable(clusterConfig, resource.RouteGroupVersionKind.GroupVersion(), "routes")
+	if err != nil {
+		log.Error(err, "unable to discover the OpenShift Route API")
+		os.Exit(1)
+	}
+	if routeAPIAvailable {
+		log.Info("OpenShift Route API is available")
+		options.Cache.ByObject[resource.NewRoute("", "")] = cache.ByObject{Label: rmqSelector}
+	}
+
+	tcpRouteAPIAvailable, err := apiAvailable(clusterConfig, resource.TCPRouteGroupVersionKind.GroupVersion(), "tcproutes")
+	if err != nil {
+		log.Error(err, "unable to discover the Gateway API")
+		os.Exit(1)
+	}
+	tlsRouteAPIAvailable, err := apiAvailable(clusterConfig, resource.TLSRouteGroupVersionKind.GroupVersion(), "tlsroutes")
+	if err != nil {
+		log.Error(err, "unable to discover the Gateway API")
+		os.Exit(1)
+	}
+	gatewayAPIAvailable := tcpRouteAPIAvailable && tlsRouteAPIAvailable
+	if gatewayAPIAvailable {
+		log.Info("Gateway API TCPRoute and TLSRoute are available")
+		options.Cache.ByObject[resource.NewGatewayRoute(resource.TCPRouteGroupVersionKind, "", "")] = cache.ByObject{Label: rmqSelector}
+		options.Cache.ByObject[resource.NewGatewayRoute(resource.TLSRouteGroupVersionKind, "", "")] = cache.ByObject{Label: rmqSelector}
+	}
+
+	serviceMonitorAPIAvailable, err := apiAvailable(clusterConfig, resource.ServiceMonitorGroupVersionKind.GroupVersion(), "servicemonitors")
+	if err != nil {
+		log.Error(err, "unable to discover the Prometheus Operator API")
+		os.Exit(1)
+	}
+	podMonitorAPIAvailable, err := apiAvailable(clusterConfig, resource.PodMonitorGroupVersionKind.GroupVersion(), "podmonitors")
+	if err != nil {
+		log.Error(err, "unable to discover the Prometheus Operator API")
+		os.Exit(1)
+	}
+	prometheusRuleAPIAvailable, err := apiAvailable(clusterConfig, resource.PrometheusRuleGroupVersionKind.GroupVersion(), "prometheusrules")
+	if err != nil {
+		log.Error(err, "unable to discover the Prometheus Operator API")
+		os.Exit(1)
+	}
+	prometheusOperatorAvailable := serviceMonitorAPIAvailable && podMonitorAPIAvailable && prometheusRuleAPIAvailable
+	if prometheusOperatorAvailable {
+		log.Info("Prometheus Operator ServiceMonitor, PodMonitor and PrometheusRule are available")
+		options.Cache.ByObject[resource.NewMonitor(resource.ServiceMonitorGroupVersionKind, "", "")] = cache.ByObject{Label: rmqSelector}
+		options.Cache.ByObject[resource.NewMonitor(resource.PodMonitorGroupVersionKind, "", "")] = cache.ByObject{Label: rmqSelector}
+		options.Cache.ByObject[resource.NewPrometheusRule("", "")] = cache.ByObject{Label: rmqSelector}
 	}
 
 	if leaseDuration := getEnvInDuration("LEASE_DURATION"); leaseDuration != 0 {
@@ -162,6 +413,11 @@ func main() {
 		options.LeaseDuration = &leaseDuration
 	}
 
+	if syncPeriod > 0 {
+		log.Info("manager configured with sync period", "period", syncPeriod.String())
+		options.Cache.SyncPeriod = &syncPeriod
+	}
+
 	if renewDeadline := getEnvInDuration("RENEW_DEADLINE"); renewDeadline != 0 {
 		log.Info("manager configured with renew deadline", "seconds", int(renewDeadline.Seconds()))
 		options.RenewDeadline = &renewDeadline
@@ -184,42 +440,156 @@ func main() {
 		}
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
+	mgr, err := ctrl.NewManager(clusterConfig, options)
 	if err != nil {
 		log.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	clusterConfig := config.GetConfigOrDie()
-
-	err = (&controllers.RabbitmqClusterReconciler{
-		Client:                  mgr.GetClient(),
-		APIReader:               mgr.GetAPIReader(),
-		Scheme:                  mgr.GetScheme(),
-		Recorder:                mgr.GetEventRecorderFor(controllerName),
-		Namespace:               operatorNamespace,
-		ClusterConfig:           clusterConfig,
-		Clientset:               kubernetes.NewForConfigOrDie(clusterConfig),
-		PodExecutor:             controllers.NewPodExecutor(),
-		DefaultRabbitmqImage:    defaultRabbitmqImage,
-		DefaultUserUpdaterImage: defaultUserUpdaterImage,
-		DefaultImagePullSecrets: defaultImagePullSecrets,
-		ControlRabbitmqImage:    controlRabbitmqImage,
-	}).SetupWithManager(mgr)
-	if err != nil {
+	reconciler := &controllers.RabbitmqClusterReconciler{
+		Client:                        mgr.GetClient(),
+		APIReader:                     mgr.GetAPIReader(),
+		Scheme:                        mgr.GetScheme(),
+		Recorder:                      mgr.GetEventRecorderFor(controllerName),
+		Namespace:                     operatorNamespace,
+		ClusterConfig:                 clusterConfig,
+		Clientset:                     kubernetes.NewForConfigOrDie(clusterConfig),
+		PodExecutor:                   controllers.NewPodExecutor(),
+		RabbitmqClientFactory:         rabbitmqclient.New,
+		DefaultRabbitmqImage:          defaultRabbitmqImage,
+		DefaultUserUpdaterImage:       defaultUserUpdaterImage,
+		DefaultImagePullSecrets:       defaultImagePullSecrets,
+		ControlRabbitmqImage:          controlRabbitmqImage,
+		LabelMappings:                 labelMappings,
+		RouteAPIAvailable:             routeAPIAvailable,
+		GatewayAPIAvailable:           gatewayAPIAvailable,
+		PrometheusOperatorAvailable:   prometheusOperatorAvailable,
+		HealthPollInterval:            healthPollInterval,
+		RetryBaseDelay:                retryBaseDelay,
+		RetryMaxDelay:                 retryMaxDelay,
+		ResyncInterval:                resyncInterval,
+		DefaultStorageClassName:       operatorConfig.DefaultStorageClassName,
+		DefaultResources:              operatorConfig.DefaultResources,
+		InjectedLabels:                operatorConfig.Labels,
+		InjectedAnnotations:           operatorConfig.Annotations,
+		RolloutAnalysisPrometheusURLs: rolloutAnalysisPrometheusURLs,
+		WatchNamespaces:               watchNamespaces,
+		RemoteClustersEnabled:         remoteClusters,
+	}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create controller", controllerName)
 		os.Exit(1)
 	}
 	log.Info("started controller")
+
+	if enableWebhooks {
+		if err := (&rabbitmqv1.RabbitmqCluster{}).SetupWebhookWithManager(mgr); err != nil {
+			log.Error(err, "unable to create webhook", "webhook", "RabbitmqCluster")
+			os.Exit(1)
+		}
+		defaulter := &rabbitmqv1beta1.RabbitmqClusterDefaulter{Defaults: reconciler.OperatorDefaults()}
+		validator := &rabbitmqv1beta1.RabbitmqClusterValidator{
+			Client:                      mgr.GetAPIReader(),
+			ProductionNamespaceSelector: productionNamespaces,
+			AllowedNamespaces:           allowedNamespaces,
+			DeniedNamespaces:            deniedNamespaces,
+		}
+		if quotaPolicyConfigMap != "" {
+			validator.CheckQuota = (&quota.Checker{
+				Client:    mgr.GetAPIReader(),
+				Namespace: operatorNamespace,
+				Name:      quotaPolicyConfigMap,
+			}).Check
+		}
+		if err := (&rabbitmqv1beta1.RabbitmqCluster{}).SetupWebhookWithManager(mgr, defaulter, validator); err != nil {
+			log.Error(err, "unable to create webhook", "webhook", "RabbitmqCluster")
+			os.Exit(1)
+		}
+		log.Info("registered webhooks")
+
+		if manageWebhookCerts {
+			if err := setupWebhookCertRotator(mgr, clusterConfig, operatorNamespace, webhookCertDir); err != nil {
+				log.Error(err, "unable to set up webhook certificates")
+				os.Exit(1)
+			}
+			log.Info("managing webhook certificates")
+		}
+	}
+	if grafanaDashboards {
+		if err := setupDashboardProvisioner(mgr, clusterConfig, grafanaDashboardsNamespace, grafanaDashboardsLabels); err != nil {
+			log.Error(err, "unable to set up Grafana dashboards")
+			os.Exit(1)
+		}
+		log.Info("provisioning Grafana dashboards", "namespace", grafanaDashboardsNamespace)
+	}
 	// +kubebuilder:scaffold:builder
 
 	log.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+		log.Error(shutdownErr, "failed to export remaining spans")
+	}
+	if err != nil {
 		log.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
 
+// setupWebhookCertRotator writes a valid webhook serving certificate before the webhook server starts, and adds a
+// runnable which renews it before it expires. The manager client cannot be used before the manager starts, and it
+// only caches Secrets of RabbitmqClusters, so the rotator uses a client without cache.
+func setupWebhookCertRotator(mgr ctrl.Manager, cfg *rest.Config, operatorNamespace, certDir string) error {
+	c, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return err
+	}
+	rotator := &webhookcert.Rotator{
+		Client: c,
+		Options: webhookcert.Options{
+			Namespace:                      operatorNamespace,
+			SecretName:                     "webhook-server-cert",
+			ServiceName:                    "webhook-service",
+			CertDir:                        certDir,
+			MutatingWebhookConfiguration:   "mutating-webhook-configuration",
+			ValidatingWebhookConfiguration: "validating-webhook-configuration",
+			CustomResourceDefinitions:      []string{"rabbitmqclusters.rabbitmq.com"},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := rotator.Sync(ctx); err != nil {
+		return err
+	}
+	return mgr.Add(rotator)
+}
+
+// setupDashboardProvisioner adds a runnable which creates the Grafana dashboard ConfigMaps. Its client is not cached,
+// since the cache of the manager only holds ConfigMaps of RabbitmqClusters.
+func setupDashboardProvisioner(mgr ctrl.Manager, cfg *rest.Config, namespace string, dashboardLabels map[string]string) error {
+	c, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return err
+	}
+	return mgr.Add(&dashboards.Provisioner{
+		Client:    c,
+		Namespace: namespace,
+		Labels:    dashboardLabels,
+	})
+}
+
+// parseList returns the non-empty items of a comma-separated list.
+func parseList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnvInDuration(envName string) time.Duration {
 	var durationInt int64
 	if durationStr := os.Getenv(envName); durationStr != "" {
@@ -231,3 +601,24 @@ func getEnvInDuration(envName string) time.Duration {
 	}
 	return time.Duration(durationInt) * time.Second
 }
+
+// apiAvailable returns true if the Kubernetes API server serves the resource in the given group version.
+func apiAvailable(cfg *rest.Config, groupVersion schema.GroupVersion, resourceName string) (bool, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return false, err
+	}
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion.String())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == resourceName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
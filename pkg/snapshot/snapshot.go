@@ -0,0 +1,143 @@
+// Package snapshot serializes the child resources the operator creates for a RabbitmqCluster,
+// so that they can be compared against golden files.
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/internal/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// Redacted replaces all Secret values, since generated credentials differ on every build.
+const Redacted = "REDACTED"
+
+// Options configure the operator settings the child resources depend on.
+type Options struct {
+	// LabelMappings copies RabbitmqCluster labels to child resources under a different key.
+	LabelMappings map[string]string
+	// RouteAPIAvailable includes the OpenShift Routes.
+	RouteAPIAvailable bool
+	// GatewayAPIAvailable includes the Gateway API routes.
+	GatewayAPIAvailable bool
+	// PrometheusOperatorAvailable includes the ServiceMonitor or PodMonitor and the PrometheusRule.
+	PrometheusOperatorAvailable bool
+	// ConfigFrom is the content of the ConfigMap key referenced by spec.rabbitmq.configFrom.
+	ConfigFrom string
+	// InjectedLabels are set on all child resources, unless the RabbitmqCluster sets the same label.
+	InjectedLabels map[string]string
+	// InjectedAnnotations are set on all child resources, unless the RabbitmqCluster sets the same annotation.
+	InjectedAnnotations map[string]string
+	// Scheme resolves the kinds of the child resources. Defaults to a scheme with the RabbitmqCluster and Kubernetes types,
+	// which does not include the OpenShift Routes, Gateway API routes and Prometheus Operator monitors.
+	Scheme *runtime.Scheme
+}
+
+// Children returns all child resources of the RabbitmqCluster as a multi-document YAML.
+// The output is deterministic: resources are sorted by kind and name, timestamps are omitted
+// and Secret values are replaced by Redacted.
+// Defaults of the RabbitmqCluster CRD are applied by the Kubernetes API server, so they must be
+// set on the given RabbitmqCluster for the output to match a running cluster.
+func Children(cluster *rabbitmqv1beta1.RabbitmqCluster, opts Options) ([]byte, error) {
+	scheme := opts.Scheme
+	if scheme == nil {
+		scheme = runtime.NewScheme()
+		if err := rabbitmqv1beta1.AddToScheme(scheme); err != nil {
+			return nil, err
+		}
+		if err := clientgoscheme.AddToScheme(scheme); err != nil {
+			return nil, err
+		}
+	}
+
+	builder := resource.RabbitmqResourceBuilder{
+		Instance:                    cluster.DeepCopy(),
+		Scheme:                      scheme,
+		LabelMappings:               opts.LabelMappings,
+		RouteAPIAvailable:           opts.RouteAPIAvailable,
+		GatewayAPIAvailable:         opts.GatewayAPIAvailable,
+		PrometheusOperatorAvailable: opts.PrometheusOperatorAvailable,
+		ConfigFrom:                  opts.ConfigFrom,
+		InjectedLabels:              opts.InjectedLabels,
+		InjectedAnnotations:         opts.InjectedAnnotations,
+	}
+
+	var children []*unstructured.Unstructured
+	for _, resourceBuilder := range builder.ResourceBuilders() {
+		obj, err := resourceBuilder.Build()
+		if err != nil {
+			return nil, err
+		}
+		if err := resourceBuilder.Update(obj); err != nil {
+			return nil, err
+		}
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, err
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s %s: %w", gvk.Kind, obj.GetName(), err)
+		}
+		child := &unstructured.Unstructured{Object: content}
+		child.SetGroupVersionKind(gvk)
+		children = append(children, sanitize(child))
+	}
+
+	sort.SliceStable(children, func(i, j int) bool {
+		if children[i].GetKind() != children[j].GetKind() {
+			return children[i].GetKind() < children[j].GetKind()
+		}
+		return children[i].GetName() < children[j].GetName()
+	})
+
+	var out bytes.Buffer
+	for i, child := range children {
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		doc, err := yaml.Marshal(child.Object)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(doc)
+	}
+	return out.Bytes(), nil
+}
+
+// sanitize removes the fields which differ between builds.
+func sanitize(child *unstructured.Unstructured) *unstructured.Unstructured {
+	unstructured.RemoveNestedField(child.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(child.Object, "spec", "template", "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(child.Object, "status")
+	if templates, found, _ := unstructured.NestedSlice(child.Object, "spec", "volumeClaimTemplates"); found {
+		for _, template := range templates {
+			if t, ok := template.(map[string]interface{}); ok {
+				unstructured.RemoveNestedField(t, "metadata", "creationTimestamp")
+				unstructured.RemoveNestedField(t, "status")
+			}
+		}
+		_ = unstructured.SetNestedSlice(child.Object, templates, "spec", "volumeClaimTemplates")
+	}
+
+	if child.GetKind() == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			values, found, _ := unstructured.NestedMap(child.Object, field)
+			if !found {
+				continue
+			}
+			for key := range values {
+				values[key] = Redacted
+			}
+			_ = unstructured.SetNestedMap(child.Object, values, field)
+		}
+	}
+	return child
+}
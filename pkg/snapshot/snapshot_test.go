@@ -0,0 +1,98 @@
+package snapshot_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rabbitmqv1beta1 "github.com/rabbitmq/cluster-operator/v2/api/v1beta1"
+	"github.com/rabbitmq/cluster-operator/v2/pkg/snapshot"
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Children", func() {
+	var cluster *rabbitmqv1beta1.RabbitmqCluster
+
+	BeforeEach(func() {
+		storage := k8sresource.MustParse("10Gi")
+		cluster = &rabbitmqv1beta1.RabbitmqCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "snapshot",
+				Namespace: "rabbitmq",
+			},
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Replicas:                      ptr.To(int32(3)),
+				Image:                         "rabbitmq:4.0-management",
+				TerminationGracePeriodSeconds: ptr.To(int64(604800)),
+				DelayStartSeconds:             ptr.To(int32(30)),
+				Service:                       rabbitmqv1beta1.RabbitmqClusterServiceSpec{Type: "ClusterIP"},
+				Persistence:                   rabbitmqv1beta1.RabbitmqClusterPersistenceSpec{Storage: &storage},
+				Resources:                     &corev1.ResourceRequirements{},
+			},
+		}
+	})
+
+	It("is deterministic", func() {
+		first, err := snapshot.Children(cluster, snapshot.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		second, err := snapshot.Children(cluster, snapshot.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(first)).To(Equal(string(second)))
+		Expect(string(first)).NotTo(ContainSubstring("creationTimestamp"))
+	})
+
+	It("sorts the child resources by kind and name", func() {
+		out, err := snapshot.Children(cluster, snapshot.Options{})
+		Expect(err).NotTo(HaveOccurred())
+
+		var kindsAndNames []string
+		for _, doc := range strings.Split(string(out), "---\n") {
+			obj := &unstructured.Unstructured{}
+			Expect(yaml.Unmarshal([]byte(doc), &obj.Object)).To(Succeed())
+			kindsAndNames = append(kindsAndNames, obj.GetKind()+"/"+obj.GetName())
+		}
+		Expect(kindsAndNames).To(Equal([]string{
+			"ConfigMap/snapshot-plugins-conf",
+			"ConfigMap/snapshot-server-conf",
+			"Role/snapshot-peer-discovery",
+			"RoleBinding/snapshot-server",
+			"Secret/snapshot-default-user",
+			"Secret/snapshot-erlang-cookie",
+			"Service/snapshot",
+			"Service/snapshot-nodes",
+			"ServiceAccount/snapshot-server",
+			"StatefulSet/snapshot-server",
+		}))
+	})
+
+	It("redacts Secret values", func() {
+		out, err := snapshot.Children(cluster, snapshot.Options{})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, doc := range strings.Split(string(out), "---\n") {
+			obj := &unstructured.Unstructured{}
+			Expect(yaml.Unmarshal([]byte(doc), &obj.Object)).To(Succeed())
+			if obj.GetKind() != "Secret" {
+				continue
+			}
+			data, found, err := unstructured.NestedStringMap(obj.Object, "data")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			for key, value := range data {
+				Expect(value).To(Equal(snapshot.Redacted), key)
+			}
+		}
+	})
+
+	It("does not modify the RabbitmqCluster", func() {
+		original := cluster.DeepCopy()
+		_, err := snapshot.Children(cluster, snapshot.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster).To(Equal(original))
+	})
+})
@@ -543,16 +543,19 @@ CONSOLE_LOG=new`
 
 				By("disabling non TLS listeners", func() {
 					// verify that rabbitmq.conf contains listeners.tcp = none
-					cfgMap := getConfigFileFromPod(namespace, cluster, "/etc/rabbitmq/conf.d/90-userDefinedConfiguration.conf")
+					cfgMap := getConfigFileFromPod(namespace, cluster, "/etc/rabbitmq/conf.d/20-tls.conf")
 					Expect(cfgMap).To(SatisfyAll(
 						HaveKeyWithValue("listeners.tcp", "none"),
-						HaveKeyWithValue("stomp.listeners.tcp", "none"),
-						HaveKeyWithValue("mqtt.listeners.tcp", "none"),
 						HaveKeyWithValue("management.ssl.port", "15671"),
 						Not(HaveKey("management.tcp.port")),
 						HaveKeyWithValue("prometheus.ssl.port", "15691"),
 						Not(HaveKey("prometheus.tcp.port")),
 					))
+					pluginsCfgMap := getConfigFileFromPod(namespace, cluster, "/etc/rabbitmq/conf.d/30-plugins.conf")
+					Expect(pluginsCfgMap).To(SatisfyAll(
+						HaveKeyWithValue("stomp.listeners.tcp", "none"),
+						HaveKeyWithValue("mqtt.listeners.tcp", "none"),
+					))
 
 					// verify that only tls ports are exposed in service
 					service, err := clientSet.CoreV1().Services(cluster.Namespace).Get(ctx, cluster.ChildResourceName(""), metav1.GetOptions{})